@@ -2,7 +2,6 @@ module github.com/sjatkinson/threadkeeper
 
 go 1.25.5
 
-require (
-	github.com/pelletier/go-toml/v2 v2.2.4 // indirect
-	github.com/spf13/pflag v1.0.10 // indirect
-)
+require github.com/pelletier/go-toml/v2 v2.2.4
+
+require github.com/spf13/pflag v1.0.10 // indirect
@@ -0,0 +1,199 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/sjatkinson/threadkeeper/internal/commands"
+	"github.com/sjatkinson/threadkeeper/internal/config"
+)
+
+// runAlias lists, adds and removes the aliases in the [alias] table of
+// config.toml. It lives here rather than in commands because checking an
+// alias needs the command registry.
+func runAlias(args []string, ctx commands.CommandContext) int {
+	if len(args) == 0 {
+		_, _ = fmt.Fprintln(ctx.Err, aliasUsage(ctx.AppName))
+		return 2
+	}
+
+	switch args[0] {
+	case "list":
+		return runAliasList(args[1:], ctx)
+	case "add":
+		return runAliasAdd(args[1:], ctx)
+	case "remove":
+		return runAliasRemove(args[1:], ctx)
+	default:
+		_, _ = fmt.Fprintf(ctx.Err, "Error: invalid alias subcommand %q (must be 'list', 'add' or 'remove')\n", args[0])
+		_, _ = fmt.Fprintln(ctx.Err)
+		_, _ = fmt.Fprintln(ctx.Err, aliasUsage(ctx.AppName))
+		return 2
+	}
+}
+
+// runAliasList prints the aliases in use, then any in config.toml that are
+// ignored and why.
+func runAliasList(args []string, ctx commands.CommandContext) int {
+	if len(args) != 0 {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: unexpected arguments\n")
+		_, _ = fmt.Fprintln(ctx.Err, aliasUsage(ctx.AppName))
+		return 2
+	}
+
+	raw, err := config.LoadAliases()
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+	if len(raw) == 0 {
+		_, _ = fmt.Fprintln(ctx.Out, "No aliases defined.")
+		return 0
+	}
+
+	names := make([]string, 0, len(raw))
+	for name := range raw {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var ignored []string
+	for _, name := range names {
+		if problem := aliasProblem(name, raw[name], raw); problem != "" {
+			ignored = append(ignored, fmt.Sprintf("  %s = %s  (%s)", name, raw[name], problem))
+			continue
+		}
+		_, _ = fmt.Fprintf(ctx.Out, "%s = %s\n", name, raw[name])
+	}
+	if len(ignored) > 0 {
+		_, _ = fmt.Fprintln(ctx.Out, "\nIgnored:")
+		_, _ = fmt.Fprintln(ctx.Out, strings.Join(ignored, "\n"))
+	}
+	return 0
+}
+
+func runAliasAdd(args []string, ctx commands.CommandContext) int {
+	if len(args) < 2 {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: expected <name> and <target>\n")
+		_, _ = fmt.Fprintln(ctx.Err, aliasUsage(ctx.AppName))
+		return 2
+	}
+
+	// The target may be given as one quoted argument or as several words
+	name, target := strings.TrimSpace(args[0]), strings.TrimSpace(args[1])
+	if len(args) > 2 {
+		words := make([]string, len(args)-1)
+		for i, w := range args[1:] {
+			words[i] = quoteAliasWord(w)
+		}
+		target = strings.Join(words, " ")
+	}
+	if name == "" || strings.ContainsAny(name, " \t") || strings.HasPrefix(name, "-") {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: invalid alias name %q\n", name)
+		return 2
+	}
+
+	raw, err := config.LoadAliases()
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+	if problem := aliasProblem(name, target, raw); problem != "" {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: alias %q %s\n", name, problem)
+		return 1
+	}
+
+	if err := config.SetAlias(name, target); err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+	ctx.Success("Added alias %s = %s\n", name, target)
+	return 0
+}
+
+func runAliasRemove(args []string, ctx commands.CommandContext) int {
+	if len(args) != 1 {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: expected one alias name\n")
+		_, _ = fmt.Fprintln(ctx.Err, aliasUsage(ctx.AppName))
+		return 2
+	}
+
+	removed, err := config.RemoveAlias(args[0])
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+	if !removed {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: no alias named %q\n", args[0])
+		return 1
+	}
+	ctx.Success("Removed alias %s\n", args[0])
+	return 0
+}
+
+// quoteAliasWord quotes w, if needed, so splitAliasTarget reads it back as
+// one word.
+func quoteAliasWord(w string) string {
+	if w != "" && !strings.ContainsAny(w, " \t\n'\"\\") {
+		return w
+	}
+	return "'" + strings.ReplaceAll(w, "'", `'\''`) + "'"
+}
+
+// splitAliasTarget splits an alias target such as `list --tag "needs review"`
+// into words the way a POSIX shell would: words are separated by
+// whitespace, single quotes keep everything literally, and inside double
+// quotes or bare words a backslash escapes the next character.
+func splitAliasTarget(s string) ([]string, error) {
+	var words []string
+	var cur strings.Builder
+	inWord := false
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			if inWord {
+				words = append(words, cur.String())
+				cur.Reset()
+				inWord = false
+			}
+		case c == '\'':
+			end := strings.IndexByte(s[i+1:], '\'')
+			if end < 0 {
+				return nil, errors.New("unterminated single quote")
+			}
+			cur.WriteString(s[i+1 : i+1+end])
+			i += end + 1
+			inWord = true
+		case c == '"':
+			i++
+			for ; i < len(s) && s[i] != '"'; i++ {
+				if s[i] == '\\' && i+1 < len(s) && (s[i+1] == '"' || s[i+1] == '\\') {
+					i++
+				}
+				cur.WriteByte(s[i])
+			}
+			if i >= len(s) {
+				return nil, errors.New("unterminated double quote")
+			}
+			inWord = true
+		case c == '\\':
+			if i+1 >= len(s) {
+				return nil, errors.New("trailing backslash")
+			}
+			i++
+			cur.WriteByte(s[i])
+			inWord = true
+		default:
+			cur.WriteByte(c)
+			inWord = true
+		}
+	}
+	if inWord {
+		words = append(words, cur.String())
+	}
+	return words, nil
+}
@@ -6,9 +6,11 @@ import (
 	"io"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/sjatkinson/threadkeeper/internal/commands"
 	"github.com/sjatkinson/threadkeeper/internal/config"
+	"github.com/sjatkinson/threadkeeper/internal/date"
 )
 
 // CommandInfo holds metadata for a command.
@@ -19,6 +21,9 @@ type CommandInfo struct {
 	Description string
 	Usage       func(app string) string
 	Runner      func(args []string, ctx commands.CommandContext) int
+	// Mutates marks commands that change the workspace; they are followed
+	// by a git auto-commit when git_autocommit is set.
+	Mutates bool
 }
 
 // commandRegistry holds all registered commands.
@@ -73,6 +78,7 @@ func init() {
 		Description: "Add a new task",
 		Usage:       addUsage,
 		Runner:      commands.RunAdd,
+		Mutates:     true,
 	})
 	registerCommand(CommandInfo{
 		Name:        "list",
@@ -80,53 +86,184 @@ func init() {
 		Usage:       listUsage,
 		Runner:      commands.RunList,
 	})
+	registerCommand(CommandInfo{
+		Name:        "search",
+		Description: "Find tasks by text in title or description",
+		Usage:       searchUsage,
+		Runner:      commands.RunSearch,
+	})
+	registerCommand(CommandInfo{
+		Name:        "grep",
+		Description: "Search the contents of note attachments",
+		Usage:       grepUsage,
+		Runner:      commands.RunGrep,
+	})
+	registerCommand(CommandInfo{
+		Name:        "count",
+		Description: "Print the number of matching tasks",
+		Usage:       countUsage,
+		Runner:      commands.RunCount,
+	})
+	registerCommand(CommandInfo{
+		Name:        "agenda",
+		Description: "List open tasks due soon, grouped by day",
+		Usage:       agendaUsage,
+		Runner:      commands.RunAgenda,
+	})
+	registerCommand(CommandInfo{
+		Name:        "today",
+		Description: "List open tasks overdue or due today",
+		Usage:       todayUsage,
+		Runner:      commands.RunToday,
+	})
+	registerCommand(CommandInfo{
+		Name:        "notify",
+		Description: "Print open tasks due soon, one line each, for cron",
+		Usage:       notifyUsage,
+		Runner:      commands.RunNotify,
+	})
+	registerCommand(CommandInfo{
+		Name:        "next",
+		Description: "Show the most urgent open task",
+		Usage:       nextUsage,
+		Runner:      commands.RunNext,
+	})
+	registerCommand(CommandInfo{
+		Name:        "recent",
+		Description: "List tasks touched in the last few days",
+		Usage:       recentUsage,
+		Runner:      commands.RunRecent,
+	})
 	registerCommand(CommandInfo{
 		Name:        "show",
 		Description: "Show details for a single task",
 		Usage:       showUsage,
 		Runner:      commands.RunShow,
 	})
+	registerCommand(CommandInfo{
+		Name:        "log",
+		Description: "Show a task's history",
+		Usage:       logUsage,
+		Runner:      commands.RunLog,
+	})
 	registerCommand(CommandInfo{
 		Name:        "describe",
 		Description: "Edit a task description in $EDITOR (later)",
 		Usage:       describeUsage,
 		Runner:      commands.RunDescribe,
+		Mutates:     true,
 	})
 	registerCommand(CommandInfo{
 		Name:        "update",
 		Description: "Update fields on one or more tasks",
 		Usage:       updateUsage,
 		Runner:      commands.RunUpdate,
+		Mutates:     true,
+	})
+	registerCommand(CommandInfo{
+		Name:        "retag",
+		Description: "Change tags and project on every task matching a filter",
+		Usage:       retagUsage,
+		Runner:      commands.RunRetag,
+		Mutates:     true,
+	})
+	registerCommand(CommandInfo{
+		Name:        "snooze",
+		Description: "Push due dates forward",
+		Usage:       snoozeUsage,
+		Runner:      commands.RunSnooze,
+		Mutates:     true,
+	})
+	registerCommand(CommandInfo{
+		Name:        "start",
+		Description: "Start a timer on a task",
+		Usage:       startUsage,
+		Runner:      commands.RunStart,
+		Mutates:     true,
+	})
+	registerCommand(CommandInfo{
+		Name:        "stop",
+		Description: "Stop a task's timer and add up the time spent",
+		Usage:       stopUsage,
+		Runner:      commands.RunStop,
+		Mutates:     true,
 	})
 	registerCommand(CommandInfo{
 		Name:        "done",
-		Description: "Mark one or more tasks done",
+		Description: "Mark tasks done by ID or filter",
 		Usage:       doneUsage,
 		Runner:      commands.RunDone,
+		Mutates:     true,
 	})
 	registerCommand(CommandInfo{
 		Name:        "archive",
-		Description: "Archive one or more tasks",
+		Description: "Archive tasks by ID or filter",
 		Usage:       archiveUsage,
 		Runner:      commands.RunArchive,
+		Mutates:     true,
 	})
 	registerCommand(CommandInfo{
 		Name:        "reopen",
 		Description: "Reopen one or more tasks (change from inactive to active)",
 		Usage:       reopenUsage,
 		Runner:      commands.RunReopen,
+		Mutates:     true,
 	})
 	registerCommand(CommandInfo{
 		Name:        "remove",
-		Description: "Remove one or more tasks (hard delete; requires --force)",
+		Description: "Move tasks to the trash (--permanent deletes them)",
 		Usage:       removeUsage,
 		Runner:      commands.RunRemove,
+		Mutates:     true,
+	})
+	registerCommand(CommandInfo{
+		Name:        "trash",
+		Description: "List, restore or empty removed tasks",
+		Usage:       trashUsage,
+		Runner:      commands.RunTrash,
+		Mutates:     true,
+	})
+	registerCommand(CommandInfo{
+		Name:        "undo",
+		Description: "Undo the last done, archive, update, snooze or remove",
+		Usage:       undoUsage,
+		Runner:      commands.RunUndo,
+		Mutates:     true,
 	})
 	registerCommand(CommandInfo{
 		Name:        "reindex",
 		Description: "Reassign short IDs for active tasks",
 		Usage:       reindexUsage,
 		Runner:      commands.RunReindex,
+		Mutates:     true,
+	})
+	registerCommand(CommandInfo{
+		Name:        "doctor",
+		Description: "Check workspace health",
+		Usage:       doctorUsage,
+		Runner:      commands.RunDoctor,
+		Mutates:     true, // --fix repairs short_ids and moves threads
+	})
+	registerCommand(CommandInfo{
+		Name:        "rebucket",
+		Description: "Move threads into buckets for the configured bucket_width",
+		Usage:       rebucketUsage,
+		Runner:      commands.RunRebucket,
+		Mutates:     true,
+	})
+	registerCommand(CommandInfo{
+		Name:        "migrate",
+		Description: "Upgrade thread files to the current schema version",
+		Usage:       migrateUsage,
+		Runner:      commands.RunMigrate,
+		Mutates:     true,
+	})
+	registerCommand(CommandInfo{
+		Name:        "migrate-blobs",
+		Description: "Move per-thread blobs into the shared blob store",
+		Usage:       migrateBlobsUsage,
+		Runner:      commands.RunMigrateBlobs,
+		Mutates:     true,
 	})
 	registerCommand(CommandInfo{
 		Name:        "path",
@@ -134,11 +271,36 @@ func init() {
 		Usage:       pathUsage,
 		Runner:      commands.RunPath,
 	})
+	registerCommand(CommandInfo{
+		Name:        "config",
+		Description: "Get or set config.toml values",
+		Usage:       configUsage,
+		Runner:      commands.RunConfig,
+	})
+	registerCommand(CommandInfo{
+		Name:        "alias",
+		Description: "List, add or remove command aliases",
+		Usage:       aliasUsage,
+		Runner:      runAlias,
+	})
 	registerCommand(CommandInfo{
 		Name:        "attach",
 		Description: "Attach an inline note to a thread",
 		Usage:       attachUsage,
 		Runner:      commands.RunAttach,
+		Mutates:     true,
+	})
+	registerCommand(CommandInfo{
+		Name:        "attachments",
+		Description: "List a thread's current attachments",
+		Usage:       attachmentsUsage,
+		Runner:      commands.RunAttachments,
+	})
+	registerCommand(CommandInfo{
+		Name:        "check-links",
+		Description: "Report broken link attachments",
+		Usage:       checkLinksUsage,
+		Runner:      commands.RunCheckLinks,
 	})
 	registerCommand(CommandInfo{
 		Name:        "open",
@@ -146,6 +308,96 @@ func init() {
 		Usage:       openUsage,
 		Runner:      commands.RunOpen,
 	})
+	registerCommand(CommandInfo{
+		Name:        "mv-att",
+		Description: "Move an attachment to another thread",
+		Usage:       mvAttUsage,
+		Runner:      commands.RunMvAtt,
+		Mutates:     true,
+	})
+	registerCommand(CommandInfo{
+		Name:        "compact",
+		Description: "Drop removed attachments from a thread's event log",
+		Usage:       compactUsage,
+		Runner:      commands.RunCompact,
+		Mutates:     true,
+	})
+	registerCommand(CommandInfo{
+		Name:        "tags",
+		Description: "List tags with task counts",
+		Usage:       tagsUsage,
+		Runner:      commands.RunTags,
+	})
+	registerCommand(CommandInfo{
+		Name:        "tag",
+		Description: "Rename a tag across all tasks",
+		Usage:       tagUsage,
+		Runner:      commands.RunTag,
+		Mutates:     true,
+	})
+	registerCommand(CommandInfo{
+		Name:        "projects",
+		Description: "List projects with task counts",
+		Usage:       projectsUsage,
+		Runner:      commands.RunProjects,
+	})
+	registerCommand(CommandInfo{
+		Name:        "project",
+		Description: "Rename a project across all tasks",
+		Usage:       projectUsage,
+		Runner:      commands.RunProject,
+		Mutates:     true,
+	})
+	registerCommand(CommandInfo{
+		Name:        "stats",
+		Description: "Count tasks by project, tag, status, or week",
+		Usage:       statsUsage,
+		Runner:      commands.RunStats,
+	})
+	registerCommand(CommandInfo{
+		Name:        "export",
+		Description: "Export tasks (markdown, ics)",
+		Usage:       exportUsage,
+		Runner:      commands.RunExport,
+	})
+	registerCommand(CommandInfo{
+		Name:        "import",
+		Description: "Import tasks (json)",
+		Usage:       importUsage,
+		Runner:      commands.RunImport,
+		Mutates:     true,
+	})
+	registerCommand(CommandInfo{
+		Name:        "backup",
+		Description: "Write the workspace to a tar.gz file",
+		Usage:       backupUsage,
+		Runner:      commands.RunBackup,
+	})
+	registerCommand(CommandInfo{
+		Name:        "restore",
+		Description: "Restore the workspace from a backup",
+		Usage:       restoreUsage,
+		Runner:      commands.RunRestore,
+		Mutates:     true,
+	})
+	registerCommand(CommandInfo{
+		Name:        "serve",
+		Description: "Serve commands over a unix socket or a read-only HTTP API",
+		Usage:       serveUsage,
+		Runner:      runServe,
+	})
+	registerCommand(CommandInfo{
+		Name:        "tui",
+		Description: "Work through tasks in an interactive session",
+		Usage:       tuiUsage,
+		Runner:      commands.RunTUI,
+	})
+	registerCommand(CommandInfo{
+		Name:        "sync",
+		Description: "Pull and push the workspace's git repository",
+		Usage:       syncUsage,
+		Runner:      commands.RunSync,
+	})
 }
 
 type Config struct {
@@ -157,6 +409,7 @@ type Config struct {
 
 	Verbose bool
 	Debug   bool
+	Quiet   bool
 }
 
 func Run(argv []string, cfg Config) int {
@@ -178,9 +431,11 @@ func Run(argv []string, cfg Config) int {
 	global.SetOutput(cfg.Err)
 
 	var (
-		flgHelp    bool
-		flgVersion bool
-		flgPath    string
+		flgHelp      bool
+		flgVersion   bool
+		flgPath      string
+		flgWorkspace string
+		flgNow       string
 	)
 	global.BoolVar(&flgHelp, "h", false, "show help")
 	global.BoolVar(&flgHelp, "help", false, "show help")
@@ -188,7 +443,13 @@ func Run(argv []string, cfg Config) int {
 	global.BoolVar(&cfg.Verbose, "v", false, "verbose output")
 	global.BoolVar(&cfg.Verbose, "verbose", false, "verbose output")
 	global.BoolVar(&cfg.Debug, "debug", false, "debug output")
+	global.BoolVar(&cfg.Quiet, "q", false, "suppress success messages")
+	global.BoolVar(&cfg.Quiet, "quiet", false, "suppress success messages")
 	global.StringVar(&flgPath, "path", "", "custom workspace path")
+	global.StringVar(&flgWorkspace, "w", "", "workspace directory (overrides --path)")
+	global.StringVar(&flgWorkspace, "workspace", "", "workspace directory (overrides --path)")
+	// Hidden: fixes the clock for reproducible scripts and demos
+	global.StringVar(&flgNow, "now", "", "fixed current time (RFC3339, requires --debug)")
 
 	global.Usage = func() { _, _ = fmt.Fprintln(cfg.Err, usage(cfg.AppName)) }
 
@@ -203,25 +464,49 @@ func Run(argv []string, cfg Config) int {
 		return 0
 	}
 
+	clock, err := resolveClock(flgNow, os.Getenv(nowEnvVar), cfg.Debug)
+	if err != nil {
+		_, _ = fmt.Fprintf(cfg.Err, "Error: %v\n", err)
+		return 2
+	}
+	if clock != nil {
+		_, _ = fmt.Fprintf(cfg.Err, "Warning: clock fixed at %s; timestamps are not real\n", clock.Now().Format(time.RFC3339))
+	}
+
 	rest := global.Args()
 	if flgHelp {
 		_, _ = fmt.Fprintln(cfg.Err, usage(cfg.AppName))
 		return 0
 	}
 
+	// A malformed config must never brick core commands: every loader falls
+	// back to defaults, and we warn about it once here.
+	configErr := config.CheckConfig()
+	if configErr != nil {
+		_, _ = fmt.Fprintf(cfg.Err, "Warning: %v (using defaults)\n", configErr)
+	}
+
 	// If no command provided, check if workspace exists
 	// If it exists, default to 'list'. Otherwise show usage.
 	if len(rest) == 0 {
-		paths, err := config.GetPaths("")
+		workspace := flgWorkspace
+		if workspace == "" {
+			workspace = flgPath
+		}
+		paths, err := config.GetPaths(workspace)
 		if err == nil {
 			// Check if threads directory exists
 			if _, err := os.Stat(paths.ThreadsDir); err == nil {
 				// Workspace exists, run list command
 				return commands.RunList([]string{}, commands.CommandContext{
-					AppName: cfg.AppName,
-					Out:     cfg.Out,
-					Err:     cfg.Err,
-					Path:    flgPath,
+					AppName:       cfg.AppName,
+					Out:           cfg.Out,
+					Err:           cfg.Err,
+					Path:          flgPath,
+					Clock:         clock,
+					WorkspacePath: flgWorkspace,
+					Quiet:         cfg.Quiet,
+					Verbose:       cfg.Verbose || cfg.Debug,
 				})
 			}
 		}
@@ -236,8 +521,9 @@ func Run(argv []string, cfg Config) int {
 	// Load aliases from config
 	rawAliases, err := config.LoadAliases()
 	if err != nil {
-		// Log warning but continue (don't fail on malformed config)
-		if cfg.Verbose || cfg.Debug {
+		// Log warning but continue (don't fail on malformed config,
+		// which was already reported above)
+		if configErr == nil && (cfg.Verbose || cfg.Debug) {
 			_, _ = fmt.Fprintf(cfg.Err, "Warning: failed to load aliases: %v\n", err)
 		}
 		rawAliases = make(config.Aliases)
@@ -249,8 +535,11 @@ func Run(argv []string, cfg Config) int {
 	// Resolve alias: built-in commands take precedence
 	if getCommand(cmd) == nil {
 		if target, ok := aliases[cmd]; ok {
-			// Alias is already validated, so target is guaranteed to be a built-in
-			cmd = target
+			// Alias is already validated, so the first word is guaranteed
+			// to be a built-in; its arguments come before the user's
+			words, _ := splitAliasTarget(target)
+			cmd = words[0]
+			args = append(append([]string{}, words[1:]...), args...)
 		}
 	}
 
@@ -272,12 +561,46 @@ func Run(argv []string, cfg Config) int {
 		return 2
 	}
 
-	return info.Runner(args, commands.CommandContext{
-		AppName: cfg.AppName,
-		Out:     cfg.Out,
-		Err:     cfg.Err,
-		Path:    flgPath,
-	})
+	ctx := commands.CommandContext{
+		AppName:       cfg.AppName,
+		Out:           cfg.Out,
+		Err:           cfg.Err,
+		Path:          flgPath,
+		Clock:         clock,
+		WorkspacePath: flgWorkspace,
+		Quiet:         cfg.Quiet,
+		Verbose:       cfg.Verbose || cfg.Debug,
+	}
+	code := info.Runner(args, ctx)
+	if code == 0 && info.Mutates {
+		commands.AutoCommit(ctx, cmd, args)
+	}
+	return code
+}
+
+// nowEnvVar is the environment equivalent of the hidden --now flag.
+const nowEnvVar = "TK_NOW"
+
+// resolveClock returns a fixed clock when --now or TK_NOW is set (the flag
+// wins), or nil for the system clock. Because a fixed clock stamps every
+// created and updated time, it is only honored together with --debug.
+func resolveClock(flagValue, envValue string, debug bool) (date.Clock, error) {
+	value, source := flagValue, "--now"
+	if value == "" {
+		value, source = envValue, nowEnvVar
+	}
+	if value == "" {
+		return nil, nil
+	}
+	if !debug {
+		return nil, fmt.Errorf("%s requires --debug (it fixes the clock for all timestamps)", source)
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s value %q (expected RFC3339, e.g. 2026-01-05T09:00:00Z)", source, value)
+	}
+	return date.FixedClock{FixedTime: t}, nil
 }
 
 func usage(app string) string {
@@ -285,7 +608,7 @@ func usage(app string) string {
 
 	// Preserve specific ordering: init first, help last, others in registration order
 	// Build ordered list manually to maintain desired output
-	orderedNames := []string{"init", "add", "list", "show", "describe", "update", "done", "archive", "reopen", "remove", "reindex", "path", "attach", "open"}
+	orderedNames := []string{"init", "add", "list", "search", "grep", "count", "agenda", "today", "notify", "next", "recent", "show", "log", "describe", "update", "retag", "snooze", "start", "stop", "done", "archive", "reopen", "remove", "trash", "undo", "reindex", "rebucket", "migrate", "migrate-blobs", "doctor", "path", "config", "alias", "attach", "attachments", "check-links", "open", "mv-att", "compact", "tags", "tag", "projects", "project", "stats", "export", "import", "backup", "restore", "serve", "tui", "sync"}
 
 	var cmdLines []string
 	seen := make(map[string]bool)
@@ -317,15 +640,37 @@ Global flags:
   -h, --help           show help
       --version        print version and exit
   -v, --verbose        verbose output
+  -q, --quiet          suppress success messages (errors still print)
       --debug          debug output
+  -w, --workspace <dir>
+                       workspace directory for this run (wins over --path
+                       and THREADKEEPER_WORKSPACE)
       --path <dir>     custom workspace path
 
 Commands:
 %s
 
+Aliases:
+  Define shortcuts in the [alias] section of config.toml. A target is a
+  command, optionally followed by arguments that are placed before the
+  ones you type (quote words containing spaces as in a shell):
+
+    [alias]
+    ls = "list"
+    urgent = "list --tag urgent"
+
+  '%s urgent -p home' then runs '%s list --tag urgent -p home'. Aliases
+  can't shadow built-in commands or point to other aliases. '%s alias'
+  lists, adds and removes them.
+
+Config:
+  Settings are read from $XDG_CONFIG_HOME/threadkeeper/config.toml
+  (~/.config/threadkeeper/config.toml if XDG_CONFIG_HOME is unset), or
+  from the file named by THREADKEEPER_CONFIG when that is set.
+
 Run:
   %s help <command>
-`, app, app, strings.Join(cmdLines, "\n"), app)
+`, app, app, strings.Join(cmdLines, "\n"), app, app, app, app)
 }
 
 // Usage functions extracted from commandUsage() switch
@@ -342,14 +687,29 @@ Flags:
 func addUsage(app string) string {
 	return fmt.Sprintf(`Usage:
   %s add <title> [flags]
+  %s add --stdin [flags]
+  %s add - [flags]
 
 Flags:
   -d, --description <t>  description
   -p, --project <name>   project name
   --due <date>           due date (format depends on date_locale config)
-  --tag <tag>            repeatable
+                         (a date before today is kept, with a warning)
+  --tag <tag>            repeatable (merged with default_tags config)
+  --estimate <dur>       expected effort, e.g. 45m, 2h, 1h30m
+  --edit                 write the description in $EDITOR (starting from
+                         -d, if given); an empty file means no description
+  --stdin                read the title from the first non-empty line of
+                         standard input and the description from the rest;
+                         -d, if given, replaces the piped description
+  --force                add even if an open task has the same title
+                         (otherwise asks on a terminal, or fails)
+
+Tags are lowercased (key:value tags keep the value's case), a leading "#"
+is dropped, and inner spaces become hyphens: --tag "#Needs Review" is
+needs-review. A tag with nothing left, like "#", is an error.
 
-`, app)
+`, app, app, app)
 }
 
 func listUsage(app string) string {
@@ -361,7 +721,101 @@ Flags:
   -p, --project <name>        filter by project
   --status <open|done|archived> filter by status
   -n, --limit <n>             limit number of tasks
-  --tag <tag>                 filter by tag (normalized)
+  --offset <n>                skip the first n matching tasks; with
+                              --limit, pages through the list
+  --tag <tag>                 filter by tag (normalized); repeat to
+                              require all of the given tags
+  --any-tag <a,b>             require at least one of the listed tags
+                              (comma-separated, repeatable)
+  --not-tag <tag>             exclude tasks with this tag (repeatable,
+                              commas allowed)
+  --tag-key <key>             filter by key of a key:value tag (e.g. sprint)
+  --tag-val <key:value>       filter by key:value tag (e.g. sprint:42)
+  --flag-dups                 mark open tasks whose title duplicates another
+  --format <tmpl>             print each task with a Go text/template over
+                              the task, or a preset: oneline, detailed
+  --no-pager                  don't pipe output through the pager
+  --overdue                   only open tasks due before today
+  --due-today                 only tasks due today
+  --due-before <date>         only tasks due on or before date
+  --due-after <date>          only tasks due on or after date
+  --created-before <date>     only tasks created on or before date
+  --created-after <date>      only tasks created on or after date
+  --group-by <field>          print tasks under a header per project, tag
+                              or status
+  --relative                  after each open task's due date, say how
+                              far off it is (due in 3 days, overdue 2 days)
+  --summary                   end with "N open, M overdue, K due this
+                              week" for the whole workspace
+
+Tag filters combine with AND: a task is listed only if it has every
+--tag, at least one --any-tag, and no --not-tag. --not-tag wins when a
+tag appears in both an inclusion flag and --not-tag.
+
+"Today" for --overdue, --due-today and --relative uses the timezone
+config key.
+Range dates accept the same input as --due on add (e.g. today, eow, +7).
+
+A [list] section in config.toml sets defaults for flags not given on the
+command line: show_all (--all), relative_dates (--relative), group_by
+(--group-by, ignored with --format) and limit (--limit). Override a
+boolean default with e.g. --all=false.
+
+--group-by tag lists a task under each of its tags. Tasks without a
+project or tag are listed last, under "(no project)" or "(no tag)".
+
+On a terminal, output longer than the screen goes through the pager
+config key or $PAGER, if either is set.
+
+--format templates see the task's fields (.ID, .ShortID, .Title,
+.Description, .Status, .Project, .Tags, .DueAt, .CreatedAt, ...) and the
+helpers short, due and tags, which print a short ID, a due date in the
+date_format layout, and comma-joined tags; each prints nothing when
+unset. "No tasks found." is not printed when --format is set.
+  %s list --format '{{short .ShortID}} {{.Title}} {{.Project}}'
+  %s list --format '{{.ID}} {{due .DueAt}} {{tags .Tags}}'
+
+`, app, app, app)
+}
+
+func snoozeUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s snooze <id> [<id> ...] <date>
+
+Moves each task's due date to <date>. Shortcuts count from the task's
+current due date, or from today if it has none or is overdue: +3 pushes a
+task due Friday to Monday. Absolute dates set the due date as given.
+
+Date shortcuts:
+  +N                  N days later
+  tomorrow            the next day
+  monday, fri, ...    the next such weekday
+  eow                 the coming Sunday
+
+Examples:
+  %s snooze 3 +7
+  %s snooze 3 5 monday
+
+`, app, app, app)
+}
+
+func startUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s start <id>
+
+Starts a timer on an open task. '%s stop' adds the elapsed time to the
+task's time spent; done and archive stop a running timer too. 'show
+--full' and 'stats' report the totals.
+
+`, app, app)
+}
+
+func stopUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s stop <id>
+
+Stops the task's running timer and adds the elapsed time, to the second,
+to its time spent.
 
 `, app)
 }
@@ -369,25 +823,54 @@ Flags:
 func doneUsage(app string) string {
 	return fmt.Sprintf(`Usage:
   %s done <id> [<id> ...]
+  %s done [flags]
 
-`, app)
+Instead of IDs, select tasks with the same filters as list. Tasks that are
+already done are skipped. Changing more than 5 tasks this way asks for
+confirmation on a terminal and requires --force elsewhere.
+
+Flags:
+  -a, --all                     select tasks of any status (default: only open)
+  -p, --project <name>          select by project
+  --status <open|done|archived> select by status
+  --tag <tag>                   select by tag (repeat to AND tags)
+  --force                       don't ask for confirmation
+
+`, app, app)
 }
 
 func removeUsage(app string) string {
 	return fmt.Sprintf(`Usage:
-  %s remove --force <id> [<id> ...]
+  %s remove [--force] [--permanent] <id> [<id> ...]
+
+Moves each task's thread directory, attachments included, into the trash.
+Use '%s trash restore <id>' to bring it back. On a terminal remove asks
+for confirmation first; elsewhere it requires --force.
 
 Flags:
-  --force   actually delete (required)
+  --force       don't ask for confirmation
+  --permanent   delete permanently instead (undo recreates only thread.json)
 
-`, app)
+`, app, app)
 }
 
 func archiveUsage(app string) string {
 	return fmt.Sprintf(`Usage:
   %s archive <id> [<id> ...]
+  %s archive [flags]
 
-`, app)
+Instead of IDs, select tasks with the same filters as list. Tasks that are
+already archived are skipped. Changing more than 5 tasks this way asks for
+confirmation on a terminal and requires --force elsewhere.
+
+Flags:
+  -a, --all                     select tasks of any status (default: only open)
+  -p, --project <name>          select by project
+  --status <open|done|archived> select by status
+  --tag <tag>                   select by tag (repeat to AND tags)
+  --force                       don't ask for confirmation
+
+`, app, app)
 }
 
 func reopenUsage(app string) string {
@@ -399,29 +882,267 @@ Reopen one or more tasks, changing their status from inactive (archived or done)
 `, app)
 }
 
+func trashUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s trash list
+  %s trash restore <id> [<id> ...]
+  %s trash empty [--yes]
+
+Removed tasks are kept in .trash/ under the workspace until the trash is
+emptied. restore takes a durable ID or a unique prefix of one, as shown
+by 'trash list'.
+
+Flags:
+  -y, --yes      empty without asking (prompts only on a terminal)
+
+`, app, app, app)
+}
+
+func undoUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s undo [--force]
+
+Reverses the most recent done, archive, update, snooze or remove --permanent by
+restoring the affected tasks from %s. Only the last operation can be
+undone. Undoing a remove recreates thread.json but not the thread's
+attachments; trashed tasks come back with 'trash restore' instead.
+
+Flags:
+  --force        undo even if a task was changed after the operation
+
+`, app, "ops.jsonl")
+}
+
 func reindexUsage(app string) string {
 	return fmt.Sprintf(`Usage:
-  %s reindex
+  %s reindex [--yes] [--dry-run] [--stable]
+  %s reindex --rebuild-cache
+
+Reassigns short IDs 1..N to open tasks. On a terminal, asks for
+confirmation first when any short ID would change.
+
+By default every open task is renumbered in creation order, so the
+numbers are compact but one task added out of order can shift all of
+them. --stable keeps each task's short ID unless another task holds the
+same number, and gives the lowest free numbers to tasks without one:
+fewer IDs change, but gaps left by closed tasks stay.
+
+Set auto_reindex = true in config.toml to renumber open tasks 1..N
+automatically after done, archive and reopen.
+
+Flags:
+  -y, --yes          renumber without asking
+  --dry-run          show what would be renumbered without saving
+  --stable           keep existing short IDs and only fill in missing ones
+  --rebuild-cache    rebuild the task index (.index.json) instead of renumbering
+
+`, app, app)
+}
+
+func logUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s log <id>
+
+Prints a timeline of one thread, oldest first: when it was created, each
+attachment added or removed, every recorded operation on it (done,
+update, undo, ...) and when it was completed and last updated.
 
 `, app)
 }
 
 func describeUsage(app string) string {
 	return fmt.Sprintf(`Usage:
-  %s describe <id>
+  %s describe [-m <text>]... <id>
+
+Opens an editor on the task description: $TK_EDITOR, $VISUAL or $EDITOR,
+whichever is set first, else the editor config key, else vi. Saving an
+empty file leaves the description unchanged. GUI editors such as code and
+subl get their wait flag if it is missing; TK_EDITOR="code --wait" sets it
+explicitly.
+
+Flags:
+  -m, --message <text>  set the description without opening an editor;
+                        repeat to add paragraphs separated by blank lines
+
+`, app)
+}
+
+func searchUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s search [flags] <query>
+
+Finds tasks whose title or description contains the query
+(case-insensitive). Searches open tasks unless --all is given.
+
+Flags:
+  -a, --all      search all tasks (default: only open)
+  --regex        treat the query as a Go regular expression
+                 (case-sensitive; prefix with (?i) to ignore case)
+  --json         print matching tasks as JSON
+  --since <when>  only tasks updated since a date, or within a window
+                  such as 7d, 2w or 12h
+  --until <when>  only tasks updated before the end of a date, or before
+                  the start of a window
+
+Dates accept the same input as --due on add (e.g. today, 2026-03-01), in
+the timezone config key. 7d means since midnight six days ago, as
+recent --days 7 does.
+
+`, app)
+}
+
+func grepUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s grep [flags] <pattern>
+
+Searches the contents of note attachments and prints matching lines as
+<thread-id>:<att-id>:<line>:<text>. Matching is a case-insensitive
+literal by default. Link attachments are skipped.
+
+Flags:
+  --id <id>       only search notes on this thread
+  --regex         treat the pattern as a Go regular expression
+  --since <when>  only threads updated since a date, or within a window
+                  such as 7d, 2w or 12h
+  --until <when>  only threads updated before the end of a date, or before
+                  the start of a window
+
+Dates accept the same input as --due on add (e.g. today, 2026-03-01), in
+the timezone config key.
+
+`, app)
+}
+
+func agendaUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s agenda [--days <n>] [--ics]
+
+Lists open tasks due within the next n days, grouped under Overdue, Today,
+Tomorrow, and then each later date. Days are computed in the timezone config
+key. Tasks without a due date are left out.
+
+With --ics the same tasks are printed as an iCalendar feed of all-day
+events on their due dates, to import just the near-term schedule. Use
+'%s export ics' for every task as to-dos.
+
+Flags:
+  --days <n>    how many days ahead to include (default 7; 0 means today)
+  --ics         print iCalendar (VEVENT) instead of the grouped list
+
+Example:
+  %s agenda --days 14 --ics > agenda.ics
+
+`, app, app, app)
+}
+
+func todayUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s today
+
+Lists open tasks that are overdue or due today. Same as '%s agenda --days 0'.
+
+`, app, app)
+}
+
+func notifyUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s notify [--days <n>]
+
+Prints one line per open task that is overdue or due within the next n
+days, soonest first, for piping into notify-send, osascript or mail:
+
+  <id>: <title> (<due>)
+
+such as "3: Renew passport (due tomorrow)" or "7: File taxes (overdue 2
+days)". <id> is the short ID, or the full ID if the task has none. Days
+are computed in the timezone config key. Prints nothing and exits 0 when
+nothing is due.
+
+Flags:
+  --days <n>    how many days ahead to include (default 1; 0 means today)
+
+Example crontab entry:
+  0 9 * * * %s notify | xargs -r -d '\n' -n1 notify-send "Due"
+
+`, app, app)
+}
+
+func nextUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s next [-p <project>] [--tag <tag>]...
+
+Shows the single most urgent open task, in the same format as show:
+overdue tasks first, then the soonest due, then the oldest task without
+a due date.
+
+Flags:
+  -p, --project <name>   only consider tasks in this project
+  --tag <tag>            only consider tasks with this tag (repeat to AND tags)
+
+`, app)
+}
+
+func countUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s count [flags]
+
+Prints the number of tasks matching the same filters as list.
+
+Flags:
+  -a, --all                   count all tasks (default: only open)
+  -p, --project <name>        filter by project
+  --status <open|done|archived> filter by status
+  --tag <tag>                 filter by tag (repeat to AND tags)
+  --tag-key <key>             filter by key of a key:value tag
+  --tag-val <key:value>       filter by key:value tag
+
+`, app)
+}
+
+func recentUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s recent [--days <n>] [--status <status>] [-p <project>]
+
+Lists tasks created or updated in the last <n> days (default 7, counting
+today), most recently touched first. Days start at midnight in the
+timezone config key. Covers tasks of every status unless --status is
+given.
+
+Flags:
+  --days <n>                    days to look back, counting today
+  --status <open|done|archived> only tasks with this status
+  -p, --project <name>          only tasks in this project
 
 `, app)
 }
 
 func showUsage(app string) string {
 	return fmt.Sprintf(`Usage:
-  %s show [--full] <id>
+  %s show [--full] [--no-pager] [--path-only [--no-newline | -0]] <id>
+  %s show --note <att> [--raw] [--no-pager] <id>
 
 Flags:
   --full         show full metadata and history
   --all          show full metadata (deprecated, use --full)
+  --no-pager     don't pipe output through the pager
+  --path-only    print only the thread directory path
+  --no-newline   with --path-only, omit the trailing newline
+  -0             with --path-only, terminate with a NUL byte
+  --note <att>   print a note attachment instead, by index (as listed by
+                 attachments) or attachment ID
+  --raw          with --note, print the note's bytes without styling
 
-`, app)
+An open task's due date is followed by how far off it is, e.g. "(due in
+3 days)" or "(overdue 2 days)", using the timezone config key.
+
+On a terminal, output longer than the screen goes through the pager
+config key or $PAGER, if either is set.
+
+With --note, a Markdown note printed to a terminal is styled: headings,
+bold, lists and code blocks. Set render_markdown = false in the config to
+turn this off. Output to a pipe or file is always the raw note.
+
+`, app, app)
 }
 
 func updateUsage(app string) string {
@@ -430,28 +1151,340 @@ func updateUsage(app string) string {
 
 Flags:
   --title <t>           set new title
+  -d, --description <t> set description ("" clears it)
+  --append-description <t>
+                        append a paragraph to the description
   --due <date>          set due date (format depends on date_locale config)
+                        (a date before today is kept, with a warning)
   --project <name>      set project name
+  --estimate <dur>      set expected effort, e.g. 45m, 2h, 1h30m
   --add-tag <tag>       repeatable
   --remove-tag <tag>    repeatable
+  --clear-due           remove the due date
+  --clear-project       remove the project
+  --clear-tags          remove all tags
+  --clear-estimate      remove the estimate
+
+`, app)
+}
+
+func attachmentsUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s attachments --id <thread-id> [--json]
+
+Lists a thread's current attachments, notes and links, without the rest
+of show's output. Removed and moved attachments are not listed. The #
+column is the index open and mv-att --att accept.
+
+Flags:
+  --id <id>   thread to list
+  --json      print [{"att_id": ..., "kind": ..., "name": ..., "url": ...,
+              "created": ...}] as JSON
+
+`, app)
+}
+
+func checkLinksUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s check-links [--id <thread-id>] [--timeout <duration>]
+
+Requests every current http and https link attachment and prints one line
+per link as <thread-id>:<att-id>  <status>  ok|broken  <url>. Links
+answering 4xx or 5xx, timing out or failing to connect are broken. Each
+link is tried with HEAD first, then GET if HEAD fails. Other schemes
+(mailto, file) are skipped.
+
+Exits 1 if any link is broken, so it can run from cron.
+
+Flags:
+  --id <id>             only check links on this thread
+  --timeout <duration>  how long to wait for each link, such as 5s
+                        (default: link_check_timeout config key, or 10s)
+
+`, app)
+}
+
+func compactUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s compact --id <thread-id>
+
+Rewrites the thread's attachments.jsonl keeping only the add events of
+attachments that are still current, so removed and moved attachments no
+longer have to be parsed. Timestamps and attachment IDs are preserved.
+Does nothing when the log has no dead entries. Blobs are left in place.
+
+Flags:
+  --id <id>   thread to compact
+
+`, app)
+}
+
+func mvAttUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s mv-att --from <thread-id> (--att <index> | --att-id <id>) --to <thread-id>
+
+Moves an attachment to another thread, keeping its name, media type, and
+attachment ID. Note content is copied into the destination's blob store.
+
+Flags:
+  --from <id>       thread that currently has the attachment
+  --to <id>         thread to move it to
+  --att <index>     attachment index (1-based, from 'show' output)
+  --att-id <id>     attachment ID (alternative to --att)
+
+`, app)
+}
+
+func tagsUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s tags [--open] [--json]
+
+Lists every tag with the number of tasks using it, most used first.
+
+Flags:
+  --open         only count open tasks (default: all tasks)
+  --json         print [{"tag": ..., "count": ...}] as JSON
+
+`, app)
+}
+
+func retagUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s retag [filter flags] [--add-tag <tag>]... [--remove-tag <tag>]...
+           [--set-project <name> | --clear-project]
+
+Changes the tags and project of every task the filters select, using the
+same filters as list. Either every selected task is saved or, if a save
+fails, none is. Changing more than %d tasks asks for confirmation on a
+terminal and requires --force elsewhere. Use update to change tasks by ID.
+
+Filter flags (at least one is required):
+  -a, --all                     select tasks of any status (default: only open)
+  -p, --project <name>          select by project
+  --status <open|done|archived> select by status
+  --tag <tag>                   select by tag (repeat to AND tags)
+
+Flags:
+  --add-tag <tag>        add a tag (repeatable)
+  --remove-tag <tag>     remove a tag (repeatable)
+  --set-project <name>   move the tasks to project <name>
+  --clear-project        remove the project (not with --set-project)
+  --force                don't ask for confirmation
+
+Examples:
+  %s retag --project old --set-project new
+  %s retag --tag urgent --add-tag q3 --remove-tag urgent
+  %s retag --all --project infra --add-tag ops
+
+`, app, commands.BulkConfirmThreshold, app, app, app)
+}
+
+func tagUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s tag rename [--dry-run] <old> <new>
+
+Replaces tag <old> with <new> on every task that has it, merging with
+<new> where a task already has both. Tags are normalized first.
+
+Flags:
+  --dry-run      report how many tasks would change without saving
+
+`, app)
+}
+
+func projectsUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s projects [--open] [--json]
+
+Lists every non-empty project with the number of tasks in it, sorted by
+name. Project names are case-sensitive: "Work" and "work" are distinct.
+
+Flags:
+  --open         only count open tasks (default: all tasks)
+  --json         print [{"project": ..., "count": ...}] as JSON
+
+`, app)
+}
+
+func projectUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s project rename [--dry-run] <old> <new>
+
+Sets the project to <new> on every task whose project is <old>. Projects
+are matched by exact, case-sensitive string comparison (unlike tags, they
+are not lowercased), so "Work" does not match "work".
+
+Flags:
+  --dry-run      report how many tasks would change without saving
+
+`, app)
+}
+
+func statsUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s stats [flags]
+
+Counts tasks along one dimension and prints a histogram. Covers tasks of
+every status unless --status is given.
+
+Flags:
+  --count-by <dim>            project, tag, status (default), or week
+                              (ISO week of last update)
+  -p, --project <name>        filter by project
+  --status <open|done|archived> filter by status
+  --tag <tag>                 filter by tag (repeat to AND tags)
+  --json                      print [{"label": ..., "count": ...}] as JSON
+  --summary                   add "N open, M overdue, K due this week" for
+                              the counted tasks; with --json, print
+                              {"buckets": [...], "summary": {"open": ...,
+                              "overdue": ..., "due_this_week": ...}}
+
+Text output ends with the total time spent on the counted tasks (see
+'%s start'), if any.
+
+`, app, app)
+}
+
+func exportUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s export markdown [flags]
+  %s export ics [--all]
+
+Formats:
+  markdown   checklist grouped by project, with due dates, #tags, and
+             attachments as nested bullets
+  ics        iCalendar feed with one VTODO per task that has a due date
+
+Output goes to stdout.
+
+Flags:
+  -a, --all                   export all tasks (default: only open)
+  -p, --project <name>        filter by project (markdown only)
+
+`, app, app)
+}
+
+func importUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s import json [--path <file>] [--merge]
+
+Reads a JSON array of tasks (the thread.json schema) from --path or stdin
+and saves each one. Tasks without an id get a new one.
+
+Flags:
+  --path <file>   read from file instead of stdin
+  --merge         skip tasks whose ID already exists (default: overwrite)
+
+`, app)
+}
+
+func doctorUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s doctor [--fix]
+
+Checks workspace health and exits 1 if any problem is found:
+  corrupt-thread      thread.json that cannot be read or parsed
+  duplicate-short-id  open tasks sharing a short_id (fix with reindex)
+  missing-short-id    open tasks without a short_id            [fixable]
+  missing-blob        note attachments whose blob is missing
+  orphan-blob         blobs no attachment refers to
+  misplaced-thread    thread directories in the wrong bucket   [fixable]
+
+Flags:
+  --fix          repair the fixable problems, then report what remains
+
+`, app)
+}
+
+func rebucketUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s rebucket
+
+Moves thread directories into buckets matching the bucket_width config
+(default 2). Run this after changing bucket_width on an existing workspace;
+until then threads in old buckets cannot be found by ID.
+
+`, app)
+}
+
+func migrateBlobsUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s migrate-blobs
+
+Moves note blobs from each thread's own blobs directory into the shared
+store at <workspace>/blobs, so identical content is kept once. Attachment
+logs are not rewritten: they refer to blobs by hash, which doesn't change.
+Blobs of trashed threads stay with them.
 
 `, app)
 }
 
 func pathUsage(app string) string {
 	return fmt.Sprintf(`Usage:
-  %s path <thread-id>
+  %s path [--no-newline | -0 | --reveal | --json] <thread-id>
 
 Prints the canonical filesystem path for the thread directory.
 Accepts either a durable thread ID or a short ID.
 
+Flags:
+  --no-newline   omit the trailing newline (for "$(...)")
+  -0             terminate with a NUL byte instead (for xargs -0)
+  --reveal       open the directory in the OS file manager instead of
+                 printing it (prints it with a warning where unsupported)
+  --json         print the durable ID, short ID, directory, thread.json
+                 and attachments.jsonl paths as JSON
+
 `, app)
 }
 
+func configUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s config get [<key>]
+  %s config set <key> <value>
+
+Reads and writes config.toml. get prints the value in use for a key, with
+defaults filled in; without a key it prints every known key as
+"key = value". set checks the value and writes it, keeping other keys
+and tables such as [alias]. Comments in config.toml are not kept.
+
+Keys:
+  %s
+
+default_tags takes a comma-separated list. Keys of the [list] section are
+written list.<key>, such as list.limit.
+
+Examples:
+  %s config set date_locale us
+  %s config set timezone Europe/Berlin
+  %s config get default_workspace
+
+`, app, app, strings.Join(config.SettingKeys(), "\n  "), app, app, app)
+}
+
+func aliasUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s alias list
+  %s alias add <name> <target>
+  %s alias remove <name>
+
+Manages the [alias] table of config.toml. list shows the aliases in use,
+then any that are ignored and why. add checks that the name isn't a
+built-in command and that the target starts with one (an alias can't
+point to another alias), then writes it. Other config keys are kept.
+
+A target with arguments can be quoted or given as separate words:
+
+  %s alias add urgent "list --tag urgent"
+  %s alias add ls list --all
+
+`, app, app, app, app, app)
+}
+
 func attachUsage(app string) string {
 	return fmt.Sprintf(`Usage:
-  %s attach note --id <thread-id>
-  %s attach link --id <thread-id> --url <url> [--label <label>]
+  %s attach note --id <thread-id> [--name <name>] [--message <text> | --file <path> | --stdin]
+  %s attach link --id <thread-id> --url <url> [--label <label> | --fetch-title]
+  %s attach export --id <thread-id> (--att <index> | --att-id <id>) --out <file> [--url-ok]
 
 Attach context to a thread.
 
@@ -459,27 +1492,61 @@ Types:
   note   Open editor, store content-addressed blob, record in attachments.jsonl.
   link   Record URL (and optional label) in attachments.jsonl.
 
+Export:
+  Copies a note's content out of the blob store unchanged. --out - writes
+  it to stdout. A link has nothing to export; with --url-ok its URL is
+  printed instead of failing.
+
 Flags:
   --id <id>       thread handle or canonical id
-  --url <url>     URL to attach [link only]
+  --url <url>     URL to attach: http, https, mailto or file [link only]
   --label <text>  label for link (pr, slack, jira, doc, etc.) [link only]
+  --fetch-title   without --label, name the link after the page's <title>
+                  (default: attach_fetch_title config key) [link only]
+  --name <name>   attachment name (default: note-YYYYMMDD-HHMMSS) [note only]
+  --message <t>   use <t> as the note content instead of opening an editor [note only]
+  --file <path>   read the note content from a file [note only]
+  --stdin         read the note content from standard input [note only]
+  --att <index>   attachment index (1-based, from 'attachments' output) [export only]
+  --att-id <id>   attachment ID (alternative to --att) [export only]
+  --out <file>    file to write the note to, or - for stdout [export only]
+  --url-ok        print a link's URL instead of failing [export only]
+
+--message, --file and --stdin are mutually exclusive. Empty note content
+cancels the attachment, however it was provided.
+
+Notes larger than the max_attachment_bytes config key (default 10 MiB, 0
+for no limit) are refused before anything is stored.
+
+--fetch-title makes one request to the link, waiting at most 5s. If it
+fails, the link is named after its host as usual.
 
 Environment variables:
-  TK_EDITOR       editor to use (defaults to $EDITOR, then vi) [note only]
-  EDITOR          editor to use (if TK_EDITOR not set) [note only]
+  TK_EDITOR       editor to use [note only]
+  VISUAL          editor to use if TK_EDITOR is not set [note only]
+  EDITOR          editor to use if neither is set; then the editor
+                  config key, then vi [note only]
+
+GUI editors must wait until the file is closed, or the note comes back
+empty. tk adds the wait flag for code, subl and similar editors when it is
+missing; setting it yourself is clearer: TK_EDITOR="code --wait".
 
 Examples:
   %s attach note --id 1
+  kubectl logs pod/web | %s attach note --id 1 --stdin
   %s attach link --id 1 --url https://example.com/pr/123 --label pr
+  %s attach export --id 1 --att 2 --out pod.log
 
-`, app, app, app, app)
+`, app, app, app, app, app, app, app)
 }
 
 func openUsage(app string) string {
 	return fmt.Sprintf(`Usage:
   %s open [--att <index> | --att-id <id>] [--print-path] <thread-id>
 
-Open an attachment from a thread.
+Open an attachment from a thread. Image notes (by the media type detected
+when they were attached) open from a temporary copy with the matching file
+extension, so image viewers recognize them.
 
 Flags:
   --att <index>     attachment index (1-based, from 'show' output)
@@ -493,6 +1560,131 @@ Examples:
 `, app, app, app)
 }
 
+func serveUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s serve --socket <path>
+  %s serve --addr <host:port>
+
+With --socket, serve core commands over a local unix socket for editor
+plugins and other long-running clients. Nothing is exposed on the network.
+
+With --addr, serve a read-only HTTP API instead. Responses are JSON:
+  GET /tasks                    tasks; query parameters mirror list flags
+                                (all, project, status, limit, tag, any-tag,
+                                not-tag, tag-key, tag-val, overdue,
+                                due-today, due-before, due-after,
+                                created-before, created-after)
+  GET /tasks/{id}               the task and its current attachments
+  GET /tasks/{id}/notes/{att}   a note's content, by index or att_id,
+                                served with its media type
+
+The HTTP API has no authentication; bind it to localhost (e.g.
+--addr localhost:8080) unless the network is trusted.
+
+Each request is one line of JSON; each reply is one line of JSON:
+  {"id": 1, "method": "add", "args": ["--tag", "x", "Write report"]}
+  {"id": 1, "code": 0, "out": "Added task 3 (...): Write report\n"}
+
+Methods:
+  list, query   run 'list' with args
+  get           run 'show' with args
+  add, update, done, attach
+                run the command of the same name with args
+
+Flags:
+  --socket <path>        unix socket to listen on (must not already exist)
+  --addr <host:port>     address for the read-only HTTP API
+
+`, app, app)
+}
+
+func tuiUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s tui
+
+Starts an interactive session: the task list is shown and redrawn after
+each action, or when another process changes tasks on disk. Type an action
+and press enter; ? lists them. The session reads and saves tasks exactly
+as the other commands do.
+
+Actions (IDs are short IDs or durable IDs):
+  d <id>...       mark done
+  a <id>...       archive
+  r <id>...       reopen
+  e <id>          edit the description in $EDITOR
+  s <id>          show the task
+  o <id> [<n>]    open attachment n (default 1)
+  v               toggle between open tasks and all tasks
+  <enter>         refresh the list
+  q               quit
+
+`, app)
+}
+
+func syncUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s sync
+
+Runs 'git pull --rebase' and then 'git push' in the workspace. The
+workspace must be in a git repository with an upstream configured.
+
+Set git_autocommit = true in config.toml to have commands that change
+tasks commit the workspace after each change.
+
+`, app)
+}
+
+func backupUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s backup --out <file.tar.gz> [--include-trash]
+
+Writes the workspace (threads, blobs, attachment logs and the undo log) to
+a gzip-compressed tarball. Temp files, the index cache, the lock file and
+any .git directory are left out, as is config.toml. Removed tasks in the
+trash are left out unless --include-trash is given.
+
+Flags:
+  --out <file>       tarball to write
+  --include-trash    also back up removed tasks
+
+`, app)
+}
+
+func restoreUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s restore --in <file.tar.gz> [--force]
+
+Extracts a tarball written by '%s backup' into the workspace. Refuses if
+the workspace already has tasks unless --force is given; with --force,
+files in the backup replace the ones in the workspace and other files are
+left alone.
+
+Flags:
+  --in <file>    tarball to restore
+  --force        restore over existing tasks
+
+`, app, app)
+}
+
+func migrateUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s migrate [--dry-run]
+
+Rewrites thread files, including those in the trash, that were written in
+an older schema version so they match the current one. Older files are
+still readable without migrating; this makes the upgrade permanent.
+Files from a newer version of %s are never touched: upgrade %s instead.
+
+Schema versions:
+  1   files without a schema_version field
+  2   done tasks always have completed_at (back-filled from updated_at)
+
+Flags:
+  --dry-run   report how many files would change without writing
+
+`, app, app, app)
+}
+
 func commandUsage(app, cmd string) string {
 	info := getCommand(cmd)
 	if info == nil {
@@ -503,33 +1695,18 @@ func commandUsage(app, cmd string) string {
 
 // validateAliases filters and validates aliases:
 // - Removes aliases that conflict with built-in commands (built-in wins)
-// - Removes aliases that point to non-existent commands
+// - Removes aliases whose target can't be split into words
+// - Removes aliases whose first word is not a built-in command
 // - Removes aliases that point to other aliases (no recursion)
-// Returns a validated map of alias -> built-in command.
+// Returns a validated map of alias -> target. A target is a built-in
+// command optionally followed by arguments, e.g. "list --tag urgent".
 func validateAliases(raw config.Aliases, verbose bool, errOut io.Writer) config.Aliases {
 	valid := make(config.Aliases)
 
 	for alias, target := range raw {
-		// Skip aliases that conflict with built-in commands
-		if getCommand(alias) != nil {
+		if problem := aliasProblem(alias, target, raw); problem != "" {
 			if verbose {
-				_, _ = fmt.Fprintf(errOut, "Warning: alias %q conflicts with built-in command, ignoring\n", alias)
-			}
-			continue
-		}
-
-		// Check if target is a built-in command
-		if getCommand(target) == nil {
-			// Check if target is another alias (recursion)
-			if _, isAlias := raw[target]; isAlias {
-				if verbose {
-					_, _ = fmt.Fprintf(errOut, "Warning: alias %q points to another alias %q (recursion not allowed), ignoring\n", alias, target)
-				}
-				continue
-			}
-			// Target is not a built-in and not an alias - invalid
-			if verbose {
-				_, _ = fmt.Fprintf(errOut, "Warning: alias %q points to non-existent command %q, ignoring\n", alias, target)
+				_, _ = fmt.Fprintf(errOut, "Warning: alias %q %s, ignoring\n", alias, problem)
 			}
 			continue
 		}
@@ -541,6 +1718,35 @@ func validateAliases(raw config.Aliases, verbose bool, errOut io.Writer) config.
 	return valid
 }
 
+// aliasProblem says why alias = target would be ignored given all the raw
+// aliases, such as "conflicts with built-in command", or "" if it is valid.
+func aliasProblem(alias, target string, raw config.Aliases) string {
+	// Built-in commands win over aliases of the same name
+	if getCommand(alias) != nil || alias == "help" {
+		return "conflicts with built-in command"
+	}
+
+	words, err := splitAliasTarget(target)
+	if err != nil || len(words) == 0 {
+		if err == nil {
+			err = fmt.Errorf("empty target")
+		}
+		return fmt.Sprintf("has an invalid target %q (%v)", target, err)
+	}
+	cmd := words[0]
+
+	// Check if target is a built-in command
+	if getCommand(cmd) == nil {
+		// Check if target is another alias (recursion)
+		if _, isAlias := raw[cmd]; isAlias {
+			return fmt.Sprintf("points to another alias %q (recursion not allowed)", cmd)
+		}
+		// Target is not a built-in and not an alias - invalid
+		return fmt.Sprintf("points to non-existent command %q", cmd)
+	}
+	return ""
+}
+
 type stringList []string
 
 func (s *stringList) String() string { return strings.Join(*s, ",") }
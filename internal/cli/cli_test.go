@@ -3,10 +3,15 @@ package cli
 import (
 	"bytes"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/sjatkinson/threadkeeper/internal/config"
+	"github.com/sjatkinson/threadkeeper/internal/store"
 )
 
 func TestGetCommand(t *testing.T) {
@@ -216,11 +221,194 @@ func TestRun_CommandParsing(t *testing.T) {
 	}
 }
 
+func TestRun_ConfigEnvVar(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("THREADKEEPER_WORKSPACE", tmpDir)
+	// An XDG config that THREADKEEPER_CONFIG must win over
+	configHome := filepath.Join(tmpDir, "config")
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	t.Setenv(nowEnvVar, "")
+	if err := os.MkdirAll(filepath.Join(tmpDir, "threads"), 0755); err != nil {
+		t.Fatalf("Failed to create threads dir: %v", err)
+	}
+	cfgDir := filepath.Join(configHome, config.AppDirName)
+	if err := os.MkdirAll(cfgDir, 0755); err != nil {
+		t.Fatalf("Failed to create config dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(cfgDir, "config.toml"), []byte("[alias]\nls = \"list --all\"\n"), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	// "~/" expands to the home directory
+	home := filepath.Join(tmpDir, "home")
+	t.Setenv("HOME", home)
+	if err := os.MkdirAll(home, 0755); err != nil {
+		t.Fatalf("Failed to create home: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(home, "tk.toml"), []byte("[alias]\nls = \"tags\"\n"), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	t.Setenv(config.ConfigEnvVar, "~/tk.toml")
+
+	got, err := config.ConfigPath()
+	if err != nil || got != filepath.Join(home, "tk.toml") {
+		t.Fatalf("ConfigPath() = %q, %v; want %q", got, err, filepath.Join(home, "tk.toml"))
+	}
+
+	var outBuf, errBuf bytes.Buffer
+	if code := Run([]string{"add", "--tag", "from-env", "a task"}, Config{Out: &outBuf, Err: &errBuf}); code != 0 {
+		t.Fatalf("add exit code = %d, stderr: %s", code, errBuf.String())
+	}
+	outBuf.Reset()
+	if code := Run([]string{"ls"}, Config{Out: &outBuf, Err: &errBuf}); code != 0 {
+		t.Fatalf("ls exit code = %d, stderr: %s", code, errBuf.String())
+	}
+	if !strings.Contains(outBuf.String(), "from-env") || strings.Contains(outBuf.String(), "a task") {
+		t.Errorf("ls output = %q, want the tags alias from THREADKEEPER_CONFIG", outBuf.String())
+	}
+}
+
 func TestRun_AliasResolution(t *testing.T) {
-	// This test would require mocking config.LoadAliases()
-	// For now, we test that alias resolution doesn't break
-	// Full alias testing would require integration tests with real config files
-	t.Skip("Alias resolution testing requires config mocking - see integration tests")
+	tmpDir := t.TempDir()
+	t.Setenv("THREADKEEPER_WORKSPACE", tmpDir)
+	configHome := filepath.Join(tmpDir, "config")
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	t.Setenv(nowEnvVar, "")
+	if err := os.MkdirAll(filepath.Join(tmpDir, "threads"), 0755); err != nil {
+		t.Fatalf("Failed to create threads dir: %v", err)
+	}
+
+	cfgDir := filepath.Join(configHome, config.AppDirName)
+	if err := os.MkdirAll(cfgDir, 0755); err != nil {
+		t.Fatalf("Failed to create config dir: %v", err)
+	}
+	cfg := "[alias]\nls = \"list\"\nurgent = \"list --tag urgent\"\nwork = 'add --tag \"day job\"'\n"
+	if err := os.WriteFile(filepath.Join(cfgDir, "config.toml"), []byte(cfg), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	run := func(argv ...string) string {
+		t.Helper()
+		var outBuf, errBuf bytes.Buffer
+		if code := Run(argv, Config{Out: &outBuf, Err: &errBuf}); code != 0 {
+			t.Fatalf("Run(%v) exit code = %d, stderr: %s", argv, code, errBuf.String())
+		}
+		return outBuf.String()
+	}
+	run("add", "--tag", "urgent", "fix prod")
+	run("work", "-p", "office", "file report")
+	run("add", "water plants")
+
+	if out := run("urgent"); !strings.Contains(out, "fix prod") || strings.Contains(out, "water plants") {
+		t.Errorf("urgent alias output = %q, want only the urgent task", out)
+	}
+	// The user's arguments follow the alias's own
+	if out := run("urgent", "-p", "office"); strings.Contains(out, "fix prod") {
+		t.Errorf("urgent -p office output = %q, want no tasks", out)
+	}
+	if out := run("list", "--tag", "day job", "-p", "office"); !strings.Contains(out, "file report") {
+		t.Errorf("quoted alias argument was not kept together: %q", out)
+	}
+	if out := run("ls"); !strings.Contains(out, "water plants") {
+		t.Errorf("ls alias output = %q, want every open task", out)
+	}
+}
+
+func TestRun_AliasCommand(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("THREADKEEPER_WORKSPACE", tmpDir)
+	configHome := filepath.Join(tmpDir, "config")
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	t.Setenv(config.ConfigEnvVar, "")
+	t.Setenv(nowEnvVar, "")
+	if err := os.MkdirAll(filepath.Join(tmpDir, "threads"), 0755); err != nil {
+		t.Fatalf("Failed to create threads dir: %v", err)
+	}
+	cfgDir := filepath.Join(configHome, config.AppDirName)
+	if err := os.MkdirAll(cfgDir, 0755); err != nil {
+		t.Fatalf("Failed to create config dir: %v", err)
+	}
+	cfg := "timezone = \"UTC\"\n\n[alias]\nadd = \"list\"\nls = \"list\"\n"
+	if err := os.WriteFile(filepath.Join(cfgDir, "config.toml"), []byte(cfg), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	run := func(argv ...string) (string, string, int) {
+		t.Helper()
+		var outBuf, errBuf bytes.Buffer
+		code := Run(argv, Config{Out: &outBuf, Err: &errBuf})
+		return outBuf.String(), errBuf.String(), code
+	}
+
+	out, _, code := run("alias", "list")
+	if code != 0 || !strings.HasPrefix(out, "ls = list\n") || !strings.Contains(out, "add = list  (conflicts with built-in command)") {
+		t.Errorf("alias list = %q (exit %d)", out, code)
+	}
+
+	if _, errOut, code := run("alias", "add", "work", "add", "--tag", "day job"); code != 0 {
+		t.Fatalf("alias add exit code = %d, stderr: %s", code, errOut)
+	}
+	if _, errOut, code := run("work", "file report"); code != 0 {
+		t.Fatalf("work alias exit code = %d, stderr: %s", code, errOut)
+	}
+	if out, _, _ := run("list", "--tag", "day job"); !strings.Contains(out, "file report") {
+		t.Errorf("list --tag 'day job' = %q, want the task added through the alias", out)
+	}
+
+	for _, argv := range [][]string{
+		{"alias", "add", "list", "tags"},
+		{"alias", "add", "help", "tags"},
+		{"alias", "add", "lsa", "ls --all"},
+		{"alias", "add", "x", "nonexistent"},
+		{"alias", "remove", "nope"},
+	} {
+		if _, _, code := run(argv...); code != 1 {
+			t.Errorf("%v exit code = %d, want 1", argv, code)
+		}
+	}
+
+	if _, errOut, code := run("alias", "remove", "ls"); code != 0 {
+		t.Fatalf("alias remove exit code = %d, stderr: %s", code, errOut)
+	}
+	aliases, err := config.LoadAliases()
+	if err != nil {
+		t.Fatalf("LoadAliases() error = %v", err)
+	}
+	if _, ok := aliases["ls"]; ok || aliases["work"] != "add --tag 'day job'" || aliases["add"] != "list" {
+		t.Errorf("aliases after remove = %v", aliases)
+	}
+	if tz, err := config.EffectiveSetting(config.TimezoneKey); err != nil || tz != "UTC" {
+		t.Errorf("timezone after alias edits = %q, %v; want UTC kept", tz, err)
+	}
+}
+
+func TestSplitAliasTarget(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    []string
+		wantErr bool
+	}{
+		{in: "list", want: []string{"list"}},
+		{in: "  list   --tag urgent ", want: []string{"list", "--tag", "urgent"}},
+		{in: `list --tag "needs review"`, want: []string{"list", "--tag", "needs review"}},
+		{in: `add -d 'it\'s'`, wantErr: true},
+		{in: `add -d 'a "b"'`, want: []string{"add", "-d", `a "b"`}},
+		{in: `add -d "a \"b\""`, want: []string{"add", "-d", `a "b"`}},
+		{in: `add two\ words`, want: []string{"add", "two words"}},
+		{in: `list --tag ""`, want: []string{"list", "--tag", ""}},
+		{in: `list "oops`, wantErr: true},
+		{in: "", want: nil},
+	}
+	for _, tt := range tests {
+		got, err := splitAliasTarget(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("splitAliasTarget(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !slices.Equal(got, tt.want) {
+			t.Errorf("splitAliasTarget(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
 }
 
 func TestRun_DefaultToList(t *testing.T) {
@@ -323,6 +511,27 @@ func TestValidateAliases(t *testing.T) {
 			want:     map[string]string{"rm": "remove"}, // Only first level should be valid
 			wantWarn: true,
 		},
+		{
+			name:     "alias with arguments",
+			raw:      map[string]string{"urgent": "list --tag urgent"},
+			verbose:  true,
+			want:     map[string]string{"urgent": "list --tag urgent"},
+			wantWarn: false,
+		},
+		{
+			name:     "alias with arguments points to another alias",
+			raw:      map[string]string{"ls": "list", "lsa": "ls --all"},
+			verbose:  true,
+			want:     map[string]string{"ls": "list"},
+			wantWarn: true,
+		},
+		{
+			name:     "alias with unbalanced quotes",
+			raw:      map[string]string{"bad": `list --tag "oops`},
+			verbose:  true,
+			want:     map[string]string{},
+			wantWarn: true,
+		},
 		{
 			name:     "multiple valid aliases",
 			raw:      map[string]string{"rm": "remove", "ls": "list"},
@@ -368,3 +577,236 @@ func TestValidateAliases(t *testing.T) {
 		})
 	}
 }
+
+func TestRun_NowFixesClock(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("THREADKEEPER_WORKSPACE", tmpDir)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(tmpDir, "config"))
+	t.Setenv(nowEnvVar, "")
+	if err := os.MkdirAll(filepath.Join(tmpDir, "threads"), 0755); err != nil {
+		t.Fatalf("Failed to create threads dir: %v", err)
+	}
+
+	// Noon UTC keeps "today" on the same date in any local timezone
+	now := "2026-01-05T12:00:00Z"
+	var outBuf, errBuf bytes.Buffer
+	code := Run([]string{"--debug", "--now", now, "add", "--due", "today", "fixed", "clock"}, Config{Out: &outBuf, Err: &errBuf})
+	if code != 0 {
+		t.Fatalf("Run() exit code = %d, stderr: %s", code, errBuf.String())
+	}
+
+	paths, err := config.GetPaths("")
+	if err != nil {
+		t.Fatalf("GetPaths() error = %v", err)
+	}
+	tk, err := store.NewFileStore(paths.ThreadsDir).ResolveID("1")
+	if err != nil {
+		t.Fatalf("ResolveID() error = %v", err)
+	}
+
+	want := time.Date(2026, 1, 5, 12, 0, 0, 0, time.UTC)
+	if !tk.CreatedAt.Equal(want) {
+		t.Errorf("CreatedAt = %v, want %v", tk.CreatedAt, want)
+	}
+	if tk.DueAt == nil || tk.DueAt.Format("2006-01-02") != "2026-01-05" {
+		t.Errorf("DueAt = %v, want 2026-01-05", tk.DueAt)
+	}
+}
+
+func TestResolveClock(t *testing.T) {
+	tests := []struct {
+		name      string
+		flagValue string
+		envValue  string
+		debug     bool
+		want      string // RFC3339, empty for the system clock
+		wantErr   bool
+	}{
+		{"unset", "", "", false, "", false},
+		{"flag", "2026-01-05T09:00:00Z", "", true, "2026-01-05T09:00:00Z", false},
+		{"env", "", "2026-02-01T00:00:00Z", true, "2026-02-01T00:00:00Z", false},
+		{"flag wins over env", "2026-01-05T09:00:00Z", "2026-02-01T00:00:00Z", true, "2026-01-05T09:00:00Z", false},
+		{"requires debug", "2026-01-05T09:00:00Z", "", false, "", true},
+		{"env requires debug", "", "2026-01-05T09:00:00Z", false, "", true},
+		{"invalid", "tomorrow", "", true, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock, err := resolveClock(tt.flagValue, tt.envValue, tt.debug)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveClock() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var got string
+			if clock != nil {
+				got = clock.Now().Format(time.RFC3339)
+			}
+			if got != tt.want {
+				t.Errorf("resolveClock() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRun_MalformedConfigDoesNotBrickList(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("THREADKEEPER_WORKSPACE", tmpDir)
+	configHome := filepath.Join(tmpDir, "config")
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	if err := os.MkdirAll(filepath.Join(tmpDir, "threads"), 0755); err != nil {
+		t.Fatalf("Failed to create threads dir: %v", err)
+	}
+
+	cfgDir := filepath.Join(configHome, config.AppDirName)
+	if err := os.MkdirAll(cfgDir, 0755); err != nil {
+		t.Fatalf("Failed to create config dir: %v", err)
+	}
+	corrupt := "timezone = \"UTC\nbucket_width = [\n[alias\n"
+	if err := os.WriteFile(filepath.Join(cfgDir, "config.toml"), []byte(corrupt), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	for _, argv := range [][]string{
+		{"add", "--due", "today", "still", "works"},
+		{"list"},
+	} {
+		var outBuf, errBuf bytes.Buffer
+		code := Run(argv, Config{Out: &outBuf, Err: &errBuf})
+		if code != 0 {
+			t.Fatalf("Run(%v) exit code = %d, stderr: %s", argv, code, errBuf.String())
+		}
+		if n := strings.Count(errBuf.String(), "Warning:"); n != 1 {
+			t.Errorf("Run(%v) printed %d warnings, want 1:\n%s", argv, n, errBuf.String())
+		}
+		if argv[0] == "list" && !strings.Contains(outBuf.String(), "still works") {
+			t.Errorf("list output missing task:\n%s", outBuf.String())
+		}
+	}
+}
+
+func TestRun_WorkspaceFlag(t *testing.T) {
+	envDir := t.TempDir()
+	t.Setenv("THREADKEEPER_WORKSPACE", envDir)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(envDir, "config"))
+	t.Setenv(nowEnvVar, "")
+
+	wsDir := t.TempDir()
+	for _, dir := range []string{envDir, wsDir} {
+		if err := os.MkdirAll(filepath.Join(dir, "threads"), 0755); err != nil {
+			t.Fatalf("Failed to create threads dir: %v", err)
+		}
+	}
+
+	var outBuf, errBuf bytes.Buffer
+	if code := Run([]string{"-w", wsDir, "add", "elsewhere"}, Config{Out: &outBuf, Err: &errBuf}); code != 0 {
+		t.Fatalf("Run() exit code = %d, stderr: %s", code, errBuf.String())
+	}
+
+	for dir, want := range map[string]int{wsDir: 1, envDir: 0} {
+		paths, err := config.GetPaths(dir)
+		if err != nil {
+			t.Fatalf("GetPaths() error = %v", err)
+		}
+		tasks, err := store.NewFileStore(paths.ThreadsDir).LoadAll()
+		if err != nil {
+			t.Fatalf("LoadAll() error = %v", err)
+		}
+		if len(tasks) != want {
+			t.Errorf("tasks in %s = %d, want %d", dir, len(tasks), want)
+		}
+	}
+}
+
+func TestRun_QuietSuppressesSuccess(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("THREADKEEPER_WORKSPACE", tmpDir)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(tmpDir, "config"))
+	t.Setenv(nowEnvVar, "")
+	if err := os.MkdirAll(filepath.Join(tmpDir, "threads"), 0755); err != nil {
+		t.Fatalf("Failed to create threads dir: %v", err)
+	}
+
+	for _, args := range [][]string{{"-q", "add", "quiet"}, {"--quiet", "done", "1"}} {
+		var outBuf, errBuf bytes.Buffer
+		if code := Run(args, Config{Out: &outBuf, Err: &errBuf}); code != 0 {
+			t.Fatalf("Run(%v) exit code = %d, stderr: %s", args, code, errBuf.String())
+		}
+		if outBuf.Len() != 0 {
+			t.Errorf("Run(%v) stdout = %q, want empty", args, outBuf.String())
+		}
+	}
+
+	// Errors are never silenced
+	var outBuf, errBuf bytes.Buffer
+	if code := Run([]string{"-q", "done", "99"}, Config{Out: &outBuf, Err: &errBuf}); code == 0 {
+		t.Errorf("Run(done 99) exit code = 0, want failure")
+	}
+	if !strings.Contains(errBuf.String(), "Error:") {
+		t.Errorf("stderr = %q, want an error", errBuf.String())
+	}
+}
+
+func TestRun_GitAutocommit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	wsDir := t.TempDir()
+	configHome := t.TempDir()
+	t.Setenv("THREADKEEPER_WORKSPACE", wsDir)
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	t.Setenv(nowEnvVar, "")
+	for _, kv := range [][2]string{
+		{"GIT_AUTHOR_NAME", "test"}, {"GIT_AUTHOR_EMAIL", "test@example.com"},
+		{"GIT_COMMITTER_NAME", "test"}, {"GIT_COMMITTER_EMAIL", "test@example.com"},
+		{"GIT_CONFIG_GLOBAL", os.DevNull}, {"GIT_CONFIG_NOSYSTEM", "1"},
+	} {
+		t.Setenv(kv[0], kv[1])
+	}
+	if err := os.MkdirAll(filepath.Join(wsDir, "threads"), 0755); err != nil {
+		t.Fatalf("Failed to create threads dir: %v", err)
+	}
+
+	cfgDir := filepath.Join(configHome, config.AppDirName)
+	if err := os.MkdirAll(cfgDir, 0755); err != nil {
+		t.Fatalf("Failed to create config dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(cfgDir, "config.toml"), []byte("git_autocommit = true\n"), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	// Not a repository yet: the change succeeds with a warning
+	var outBuf, errBuf bytes.Buffer
+	if code := Run([]string{"add", "first"}, Config{Out: &outBuf, Err: &errBuf}); code != 0 {
+		t.Fatalf("Run(add) exit code = %d, stderr: %s", code, errBuf.String())
+	}
+	if !strings.Contains(errBuf.String(), "Warning: git auto-commit skipped") {
+		t.Errorf("stderr = %q, want auto-commit warning", errBuf.String())
+	}
+
+	if out, err := exec.Command("git", "-C", wsDir, "init", "-q").CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v: %s", err, out)
+	}
+	// doctor may change the workspace, but a clean check commits nothing
+	for _, argv := range [][]string{{"add", "second"}, {"list"}, {"doctor"}, {"done", "1"}} {
+		outBuf.Reset()
+		errBuf.Reset()
+		if code := Run(argv, Config{Out: &outBuf, Err: &errBuf}); code != 0 || errBuf.Len() != 0 {
+			t.Fatalf("Run(%v) exit code = %d, stderr: %s", argv, code, errBuf.String())
+		}
+	}
+
+	out, err := exec.Command("git", "-C", wsDir, "log", "--format=%s").Output()
+	if err != nil {
+		t.Fatalf("git log: %v", err)
+	}
+	if got, want := string(out), "tk: done 1\ntk: add second\n"; got != want {
+		t.Errorf("git log = %q, want %q", got, want)
+	}
+	tracked, err := exec.Command("git", "-C", wsDir, "ls-files").Output()
+	if err != nil {
+		t.Fatalf("git ls-files: %v", err)
+	}
+	if strings.Contains(string(tracked), ".index.json") {
+		t.Errorf("index cache was committed:\n%s", tracked)
+	}
+}
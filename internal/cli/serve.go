@@ -0,0 +1,213 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/sjatkinson/threadkeeper/internal/commands"
+)
+
+// serveMethods maps protocol method names to the built-in commands they run.
+// Only the core operations are exposed; anything else is rejected.
+var serveMethods = map[string]string{
+	"list":   "list",
+	"query":  "list",
+	"get":    "show",
+	"add":    "add",
+	"update": "update",
+	"done":   "done",
+	"attach": "attach",
+}
+
+// serveRequest is one line of the serve protocol sent by a client.
+// Args are passed to the command exactly as they would be on the command line.
+type serveRequest struct {
+	ID     int      `json:"id"`
+	Method string   `json:"method"`
+	Args   []string `json:"args"`
+}
+
+// serveResponse is the reply to a serveRequest.
+// Code is the command's exit code; Out and Err hold what it printed.
+type serveResponse struct {
+	ID    int    `json:"id"`
+	Code  int    `json:"code"`
+	Out   string `json:"out"`
+	Err   string `json:"err,omitempty"`
+	Error string `json:"error,omitempty"` // protocol-level error (bad request, unknown method)
+}
+
+// serveMu serializes command execution: commands are not safe to run
+// concurrently against the same workspace, and the workspace lock only
+// excludes other processes, not other connections' goroutines.
+var serveMu sync.Mutex
+
+func runServe(args []string, ctx commands.CommandContext) int {
+	fs := flag.NewFlagSet(ctx.AppName+" serve", flag.ContinueOnError)
+	fs.SetOutput(ctx.Err)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintln(ctx.Err, serveUsage(ctx.AppName))
+	}
+
+	var socketPath, addr string
+	fs.StringVar(&socketPath, "socket", "", "unix socket path to listen on")
+	fs.StringVar(&addr, "addr", "", "address for the read-only HTTP API")
+
+	if err := fs.Parse(args); err != nil {
+		_, _ = fmt.Fprintln(ctx.Err)
+		_, _ = fmt.Fprintln(ctx.Err, serveUsage(ctx.AppName))
+		return 2
+	}
+
+	if len(fs.Args()) != 0 {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: unexpected arguments\n")
+		_, _ = fmt.Fprintln(ctx.Err, serveUsage(ctx.AppName))
+		return 2
+	}
+
+	if (socketPath == "") == (addr == "") {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: exactly one of --socket or --addr is required\n")
+		_, _ = fmt.Fprintln(ctx.Err, serveUsage(ctx.AppName))
+		return 2
+	}
+
+	if addr != "" {
+		return serveHTTP(addr, ctx)
+	}
+
+	// Refuse to clobber an existing file; a stale socket must be removed by hand
+	if _, err := os.Stat(socketPath); err == nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %s already exists (remove it if no server is running)\n", socketPath)
+		return 1
+	}
+
+	ln, err := net.Listen("unix", socketPath)
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: failed to listen on %s: %v\n", socketPath, err)
+		return 1
+	}
+	defer os.Remove(socketPath)
+
+	// Stop cleanly on Ctrl-C / SIGTERM so the socket file is removed
+	sigCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-sigCtx.Done()
+		_ = ln.Close()
+	}()
+
+	_, _ = fmt.Fprintf(ctx.Err, "Listening on %s\n", socketPath)
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return 0
+			}
+			_, _ = fmt.Fprintf(ctx.Err, "Error: accept failed: %v\n", err)
+			return 1
+		}
+		go func() {
+			defer conn.Close()
+			serveConn(conn, ctx)
+		}()
+	}
+}
+
+// serveHTTP runs the read-only HTTP API on addr until interrupted.
+func serveHTTP(addr string, ctx commands.CommandContext) int {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: failed to listen on %s: %v\n", addr, err)
+		return 1
+	}
+
+	srv := &http.Server{Handler: commands.NewAPIHandler(ctx)}
+
+	// Stop cleanly on Ctrl-C / SIGTERM, letting in-flight requests finish
+	sigCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-sigCtx.Done()
+		_ = srv.Shutdown(context.Background())
+	}()
+
+	_, _ = fmt.Fprintf(ctx.Err, "Listening on http://%s\n", ln.Addr())
+
+	if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// serveConn reads newline-delimited JSON requests from rw and writes one
+// JSON response line per request until the client disconnects.
+func serveConn(rw io.ReadWriter, ctx commands.CommandContext) {
+	scanner := bufio.NewScanner(rw)
+	// Allow large requests (e.g. long descriptions), up to 1MB per line
+	const maxCapacity = 1024 * 1024
+	scanner.Buffer(make([]byte, 0, 64*1024), maxCapacity)
+	enc := json.NewEncoder(rw)
+
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var req serveRequest
+		if err := json.Unmarshal(line, &req); err != nil {
+			if enc.Encode(serveResponse{Code: 2, Error: fmt.Sprintf("invalid request: %v", err)}) != nil {
+				return
+			}
+			continue
+		}
+
+		if err := enc.Encode(handleServeRequest(req, ctx)); err != nil {
+			return
+		}
+	}
+}
+
+// handleServeRequest runs the command for a single request and captures its output.
+func handleServeRequest(req serveRequest, ctx commands.CommandContext) serveResponse {
+	name, ok := serveMethods[req.Method]
+	if !ok {
+		return serveResponse{ID: req.ID, Code: 2, Error: fmt.Sprintf("unknown method %q", req.Method)}
+	}
+	info := getCommand(name)
+	if info == nil {
+		return serveResponse{ID: req.ID, Code: 2, Error: fmt.Sprintf("command %q is not available", name)}
+	}
+
+	// Commands never read the daemon's stdin: a prompt would block every
+	// client behind serveMu, and the client could never answer it
+	var out, errOut bytes.Buffer
+	cmdCtx := ctx
+	cmdCtx.Out = &out
+	cmdCtx.Err = &errOut
+	cmdCtx.Stdin = strings.NewReader("")
+
+	serveMu.Lock()
+	code := info.Runner(req.Args, cmdCtx)
+	if code == 0 && info.Mutates {
+		commands.AutoCommit(cmdCtx, name, req.Args)
+	}
+	serveMu.Unlock()
+
+	return serveResponse{ID: req.ID, Code: code, Out: out.String(), Err: errOut.String()}
+}
@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sjatkinson/threadkeeper/internal/commands"
+)
+
+// startServeConn serves one connection with ctx in a fresh workspace and
+// returns a function making one call over it. A call fails the test if no
+// response arrives within five seconds.
+func startServeConn(t *testing.T, ctx commands.CommandContext) func(serveRequest) serveResponse {
+	t.Helper()
+	tmpDir := t.TempDir()
+	t.Setenv("THREADKEEPER_WORKSPACE", tmpDir)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(tmpDir, "config"))
+	if err := os.MkdirAll(filepath.Join(tmpDir, "threads"), 0755); err != nil {
+		t.Fatalf("Failed to create threads dir: %v", err)
+	}
+
+	server, client := net.Pipe()
+	t.Cleanup(func() { client.Close() })
+	go func() {
+		defer server.Close()
+		serveConn(server, ctx)
+	}()
+
+	reader := bufio.NewReader(client)
+	return func(req serveRequest) serveResponse {
+		t.Helper()
+		data, err := json.Marshal(req)
+		if err != nil {
+			t.Fatalf("Failed to marshal request: %v", err)
+		}
+		if err := client.SetDeadline(time.Now().Add(5 * time.Second)); err != nil {
+			t.Fatalf("Failed to set deadline: %v", err)
+		}
+		if _, err := client.Write(append(data, '\n')); err != nil {
+			t.Fatalf("Failed to write request: %v", err)
+		}
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			t.Fatalf("Failed to read response: %v", err)
+		}
+		var resp serveResponse
+		if err := json.Unmarshal(line, &resp); err != nil {
+			t.Fatalf("Failed to parse response %q: %v", line, err)
+		}
+		return resp
+	}
+}
+
+func TestServeConn_AddThenList(t *testing.T) {
+	call := startServeConn(t, commands.CommandContext{AppName: "tk"})
+
+	resp := call(serveRequest{ID: 1, Method: "add", Args: []string{"--tag", "rpc", "Served task"}})
+	if resp.ID != 1 || resp.Code != 0 {
+		t.Fatalf("add response = %+v, want id 1 and code 0", resp)
+	}
+	if !strings.Contains(resp.Out, "Served task") {
+		t.Errorf("add output = %q, want it to mention the title", resp.Out)
+	}
+
+	resp = call(serveRequest{ID: 2, Method: "list"})
+	if resp.ID != 2 || resp.Code != 0 {
+		t.Fatalf("list response = %+v, want id 2 and code 0", resp)
+	}
+	if !strings.Contains(resp.Out, "Served task") || !strings.Contains(resp.Out, "#rpc") {
+		t.Errorf("list output = %q, want the added task", resp.Out)
+	}
+
+	resp = call(serveRequest{ID: 3, Method: "remove", Args: []string{"--force", "1"}})
+	if resp.Code != 2 || resp.Error == "" {
+		t.Errorf("remove response = %+v, want an unknown method error", resp)
+	}
+}
+
+func TestServeConn_NeverReadsDaemonStdin(t *testing.T) {
+	// The daemon's stdin never delivers anything, like an idle terminal
+	stdin, stdinW := io.Pipe()
+	defer stdinW.Close()
+	call := startServeConn(t, commands.CommandContext{AppName: "tk", Stdin: stdin})
+
+	if resp := call(serveRequest{ID: 1, Method: "add", Args: []string{"Served task"}}); resp.Code != 0 {
+		t.Fatalf("add response = %+v, want code 0", resp)
+	}
+
+	// A duplicate title can't be confirmed over the socket, so it fails
+	resp := call(serveRequest{ID: 2, Method: "add", Args: []string{"Served task"}})
+	if resp.Code != 1 || !strings.Contains(resp.Err, "--force") {
+		t.Errorf("duplicate add response = %+v, want code 1 asking for --force", resp)
+	}
+
+	resp = call(serveRequest{ID: 3, Method: "add", Args: []string{"-"}})
+	if resp.Code == 0 {
+		t.Errorf("add - response = %+v, want an error for the empty title", resp)
+	}
+}
@@ -1,15 +1,17 @@
 package commands
 
 import (
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"os/exec"
 	"strings"
 	"time"
 
 	"github.com/sjatkinson/threadkeeper/internal/config"
 	"github.com/sjatkinson/threadkeeper/internal/date"
-	"github.com/sjatkinson/threadkeeper/internal/store"
 	"github.com/sjatkinson/threadkeeper/internal/task"
 )
 
@@ -22,6 +24,33 @@ func (s *stringList) Set(v string) error {
 }
 func (s *stringList) Type() string { return "stringList" }
 
+// durationFlag is a flag.Value for a positive duration such as 2h or
+// 1h30m. Values go through time.ParseDuration, so a bad duration fails
+// when flags are parsed.
+type durationFlag struct {
+	value time.Duration
+	set   bool
+}
+
+func (d *durationFlag) String() string {
+	if d == nil || !d.set {
+		return ""
+	}
+	return d.value.String()
+}
+
+func (d *durationFlag) Set(v string) error {
+	parsed, err := time.ParseDuration(v)
+	if err != nil {
+		return fmt.Errorf("invalid duration %q (e.g. 45m, 2h, 1h30m)", v)
+	}
+	if parsed <= 0 {
+		return fmt.Errorf("duration must be positive, got %q", v)
+	}
+	d.value, d.set = parsed, true
+	return nil
+}
+
 func RunAdd(args []string, ctx CommandContext) int {
 	fs := flag.NewFlagSet(ctx.AppName+" add", flag.ContinueOnError)
 	fs.SetOutput(ctx.Err)
@@ -30,10 +59,14 @@ func RunAdd(args []string, ctx CommandContext) int {
 	}
 
 	var (
-		desc    string
-		project string
-		due     string
-		tags    stringList
+		desc     string
+		project  string
+		due      string
+		tags     stringList
+		edit     bool
+		stdin    bool
+		force    bool
+		estimate durationFlag
 	)
 	fs.StringVar(&desc, "description", "", "description")
 	fs.StringVar(&desc, "d", "", "description (shorthand)")
@@ -41,6 +74,10 @@ func RunAdd(args []string, ctx CommandContext) int {
 	fs.StringVar(&project, "p", "", "project name (shorthand)")
 	fs.StringVar(&due, "due", "", "due date (YYYY-MM-DD)")
 	fs.Var(&tags, "tag", "repeatable tag")
+	fs.BoolVar(&edit, "edit", false, "write the description in $EDITOR")
+	fs.BoolVar(&stdin, "stdin", false, "read the title and description from standard input")
+	fs.BoolVar(&force, "force", false, "add even if an open task has the same title")
+	fs.Var(&estimate, "estimate", "expected effort, e.g. 2h or 1h30m")
 
 	if err := fs.Parse(args); err != nil {
 		if err == flag.ErrHelp {
@@ -52,15 +89,47 @@ func RunAdd(args []string, ctx CommandContext) int {
 		return 2
 	}
 
-	if len(fs.Args()) == 0 {
-		_, _ = fmt.Fprintf(ctx.Err, "Error: missing argument: title required\n")
+	if err := task.ValidateTags(tags); err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
 		return 2
 	}
 
-	title := strings.Join(fs.Args(), " ")
+	// "tk add -" is shorthand for --stdin
+	rest := fs.Args()
+	if len(rest) == 1 && rest[0] == "-" {
+		stdin, rest = true, nil
+	}
+	if stdin && len(rest) > 0 {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: --stdin reads the title from standard input; don't also give one\n")
+		return 2
+	}
+
+	var title string
+	if stdin {
+		data, err := io.ReadAll(ctx.stdin())
+		if err != nil {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: failed to read stdin: %v\n", err)
+			return 1
+		}
+		var body string
+		title, body = splitStdinTask(string(data))
+		if title == "" {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: missing argument: no title on stdin\n")
+			return 2
+		}
+		if desc == "" {
+			desc = body
+		}
+	} else {
+		if len(rest) == 0 {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: missing argument: title required\n")
+			return 2
+		}
+		title = strings.Join(rest, " ")
+	}
 
 	// Get paths and verify tasks directory exists
-	paths, err := config.GetPaths(ctx.Path)
+	paths, err := config.GetPaths(ctx.workspace())
 	if err != nil {
 		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
 		return 1
@@ -71,6 +140,31 @@ func RunAdd(args []string, ctx CommandContext) int {
 		return 1
 	}
 
+	// Catch "did I already add this?" before creating anything: ask on a
+	// terminal, and require --force everywhere else
+	st := newStore(paths)
+	if !force {
+		existing, err := loadAllTasks(st, ctx)
+		if err != nil {
+			_, _ = fmt.Fprintf(ctx.Err, "Warning: failed to check for duplicate titles: %v\n", err)
+		}
+		if dup := findOpenTitle(existing, title); dup != nil {
+			sidStr := "?"
+			if dup.ShortID != nil {
+				sidStr = fmt.Sprintf("%d", *dup.ShortID)
+			}
+			_, _ = fmt.Fprintf(ctx.Err, "Warning: open task %s (%s) already has this title\n", sidStr, dup.ID)
+			if !isTerminal(ctx.stdin()) {
+				_, _ = fmt.Fprintf(ctx.Err, "Error: pass --force to add a duplicate\n")
+				return 1
+			}
+			if !confirm(ctx, "Add it anyway?") {
+				_, _ = fmt.Fprintln(ctx.Err, "Aborted; no task was added.")
+				return 1
+			}
+		}
+	}
+
 	// Generate task ID
 	taskID, err := task.GenerateID()
 	if err != nil {
@@ -87,12 +181,20 @@ func RunAdd(args []string, ctx CommandContext) int {
 			locale = config.DateLocaleISO // Default on error
 		}
 
+		// Load timezone used to resolve "today" and shortcuts
+		tz, err := config.LoadTimezone()
+		if err != nil {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+			return 1
+		}
+
 		// Parse date using locale-aware parser
-		canonical, err := date.ParseDate(due, locale, date.RealClock{}, nil)
+		canonical, err := date.ParseDate(due, locale, ctx.clock(), tz)
 		if err != nil {
 			_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
 			return 1
 		}
+		warnPastDue(ctx, canonical, tz)
 
 		// Convert canonical string to time.Time
 		parsed, err := time.Parse("2006-01-02", canonical)
@@ -104,19 +206,36 @@ func RunAdd(args []string, ctx CommandContext) int {
 		dueAt = &parsed
 	}
 
+	// Merge default tags from config with explicit --tag flags.
+	// NormalizeTags de-duplicates, so overlap between the two is harmless.
+	defaultTags, err := config.LoadDefaultTags()
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Warning: failed to load default_tags: %v\n", err)
+		defaultTags = nil
+	}
+	allTags := append(defaultTags, tags...)
+
 	// Normalize tags
-	normalizedTags := task.NormalizeTags([]string(tags))
+	normalizedTags := task.NormalizeTags(allTags)
 
-	// Get next short_id
-	st := store.NewFileStore(paths.ThreadsDir)
-	shortID, err := st.GenerateNextShortID()
-	if err != nil {
-		_, _ = fmt.Fprintf(ctx.Err, "Error: failed to generate short_id: %v\n", err)
-		return 1
+	// Capture the description before anything is saved, so a failed
+	// editor leaves no half-made task behind
+	if edit {
+		content, err := ctx.editor(getEditor(), "tk-add-*.txt").Capture([]byte(desc))
+		if err != nil {
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				err = fmt.Errorf("editor exited with code %d", exitErr.ExitCode())
+			}
+			_, _ = fmt.Fprintf(ctx.Err, "Error: %v; task not added\n", err)
+			return 1
+		}
+		// Same trimming as describe; an empty file means no description
+		desc = strings.TrimRight(string(content), " \t\n\r")
 	}
 
 	// Create task
-	now := time.Now().UTC()
+	now := ctx.clock().Now().UTC()
 	t := &task.Task{
 		ID:          taskID,
 		Title:       title,
@@ -127,30 +246,95 @@ func RunAdd(args []string, ctx CommandContext) int {
 		DueAt:       dueAt,
 		Project:     project,
 		Tags:        normalizedTags,
-		ShortID:     &shortID,
+		Estimate:    estimate.value,
 	}
 
-	// Save task
-	if err := st.Save(t); err != nil {
-		_, _ = fmt.Fprintf(ctx.Err, "Error: failed to save task: %v\n", err)
+	// Assign the next short_id and save under the workspace lock so
+	// concurrent adds can't pick the same number
+	var shortID int
+	if err := st.WithLock(func() error {
+		var err error
+		shortID, err = st.GenerateNextShortID()
+		if err != nil {
+			return fmt.Errorf("failed to generate short_id: %w", err)
+		}
+		t.ShortID = &shortID
+		if err := st.Save(t); err != nil {
+			return fmt.Errorf("failed to save task: %w", err)
+		}
+		return nil
+	}); err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
 		return 1
 	}
 
 	// Output success message
-	_, _ = fmt.Fprintf(ctx.Out, "Added task %d (%s): %s\n", shortID, taskID, title)
+	ctx.Success("Added task %d (%s): %s\n", shortID, taskID, title)
 
 	return 0
 }
 
+// findOpenTitle returns an open task whose title matches title, ignoring
+// case and whitespace the way list --flag-dups does, or nil.
+func findOpenTitle(tasks []*task.Task, title string) *task.Task {
+	key := normalizeTitle(title)
+	for _, t := range tasks {
+		if t.Status == task.StatusOpen && normalizeTitle(t.Title) == key {
+			return t
+		}
+	}
+	return nil
+}
+
+// warnPastDue warns on Err when canonical (YYYY-MM-DD) is before today in
+// tz, which usually means a mistyped year or the wrong date_locale. It
+// never blocks the change, so back-dating on purpose still works.
+func warnPastDue(ctx CommandContext, canonical string, tz *time.Location) {
+	today := ctx.clock().Now().In(tz).Format(dueDateLayout)
+	if canonical < today {
+		_, _ = fmt.Fprintf(ctx.Err, "Warning: due date %s is in the past\n", canonical)
+	}
+}
+
+// splitStdinTask splits piped add input into a title, the first non-empty
+// line, and a description, the lines after it with surrounding blank lines
+// and trailing whitespace removed.
+func splitStdinTask(data string) (string, string) {
+	lines := strings.Split(strings.ReplaceAll(data, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		if title := strings.TrimSpace(line); title != "" {
+			body := strings.Join(lines[i+1:], "\n")
+			body = strings.TrimLeft(body, "\n")
+			return title, strings.TrimRight(body, " \t\n\r")
+		}
+	}
+	return "", ""
+}
+
 func addUsage(app string) string {
 	return fmt.Sprintf(`Usage:
   %s add <title> [flags]
+  %s add --stdin [flags]
+  %s add - [flags]
 
 Flags:
   -d, --description <t>  description
   -p, --project <name>   project name
   --due <date>           due date (format depends on date_locale config)
-  --tag <tag>            repeatable tag
+                         (a date before today is kept, with a warning)
+  --tag <tag>            repeatable tag (merged with default_tags config)
+  --estimate <dur>       expected effort, e.g. 45m, 2h, 1h30m
+  --edit                 write the description in $EDITOR (starting from
+                         -d, if given); an empty file means no description
+  --stdin                read the title from the first non-empty line of
+                         standard input and the description from the rest;
+                         -d, if given, replaces the piped description
+  --force                add even if an open task has the same title
+                         (otherwise asks on a terminal, or fails)
+
+Tags are lowercased (key:value tags keep the value's case), a leading "#"
+is dropped, and inner spaces become hyphens: --tag "#Needs Review" is
+needs-review. A tag with nothing left, like "#", is an error.
 
-`, app)
+`, app, app, app)
 }
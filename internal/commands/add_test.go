@@ -0,0 +1,273 @@
+package commands
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sjatkinson/threadkeeper/internal/config"
+	"github.com/sjatkinson/threadkeeper/internal/date"
+)
+
+// setupWorkspace creates a temporary workspace with a threads directory and
+// isolated config, and returns the workspace path.
+func setupWorkspace(t *testing.T) string {
+	t.Helper()
+	tmpDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmpDir, "threads"), 0755); err != nil {
+		t.Fatalf("Failed to create threads dir: %v", err)
+	}
+	t.Setenv("THREADKEEPER_WORKSPACE", tmpDir)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(tmpDir, "config"))
+	t.Setenv("THREADKEEPER_CONFIG", "")
+	return tmpDir
+}
+
+// writeConfig writes config.toml for the workspace from setupWorkspace.
+func writeConfig(t *testing.T, ws, content string) {
+	t.Helper()
+	dir := filepath.Join(ws, "config", config.AppDirName)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("Failed to create config dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "config.toml"), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+}
+
+// testClock returns a clock fixed at Tuesday 2026-03-10, midday so the
+// date is the same in any timezone within twelve hours of UTC.
+func testClock() date.FixedClock {
+	return date.FixedClock{FixedTime: time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)}
+}
+
+// newTestContext returns a CommandContext writing to fresh buffers.
+func newTestContext() (CommandContext, *bytes.Buffer, *bytes.Buffer) {
+	var out, errOut bytes.Buffer
+	return CommandContext{AppName: "tk", Out: &out, Err: &errOut}, &out, &errOut
+}
+
+func TestRunAdd_ThenListSeesTask(t *testing.T) {
+	setupWorkspace(t)
+
+	ctx, out, errOut := newTestContext()
+	if code := RunAdd([]string{"--tag", "regression", "write", "the", "report"}, ctx); code != 0 {
+		t.Fatalf("RunAdd() exit code = %d, stderr: %s", code, errOut.String())
+	}
+	added := out.String()
+
+	ctx, out, errOut = newTestContext()
+	if code := RunList([]string{}, ctx); code != 0 {
+		t.Fatalf("RunList() exit code = %d, stderr: %s", code, errOut.String())
+	}
+	listed := out.String()
+
+	if !strings.Contains(listed, "write the report") {
+		t.Errorf("list output does not contain added task:\n%s", listed)
+	}
+
+	// The durable ID printed by add must be the one list shows
+	start := strings.Index(added, "(")
+	end := strings.Index(added, ")")
+	if start < 0 || end < start {
+		t.Fatalf("unexpected add output: %q", added)
+	}
+	id := added[start+1 : end]
+	if !strings.Contains(listed, id) {
+		t.Errorf("list output does not contain ID %s:\n%s", id, listed)
+	}
+}
+
+func TestRunAdd_Stdin(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		input    string
+		wantCode int
+		title    string
+		desc     string
+	}{
+		{"dash", []string{"-"}, "fix flaky test\n", 0, "fix flaky test", ""},
+		{"flag with body", []string{"--stdin", "--tag", "ci"}, "\n  fix flaky test  \n\nIt fails on Tuesdays.\n\nOnly on arm64.\n\n", 0, "fix flaky test", "It fails on Tuesdays.\n\nOnly on arm64."},
+		{"description flag wins", []string{"-d", "from flag", "-"}, "title\nfrom stdin\n", 0, "title", "from flag"},
+		{"empty input", []string{"-"}, " \n\n", 2, "", ""},
+		{"title args too", []string{"--stdin", "extra"}, "title\n", 2, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupWorkspace(t)
+			ctx, _, errOut := newTestContext()
+			ctx.Stdin = strings.NewReader(tt.input)
+			if code := RunAdd(tt.args, ctx); code != tt.wantCode {
+				t.Fatalf("RunAdd() exit code = %d, want %d, stderr: %s", code, tt.wantCode, errOut.String())
+			}
+			if tt.wantCode != 0 {
+				return
+			}
+			got := loadOnlyTask(t)
+			if got.Title != tt.title {
+				t.Errorf("Title = %q, want %q", got.Title, tt.title)
+			}
+			if got.Description != tt.desc {
+				t.Errorf("Description = %q, want %q", got.Description, tt.desc)
+			}
+		})
+	}
+}
+
+func TestRunAddUpdate_PastDueWarning(t *testing.T) {
+	setupWorkspace(t)
+	clock := testClock()
+
+	tests := []struct {
+		name string
+		run  func(ctx CommandContext) int
+		warn bool
+	}{
+		{"add past", func(ctx CommandContext) int { return RunAdd([]string{"--due", "2024-01-01", "old"}, ctx) }, true},
+		{"add today", func(ctx CommandContext) int { return RunAdd([]string{"--due", "today", "now"}, ctx) }, false},
+		{"update past", func(ctx CommandContext) int { return RunUpdate([]string{"--due", "2026-03-09", "1"}, ctx) }, true},
+		{"update future", func(ctx CommandContext) int { return RunUpdate([]string{"--due", "+1", "1"}, ctx) }, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _, errOut := newTestContext()
+			ctx.Clock = clock
+			if code := tt.run(ctx); code != 0 {
+				t.Fatalf("exit code = %d, stderr: %s", code, errOut.String())
+			}
+			if got := strings.Contains(errOut.String(), "is in the past"); got != tt.warn {
+				t.Errorf("warned = %v, want %v; stderr: %q", got, tt.warn, errOut.String())
+			}
+		})
+	}
+}
+
+func TestRunAdd_DuplicateTitle(t *testing.T) {
+	orig := isTerminal
+	t.Cleanup(func() { isTerminal = orig })
+
+	tests := []struct {
+		name      string
+		args      []string
+		terminal  bool
+		input     string
+		wantCode  int
+		wantWarn  bool
+		wantTasks int
+	}{
+		{"confirm yes", []string{"  Write The Report "}, true, "y\n", 0, true, 2},
+		{"confirm no", []string{"write the report"}, true, "n\n", 1, true, 1},
+		{"not a terminal", []string{"write the report"}, false, "", 1, true, 1},
+		{"force skips check", []string{"--force", "write the report"}, false, "", 0, false, 2},
+		{"different title", []string{"write the summary"}, false, "", 0, false, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupWorkspace(t)
+			addAndLoad(t, []string{"write the report"})
+			isTerminal = func(io.Reader) bool { return tt.terminal }
+
+			ctx, _, errOut := newTestContext()
+			ctx.Stdin = strings.NewReader(tt.input)
+			if code := RunAdd(tt.args, ctx); code != tt.wantCode {
+				t.Fatalf("RunAdd() exit code = %d, want %d, stderr: %s", code, tt.wantCode, errOut.String())
+			}
+			if warned := strings.Contains(errOut.String(), "open task 1"); warned != tt.wantWarn {
+				t.Errorf("warned = %v, want %v; stderr: %q", warned, tt.wantWarn, errOut.String())
+			}
+
+			paths, _ := config.GetPaths("")
+			tasks, err := newStore(paths).LoadAll()
+			if err != nil || len(tasks) != tt.wantTasks {
+				t.Errorf("LoadAll() = %d tasks, err = %v; want %d", len(tasks), err, tt.wantTasks)
+			}
+		})
+	}
+}
+
+func TestRunAddListUpdate_TagSyntax(t *testing.T) {
+	setupWorkspace(t)
+	tk := addAndLoad(t, []string{"--tag", "#Bug", "--tag", "needs review", "tagged"})
+	if got, want := strings.Join(tk.Tags, ","), "bug,needs-review"; got != want {
+		t.Errorf("Tags = %q, want %q", got, want)
+	}
+
+	ctx, out, errOut := newTestContext()
+	if code := RunList([]string{"--tag", "#bug"}, ctx); code != 0 {
+		t.Fatalf("RunList() exit code = %d, stderr: %s", code, errOut.String())
+	}
+	if !strings.Contains(out.String(), "tagged") {
+		t.Errorf("list --tag #bug did not match:\n%s", out.String())
+	}
+
+	for _, run := range []func(CommandContext) int{
+		func(ctx CommandContext) int { return RunAdd([]string{"--tag", "#", "bad"}, ctx) },
+		func(ctx CommandContext) int { return RunUpdate([]string{"--add-tag", " # ", tk.ID}, ctx) },
+		func(ctx CommandContext) int { return RunList([]string{"--not-tag", "#"}, ctx) },
+	} {
+		ctx, _, errOut := newTestContext()
+		if code := run(ctx); code != 2 {
+			t.Errorf("exit code = %d, want 2 for an empty tag; stderr: %s", code, errOut.String())
+		}
+	}
+}
+
+func TestRunAdd_DefaultTags(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		want   string
+	}{
+		{"merged without duplicates", "default_tags = [\"team\", \"#Urgent\"]\n", "team,urgent,q3"},
+		{"missing key", "date_locale = \"iso\"\n", "urgent,q3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ws := setupWorkspace(t)
+			writeConfig(t, ws, tt.config)
+			tk := addAndLoad(t, []string{"--tag", "urgent", "--tag", "q3", "tagged"})
+			if got := strings.Join(tk.Tags, ","); got != tt.want {
+				t.Errorf("Tags = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunAddUpdate_ClockResolvesDue(t *testing.T) {
+	setupWorkspace(t)
+	now := testClock().FixedTime
+
+	ctx, _, errOut := newTestContext()
+	ctx.Clock = date.FixedClock{FixedTime: now}
+	if code := RunAdd([]string{"--due", "+7", "next week"}, ctx); code != 0 {
+		t.Fatalf("RunAdd() exit code = %d, stderr: %s", code, errOut.String())
+	}
+	tk := loadOnlyTask(t)
+	if tk.DueAt == nil || tk.DueAt.Format("2006-01-02") != "2026-03-17" {
+		t.Errorf("DueAt after add = %v, want 2026-03-17", tk.DueAt)
+	}
+	if !tk.CreatedAt.Equal(now) || !tk.UpdatedAt.Equal(now) {
+		t.Errorf("CreatedAt, UpdatedAt = %v, %v, want %v", tk.CreatedAt, tk.UpdatedAt, now)
+	}
+
+	later := now.AddDate(0, 0, 2)
+	ctx, _, errOut = newTestContext()
+	ctx.Clock = date.FixedClock{FixedTime: later}
+	if code := RunUpdate([]string{"--due", "+7", tk.ID}, ctx); code != 0 {
+		t.Fatalf("RunUpdate() exit code = %d, stderr: %s", code, errOut.String())
+	}
+	tk = loadOnlyTask(t)
+	if tk.DueAt == nil || tk.DueAt.Format("2006-01-02") != "2026-03-19" {
+		t.Errorf("DueAt after update = %v, want 2026-03-19", tk.DueAt)
+	}
+	if !tk.CreatedAt.Equal(now) || !tk.UpdatedAt.Equal(later) {
+		t.Errorf("CreatedAt, UpdatedAt = %v, %v, want %v, %v", tk.CreatedAt, tk.UpdatedAt, now, later)
+	}
+}
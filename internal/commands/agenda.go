@@ -0,0 +1,239 @@
+package commands
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/sjatkinson/threadkeeper/internal/config"
+	"github.com/sjatkinson/threadkeeper/internal/task"
+)
+
+func RunAgenda(args []string, ctx CommandContext) int {
+	fs := flag.NewFlagSet(ctx.AppName+" agenda", flag.ContinueOnError)
+	fs.SetOutput(ctx.Err)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintln(ctx.Err, agendaUsage(ctx.AppName))
+	}
+
+	var (
+		days int
+		ics  bool
+	)
+	fs.IntVar(&days, "days", 7, "number of days ahead to include")
+	fs.BoolVar(&ics, "ics", false, "print the agenda as iCalendar events")
+
+	if err := fs.Parse(args); err != nil {
+		_, _ = fmt.Fprintln(ctx.Err)
+		_, _ = fmt.Fprintln(ctx.Err, agendaUsage(ctx.AppName))
+		return 2
+	}
+
+	if len(fs.Args()) != 0 {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: unexpected arguments\n")
+		_, _ = fmt.Fprintln(ctx.Err, agendaUsage(ctx.AppName))
+		return 2
+	}
+
+	if days < 0 {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: --days must not be negative\n")
+		return 2
+	}
+
+	return runAgenda(days, ics, ctx)
+}
+
+// RunToday is agenda --days 0: overdue tasks and tasks due today.
+func RunToday(args []string, ctx CommandContext) int {
+	fs := flag.NewFlagSet(ctx.AppName+" today", flag.ContinueOnError)
+	fs.SetOutput(ctx.Err)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintln(ctx.Err, todayUsage(ctx.AppName))
+	}
+
+	if err := fs.Parse(args); err != nil {
+		_, _ = fmt.Fprintln(ctx.Err)
+		_, _ = fmt.Fprintln(ctx.Err, todayUsage(ctx.AppName))
+		return 2
+	}
+
+	if len(fs.Args()) != 0 {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: unexpected arguments\n")
+		_, _ = fmt.Fprintln(ctx.Err, todayUsage(ctx.AppName))
+		return 2
+	}
+
+	return runAgenda(0, false, ctx)
+}
+
+// runAgenda prints the open tasks due within days, as text or, with ics,
+// as an iCalendar feed of all-day events.
+func runAgenda(days int, ics bool, ctx CommandContext) int {
+	// Get paths and verify threads directory exists
+	paths, err := config.GetPaths(ctx.workspace())
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+
+	if _, err := os.Stat(paths.ThreadsDir); err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: threads directory does not exist at %s. Run '%s init' first.\n", paths.ThreadsDir, ctx.AppName)
+		return 1
+	}
+
+	tz, err := config.LoadTimezone()
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+
+	st := newStore(paths)
+	tasks, err := loadAllTasks(st, ctx)
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+
+	// Open tasks due on or before the last day; overdue ones have no lower bound
+	now := ctx.clock().Now().In(tz)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	due := filterTasks(tasks, taskFilter{
+		DueBefore: today.AddDate(0, 0, days).Format(dueDateLayout),
+	})
+
+	if ics {
+		sort.SliceStable(due, func(i, j int) bool {
+			return due[i].DueAt.Before(*due[j].DueAt)
+		})
+		renderICS(ctx.Out, due, ctx.clock().Now(), icsEvent)
+		return 0
+	}
+
+	if len(due) == 0 {
+		if days == 0 {
+			_, _ = fmt.Fprintln(ctx.Out, "Nothing due today.")
+		} else {
+			_, _ = fmt.Fprintf(ctx.Out, "Nothing due in the next %d days.\n", days)
+		}
+		return 0
+	}
+
+	dateLayout, err := config.LoadDisplayDateFormat()
+	if err != nil {
+		dateLayout = config.DisplayLayoutISO // Default on error
+	}
+
+	displayAgenda(ctx.Out, due, today, displayOptions{DateLayout: dateLayout})
+	return 0
+}
+
+// summaryWeekDays is how many days, starting today, count as "this week"
+// in a due summary.
+const summaryWeekDays = 7
+
+// dueSummary counts open tasks for list --summary and stats --summary.
+type dueSummary struct {
+	Open        int `json:"open"`
+	Overdue     int `json:"overdue"`
+	DueThisWeek int `json:"due_this_week"` // due today or in the next six days
+}
+
+// summarizeDue counts the open tasks among tasks, those overdue, and those
+// due this week, using the same filters as agenda. today is the current
+// calendar day at midnight UTC (see date.Today).
+func summarizeDue(tasks []*task.Task, today time.Time) dueSummary {
+	todayStr := today.Format(dueDateLayout)
+	return dueSummary{
+		Open:    len(filterTasks(tasks, taskFilter{})),
+		Overdue: len(filterTasks(tasks, taskFilter{Overdue: true, Today: todayStr})),
+		DueThisWeek: len(filterTasks(tasks, taskFilter{
+			DueAfter:  todayStr,
+			DueBefore: today.AddDate(0, 0, summaryWeekDays-1).Format(dueDateLayout),
+		})),
+	}
+}
+
+// String formats s as "3 open, 1 overdue, 2 due this week".
+func (s dueSummary) String() string {
+	return fmt.Sprintf("%d open, %d overdue, %d due this week", s.Open, s.Overdue, s.DueThisWeek)
+}
+
+// taskGroup is one header and the tasks listed under it, such as a date in
+// the agenda or a project in list --group-by.
+type taskGroup struct {
+	Header string
+	Tasks  []*task.Task
+}
+
+// groupAgenda sorts tasks by due date and groups them under Overdue, Today,
+// Tomorrow, and then one header per later date. today is the current
+// calendar day at midnight UTC, matching how due_at is stored.
+func groupAgenda(tasks []*task.Task, today time.Time, dateLayout string) []taskGroup {
+	sorted := append([]*task.Task(nil), tasks...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].DueAt.Before(*sorted[j].DueAt)
+	})
+
+	todayStr := today.Format(dueDateLayout)
+	tomorrowStr := today.AddDate(0, 0, 1).Format(dueDateLayout)
+
+	var groups []taskGroup
+	for _, t := range sorted {
+		var header string
+		switch day := t.DueAt.UTC().Format(dueDateLayout); {
+		case day < todayStr:
+			header = "Overdue"
+		case day == todayStr:
+			header = "Today"
+		case day == tomorrowStr:
+			header = "Tomorrow"
+		default:
+			header = t.DueAt.UTC().Format("Mon " + dateLayout)
+		}
+
+		if len(groups) == 0 || groups[len(groups)-1].Header != header {
+			groups = append(groups, taskGroup{Header: header})
+		}
+		groups[len(groups)-1].Tasks = append(groups[len(groups)-1].Tasks, t)
+	}
+	return groups
+}
+
+// displayAgenda prints each agenda group as a header followed by list lines.
+func displayAgenda(out io.Writer, tasks []*task.Task, today time.Time, opts displayOptions) {
+	displayTaskGroups(out, groupAgenda(tasks, today, opts.DateLayout), opts)
+}
+
+func agendaUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s agenda [--days <n>] [--ics]
+
+Lists open tasks due within the next n days, grouped under Overdue, Today,
+Tomorrow, and then each later date. Days are computed in the timezone config
+key. Tasks without a due date are left out.
+
+With --ics the same tasks are printed as an iCalendar feed of all-day
+events on their due dates, to import just the near-term schedule. Use
+'%s export ics' for every task as to-dos.
+
+Flags:
+  --days <n>    how many days ahead to include (default 7; 0 means today)
+  --ics         print iCalendar (VEVENT) instead of the grouped list
+
+Example:
+  %s agenda --days 14 --ics > agenda.ics
+
+`, app, app, app)
+}
+
+func todayUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s today
+
+Lists open tasks that are overdue or due today. Same as '%s agenda --days 0'.
+
+`, app, app)
+}
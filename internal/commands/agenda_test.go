@@ -0,0 +1,145 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sjatkinson/threadkeeper/internal/date"
+	"github.com/sjatkinson/threadkeeper/internal/task"
+)
+
+// addAgendaTasks adds tasks due around clock's day (Tuesday 2026-03-10):
+// "late" (overdue), "now", "soon", "friday", "far" (two weeks out) and
+// "undated".
+func addAgendaTasks(t *testing.T, clock date.Clock) {
+	t.Helper()
+	for _, tt := range []struct{ due, title string }{
+		{"2026-03-08", "late"},
+		{"2026-03-10", "now"},
+		{"2026-03-11", "soon"},
+		{"2026-03-13", "friday"},
+		{"2026-03-25", "far"},
+		{"", "undated"},
+	} {
+		args := []string{tt.title}
+		if tt.due != "" {
+			args = []string{"--due", tt.due, tt.title}
+		}
+		ctx, _, errOut := newTestContext()
+		ctx.Clock = clock
+		if code := RunAdd(args, ctx); code != 0 {
+			t.Fatalf("RunAdd(%v) exit code = %d, stderr: %s", args, code, errOut.String())
+		}
+	}
+}
+
+func TestRunAgenda(t *testing.T) {
+	setupWorkspace(t)
+
+	clock := testClock()
+	addAgendaTasks(t, clock)
+
+	ctx, out, errOut := newTestContext()
+	ctx.Clock = clock
+	if code := RunAgenda(nil, ctx); code != 0 {
+		t.Fatalf("RunAgenda() exit code = %d, stderr: %s", code, errOut.String())
+	}
+	got := out.String()
+	order := []string{"Overdue:", "late", "Today:", "now", "Tomorrow:", "soon", "Fri 2026-03-13:", "friday"}
+	pos := 0
+	for _, want := range order {
+		i := strings.Index(got[pos:], want)
+		if i < 0 {
+			t.Fatalf("agenda output missing %q after position %d:\n%s", want, pos, got)
+		}
+		pos += i + len(want)
+	}
+	for _, absent := range []string{"far", "undated"} {
+		if strings.Contains(got, absent) {
+			t.Errorf("agenda output contains %q:\n%s", absent, got)
+		}
+	}
+
+	ctx, out, _ = newTestContext()
+	ctx.Clock = clock
+	if code := RunToday(nil, ctx); code != 0 {
+		t.Fatalf("RunToday() exit code = %d", code)
+	}
+	if got := out.String(); !strings.Contains(got, "late") || !strings.Contains(got, "now") || strings.Contains(got, "soon") {
+		t.Errorf("today output = %q, want only overdue and due today", got)
+	}
+
+	ctx, _, _ = newTestContext()
+	if code := RunAgenda([]string{"--days", "-1"}, ctx); code != 2 {
+		t.Errorf("RunAgenda(--days -1) exit code = %d, want 2", code)
+	}
+}
+
+func TestRunAgenda_ICS(t *testing.T) {
+	setupWorkspace(t)
+	clock := testClock()
+	addAgendaTasks(t, clock)
+
+	ctx, out, errOut := newTestContext()
+	ctx.Clock = clock
+	if code := RunAgenda([]string{"--ics"}, ctx); code != 0 {
+		t.Fatalf("RunAgenda(--ics) exit code = %d, stderr: %s", code, errOut.String())
+	}
+	got := out.String()
+
+	// One event per task in the text agenda, soonest first
+	var summaries []string
+	for _, line := range strings.Split(got, "\r\n") {
+		if s, ok := strings.CutPrefix(line, "SUMMARY:"); ok {
+			summaries = append(summaries, s)
+		}
+	}
+	if want := "late,now,soon,friday"; strings.Join(summaries, ",") != want {
+		t.Errorf("event summaries = %v, want %s", summaries, want)
+	}
+	if n := strings.Count(got, "BEGIN:VEVENT"); n != 4 || strings.Contains(got, "VTODO") {
+		t.Errorf("got %d VEVENTs in:\n%s\nwant 4 and no VTODO", n, got)
+	}
+	if want := "DTSTART;VALUE=DATE:20260311\r\nDTEND;VALUE=DATE:20260312\r\n"; !strings.Contains(got, want) {
+		t.Errorf("agenda --ics output missing %q:\n%s", want, got)
+	}
+	if strings.Contains(got, "STATUS:") {
+		t.Errorf("agenda --ics output has a to-do STATUS:\n%s", got)
+	}
+
+	// --days narrows the window the same way
+	ctx, out, _ = newTestContext()
+	ctx.Clock = clock
+	if code := RunAgenda([]string{"--days", "0", "--ics"}, ctx); code != 0 {
+		t.Fatalf("RunAgenda(--days 0 --ics) exit code = %d", code)
+	}
+	if n := strings.Count(out.String(), "BEGIN:VEVENT"); n != 2 {
+		t.Errorf("agenda --days 0 --ics has %d events, want 2 (late and now)", n)
+	}
+}
+
+func TestSummarizeDue(t *testing.T) {
+	today := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	due := func(days int) *time.Time {
+		d := today.AddDate(0, 0, days)
+		return &d
+	}
+	tasks := []*task.Task{
+		{ID: "late", Status: task.StatusOpen, DueAt: due(-1)},
+		{ID: "now", Status: task.StatusOpen, DueAt: due(0)},
+		{ID: "sixth", Status: task.StatusOpen, DueAt: due(6)},
+		{ID: "next-week", Status: task.StatusOpen, DueAt: due(7)},
+		{ID: "undated", Status: task.StatusOpen},
+		{ID: "done-late", Status: task.StatusDone, DueAt: due(-3)},
+	}
+
+	got := summarizeDue(tasks, today)
+	want := dueSummary{Open: 5, Overdue: 1, DueThisWeek: 2}
+	if got != want {
+		t.Errorf("summarizeDue() = %+v, want %+v", got, want)
+	}
+	if s := got.String(); s != "5 open, 1 overdue, 2 due this week" {
+		t.Errorf("String() = %q", s)
+	}
+}
@@ -4,10 +4,8 @@ import (
 	"flag"
 	"fmt"
 	"os"
-	"time"
 
 	"github.com/sjatkinson/threadkeeper/internal/config"
-	"github.com/sjatkinson/threadkeeper/internal/store"
 	"github.com/sjatkinson/threadkeeper/internal/task"
 )
 
@@ -18,6 +16,9 @@ func RunArchive(args []string, ctx CommandContext) int {
 		_, _ = fmt.Fprintln(ctx.Err, archiveUsage(ctx.AppName))
 	}
 
+	var sel bulkSelection
+	sel.register(fs)
+
 	if err := fs.Parse(args); err != nil {
 		_, _ = fmt.Fprintln(ctx.Err)
 		_, _ = fmt.Fprintln(ctx.Err, archiveUsage(ctx.AppName))
@@ -25,13 +26,17 @@ func RunArchive(args []string, ctx CommandContext) int {
 	}
 
 	ids := fs.Args()
-	if len(ids) == 0 {
-		_, _ = fmt.Fprintf(ctx.Err, "Error: missing argument: task ID required\n")
+	if len(ids) > 0 && sel.active() {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: task IDs cannot be combined with filter flags\n")
+		return 2
+	}
+	if len(ids) == 0 && !sel.active() {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: missing argument: task ID or filter flag required\n")
 		return 2
 	}
 
 	// Get paths and verify tasks directory exists
-	paths, err := config.GetPaths(ctx.Path)
+	paths, err := config.GetPaths(ctx.workspace())
 	if err != nil {
 		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
 		return 1
@@ -43,10 +48,16 @@ func RunArchive(args []string, ctx CommandContext) int {
 	}
 
 	// Load and resolve tasks, continue on errors
-	st := store.NewFileStore(paths.ThreadsDir)
+	st := newStore(paths)
 	var tasks []*task.Task
 	hasErrors := false
 
+	if sel.active() {
+		var code int
+		if tasks, code = selectBulkTasks(st, ctx, sel, task.StatusArchived, "archive"); code != 0 {
+			return code
+		}
+	}
 	for _, idStr := range ids {
 		t, err := st.ResolveID(idStr)
 		if err != nil {
@@ -58,7 +69,10 @@ func RunArchive(args []string, ctx CommandContext) int {
 	}
 
 	// Archive each task
-	now := time.Now().UTC()
+	archived := 0
+	now := ctx.clock().Now().UTC()
+	rec := newOpRecorder("archive")
+	defer rec.commit(st, paths, ctx)
 	for _, t := range tasks {
 		// Capture short_id before removing it for output
 		sidStr := "?"
@@ -73,8 +87,10 @@ func RunArchive(args []string, ctx CommandContext) int {
 		}
 
 		// Archive the task
+		snap := snapshotTask(t)
 		t.Status = task.StatusArchived
 		t.UpdatedAt = now
+		t.StopTimer(now)
 		// Remove short_id since it's only for open tasks
 		t.ShortID = nil
 
@@ -83,8 +99,19 @@ func RunArchive(args []string, ctx CommandContext) int {
 			hasErrors = true
 			continue
 		}
+		rec.add(t.ID, snap)
+
+		archived++
 
-		_, _ = fmt.Fprintf(ctx.Out, "Archived task %s (%s)\n", sidStr, t.ID)
+		ctx.Success("Archived task %s (%s)\n", sidStr, t.ID)
+	}
+
+	if sel.active() {
+		ctx.Success("Archived %d task(s)\n", archived)
+	}
+
+	if archived > 0 {
+		autoReindex(st, ctx, rec)
 	}
 
 	if hasErrors {
@@ -97,6 +124,18 @@ func RunArchive(args []string, ctx CommandContext) int {
 func archiveUsage(app string) string {
 	return fmt.Sprintf(`Usage:
   %s archive <id> [<id> ...]
+  %s archive [flags]
+
+Instead of IDs, select tasks with the same filters as list. Tasks that are
+already archived are skipped. Changing more than %d tasks this way asks for
+confirmation on a terminal and requires --force elsewhere.
+
+Flags:
+  -a, --all                     select tasks of any status (default: only open)
+  -p, --project <name>          select by project
+  --status <open|done|archived> select by status
+  --tag <tag>                   select by tag (repeat to AND tags)
+  --force                       don't ask for confirmation
 
-`, app)
+`, app, app, BulkConfirmThreshold)
 }
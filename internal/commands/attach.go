@@ -4,11 +4,17 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"html"
+	"io"
+	"mime"
+	"net/http"
+	neturl "net/url"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"time"
 
@@ -42,53 +48,84 @@ type BlobRef struct {
 	Hash string `json:"hash"`
 }
 
-// captureEditorContent opens the user's editor and captures the content.
-// Returns the content bytes, or an error.
-func captureEditorContent() ([]byte, error) {
-	// Determine editor: $TK_EDITOR > $EDITOR > vi
-	editor := os.Getenv("TK_EDITOR")
-	if editor == "" {
-		editor = os.Getenv("EDITOR")
+// noteHeader is the template comment shown when capturing a note.
+const noteHeader = "# Enter your note below. Lines starting with # are ignored.\n# Save and exit to attach, or delete all content to cancel.\n\n"
+
+// errEmptyNote reports that the captured note had no content.
+var errEmptyNote = errors.New("note content is empty; attachment cancelled")
+
+// noteSource says where attach note reads its content from. The zero value
+// means the editor.
+type noteSource struct {
+	Message    string
+	MessageSet bool
+	File       string
+	Stdin      bool
+}
+
+// inputs returns how many non-editor inputs are selected.
+func (s noteSource) inputs() int {
+	n := 0
+	for _, set := range []bool{s.MessageSet, s.File != "", s.Stdin} {
+		if set {
+			n++
+		}
 	}
-	if editor == "" {
-		editor = "vi"
+	return n
+}
+
+// captureNoteContent reads note content from src, falling back to the
+// editor. Returns errEmptyNote if the content is empty or whitespace-only.
+func captureNoteContent(ctx CommandContext, src noteSource) ([]byte, error) {
+	var content []byte
+	switch {
+	case src.MessageSet:
+		content = []byte(src.Message)
+	case src.File != "":
+		data, err := os.ReadFile(src.File)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read note file: %w", err)
+		}
+		content = data
+	case src.Stdin:
+		data, err := io.ReadAll(ctx.stdin())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read note from stdin: %w", err)
+		}
+		content = data
+	default:
+		return captureEditorContent(ctx)
 	}
 
-	// Create temporary file
-	tmpFile, err := os.CreateTemp("", "tk-attach-*.md")
-	if err != nil {
-		return nil, fmt.Errorf("failed to create temp file: %w", err)
+	if strings.TrimSpace(string(content)) == "" {
+		return nil, errEmptyNote
 	}
-	tmpPath := tmpFile.Name()
-	defer os.Remove(tmpPath) // Clean up temp file
+	return content, nil
+}
 
-	// Write optional header comment
-	header := "# Enter your note below. Lines starting with # are ignored.\n# Save and exit to attach, or delete all content to cancel.\n\n"
-	if _, err := tmpFile.WriteString(header); err != nil {
-		tmpFile.Close()
-		return nil, fmt.Errorf("failed to write temp file: %w", err)
+// captureEditorContent opens the user's editor and captures the content.
+// Returns the content bytes, or errEmptyNote if nothing was written.
+func captureEditorContent(ctx CommandContext) ([]byte, error) {
+	content, err := ctx.editor(getEditor(), "tk-attach-*.md").Capture([]byte(noteHeader))
+	if err != nil {
+		return nil, err
 	}
-	tmpFile.Close()
 
-	// Open editor
-	cmd := exec.Command(editor, tmpPath)
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	body := stripNoteHeader(string(content))
 
-	if err := cmd.Run(); err != nil {
-		return nil, fmt.Errorf("editor exited with error: %w", err)
+	// Check if content is empty or whitespace-only
+	if strings.TrimSpace(body) == "" {
+		return nil, errEmptyNote
 	}
 
-	// Read the file content
-	content, err := os.ReadFile(tmpPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read edited file: %w", err)
-	}
+	return []byte(body), nil
+}
 
+// stripNoteHeader removes the template header comment from captured note
+// content, preserving user content that starts with #.
+func stripNoteHeader(content string) string {
 	// Remove initial header comment lines (first few lines starting with #)
-	// This removes the template header but preserves user content that starts with #
-	lines := strings.Split(string(content), "\n")
+	lines := strings.Split(content, "\n")
 	var bodyLines []string
 	headerLinesRemoved := 0
 	for i, line := range lines {
@@ -104,27 +141,58 @@ func captureEditorContent() ([]byte, error) {
 	if headerLinesRemoved > 0 && len(bodyLines) > 0 && strings.TrimSpace(bodyLines[0]) == "" {
 		bodyLines = bodyLines[1:]
 	}
-	body := strings.Join(bodyLines, "\n")
+	return strings.Join(bodyLines, "\n")
+}
 
-	// Check if content is empty or whitespace-only
-	if strings.TrimSpace(body) == "" {
-		return nil, fmt.Errorf("note content is empty; attachment cancelled")
+// noteMediaType detects the media type of note content from its first 512
+// bytes. Plain text is recorded as Markdown, which notes are written in.
+func noteMediaType(content []byte) string {
+	mediaType := http.DetectContentType(content)
+	if strings.HasPrefix(mediaType, "text/plain") {
+		return "text/markdown"
 	}
+	return mediaType
+}
 
-	return []byte(body), nil
+// isImageMediaType reports whether mediaType is an image type.
+func isImageMediaType(mediaType string) bool {
+	return strings.HasPrefix(mediaType, "image/")
 }
 
-// storeBlob stores content as a content-addressed blob and returns the hash and size.
-// Path: <thread-dir>/blobs/sha256/<first2>/<next2>/<hash>
-func storeBlob(threadDir string, content []byte) (string, int64, error) {
+// imageExtensions are the file extensions used for the image types
+// http.DetectContentType recognizes; mime lists several for some.
+var imageExtensions = map[string]string{
+	"image/bmp":    ".bmp",
+	"image/gif":    ".gif",
+	"image/jpeg":   ".jpg",
+	"image/png":    ".png",
+	"image/webp":   ".webp",
+	"image/x-icon": ".ico",
+}
+
+// mediaTypeExtension returns a file extension for mediaType, or "" if none
+// is known.
+func mediaTypeExtension(mediaType string) string {
+	if ext, ok := imageExtensions[mediaType]; ok {
+		return ext
+	}
+	if exts, err := mime.ExtensionsByType(mediaType); err == nil && len(exts) > 0 {
+		return exts[0]
+	}
+	return ""
+}
+
+// storeBlob stores content as a content-addressed blob in the workspace blob
+// store and returns the hash and size. Identical content attached to several
+// threads is stored once.
+// Path: <blobs-dir>/sha256/<first2>/<next2>/<hash>
+func storeBlob(blobsDir string, content []byte) (string, int64, error) {
 	// Compute SHA-256 hash
 	hash := sha256.Sum256(content)
 	hashHex := hex.EncodeToString(hash[:])
 
-	// Build nested path: blobs/sha256/<first2>/<next2>/<hash>
-	first2 := hashHex[0:2]
-	next2 := hashHex[2:4]
-	blobPath := filepath.Join(threadDir, "blobs", "sha256", first2, next2, hashHex)
+	// Build nested path: sha256/<first2>/<next2>/<hash>
+	blobPath := blobPathIn(blobsDir, BlobRef{Algo: "sha256", Hash: hashHex})
 
 	// Check if blob already exists (idempotent)
 	if _, err := os.Stat(blobPath); err == nil {
@@ -149,9 +217,17 @@ func storeBlob(threadDir string, content []byte) (string, int64, error) {
 	return hashHex, int64(len(content)), nil
 }
 
-// appendAttachmentEvent appends an attachment event to attachments.jsonl.
+// appendAttachmentEvent appends an attachment event to attachments.jsonl
+// while holding the workspace lock.
 // Returns error if write fails.
-func appendAttachmentEvent(threadDir string, event AttachmentEvent) error {
+func appendAttachmentEvent(st *store.FileStore, threadDir string, event AttachmentEvent) error {
+	return st.WithLock(func() error {
+		return writeAttachmentEvent(threadDir, event)
+	})
+}
+
+// writeAttachmentEvent appends one JSON line to attachments.jsonl.
+func writeAttachmentEvent(threadDir string, event AttachmentEvent) error {
 	attachmentsPath := filepath.Join(threadDir, "attachments.jsonl")
 
 	// Open file in append mode
@@ -180,21 +256,20 @@ func appendAttachmentEvent(threadDir string, event AttachmentEvent) error {
 
 // updateThreadAttachmentsLog updates thread.json to reference attachments.jsonl.
 // Uses atomic write (temp file + rename). Loads existing task, updates it, and saves.
-func updateThreadAttachmentsLog(threadsDir, threadID string) error {
+func updateThreadAttachmentsLog(st *store.FileStore, threadID string, now time.Time) error {
 	// Load existing task
-	st := store.NewFileStore(threadsDir)
 	t, err := st.GetByID(threadID)
 	if err != nil {
 		return fmt.Errorf("failed to load thread: %w", err)
 	}
 
 	// Update UpdatedAt timestamp
-	t.UpdatedAt = time.Now().UTC()
+	t.UpdatedAt = now
 
 	// Save task (this will write thread.json with all fields preserved)
 	// Note: We need to add attachments_log field, but Task struct doesn't have it yet.
 	// For now, we'll update it via JSON manipulation to preserve backward compatibility.
-	threadPath := store.ThreadFilePath(threadsDir, threadID)
+	threadPath := st.ThreadFile(threadID)
 
 	// Read existing thread.json to preserve all fields
 	data, err := os.ReadFile(threadPath)
@@ -262,6 +337,9 @@ func RunAttach(args []string, ctx CommandContext) int {
 	}
 
 	attachType := args[0]
+	if attachType == "export" {
+		return runAttachExport(args[1:], ctx)
+	}
 	if attachType != "note" && attachType != "link" {
 		_, _ = fmt.Fprintf(ctx.Err, "Error: invalid attachment type %q (must be 'note' or 'link')\n", attachType)
 		_, _ = fmt.Fprintf(ctx.Err, "\n")
@@ -278,14 +356,24 @@ func RunAttach(args []string, ctx CommandContext) int {
 	}
 
 	var (
-		id    string
-		url   string
-		label string
+		id         string
+		url        string
+		label      string
+		name       string
+		src        noteSource
+		fetchTitle bool
 	)
 	fs.StringVar(&id, "id", "", "thread handle or canonical id")
+	if attachType == "note" {
+		fs.StringVar(&name, "name", "", "attachment name")
+		fs.StringVar(&src.Message, "message", "", "note content (skips the editor)")
+		fs.StringVar(&src.File, "file", "", "read note content from a file")
+		fs.BoolVar(&src.Stdin, "stdin", false, "read note content from standard input")
+	}
 	if attachType == "link" {
 		fs.StringVar(&url, "url", "", "URL to attach")
 		fs.StringVar(&label, "label", "", "label for link")
+		fs.BoolVar(&fetchTitle, "fetch-title", false, "name the link after the page title when --label is not given")
 	}
 
 	if err := fs.Parse(subArgs); err != nil {
@@ -317,7 +405,25 @@ func RunAttach(args []string, ctx CommandContext) int {
 	}
 
 	if attachType == "note" {
-		return runAttachNote(id, ctx.Path, ctx)
+		nameSet := false
+		fs.Visit(func(f *flag.Flag) {
+			switch f.Name {
+			case "message":
+				src.MessageSet = true
+			case "name":
+				nameSet = true
+			}
+		})
+		name = strings.TrimSpace(name)
+		if nameSet && name == "" {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: --name must not be empty\n")
+			return 2
+		}
+		if src.inputs() > 1 {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: --message, --file and --stdin are mutually exclusive\n")
+			return 2
+		}
+		return runAttachNote(id, name, ctx.workspace(), src, ctx)
 	}
 
 	// Link attachment
@@ -327,10 +433,35 @@ func RunAttach(args []string, ctx CommandContext) int {
 		return 2
 	}
 
-	return runAttachLink(id, url, label, ctx.Path, ctx)
+	normalized, defaultName, err := normalizeLinkURL(url)
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: invalid --url: %v\n", err)
+		return 2
+	}
+
+	// --fetch-title, given either way, wins over attach_fetch_title
+	fetchTitleSet := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "fetch-title" {
+			fetchTitleSet = true
+		}
+	})
+	if !fetchTitleSet {
+		fetchTitle, _ = config.LoadAttachFetchTitle()
+	}
+	if fetchTitle && label == "" && isHTTPURL(normalized) {
+		client := &http.Client{Timeout: linkTitleTimeout}
+		if title, err := fetchLinkTitle(client, normalized); err == nil {
+			defaultName = title
+		} else if ctx.Verbose {
+			_, _ = fmt.Fprintf(ctx.Err, "Warning: could not fetch title for %s: %v\n", normalized, err)
+		}
+	}
+
+	return runAttachLink(id, normalized, label, defaultName, ctx.workspace(), ctx)
 }
 
-func runAttachNote(threadIDStr, path string, ctx CommandContext) int {
+func runAttachNote(threadIDStr, name, path string, src noteSource, ctx CommandContext) int {
 
 	// Get paths and verify threads directory exists
 	paths, err := config.GetPaths(path)
@@ -345,7 +476,7 @@ func runAttachNote(threadIDStr, path string, ctx CommandContext) int {
 	}
 
 	// Resolve thread ID
-	st := store.NewFileStore(paths.ThreadsDir)
+	st := newStore(paths)
 	t, err := st.ResolveID(threadIDStr)
 	if err != nil {
 		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
@@ -353,19 +484,19 @@ func runAttachNote(threadIDStr, path string, ctx CommandContext) int {
 	}
 
 	// Get thread directory path
-	threadDir := store.ThreadPath(paths.ThreadsDir, t.ID)
+	threadDir := st.ThreadDir(t.ID)
 
 	// Verify thread directory and thread.json exist
-	threadJSONPath := store.ThreadFilePath(paths.ThreadsDir, t.ID)
+	threadJSONPath := st.ThreadFile(t.ID)
 	if _, err := os.Stat(threadJSONPath); err != nil {
 		_, _ = fmt.Fprintf(ctx.Err, "Error: thread %s not found\n", t.ID)
 		return 1
 	}
 
-	// Capture content from editor
-	content, err := captureEditorContent()
+	// Capture content from the flags or the editor
+	content, err := captureNoteContent(ctx, src)
 	if err != nil {
-		if err.Error() == "note content is empty; attachment cancelled" {
+		if errors.Is(err, errEmptyNote) {
 			_, _ = fmt.Fprintf(ctx.Err, "Note content is empty; attachment cancelled\n")
 			return 0 // Not an error, user cancelled
 		}
@@ -373,8 +504,15 @@ func runAttachNote(threadIDStr, path string, ctx CommandContext) int {
 		return 1
 	}
 
+	// Refuse oversized notes before anything is written
+	limit, _ := config.LoadMaxAttachmentBytes()
+	if limit > 0 && int64(len(content)) > limit {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: note is %d bytes, over the %s limit of %d bytes; raise it in config.toml, or set it to 0 for no limit\n", len(content), config.MaxAttachmentKey, limit)
+		return 1
+	}
+
 	// Store blob
-	hashHex, size, err := storeBlob(threadDir, content)
+	hashHex, size, err := storeBlob(paths.BlobsDir, content)
 	if err != nil {
 		_, _ = fmt.Fprintf(ctx.Err, "Error: failed to store blob: %v\n", err)
 		return 1
@@ -388,8 +526,10 @@ func runAttachNote(threadIDStr, path string, ctx CommandContext) int {
 	}
 
 	// Generate default name: note-YYYYMMDD-HHMMSS
-	now := time.Now().UTC()
-	name := fmt.Sprintf("note-%s", now.Format("20060102-150405"))
+	now := ctx.clock().Now().UTC()
+	if name == "" {
+		name = fmt.Sprintf("note-%s", now.Format("20060102-150405"))
+	}
 
 	// Create attachment event
 	event := AttachmentEvent{
@@ -399,7 +539,7 @@ func runAttachNote(threadIDStr, path string, ctx CommandContext) int {
 			AttID:     attID,
 			Kind:      "note",
 			Name:      name,
-			MediaType: "text/markdown",
+			MediaType: noteMediaType(content),
 			Blob: &BlobRef{
 				Algo: "sha256",
 				Hash: hashHex,
@@ -409,24 +549,110 @@ func runAttachNote(threadIDStr, path string, ctx CommandContext) int {
 	}
 
 	// Append to attachments.jsonl
-	if err := appendAttachmentEvent(threadDir, event); err != nil {
+	if err := appendAttachmentEvent(st, threadDir, event); err != nil {
 		_, _ = fmt.Fprintf(ctx.Err, "Error: failed to append attachment event: %v\n", err)
 		return 1
 	}
 
 	// Update thread.json to reference attachments.jsonl
-	if err := updateThreadAttachmentsLog(paths.ThreadsDir, t.ID); err != nil {
+	if err := updateThreadAttachmentsLog(st, t.ID, now); err != nil {
 		_, _ = fmt.Fprintf(ctx.Err, "Error: failed to update thread.json: %v\n", err)
 		return 1
 	}
 
 	// Print success message
-	_, _ = fmt.Fprintf(ctx.Out, "Attached note %s to %s (sha256:%s)\n", attID, t.ID, hashHex)
+	ctx.Success("Attached note %s to %s (sha256:%s)\n", attID, t.ID, hashHex)
 
 	return 0
 }
 
-func runAttachLink(threadIDStr, url, label, path string, ctx CommandContext) int {
+// normalizeLinkURL validates a link URL and returns it normalized, along with
+// a default attachment name derived from it. http and https URLs need a host,
+// mailto an address and file a path.
+func normalizeLinkURL(raw string) (string, string, error) {
+	raw = strings.TrimSpace(raw)
+	u, err := neturl.Parse(raw)
+	if err != nil {
+		return "", "", err
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	var name string
+	switch u.Scheme {
+	case "http", "https":
+		if u.Host == "" {
+			return "", "", fmt.Errorf("%q has no host", raw)
+		}
+		u.Host = strings.ToLower(u.Host)
+		name = u.Hostname()
+	case "mailto":
+		if u.Opaque == "" {
+			return "", "", fmt.Errorf("%q has no address", raw)
+		}
+		name = u.Opaque
+	case "file":
+		if u.Path == "" {
+			return "", "", fmt.Errorf("%q has no path", raw)
+		}
+		name = filepath.Base(u.Path)
+	case "":
+		return "", "", fmt.Errorf("%q has no scheme (want http, https, mailto or file)", raw)
+	default:
+		return "", "", fmt.Errorf("unsupported scheme %q (want http, https, mailto or file)", u.Scheme)
+	}
+	return u.String(), name, nil
+}
+
+// linkTitleTimeout bounds the page request made by attach link --fetch-title.
+const linkTitleTimeout = 5 * time.Second
+
+// maxTitleScanBytes is how much of a page fetchLinkTitle reads looking for
+// its <title>.
+const maxTitleScanBytes = 64 << 10
+
+// maxLinkTitleRunes caps the length of a fetched title used as a link name.
+const maxLinkTitleRunes = 120
+
+// titlePattern matches an HTML <title> element and captures its text.
+var titlePattern = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+
+// fetchLinkTitle requests an http(s) URL and returns the text of the page's
+// <title>, unescaped, with whitespace collapsed and capped at
+// maxLinkTitleRunes. It fails on an error status, a non-HTML response or a
+// page without a non-empty title.
+func fetchLinkTitle(client *http.Client, url string) (string, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode >= 400 {
+		return "", fmt.Errorf("server returned %s", resp.Status)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "" && !strings.Contains(strings.ToLower(ct), "html") {
+		return "", fmt.Errorf("not an HTML page (%s)", ct)
+	}
+
+	page, err := io.ReadAll(io.LimitReader(resp.Body, maxTitleScanBytes))
+	if err != nil {
+		return "", err
+	}
+	m := titlePattern.FindSubmatch(page)
+	if m == nil {
+		return "", errors.New("page has no title")
+	}
+	title := strings.Join(strings.Fields(html.UnescapeString(string(m[1]))), " ")
+	if title == "" {
+		return "", errors.New("page has an empty title")
+	}
+	if r := []rune(title); len(r) > maxLinkTitleRunes {
+		title = string(r[:maxLinkTitleRunes])
+	}
+	return title, nil
+}
+
+func runAttachLink(threadIDStr, url, label, defaultName, path string, ctx CommandContext) int {
 	// Get paths and verify threads directory exists
 	paths, err := config.GetPaths(path)
 	if err != nil {
@@ -440,7 +666,7 @@ func runAttachLink(threadIDStr, url, label, path string, ctx CommandContext) int
 	}
 
 	// Resolve thread ID
-	st := store.NewFileStore(paths.ThreadsDir)
+	st := newStore(paths)
 	t, err := st.ResolveID(threadIDStr)
 	if err != nil {
 		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
@@ -448,10 +674,10 @@ func runAttachLink(threadIDStr, url, label, path string, ctx CommandContext) int
 	}
 
 	// Get thread directory path
-	threadDir := store.ThreadPath(paths.ThreadsDir, t.ID)
+	threadDir := st.ThreadDir(t.ID)
 
 	// Verify thread directory and thread.json exist
-	threadJSONPath := store.ThreadFilePath(paths.ThreadsDir, t.ID)
+	threadJSONPath := st.ThreadFile(t.ID)
 	if _, err := os.Stat(threadJSONPath); err != nil {
 		_, _ = fmt.Fprintf(ctx.Err, "Error: thread %s not found\n", t.ID)
 		return 1
@@ -465,12 +691,15 @@ func runAttachLink(threadIDStr, url, label, path string, ctx CommandContext) int
 	}
 
 	// Generate default name from URL or label
-	now := time.Now().UTC()
+	now := ctx.clock().Now().UTC()
 	var name string
-	if label != "" {
+	switch {
+	case label != "":
 		name = label
-	} else {
-		// Use URL hostname as name, or fallback to link-YYYYMMDD-HHMMSS
+	case defaultName != "":
+		name = defaultName
+	default:
+		// Fallback to link-YYYYMMDD-HHMMSS
 		name = fmt.Sprintf("link-%s", now.Format("20060102-150405"))
 	}
 
@@ -488,22 +717,22 @@ func runAttachLink(threadIDStr, url, label, path string, ctx CommandContext) int
 	}
 
 	// Append to attachments.jsonl
-	if err := appendAttachmentEvent(threadDir, event); err != nil {
+	if err := appendAttachmentEvent(st, threadDir, event); err != nil {
 		_, _ = fmt.Fprintf(ctx.Err, "Error: failed to append attachment event: %v\n", err)
 		return 1
 	}
 
 	// Update thread.json to reference attachments.jsonl
-	if err := updateThreadAttachmentsLog(paths.ThreadsDir, t.ID); err != nil {
+	if err := updateThreadAttachmentsLog(st, t.ID, now); err != nil {
 		_, _ = fmt.Fprintf(ctx.Err, "Error: failed to update thread.json: %v\n", err)
 		return 1
 	}
 
 	// Print success message
 	if label != "" {
-		_, _ = fmt.Fprintf(ctx.Out, "Attached link %s to %s: [%s] %s\n", attID, t.ID, label, url)
+		ctx.Success("Attached link %s to %s: [%s] %s\n", attID, t.ID, label, url)
 	} else {
-		_, _ = fmt.Fprintf(ctx.Out, "Attached link %s to %s: %s\n", attID, t.ID, url)
+		ctx.Success("Attached link %s to %s: %s\n", attID, t.ID, url)
 	}
 
 	return 0
@@ -511,8 +740,9 @@ func runAttachLink(threadIDStr, url, label, path string, ctx CommandContext) int
 
 func attachUsage(app string) string {
 	return fmt.Sprintf(`Usage:
-  %s attach note --id <thread-id>
-  %s attach link --id <thread-id> --url <url> [--label <label>]
+  %s attach note --id <thread-id> [--name <name>] [--message <text> | --file <path> | --stdin]
+  %s attach link --id <thread-id> --url <url> [--label <label> | --fetch-title]
+  %s attach export --id <thread-id> (--att <index> | --att-id <id>) --out <file> [--url-ok]
 
 Attach context to a thread.
 
@@ -520,19 +750,51 @@ Types:
   note   Open editor, store content-addressed blob, record in attachments.jsonl.
   link   Record URL (and optional label) in attachments.jsonl.
 
+Export:
+  Copies a note's content out of the blob store unchanged. --out - writes
+  it to stdout. A link has nothing to export; with --url-ok its URL is
+  printed instead of failing.
+
 Flags:
   --id <id>       thread handle or canonical id
-  --url <url>     URL to attach [link only]
+  --url <url>     URL to attach: http, https, mailto or file [link only]
   --label <text>  label for link (pr, slack, jira, doc, etc.) [link only]
+  --fetch-title   without --label, name the link after the page's <title>
+                  (default: attach_fetch_title config key) [link only]
+  --name <name>   attachment name (default: note-YYYYMMDD-HHMMSS) [note only]
+  --message <t>   use <t> as the note content instead of opening an editor [note only]
+  --file <path>   read the note content from a file [note only]
+  --stdin         read the note content from standard input [note only]
+  --att <index>   attachment index (1-based, from 'attachments' output) [export only]
+  --att-id <id>   attachment ID (alternative to --att) [export only]
+  --out <file>    file to write the note to, or - for stdout [export only]
+  --url-ok        print a link's URL instead of failing [export only]
+
+--message, --file and --stdin are mutually exclusive. Empty note content
+cancels the attachment, however it was provided.
+
+Notes larger than the max_attachment_bytes config key (default 10 MiB, 0
+for no limit) are refused before anything is stored.
+
+--fetch-title makes one request to the link, waiting at most 5s. If it
+fails, the link is named after its host as usual.
 
 Environment variables:
-  TK_EDITOR       editor to use (defaults to $EDITOR, then vi) [note only]
-  EDITOR          editor to use (if TK_EDITOR not set) [note only]
+  TK_EDITOR       editor to use [note only]
+  VISUAL          editor to use if TK_EDITOR is not set [note only]
+  EDITOR          editor to use if neither is set; then the editor
+                  config key, then vi [note only]
+
+GUI editors must wait until the file is closed, or the note comes back
+empty. tk adds the wait flag for code, subl and similar editors when it is
+missing; setting it yourself is clearer: TK_EDITOR="code --wait".
 
 Examples:
   %s attach note --id 1
+  kubectl logs pod/web | %s attach note --id 1 --stdin
   %s attach link --id 1 --url https://example.com/pr/123 --label pr
   %s attach link --id 1 --url https://slack.com/archives/C123
+  %s attach export --id 1 --att 2 --out pod.log
 
-`, app, app, app, app, app)
+`, app, app, app, app, app, app, app, app)
 }
@@ -4,6 +4,9 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
@@ -22,7 +25,7 @@ func TestStoreBlob(t *testing.T) {
 	defer os.RemoveAll(tmpDir)
 
 	content := []byte("Test note content\nLine 2")
-	hashHex, size, err := storeBlob(tmpDir, content)
+	hashHex, size, err := storeBlob(filepath.Join(tmpDir, "blobs"), content)
 	if err != nil {
 		t.Fatalf("storeBlob() error = %v", err)
 	}
@@ -48,7 +51,7 @@ func TestStoreBlob(t *testing.T) {
 	}
 
 	// Verify idempotency - storing again should return same hash and size
-	hashHex2, size2, err := storeBlob(tmpDir, content)
+	hashHex2, size2, err := storeBlob(filepath.Join(tmpDir, "blobs"), content)
 	if err != nil {
 		t.Fatalf("storeBlob() second call error = %v", err)
 	}
@@ -83,8 +86,10 @@ func TestAppendAttachmentEvent(t *testing.T) {
 		},
 	}
 
+	st := store.NewFileStore(filepath.Join(tmpDir, "threads"))
+
 	// First append
-	if err := appendAttachmentEvent(tmpDir, event); err != nil {
+	if err := appendAttachmentEvent(st, tmpDir, event); err != nil {
 		t.Fatalf("appendAttachmentEvent() error = %v", err)
 	}
 
@@ -133,7 +138,7 @@ func TestAppendAttachmentEvent(t *testing.T) {
 	// Second append (verify append mode works)
 	event2 := event
 	event2.Att.AttID = "01TEST987654321"
-	if err := appendAttachmentEvent(tmpDir, event2); err != nil {
+	if err := appendAttachmentEvent(st, tmpDir, event2); err != nil {
 		t.Fatalf("appendAttachmentEvent() second call error = %v", err)
 	}
 
@@ -195,7 +200,7 @@ func TestUpdateThreadAttachmentsLog(t *testing.T) {
 	}
 
 	// Update attachments log
-	if err := updateThreadAttachmentsLog(threadsDir, threadID); err != nil {
+	if err := updateThreadAttachmentsLog(st, threadID, time.Now().UTC()); err != nil {
 		t.Fatalf("updateThreadAttachmentsLog() error = %v", err)
 	}
 
@@ -250,3 +255,302 @@ func TestBlobPathComputation(t *testing.T) {
 		t.Errorf("Blob path does not follow expected structure: %v", expectedPath)
 	}
 }
+
+func TestRunAttachNoteInputs(t *testing.T) {
+	tmpDir := setupWorkspace(t)
+	tk := addAndLoad(t, []string{"notes"})
+
+	notePath := filepath.Join(t.TempDir(), "note.md")
+	if err := os.WriteFile(notePath, []byte("from file\n"), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		args  []string
+		stdin string
+		code  int
+		blobs int
+	}{
+		{"message", []string{"--message", "from flag"}, "", 0, 1},
+		{"file", []string{"--file", notePath}, "", 0, 2},
+		{"stdin", []string{"--stdin"}, "from stdin\n", 0, 3},
+		{"empty stdin cancels", []string{"--stdin"}, "  \n", 0, 3},
+		{"empty message cancels", []string{"--message", ""}, "", 0, 3},
+		{"exclusive", []string{"--message", "x", "--stdin"}, "", 2, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _, errOut := newTestContext()
+			ctx.Stdin = strings.NewReader(tt.stdin)
+			ctx.Editor = &fakeEditor{err: errors.New("editor must not be opened")}
+			args := append([]string{"note", "--id", tk.ID}, tt.args...)
+			if code := RunAttach(args, ctx); code != tt.code {
+				t.Fatalf("RunAttach(%v) exit code = %d, want %d, stderr: %s", tt.args, code, tt.code, errOut.String())
+			}
+			blobs, _ := filepath.Glob(filepath.Join(tmpDir, "blobs", "sha256", "*", "*", "*"))
+			if len(blobs) != tt.blobs {
+				t.Errorf("blob count = %d, want %d", len(blobs), tt.blobs)
+			}
+		})
+	}
+}
+
+func TestRunAttachNoteName(t *testing.T) {
+	tmpDir := setupWorkspace(t)
+	tk := addAndLoad(t, []string{"notes"})
+
+	ctx, _, errOut := newTestContext()
+	if code := RunAttach([]string{"note", "--id", tk.ID, "--name", "  incident-postmortem ", "--message", "body"}, ctx); code != 0 {
+		t.Fatalf("RunAttach() exit code = %d, stderr: %s", code, errOut.String())
+	}
+
+	threadDirs, _ := filepath.Glob(filepath.Join(tmpDir, "threads", "*", tk.ID))
+	if len(threadDirs) != 1 {
+		t.Fatalf("thread dirs = %v, want one", threadDirs)
+	}
+	events, err := loadAttachments(threadDirs[0])
+	if err != nil || len(events) != 1 {
+		t.Fatalf("loadAttachments() = %v, err = %v", events, err)
+	}
+	if got := events[0].Att.Name; got != "incident-postmortem" {
+		t.Errorf("attachment name = %q, want %q", got, "incident-postmortem")
+	}
+
+	ctx, _, _ = newTestContext()
+	if code := RunAttach([]string{"note", "--id", tk.ID, "--name", "   ", "--message", "body"}, ctx); code != 2 {
+		t.Errorf("RunAttach() with blank --name exit code = %d, want 2", code)
+	}
+}
+
+func TestNormalizeLinkURL(t *testing.T) {
+	tests := []struct {
+		raw      string
+		wantURL  string
+		wantName string
+		wantErr  bool
+	}{
+		{"  https://Example.com/pr/123 ", "https://example.com/pr/123", "example.com", false},
+		{"HTTP://example.com:8080/x", "http://example.com:8080/x", "example.com", false},
+		{"mailto:alice@example.com", "mailto:alice@example.com", "alice@example.com", false},
+		{"file:///home/me/notes.txt", "file:///home/me/notes.txt", "notes.txt", false},
+		{"htp://example.com", "", "", true},
+		{"https://", "", "", true},
+		{"example.com/path", "", "", true},
+		{"mailto:", "", "", true},
+		{"", "", "", true},
+	}
+	for _, tt := range tests {
+		gotURL, gotName, err := normalizeLinkURL(tt.raw)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("normalizeLinkURL(%q) error = %v, wantErr %v", tt.raw, err, tt.wantErr)
+			continue
+		}
+		if gotURL != tt.wantURL || gotName != tt.wantName {
+			t.Errorf("normalizeLinkURL(%q) = (%q, %q), want (%q, %q)", tt.raw, gotURL, gotName, tt.wantURL, tt.wantName)
+		}
+	}
+}
+
+func TestRunAttachExport(t *testing.T) {
+	ws := setupWorkspace(t)
+	ctx, _, errOut := newTestContext()
+	if code := RunAdd([]string{"debug crash"}, ctx); code != 0 {
+		t.Fatalf("RunAdd() exit code = %d, stderr: %s", code, errOut.String())
+	}
+
+	content := "panic: nil map\r\n\tat main.go:12\n"
+	src := filepath.Join(ws, "crash.log")
+	if err := os.WriteFile(src, []byte(content), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	for _, args := range [][]string{
+		{"note", "--id", "1", "--name", "crash.log", "--file", src},
+		{"link", "--id", "1", "--url", "https://example.com/issue/9"},
+	} {
+		ctx, _, errOut := newTestContext()
+		if code := RunAttach(args, ctx); code != 0 {
+			t.Fatalf("RunAttach(%v) exit code = %d, stderr: %s", args, code, errOut.String())
+		}
+	}
+
+	dst := filepath.Join(ws, "exported.log")
+	ctx, _, errOut = newTestContext()
+	if code := RunAttach([]string{"export", "--id", "1", "--att", "1", "--out", dst}, ctx); code != 0 {
+		t.Fatalf("attach export exit code = %d, stderr: %s", code, errOut.String())
+	}
+	if got, err := os.ReadFile(dst); err != nil || string(got) != content {
+		t.Errorf("exported file = %q, %v; want %q", got, err, content)
+	}
+
+	ctx, out, errOut := newTestContext()
+	if code := RunAttach([]string{"export", "--id", "1", "--att", "1", "--out", "-"}, ctx); code != 0 {
+		t.Fatalf("attach export --out - exit code = %d, stderr: %s", code, errOut.String())
+	}
+	if out.String() != content {
+		t.Errorf("attach export --out - = %q, want %q", out.String(), content)
+	}
+
+	ctx, _, errOut = newTestContext()
+	if code := RunAttach([]string{"export", "--id", "1", "--att", "2", "--out", "-"}, ctx); code != 1 || !strings.Contains(errOut.String(), "is a link") {
+		t.Errorf("attach export of a link = %d, stderr %q; want 1 and a link error", code, errOut.String())
+	}
+	ctx, out, _ = newTestContext()
+	if code := RunAttach([]string{"export", "--id", "1", "--att", "2", "--out", "-", "--url-ok"}, ctx); code != 0 || out.String() != "https://example.com/issue/9\n" {
+		t.Errorf("attach export --url-ok = %d, %q; want the URL", code, out.String())
+	}
+
+	ctx, _, _ = newTestContext()
+	if code := RunAttach([]string{"export", "--id", "1", "--att", "1"}, ctx); code != 2 {
+		t.Errorf("attach export without --out exit code = %d, want 2", code)
+	}
+}
+
+func TestRunAttachNote_ImageMediaType(t *testing.T) {
+	ws := setupWorkspace(t)
+	t.Setenv("TMPDIR", t.TempDir())
+	ctx, _, errOut := newTestContext()
+	if code := RunAdd([]string{"fix layout"}, ctx); code != 0 {
+		t.Fatalf("RunAdd() exit code = %d, stderr: %s", code, errOut.String())
+	}
+
+	png := append([]byte("\x89PNG\r\n\x1a\n"), []byte("not really pixels")...)
+	src := filepath.Join(ws, "screenshot")
+	if err := os.WriteFile(src, png, 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	for _, args := range [][]string{
+		{"note", "--id", "1", "--name", "screenshot", "--file", src},
+		{"note", "--id", "1", "--name", "notes", "--message", "# Findings\n"},
+	} {
+		ctx, _, errOut := newTestContext()
+		if code := RunAttach(args, ctx); code != 0 {
+			t.Fatalf("RunAttach(%v) exit code = %d, stderr: %s", args, code, errOut.String())
+		}
+	}
+
+	ctx, out, errOut := newTestContext()
+	if code := RunAttachments([]string{"--id", "1", "--json"}, ctx); code != 0 {
+		t.Fatalf("RunAttachments() exit code = %d, stderr: %s", code, errOut.String())
+	}
+	var atts []attachmentJSON
+	if err := json.Unmarshal(out.Bytes(), &atts); err != nil {
+		t.Fatalf("attachments --json: %v", err)
+	}
+	if len(atts) != 2 || atts[0].MediaType != "image/png" || atts[1].MediaType != "text/markdown" {
+		t.Fatalf("media types = %+v, want image/png then text/markdown", atts)
+	}
+
+	ctx, out, errOut = newTestContext()
+	if code := RunShow([]string{"--full", "1"}, ctx); code != 0 {
+		t.Fatalf("RunShow(--full) exit code = %d, stderr: %s", code, errOut.String())
+	}
+	if strings.Count(out.String(), "image/png") != 1 {
+		t.Errorf("show --full should mark the image once:\n%s", out.String())
+	}
+
+	orig := newFileOpener
+	t.Cleanup(func() { newFileOpener = orig })
+	fake := &fakeOpener{}
+	newFileOpener = func() (FileOpener, error) { return fake, nil }
+	ctx, _, errOut = newTestContext()
+	if code := RunOpen([]string{"1", "--att", "1"}, ctx); code != 0 {
+		t.Fatalf("RunOpen() exit code = %d, stderr: %s", code, errOut.String())
+	}
+	if len(fake.opened) != 1 || filepath.Ext(fake.opened[0]) != ".png" {
+		t.Fatalf("opened %v, want one .png file", fake.opened)
+	}
+	if got, err := os.ReadFile(fake.opened[0]); err != nil || string(got) != string(png) {
+		t.Errorf("opened copy = %q, %v; want the original bytes", got, err)
+	}
+}
+
+func TestRunAttachNote_MaxAttachmentBytes(t *testing.T) {
+	ws := setupWorkspace(t)
+	ctx, _, errOut := newTestContext()
+	if code := RunAdd([]string{"collect logs"}, ctx); code != 0 {
+		t.Fatalf("RunAdd() exit code = %d, stderr: %s", code, errOut.String())
+	}
+
+	writeConfig(t, ws, "max_attachment_bytes = 8\n")
+	ctx, _, errOut = newTestContext()
+	if code := RunAttach([]string{"note", "--id", "1", "--message", "nine byte"}, ctx); code != 1 {
+		t.Errorf("RunAttach() over the limit exit code = %d, want 1", code)
+	}
+	if !strings.Contains(errOut.String(), "max_attachment_bytes limit of 8 bytes") {
+		t.Errorf("stderr = %q, want the configured limit", errOut.String())
+	}
+	if blobs, _ := filepath.Glob(filepath.Join(ws, "blobs", "sha256", "*", "*", "*")); len(blobs) != 0 {
+		t.Errorf("blobs written over the limit: %v", blobs)
+	}
+	ctx, out, _ := newTestContext()
+	if code := RunAttachments([]string{"--id", "1"}, ctx); code != 0 || out.String() != "(no attachments)\n" {
+		t.Errorf("attachments after refusal = %q, want none", out.String())
+	}
+
+	for _, cfg := range []string{"max_attachment_bytes = 9\n", "max_attachment_bytes = 0\n"} {
+		writeConfig(t, ws, cfg)
+		ctx, _, errOut = newTestContext()
+		if code := RunAttach([]string{"note", "--id", "1", "--message", "nine byte"}, ctx); code != 0 {
+			t.Errorf("RunAttach() with %q exit code = %d, stderr: %s", cfg, code, errOut.String())
+		}
+	}
+}
+
+func TestRunAttachLink_FetchTitle(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/doc":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			_, _ = w.Write([]byte("<html><head><TITLE>\n  Design  &amp; Notes\n</TITLE></head></html>"))
+		case "/raw":
+			w.Header().Set("Content-Type", "text/plain")
+			_, _ = w.Write([]byte("<title>not a page</title>"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	ws := setupWorkspace(t)
+	ctx, _, errOut := newTestContext()
+	if code := RunAdd([]string{"read up"}, ctx); code != 0 {
+		t.Fatalf("RunAdd() exit code = %d, stderr: %s", code, errOut.String())
+	}
+
+	attach := func(args ...string) {
+		t.Helper()
+		ctx, _, errOut := newTestContext()
+		if code := RunAttach(append([]string{"link", "--id", "1"}, args...), ctx); code != 0 {
+			t.Fatalf("RunAttach(%v) exit code = %d, stderr: %s", args, code, errOut.String())
+		}
+	}
+	attach("--url", srv.URL+"/doc", "--fetch-title")
+	attach("--url", srv.URL+"/raw", "--fetch-title")
+	attach("--url", srv.URL+"/missing", "--fetch-title")
+	attach("--url", srv.URL+"/doc", "--fetch-title", "--label", "spec")
+	attach("--url", srv.URL+"/doc")
+	writeConfig(t, ws, "attach_fetch_title = true\n")
+	attach("--url", srv.URL+"/doc")
+	attach("--url", srv.URL+"/doc", "--fetch-title=false")
+
+	ctx, out, errOut := newTestContext()
+	if code := RunAttachments([]string{"--id", "1", "--json"}, ctx); code != 0 {
+		t.Fatalf("RunAttachments() exit code = %d, stderr: %s", code, errOut.String())
+	}
+	var atts []attachmentJSON
+	if err := json.Unmarshal(out.Bytes(), &atts); err != nil {
+		t.Fatalf("unmarshal attachments: %v", err)
+	}
+	host := strings.TrimPrefix(srv.URL, "http://")
+	host = host[:strings.LastIndex(host, ":")]
+	want := []string{"Design & Notes", host, host, "spec", host, "Design & Notes", host}
+	if len(atts) != len(want) {
+		t.Fatalf("got %d attachments, want %d", len(atts), len(want))
+	}
+	for i, a := range atts {
+		if a.Name != want[i] {
+			t.Errorf("attachment %d name = %q, want %q", i+1, a.Name, want[i])
+		}
+	}
+}
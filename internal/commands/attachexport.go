@@ -0,0 +1,171 @@
+package commands
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/sjatkinson/threadkeeper/internal/config"
+)
+
+// runAttachExport implements "attach export": it copies a note's blob out
+// of the store, byte for byte, to a file or stdout.
+func runAttachExport(args []string, ctx CommandContext) int {
+	fs := flag.NewFlagSet(ctx.AppName+" attach export", flag.ContinueOnError)
+	fs.SetOutput(ctx.Err)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintln(ctx.Err, attachUsage(ctx.AppName))
+	}
+
+	var (
+		id       string
+		attIndex int
+		attID    string
+		outPath  string
+		urlOK    bool
+	)
+	fs.StringVar(&id, "id", "", "thread handle or canonical id")
+	fs.IntVar(&attIndex, "att", 0, "attachment index (1-based)")
+	fs.StringVar(&attID, "att-id", "", "attachment ID (alternative to --att)")
+	fs.StringVar(&outPath, "out", "", "file to write the note to, or - for stdout")
+	fs.BoolVar(&urlOK, "url-ok", false, "print a link's URL instead of failing")
+
+	if err := fs.Parse(args); err != nil {
+		_, _ = fmt.Fprintln(ctx.Err)
+		_, _ = fmt.Fprintln(ctx.Err, attachUsage(ctx.AppName))
+		return 2
+	}
+
+	if len(fs.Args()) != 0 {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: unexpected arguments\n")
+		_, _ = fmt.Fprintln(ctx.Err, attachUsage(ctx.AppName))
+		return 2
+	}
+
+	if id == "" || outPath == "" {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: both --id and --out are required\n")
+		return 2
+	}
+
+	if attIndex == 0 && attID == "" {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: must specify either --att <index> or --att-id <id>\n")
+		return 2
+	}
+
+	if attIndex != 0 && attID != "" {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: cannot specify both --att and --att-id\n")
+		return 2
+	}
+
+	// Get paths and verify threads directory exists
+	paths, err := config.GetPaths(ctx.workspace())
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+
+	if _, err := os.Stat(paths.ThreadsDir); err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: threads directory does not exist at %s. Run '%s init' first.\n", paths.ThreadsDir, ctx.AppName)
+		return 1
+	}
+
+	st := newStore(paths)
+	t, err := st.ResolveID(id)
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+	threadDir := st.ThreadDir(t.ID)
+
+	events, err := loadAttachments(threadDir)
+	if err != nil && !os.IsNotExist(err) {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: failed to load attachments: %v\n", err)
+		return 1
+	}
+	currentAtts := computeCurrentAttachments(events)
+
+	var target *AttachmentEvent
+	if attID != "" {
+		for i := range currentAtts {
+			if currentAtts[i].Att.AttID == attID {
+				target = &currentAtts[i]
+				break
+			}
+		}
+		if target == nil {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: attachment with ID %q not found\n", attID)
+			return 1
+		}
+	} else {
+		if attIndex < 1 {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: attachment index must be >= 1\n")
+			return 2
+		}
+		if attIndex > len(currentAtts) {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: attachment index %d out of range (max: %d)\n", attIndex, len(currentAtts))
+			return 1
+		}
+		target = &currentAtts[attIndex-1]
+	}
+
+	// A link has no content to export
+	if target.Att.Kind == "link" {
+		if !urlOK {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: attachment %s is a link; nothing to export (use --url-ok to print its URL)\n", target.Att.AttID)
+			return 1
+		}
+		_, _ = fmt.Fprintln(ctx.Out, target.Att.URL)
+		return 0
+	}
+
+	if target.Att.Blob == nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: note attachment has no blob reference\n")
+		return 1
+	}
+
+	src := blobPath(paths.BlobsDir, threadDir, *target.Att.Blob)
+	if src == "" {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: unsupported blob algorithm %q\n", target.Att.Blob.Algo)
+		return 1
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		if os.IsNotExist(err) {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: blob file not found at %s\n", src)
+			return 1
+		}
+		_, _ = fmt.Fprintf(ctx.Err, "Error: failed to access blob file: %v\n", err)
+		return 1
+	}
+	defer func() { _ = in.Close() }()
+
+	if outPath == "-" {
+		if _, err := io.Copy(ctx.Out, in); err != nil {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: failed to write note: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	if err := writeExport(outPath, in); err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: failed to write %s: %v\n", outPath, err)
+		return 1
+	}
+	ctx.Success("Exported %s (%s) to %s\n", target.Att.Name, target.Att.AttID, outPath)
+	return 0
+}
+
+// writeExport copies r to a new or truncated file at path.
+func writeExport(path string, r io.Reader) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
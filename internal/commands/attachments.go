@@ -0,0 +1,113 @@
+package commands
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/sjatkinson/threadkeeper/internal/config"
+)
+
+// attachmentJSON is one current attachment in attachments --json output.
+type attachmentJSON struct {
+	Attachment
+	Created string `json:"created"` // RFC3339 UTC timestamp of the add event
+}
+
+func RunAttachments(args []string, ctx CommandContext) int {
+	fs := flag.NewFlagSet(ctx.AppName+" attachments", flag.ContinueOnError)
+	fs.SetOutput(ctx.Err)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintln(ctx.Err, attachmentsUsage(ctx.AppName))
+	}
+
+	var (
+		id     string
+		asJSON bool
+	)
+	fs.StringVar(&id, "id", "", "thread handle or canonical id")
+	fs.BoolVar(&asJSON, "json", false, "print JSON instead of a table")
+
+	if err := fs.Parse(args); err != nil {
+		_, _ = fmt.Fprintln(ctx.Err)
+		_, _ = fmt.Fprintln(ctx.Err, attachmentsUsage(ctx.AppName))
+		return 2
+	}
+
+	if len(fs.Args()) != 0 {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: unexpected arguments\n")
+		_, _ = fmt.Fprintln(ctx.Err, attachmentsUsage(ctx.AppName))
+		return 2
+	}
+
+	if id == "" {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: --id is required\n")
+		_, _ = fmt.Fprintln(ctx.Err, attachmentsUsage(ctx.AppName))
+		return 2
+	}
+
+	// Get paths and verify threads directory exists
+	paths, err := config.GetPaths(ctx.workspace())
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+
+	if _, err := os.Stat(paths.ThreadsDir); err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: threads directory does not exist at %s. Run '%s init' first.\n", paths.ThreadsDir, ctx.AppName)
+		return 1
+	}
+
+	st := newStore(paths)
+	t, err := st.ResolveID(id)
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+
+	events, err := loadAttachments(st.ThreadDir(t.ID))
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: failed to load attachments: %v\n", err)
+		return 1
+	}
+
+	if asJSON {
+		current := computeCurrentAttachments(events)
+		out := make([]attachmentJSON, 0, len(current))
+		for _, e := range current {
+			out = append(out, attachmentJSON{Attachment: e.Att, Created: e.TS})
+		}
+		enc := json.NewEncoder(ctx.Out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(out); err != nil {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	dateLayout, err := config.LoadDisplayDateFormat()
+	if err != nil {
+		dateLayout = config.DisplayLayoutISO // Default on error
+	}
+
+	displayAttachmentsTable(ctx.Out, events, dateLayout)
+	return 0
+}
+
+func attachmentsUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s attachments --id <thread-id> [--json]
+
+Lists a thread's current attachments, notes and links, without the rest
+of show's output. Removed and moved attachments are not listed. The #
+column is the index open and mv-att --att accept.
+
+Flags:
+  --id <id>   thread to list
+  --json      print [{"att_id": ..., "kind": ..., "name": ..., "url": ...,
+              "created": ...}] as JSON
+
+`, app)
+}
@@ -0,0 +1,61 @@
+package commands
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestRunAttachments(t *testing.T) {
+	setupWorkspace(t)
+	ctx, _, errOut := newTestContext()
+	if code := RunAdd([]string{"investigate outage"}, ctx); code != 0 {
+		t.Fatalf("RunAdd() exit code = %d, stderr: %s", code, errOut.String())
+	}
+
+	ctx, out, errOut := newTestContext()
+	if code := RunAttachments([]string{"--id", "1"}, ctx); code != 0 {
+		t.Fatalf("RunAttachments() exit code = %d, stderr: %s", code, errOut.String())
+	}
+	if out.String() != "(no attachments)\n" {
+		t.Errorf("RunAttachments() with none = %q, want \"(no attachments)\"", out.String())
+	}
+
+	for _, args := range [][]string{
+		{"note", "--id", "1", "--name", "timeline", "--message", "body"},
+		{"link", "--id", "1", "--url", "https://example.com/pr/7", "--label", "pr"},
+	} {
+		ctx, _, errOut := newTestContext()
+		if code := RunAttach(args, ctx); code != 0 {
+			t.Fatalf("RunAttach(%v) exit code = %d, stderr: %s", args, code, errOut.String())
+		}
+	}
+
+	ctx, out, errOut = newTestContext()
+	if code := RunAttachments([]string{"--id", "1"}, ctx); code != 0 {
+		t.Fatalf("RunAttachments() exit code = %d, stderr: %s", code, errOut.String())
+	}
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != 3 || !strings.HasSuffix(lines[0], "URL") ||
+		!strings.Contains(lines[1], "timeline") || !strings.HasSuffix(lines[1], " -") ||
+		!strings.HasSuffix(lines[2], "https://example.com/pr/7") {
+		t.Errorf("RunAttachments() table:\n%s", out.String())
+	}
+
+	ctx, out, errOut = newTestContext()
+	if code := RunAttachments([]string{"--id", "1", "--json"}, ctx); code != 0 {
+		t.Fatalf("RunAttachments(--json) exit code = %d, stderr: %s", code, errOut.String())
+	}
+	var got []attachmentJSON
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("RunAttachments(--json) output is not JSON: %v\n%s", err, out.String())
+	}
+	if len(got) != 2 || got[0].Kind != "note" || got[1].URL != "https://example.com/pr/7" || got[1].Created == "" {
+		t.Errorf("RunAttachments(--json) = %+v", got)
+	}
+
+	ctx, _, _ = newTestContext()
+	if code := RunAttachments(nil, ctx); code != 2 {
+		t.Errorf("RunAttachments() without --id exit code = %d, want 2", code)
+	}
+}
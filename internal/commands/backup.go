@@ -0,0 +1,371 @@
+package commands
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/sjatkinson/threadkeeper/internal/config"
+	"github.com/sjatkinson/threadkeeper/internal/store"
+)
+
+func RunBackup(args []string, ctx CommandContext) int {
+	fs := flag.NewFlagSet(ctx.AppName+" backup", flag.ContinueOnError)
+	fs.SetOutput(ctx.Err)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintln(ctx.Err, backupUsage(ctx.AppName))
+	}
+
+	var out string
+	var withTrash bool
+	fs.StringVar(&out, "out", "", "tarball to write")
+	fs.BoolVar(&withTrash, "include-trash", false, "also back up removed tasks")
+
+	if err := fs.Parse(args); err != nil {
+		_, _ = fmt.Fprintln(ctx.Err)
+		_, _ = fmt.Fprintln(ctx.Err, backupUsage(ctx.AppName))
+		return 2
+	}
+
+	if len(fs.Args()) != 0 {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: unexpected arguments\n")
+		_, _ = fmt.Fprintln(ctx.Err, backupUsage(ctx.AppName))
+		return 2
+	}
+
+	if out == "" {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: --out is required\n")
+		_, _ = fmt.Fprintln(ctx.Err, backupUsage(ctx.AppName))
+		return 2
+	}
+
+	// Get paths and verify threads directory exists
+	paths, err := config.GetPaths(ctx.workspace())
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+
+	if _, err := os.Stat(paths.ThreadsDir); err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: threads directory does not exist at %s. Run '%s init' first.\n", paths.ThreadsDir, ctx.AppName)
+		return 1
+	}
+
+	outPath, err := filepath.Abs(out)
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+
+	// Hold the lock so the snapshot doesn't catch a command halfway through
+	var files int
+	err = newStore(paths).WithLock(func() error {
+		var err error
+		files, err = writeBackup(outPath, paths.Workspace, backupSkip(paths.Workspace, outPath, withTrash))
+		return err
+	})
+	if err != nil {
+		_ = os.Remove(outPath)
+		_, _ = fmt.Fprintf(ctx.Err, "Error: backup failed: %v\n", err)
+		return 1
+	}
+
+	ctx.Success("Backed up %d files to %s\n", files, out)
+	return 0
+}
+
+// backupSkip returns the filter for files and directories, relative to the
+// workspace, that backups leave out: temp files, the lock and index cache,
+// the trash unless withTrash, any git metadata, and the backup itself and
+// the config directory if they happen to live inside the workspace.
+func backupSkip(workspace, outPath string, withTrash bool) func(rel string, isDir bool) bool {
+	var excluded []string
+	for _, p := range []string{outPath, configDir()} {
+		if p == "" {
+			continue
+		}
+		if rel, err := filepath.Rel(workspace, p); err == nil && rel != "." && !strings.HasPrefix(rel, "..") {
+			excluded = append(excluded, filepath.ToSlash(rel))
+		}
+	}
+
+	return func(rel string, isDir bool) bool {
+		base := path.Base(rel)
+		switch {
+		case rel == store.LockFileName, rel == ".git":
+			return true
+		case strings.HasPrefix(rel, store.IndexFileName):
+			return true
+		case rel == store.TrashDirName && !withTrash:
+			return true
+		case !isDir && strings.HasSuffix(base, ".tmp"):
+			return true
+		}
+		for _, e := range excluded {
+			if rel == e {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// configDir is the directory holding config.toml, or "" if unknown.
+func configDir() string {
+	p, err := config.ConfigPath()
+	if err != nil {
+		return ""
+	}
+	return filepath.Dir(p)
+}
+
+// writeBackup writes every file under root not rejected by skip to a
+// gzip-compressed tarball at outPath, with paths relative to root.
+// Returns the number of files written.
+func writeBackup(outPath, root string, skip func(rel string, isDir bool) bool) (int, error) {
+	f, err := os.Create(outPath)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+
+	files := 0
+	err = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil || rel == "." {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if skip(rel, d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.IsDir() && !d.Type().IsRegular() {
+			return nil // Symlinks and other special files aren't workspace data
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		hdr, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = rel
+		if d.IsDir() {
+			hdr.Name += "/"
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		src, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		if _, err := io.Copy(tw, src); err != nil {
+			return err
+		}
+		files++
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tw.Close(); err != nil {
+		return 0, err
+	}
+	if err := gz.Close(); err != nil {
+		return 0, err
+	}
+	return files, f.Close()
+}
+
+func RunRestore(args []string, ctx CommandContext) int {
+	fs := flag.NewFlagSet(ctx.AppName+" restore", flag.ContinueOnError)
+	fs.SetOutput(ctx.Err)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintln(ctx.Err, restoreUsage(ctx.AppName))
+	}
+
+	var in string
+	var force bool
+	fs.StringVar(&in, "in", "", "tarball to restore")
+	fs.BoolVar(&force, "force", false, "restore into a workspace that already has tasks")
+
+	if err := fs.Parse(args); err != nil {
+		_, _ = fmt.Fprintln(ctx.Err)
+		_, _ = fmt.Fprintln(ctx.Err, restoreUsage(ctx.AppName))
+		return 2
+	}
+
+	if len(fs.Args()) != 0 {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: unexpected arguments\n")
+		_, _ = fmt.Fprintln(ctx.Err, restoreUsage(ctx.AppName))
+		return 2
+	}
+
+	if in == "" {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: --in is required\n")
+		_, _ = fmt.Fprintln(ctx.Err, restoreUsage(ctx.AppName))
+		return 2
+	}
+
+	paths, err := config.GetPaths(ctx.workspace())
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+
+	if !force {
+		entries, err := os.ReadDir(paths.ThreadsDir)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+			return 1
+		}
+		if len(entries) > 0 {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: workspace %s already has tasks; pass --force to restore over them\n", paths.Workspace)
+			return 1
+		}
+	}
+
+	if err := os.MkdirAll(paths.Workspace, 0755); err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: failed to create workspace: %v\n", err)
+		return 1
+	}
+
+	var files int
+	err = newStore(paths).WithLock(func() error {
+		var err error
+		files, err = extractBackup(in, paths.Workspace)
+		return err
+	})
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: restore failed: %v\n", err)
+		return 1
+	}
+
+	ctx.Success("Restored %d files into %s\n", files, paths.Workspace)
+	return 0
+}
+
+// extractBackup unpacks a tarball written by writeBackup into root,
+// overwriting files that already exist. Entries that would land outside
+// root are rejected. Returns the number of files written.
+func extractBackup(inPath, root string) (int, error) {
+	f, err := os.Open(inPath)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return 0, fmt.Errorf("%s is not a gzip file: %w", inPath, err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+
+	files := 0
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return files, nil
+		}
+		if err != nil {
+			return files, err
+		}
+
+		name := path.Clean(hdr.Name)
+		if !fs.ValidPath(name) || name == "." {
+			return files, fmt.Errorf("unsafe path %q in backup", hdr.Name)
+		}
+		dst := filepath.Join(root, filepath.FromSlash(name))
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(dst, 0755); err != nil {
+				return files, err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+				return files, err
+			}
+			// Write to a temp file first so a failed restore never leaves a
+			// truncated thread file behind
+			tmp := dst + ".tmp"
+			out, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+			if err != nil {
+				return files, err
+			}
+			if _, err := io.Copy(out, tr); err != nil {
+				_ = out.Close()
+				_ = os.Remove(tmp)
+				return files, err
+			}
+			if err := out.Close(); err != nil {
+				_ = os.Remove(tmp)
+				return files, err
+			}
+			if err := os.Rename(tmp, dst); err != nil {
+				_ = os.Remove(tmp)
+				return files, err
+			}
+			files++
+		default:
+			// writeBackup only stores directories and regular files
+		}
+	}
+}
+
+func backupUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s backup --out <file.tar.gz> [--include-trash]
+
+Writes the workspace (threads, blobs, attachment logs and the undo log) to
+a gzip-compressed tarball. Temp files, the index cache, the lock file and
+any .git directory are left out, as is config.toml. Removed tasks in the
+trash are left out unless --include-trash is given.
+
+Flags:
+  --out <file>       tarball to write
+  --include-trash    also back up removed tasks
+
+`, app)
+}
+
+func restoreUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s restore --in <file.tar.gz> [--force]
+
+Extracts a tarball written by '%s backup' into the workspace. Refuses if
+the workspace already has tasks unless --force is given; with --force,
+files in the backup replace the ones in the workspace and other files are
+left alone.
+
+Flags:
+  --in <file>    tarball to restore
+  --force        restore over existing tasks
+
+`, app, app)
+}
@@ -0,0 +1,67 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBackupRestoreRoundTrip(t *testing.T) {
+	ws := setupWorkspace(t)
+	for _, args := range [][]string{{"keep me"}, {"drop me"}} {
+		ctx, _, errOut := newTestContext()
+		if code := RunAdd(args, ctx); code != 0 {
+			t.Fatalf("RunAdd(%v) exit code = %d, stderr: %s", args, code, errOut.String())
+		}
+	}
+	ctx, _, errOut := newTestContext()
+	if code := RunAttach([]string{"note", "--id", "1", "--name", "n", "--message", "note body"}, ctx); code != 0 {
+		t.Fatalf("RunAttach() exit code = %d, stderr: %s", code, errOut.String())
+	}
+	ctx, _, errOut = newTestContext()
+	if code := RunRemove([]string{"--force", "2"}, ctx); code != 0 {
+		t.Fatalf("RunRemove() exit code = %d, stderr: %s", code, errOut.String())
+	}
+	if err := os.WriteFile(filepath.Join(ws, "threads", "stray.tmp"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	archive := filepath.Join(t.TempDir(), "ws.tar.gz")
+	ctx, _, errOut = newTestContext()
+	if code := RunBackup([]string{"--out", archive}, ctx); code != 0 {
+		t.Fatalf("RunBackup() exit code = %d, stderr: %s", code, errOut.String())
+	}
+
+	// Restore into a fresh workspace
+	fresh := t.TempDir()
+	t.Setenv("THREADKEEPER_WORKSPACE", fresh)
+	ctx, _, errOut = newTestContext()
+	if code := RunRestore([]string{"--in", archive}, ctx); code != 0 {
+		t.Fatalf("RunRestore() exit code = %d, stderr: %s", code, errOut.String())
+	}
+
+	ctx, out, _ := newTestContext()
+	if code := RunList(nil, ctx); code != 0 || !strings.Contains(out.String(), "keep me") {
+		t.Errorf("list after restore = %q, want the kept task", out.String())
+	}
+	ctx, out, _ = newTestContext()
+	if code := RunGrep([]string{"note body"}, ctx); code != 0 || !strings.Contains(out.String(), "note body") {
+		t.Errorf("grep after restore exit code = %d, output %q; want the note blob restored", code, out.String())
+	}
+	for _, skipped := range []string{".trash", filepath.Join("threads", "stray.tmp"), "config"} {
+		if _, err := os.Stat(filepath.Join(fresh, skipped)); err == nil {
+			t.Errorf("%s was restored, want it left out of the backup", skipped)
+		}
+	}
+
+	// A second restore needs --force
+	ctx, _, errOut = newTestContext()
+	if code := RunRestore([]string{"--in", archive}, ctx); code != 1 || !strings.Contains(errOut.String(), "--force") {
+		t.Errorf("RunRestore() into a populated workspace = %d, stderr %q; want refusal", code, errOut.String())
+	}
+	ctx, _, errOut = newTestContext()
+	if code := RunRestore([]string{"--force", "--in", archive}, ctx); code != 0 {
+		t.Errorf("RunRestore(--force) exit code = %d, stderr: %s", code, errOut.String())
+	}
+}
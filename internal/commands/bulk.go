@@ -0,0 +1,91 @@
+package commands
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/sjatkinson/threadkeeper/internal/store"
+	"github.com/sjatkinson/threadkeeper/internal/task"
+)
+
+// BulkConfirmThreshold is the largest filter selection done, archive and
+// retag will change without confirmation or --force.
+const BulkConfirmThreshold = 5
+
+// bulkSelection holds the filter flags done and archive accept in place of
+// explicit task IDs, and that retag requires. The filters behave exactly as
+// they do for list.
+type bulkSelection struct {
+	All     bool
+	Project string
+	Status  string
+	Tags    stringList
+	Force   bool
+}
+
+func (b *bulkSelection) register(fs *flag.FlagSet) {
+	fs.BoolVar(&b.All, "all", false, "select tasks of any status")
+	fs.BoolVar(&b.All, "a", false, "select tasks of any status (shorthand)")
+	fs.StringVar(&b.Project, "project", "", "select by project")
+	fs.StringVar(&b.Project, "p", "", "select by project (shorthand)")
+	fs.StringVar(&b.Status, "status", "", "select by status (open|done|archived)")
+	fs.Var(&b.Tags, "tag", "select by tag (repeatable; all must match)")
+	fs.BoolVar(&b.Force, "force", false, "don't ask for confirmation")
+}
+
+// active reports whether any filter flag was given.
+func (b *bulkSelection) active() bool {
+	return b.All || b.Project != "" || b.Status != "" || len(b.Tags) > 0
+}
+
+// filter returns the list filter equivalent to b.
+func (b *bulkSelection) filter() taskFilter {
+	return taskFilter{
+		All:     b.All,
+		Status:  b.Status,
+		Project: b.Project,
+		Tags:    b.Tags,
+	}
+}
+
+// selectBulkTasks returns the tasks matching b, leaving out those already in
+// status target. Selections above BulkConfirmThreshold need --force, or a yes
+// at the prompt when stdin is a terminal. The int is a non-zero exit code
+// when the caller should stop.
+func selectBulkTasks(st *store.FileStore, ctx CommandContext, b bulkSelection, target task.Status, verb string) ([]*task.Task, int) {
+	all, err := loadAllTasks(st, ctx)
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return nil, 1
+	}
+
+	var tasks []*task.Task
+	for _, t := range filterTasks(all, b.filter()) {
+		if t.Status != target {
+			tasks = append(tasks, t)
+		}
+	}
+
+	if code := confirmBulk(ctx, b, len(tasks), verb); code != 0 {
+		return nil, code
+	}
+	return tasks, 0
+}
+
+// confirmBulk asks before changing n tasks selected by b: above
+// BulkConfirmThreshold it needs --force, or a yes at the prompt when stdin
+// is a terminal. Returns a non-zero exit code when the caller should stop.
+func confirmBulk(ctx CommandContext, b bulkSelection, n int, verb string) int {
+	if n <= BulkConfirmThreshold || b.Force {
+		return 0
+	}
+	if !isTerminal(ctx.stdin()) {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %d tasks match; pass --force to %s more than %d tasks\n", n, verb, BulkConfirmThreshold)
+		return 1
+	}
+	if !confirm(ctx, fmt.Sprintf("%d tasks match; %s them all?", n, verb)) {
+		_, _ = fmt.Fprintln(ctx.Err, "Aborted; no tasks were changed.")
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,239 @@
+package commands
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	neturl "net/url"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sjatkinson/threadkeeper/internal/config"
+	"github.com/sjatkinson/threadkeeper/internal/task"
+)
+
+// linkCheckWorkers bounds how many links check-links requests at once.
+const linkCheckWorkers = 8
+
+// linkCheck is one live link attachment and the outcome of requesting it.
+type linkCheck struct {
+	ThreadID string
+	AttID    string
+	URL      string
+
+	Status int   // HTTP status code; 0 if the request failed
+	Err    error // transport error, including timeouts
+}
+
+// broken reports whether the link failed to load or answered 4xx/5xx.
+func (c linkCheck) broken() bool {
+	return c.Err != nil || c.Status >= 400
+}
+
+// statusText is the status column: the code, "timeout" or "error".
+func (c linkCheck) statusText() string {
+	if c.Err == nil {
+		return strconv.Itoa(c.Status)
+	}
+	var netErr net.Error
+	if errors.As(c.Err, &netErr) && netErr.Timeout() {
+		return "timeout"
+	}
+	return "error"
+}
+
+func RunCheckLinks(args []string, ctx CommandContext) int {
+	fs := flag.NewFlagSet(ctx.AppName+" check-links", flag.ContinueOnError)
+	fs.SetOutput(ctx.Err)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintln(ctx.Err, checkLinksUsage(ctx.AppName))
+	}
+
+	var (
+		threadID string
+		timeout  time.Duration
+	)
+	fs.StringVar(&threadID, "id", "", "only check links on this thread")
+	fs.DurationVar(&timeout, "timeout", 0, "how long to wait for each link (e.g. 5s)")
+
+	if err := fs.Parse(args); err != nil {
+		_, _ = fmt.Fprintln(ctx.Err)
+		_, _ = fmt.Fprintln(ctx.Err, checkLinksUsage(ctx.AppName))
+		return 2
+	}
+
+	if len(fs.Args()) != 0 {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: unexpected arguments\n")
+		_, _ = fmt.Fprintln(ctx.Err, checkLinksUsage(ctx.AppName))
+		return 2
+	}
+
+	if timeout < 0 {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: --timeout must be positive\n")
+		return 2
+	}
+	if timeout == 0 {
+		timeout, _ = config.LoadLinkCheckTimeout()
+	}
+
+	// Get paths and verify threads directory exists
+	paths, err := config.GetPaths(ctx.workspace())
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+
+	if _, err := os.Stat(paths.ThreadsDir); err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: threads directory does not exist at %s. Run '%s init' first.\n", paths.ThreadsDir, ctx.AppName)
+		return 1
+	}
+
+	st := newStore(paths)
+	var tasks []*task.Task
+	if threadID != "" {
+		t, err := st.ResolveID(threadID)
+		if err != nil {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+			return 1
+		}
+		tasks = []*task.Task{t}
+	} else {
+		tasks, err = loadAllTasks(st, ctx)
+		if err != nil {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+			return 1
+		}
+	}
+
+	var checks []linkCheck
+	for _, t := range tasks {
+		events, err := loadAttachments(st.ThreadDir(t.ID))
+		if err != nil {
+			if !os.IsNotExist(err) {
+				_, _ = fmt.Fprintf(ctx.Err, "Warning: failed to load attachments for %s: %v\n", t.ID, err)
+			}
+			continue
+		}
+		for _, att := range computeCurrentAttachments(events) {
+			if att.Att.Kind != "link" || !isHTTPURL(att.Att.URL) {
+				continue
+			}
+			checks = append(checks, linkCheck{ThreadID: t.ID, AttID: att.Att.AttID, URL: att.Att.URL})
+		}
+	}
+
+	if len(checks) == 0 {
+		_, _ = fmt.Fprintln(ctx.Out, "No links to check.")
+		return 0
+	}
+
+	checkLinks(&http.Client{Timeout: timeout}, checks)
+
+	broken := 0
+	for _, c := range checks {
+		verdict := "ok"
+		if c.broken() {
+			verdict = "broken"
+			broken++
+		}
+		_, _ = fmt.Fprintf(ctx.Out, "%s:%s  %-7s  %-6s  %s\n", c.ThreadID, c.AttID, c.statusText(), verdict, c.URL)
+		if c.Err != nil && ctx.Verbose {
+			_, _ = fmt.Fprintf(ctx.Err, "  %v\n", c.Err)
+		}
+	}
+	_, _ = fmt.Fprintf(ctx.Out, "Checked %d links, %d broken.\n", len(checks), broken)
+
+	if broken > 0 {
+		return 1
+	}
+	return 0
+}
+
+// isHTTPURL reports whether raw is an http or https URL; other link
+// schemes (mailto, file) have nothing to request.
+func isHTTPURL(raw string) bool {
+	u, err := neturl.Parse(raw)
+	if err != nil {
+		return false
+	}
+	return u.Scheme == "http" || u.Scheme == "https"
+}
+
+// checkLinks requests every link on a bounded pool of workers, filling in
+// each check's Status and Err in place.
+func checkLinks(client *http.Client, checks []linkCheck) {
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(linkCheckWorkers, len(checks)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				checks[i].Status, checks[i].Err = checkLink(client, checks[i].URL)
+			}
+		}()
+	}
+	for i := range checks {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// checkLink requests url with HEAD and returns the status code. Servers
+// that refuse HEAD or answer it with an error status are retried with GET,
+// whose answer wins; a HEAD timeout is not retried.
+func checkLink(client *http.Client, url string) (int, error) {
+	status, err := requestStatus(client, http.MethodHead, url)
+	if err == nil && status < 400 {
+		return status, nil
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return 0, err
+	}
+	return requestStatus(client, http.MethodGet, url)
+}
+
+// requestStatus sends one request and returns the response status code,
+// discarding the body.
+func requestStatus(client *http.Client, method, url string) (int, error) {
+	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	// Drain a little so the connection can be reused, without reading
+	// whole pages
+	_, _ = io.CopyN(io.Discard, resp.Body, 4096)
+	return resp.StatusCode, nil
+}
+
+func checkLinksUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s check-links [--id <thread-id>] [--timeout <duration>]
+
+Requests every current http and https link attachment and prints one line
+per link as <thread-id>:<att-id>  <status>  ok|broken  <url>. Links
+answering 4xx or 5xx, timing out or failing to connect are broken. Each
+link is tried with HEAD first, then GET if HEAD fails. Other schemes
+(mailto, file) are skipped.
+
+Exits 1 if any link is broken, so it can run from cron.
+
+Flags:
+  --id <id>             only check links on this thread
+  --timeout <duration>  how long to wait for each link, such as 5s
+                        (default: link_check_timeout config key, or 10s)
+
+`, app)
+}
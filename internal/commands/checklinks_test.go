@@ -0,0 +1,69 @@
+package commands
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunCheckLinks(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+		case "/gone":
+			w.WriteHeader(http.StatusNotFound)
+		case "/nohead":
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+			}
+		case "/slow":
+			time.Sleep(500 * time.Millisecond)
+		}
+	}))
+	defer srv.Close()
+
+	setupWorkspace(t)
+	ctx, _, errOut := newTestContext()
+	if code := RunAdd([]string{"read up"}, ctx); code != 0 {
+		t.Fatalf("RunAdd() exit code = %d, stderr: %s", code, errOut.String())
+	}
+	for _, url := range []string{srv.URL + "/ok", srv.URL + "/nohead", "mailto:someone@example.com"} {
+		ctx, _, errOut = newTestContext()
+		if code := RunAttach([]string{"link", "--id", "1", "--url", url}, ctx); code != 0 {
+			t.Fatalf("RunAttach(%s) exit code = %d, stderr: %s", url, code, errOut.String())
+		}
+	}
+
+	ctx, out, errOut := newTestContext()
+	if code := RunCheckLinks([]string{"--id", "1"}, ctx); code != 0 {
+		t.Fatalf("RunCheckLinks() exit code = %d, stderr: %s, out: %s", code, errOut.String(), out.String())
+	}
+	if got := out.String(); strings.Contains(got, "mailto") || !strings.Contains(got, "Checked 2 links, 0 broken.") {
+		t.Errorf("RunCheckLinks() output = %q, want 2 http links ok and mailto skipped", got)
+	}
+
+	for _, path := range []string{"/gone", "/slow"} {
+		ctx, _, errOut = newTestContext()
+		if code := RunAttach([]string{"link", "--id", "1", "--url", srv.URL + path}, ctx); code != 0 {
+			t.Fatalf("RunAttach(%s) exit code = %d, stderr: %s", path, code, errOut.String())
+		}
+	}
+
+	ctx, out, _ = newTestContext()
+	if code := RunCheckLinks([]string{"--timeout", "100ms"}, ctx); code != 1 {
+		t.Errorf("RunCheckLinks() with broken links exit code = %d, want 1", code)
+	}
+	got := out.String()
+	for _, want := range []string{
+		"200      ok      " + srv.URL + "/nohead",
+		"404      broken  " + srv.URL + "/gone",
+		"timeout  broken  " + srv.URL + "/slow",
+		"Checked 4 links, 2 broken.",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("RunCheckLinks() output = %q, want it to contain %q", got, want)
+		}
+	}
+}
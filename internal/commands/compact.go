@@ -0,0 +1,138 @@
+package commands
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/sjatkinson/threadkeeper/internal/config"
+)
+
+func RunCompact(args []string, ctx CommandContext) int {
+	fs := flag.NewFlagSet(ctx.AppName+" compact", flag.ContinueOnError)
+	fs.SetOutput(ctx.Err)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintln(ctx.Err, compactUsage(ctx.AppName))
+	}
+
+	var id string
+	fs.StringVar(&id, "id", "", "thread handle or canonical id")
+
+	if err := fs.Parse(args); err != nil {
+		_, _ = fmt.Fprintln(ctx.Err)
+		_, _ = fmt.Fprintln(ctx.Err, compactUsage(ctx.AppName))
+		return 2
+	}
+
+	if len(fs.Args()) != 0 {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: unexpected arguments\n")
+		_, _ = fmt.Fprintln(ctx.Err, compactUsage(ctx.AppName))
+		return 2
+	}
+
+	if id == "" {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: --id is required\n")
+		_, _ = fmt.Fprintln(ctx.Err, compactUsage(ctx.AppName))
+		return 2
+	}
+
+	// Get paths and verify threads directory exists
+	paths, err := config.GetPaths(ctx.workspace())
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+
+	if _, err := os.Stat(paths.ThreadsDir); err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: threads directory does not exist at %s. Run '%s init' first.\n", paths.ThreadsDir, ctx.AppName)
+		return 1
+	}
+
+	st := newStore(paths)
+	t, err := st.ResolveID(id)
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+
+	// Hold the lock so no attach or mv-att appends between reading and
+	// replacing the log
+	var before, after int
+	err = st.WithLock(func() error {
+		var err error
+		before, after, err = compactAttachments(st.ThreadDir(t.ID))
+		return err
+	})
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: failed to compact attachments: %v\n", err)
+		return 1
+	}
+
+	if before == after {
+		ctx.Success("Attachments for task %s are already compact\n", t.ID)
+		return 0
+	}
+	ctx.Success("Compacted attachments for task %s: kept %d of %d events\n", t.ID, after, before)
+	return 0
+}
+
+// compactAttachments rewrites attachments.jsonl in threadDir with only the
+// add events of current attachments, in timestamp order. Timestamps and
+// att_ids are kept as they are. Returns the event counts before and after;
+// when they match the log is left untouched. Callers must hold the
+// workspace lock.
+func compactAttachments(threadDir string) (int, int, error) {
+	result, err := loadAttachmentsWithMetadata(threadDir)
+	if err != nil {
+		return 0, 0, err
+	}
+	if result.MalformedLine > 0 {
+		// Rewriting would silently drop them
+		return 0, 0, fmt.Errorf("attachments.jsonl has %d malformed lines; fix them first", result.MalformedLine)
+	}
+
+	live := computeCurrentAttachments(result.Events)
+	if len(live) == len(result.Events) {
+		return len(live), len(live), nil
+	}
+
+	var data []byte
+	for _, event := range live {
+		line, err := json.Marshal(event)
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to marshal attachment event: %w", err)
+		}
+		data = append(data, line...)
+		data = append(data, '\n')
+	}
+
+	// Use atomic write: write to temp file, then rename
+	path := filepath.Join(threadDir, "attachments.jsonl")
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return 0, 0, fmt.Errorf("failed to write attachments.jsonl: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath) // Clean up on error
+		return 0, 0, fmt.Errorf("failed to rename attachments.jsonl: %w", err)
+	}
+
+	return len(result.Events), len(live), nil
+}
+
+func compactUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s compact --id <thread-id>
+
+Rewrites the thread's attachments.jsonl keeping only the add events of
+attachments that are still current, so removed and moved attachments no
+longer have to be parsed. Timestamps and attachment IDs are preserved.
+Does nothing when the log has no dead entries. Blobs are left in place.
+
+Flags:
+  --id <id>   thread to compact
+
+`, app)
+}
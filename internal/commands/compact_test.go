@@ -0,0 +1,60 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sjatkinson/threadkeeper/internal/config"
+)
+
+func TestRunCompact(t *testing.T) {
+	setupWorkspace(t)
+	tk := addAndLoad(t, []string{"noisy thread"})
+
+	paths, err := config.GetPaths("")
+	if err != nil {
+		t.Fatalf("GetPaths() error = %v", err)
+	}
+	st := newStore(paths)
+	dir := st.ThreadDir(tk.ID)
+
+	keep := Attachment{AttID: "ATTKEEP", Kind: "link", Name: "docs", URL: "https://example.com"}
+	drop := Attachment{AttID: "ATTDROP", Kind: "link", Name: "scratch", URL: "https://example.org"}
+	for _, e := range []AttachmentEvent{
+		{Op: "add", TS: "2026-01-01T00:00:00Z", Att: drop},
+		{Op: "add", TS: "2026-01-02T00:00:00Z", Att: keep},
+		{Op: "remove", TS: "2026-01-03T00:00:00Z", Att: drop},
+	} {
+		if err := appendAttachmentEvent(st, dir, e); err != nil {
+			t.Fatalf("appendAttachmentEvent() error = %v", err)
+		}
+	}
+
+	ctx, _, errOut := newTestContext()
+	if code := RunCompact([]string{"--id", tk.ID}, ctx); code != 0 {
+		t.Fatalf("RunCompact() exit code = %d, stderr: %s", code, errOut.String())
+	}
+	events, err := loadAttachments(dir)
+	if err != nil {
+		t.Fatalf("loadAttachments() error = %v", err)
+	}
+	if len(events) != 1 || events[0].Op != "add" || events[0].Att.AttID != "ATTKEEP" || events[0].TS != "2026-01-02T00:00:00Z" {
+		t.Fatalf("events after compact = %+v, want only the original ATTKEEP add", events)
+	}
+
+	// A second run leaves the file alone
+	logPath := filepath.Join(dir, "attachments.jsonl")
+	before, _ := os.ReadFile(logPath)
+	ctx, out, _ := newTestContext()
+	if code := RunCompact([]string{"--id", tk.ID}, ctx); code != 0 {
+		t.Fatalf("second RunCompact() exit code = %d", code)
+	}
+	after, _ := os.ReadFile(logPath)
+	if string(before) != string(after) {
+		t.Errorf("second compact changed the log:\n%s\nwant\n%s", after, before)
+	}
+	if out.Len() == 0 {
+		t.Errorf("second compact printed nothing, want an already-compact message")
+	}
+}
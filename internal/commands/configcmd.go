@@ -0,0 +1,97 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sjatkinson/threadkeeper/internal/config"
+)
+
+func RunConfig(args []string, ctx CommandContext) int {
+	if len(args) == 0 {
+		_, _ = fmt.Fprintln(ctx.Err, configUsage(ctx.AppName))
+		return 2
+	}
+
+	switch args[0] {
+	case "get":
+		return runConfigGet(args[1:], ctx)
+	case "set":
+		return runConfigSet(args[1:], ctx)
+	default:
+		_, _ = fmt.Fprintf(ctx.Err, "Error: invalid config subcommand %q (must be 'get' or 'set')\n", args[0])
+		_, _ = fmt.Fprintln(ctx.Err)
+		_, _ = fmt.Fprintln(ctx.Err, configUsage(ctx.AppName))
+		return 2
+	}
+}
+
+// runConfigGet prints one key's effective value, or every known key as
+// "key = value" when no key is given.
+func runConfigGet(args []string, ctx CommandContext) int {
+	if len(args) > 1 {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: expected at most one key\n")
+		_, _ = fmt.Fprintln(ctx.Err, configUsage(ctx.AppName))
+		return 2
+	}
+
+	if len(args) == 1 {
+		value, err := config.EffectiveSetting(args[0])
+		if err != nil {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+			return 1
+		}
+		_, _ = fmt.Fprintln(ctx.Out, value)
+		return 0
+	}
+
+	for _, key := range config.SettingKeys() {
+		value, err := config.EffectiveSetting(key)
+		if err != nil {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: %s: %v\n", key, err)
+			return 1
+		}
+		_, _ = fmt.Fprintf(ctx.Out, "%s = %s\n", key, value)
+	}
+	return 0
+}
+
+func runConfigSet(args []string, ctx CommandContext) int {
+	if len(args) != 2 {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: expected <key> and <value>\n")
+		_, _ = fmt.Fprintln(ctx.Err, configUsage(ctx.AppName))
+		return 2
+	}
+
+	key, value := args[0], args[1]
+	if err := config.SetSetting(key, value); err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+	ctx.Success("Set %s = %s\n", key, value)
+	return 0
+}
+
+func configUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s config get [<key>]
+  %s config set <key> <value>
+
+Reads and writes config.toml. get prints the value in use for a key, with
+defaults filled in; without a key it prints every known key as
+"key = value". set checks the value and writes it, keeping other keys
+and tables such as [alias]. Comments in config.toml are not kept.
+
+Keys:
+  %s
+
+default_tags takes a comma-separated list. Keys of the [list] section are
+written list.<key>, such as list.limit.
+
+Examples:
+  %s config set date_locale us
+  %s config set timezone Europe/Berlin
+  %s config get default_workspace
+
+`, app, app, strings.Join(config.SettingKeys(), "\n  "), app, app, app)
+}
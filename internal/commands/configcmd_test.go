@@ -0,0 +1,87 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pelletier/go-toml/v2"
+
+	"github.com/sjatkinson/threadkeeper/internal/config"
+)
+
+func TestRunConfig(t *testing.T) {
+	ws := setupWorkspace(t)
+	writeConfig(t, ws, "date_locale = \"eu\"\n\n[alias]\nls = \"list\"\n")
+
+	run := func(args ...string) (string, string, int) {
+		t.Helper()
+		ctx, out, errOut := newTestContext()
+		code := RunConfig(args, ctx)
+		return out.String(), errOut.String(), code
+	}
+
+	if out, _, code := run("get", "date_locale"); code != 0 || out != "eu\n" {
+		t.Errorf("config get date_locale = %q (exit %d), want eu", out, code)
+	}
+	// Unset keys report their default
+	if out, _, code := run("get", "bucket_width"); code != 0 || out != "2\n" {
+		t.Errorf("config get bucket_width = %q (exit %d), want 2", out, code)
+	}
+
+	for _, args := range [][]string{
+		{"set", "date_locale", "us"},
+		{"set", "default_tags", "home, errand"},
+		{"set", "list.limit", "20"},
+		{"set", "git_autocommit", "true"},
+	} {
+		if _, errOut, code := run(args...); code != 0 {
+			t.Fatalf("config %v exit code = %d, stderr: %s", args, code, errOut)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(ws, "config", config.AppDirName, "config.toml"))
+	if err != nil {
+		t.Fatalf("read config: %v", err)
+	}
+	var cfg struct {
+		DateLocale    string            `toml:"date_locale"`
+		DefaultTags   []string          `toml:"default_tags"`
+		GitAutocommit bool              `toml:"git_autocommit"`
+		Alias         map[string]string `toml:"alias"`
+		List          struct {
+			Limit int `toml:"limit"`
+		} `toml:"list"`
+	}
+	if err := toml.Unmarshal(data, &cfg); err != nil {
+		t.Fatalf("written config is not TOML: %v\n%s", err, data)
+	}
+	if cfg.DateLocale != "us" || strings.Join(cfg.DefaultTags, ",") != "home,errand" || !cfg.GitAutocommit || cfg.List.Limit != 20 || cfg.Alias["ls"] != "list" {
+		t.Errorf("written config = %+v\n%s", cfg, data)
+	}
+
+	out, _, code := run("get")
+	if code != 0 || !strings.Contains(out, "date_locale = us\n") || !strings.Contains(out, "list.limit = 20\n") || !strings.Contains(out, "render_markdown = true\n") {
+		t.Errorf("config get = %q (exit %d)", out, code)
+	}
+
+	for _, args := range [][]string{
+		{"set", "date_locale", "fr"},
+		{"set", "timezone", "Mars/Olympus"},
+		{"set", "bucket_width", "9"},
+		{"set", "no_such_key", "x"},
+		{"get", "no_such_key"},
+	} {
+		if _, _, code := run(args...); code != 1 {
+			t.Errorf("config %v exit code = %d, want 1", args, code)
+		}
+	}
+	if out, _, _ := run("get", "date_locale"); out != "us\n" {
+		t.Errorf("date_locale after rejected set = %q, want us", out)
+	}
+
+	if _, _, code := run("set", "date_locale"); code != 2 {
+		t.Errorf("config set without a value exit code = %d, want 2", code)
+	}
+}
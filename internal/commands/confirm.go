@@ -0,0 +1,41 @@
+package commands
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// isTerminal reports whether r is an interactive terminal. Prompts are only
+// shown on a terminal, so scripts and pipes never block on input.
+// It is a variable so tests can simulate a TTY.
+var isTerminal = func(r io.Reader) bool {
+	f, ok := r.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// confirm writes prompt to ctx.Err and reads a y/N answer from ctx's input.
+// Anything other than "y" or "yes" (including EOF) means no.
+func confirm(ctx CommandContext, prompt string) bool {
+	_, _ = fmt.Fprintf(ctx.Err, "%s [y/N] ", prompt)
+	line, err := bufio.NewReader(ctx.stdin()).ReadString('\n')
+	if err != nil && line == "" {
+		_, _ = fmt.Fprintln(ctx.Err)
+		return false
+	}
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "y", "yes":
+		return true
+	default:
+		return false
+	}
+}
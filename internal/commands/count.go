@@ -0,0 +1,96 @@
+package commands
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/sjatkinson/threadkeeper/internal/config"
+)
+
+func RunCount(args []string, ctx CommandContext) int {
+	fs := flag.NewFlagSet(ctx.AppName+" count", flag.ContinueOnError)
+	fs.SetOutput(ctx.Err)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintln(ctx.Err, countUsage(ctx.AppName))
+	}
+
+	var (
+		all     bool
+		project string
+		status  string
+		tags    stringList
+		tagKey  string
+		tagVal  string
+	)
+
+	fs.BoolVar(&all, "all", false, "count all tasks")
+	fs.BoolVar(&all, "a", false, "count all tasks (shorthand)")
+	fs.StringVar(&project, "project", "", "filter by project")
+	fs.StringVar(&project, "p", "", "filter by project (shorthand)")
+	fs.StringVar(&status, "status", "", "filter by status (open|done|archived)")
+	fs.Var(&tags, "tag", "filter by tag (repeatable; all must match)")
+	fs.StringVar(&tagKey, "tag-key", "", "filter by key of a key:value tag")
+	fs.StringVar(&tagVal, "tag-val", "", "filter by key:value tag")
+
+	if err := fs.Parse(args); err != nil {
+		_, _ = fmt.Fprintln(ctx.Err)
+		_, _ = fmt.Fprintln(ctx.Err, countUsage(ctx.AppName))
+		return 2
+	}
+
+	if len(fs.Args()) != 0 {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: unexpected arguments\n")
+		_, _ = fmt.Fprintln(ctx.Err, countUsage(ctx.AppName))
+		return 2
+	}
+
+	// Get paths and verify threads directory exists
+	paths, err := config.GetPaths(ctx.workspace())
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+
+	if _, err := os.Stat(paths.ThreadsDir); err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: threads directory does not exist at %s. Run '%s init' first.\n", paths.ThreadsDir, ctx.AppName)
+		return 1
+	}
+
+	st := newStore(paths)
+	tasks, err := st.LoadAll()
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+
+	filtered := filterTasks(tasks, taskFilter{
+		All:     all,
+		Status:  status,
+		Project: project,
+		Tags:    tags,
+		TagKey:  tagKey,
+		TagVal:  tagVal,
+	})
+
+	// Print only the number so the output is stable for scripts
+	_, _ = fmt.Fprintln(ctx.Out, len(filtered))
+	return 0
+}
+
+func countUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s count [flags]
+
+Prints the number of tasks matching the same filters as list.
+
+Flags:
+  -a, --all                   count all tasks (default: only open)
+  -p, --project <name>        filter by project
+  --status <open|done|archived> filter by status
+  --tag <tag>                 filter by tag (repeat to AND tags)
+  --tag-key <key>             filter by key of a key:value tag
+  --tag-val <key:value>       filter by key:value tag
+
+`, app)
+}
@@ -0,0 +1,40 @@
+package commands
+
+import "testing"
+
+func TestRunCount_PrintsOnlyNumber(t *testing.T) {
+	setupWorkspace(t)
+
+	for _, args := range [][]string{
+		{"--project", "web", "one"},
+		{"--project", "web", "two"},
+		{"three"},
+	} {
+		ctx, _, errOut := newTestContext()
+		if code := RunAdd(args, ctx); code != 0 {
+			t.Fatalf("RunAdd() exit code = %d, stderr: %s", code, errOut.String())
+		}
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"all open", []string{}, "3\n"},
+		{"project", []string{"--project", "web"}, "2\n"},
+		{"no match", []string{"--status", "done"}, "0\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, out, errOut := newTestContext()
+			if code := RunCount(tt.args, ctx); code != 0 {
+				t.Fatalf("RunCount() exit code = %d, stderr: %s", code, errOut.String())
+			}
+			if got := out.String(); got != tt.want {
+				t.Errorf("RunCount() output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
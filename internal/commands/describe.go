@@ -1,15 +1,14 @@
 package commands
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"os"
 	"os/exec"
 	"strings"
-	"time"
 
 	"github.com/sjatkinson/threadkeeper/internal/config"
-	"github.com/sjatkinson/threadkeeper/internal/store"
 )
 
 func RunDescribe(args []string, ctx CommandContext) int {
@@ -19,6 +18,10 @@ func RunDescribe(args []string, ctx CommandContext) int {
 		_, _ = fmt.Fprintln(ctx.Err, describeUsage(ctx.AppName))
 	}
 
+	var messages stringList
+	fs.Var(&messages, "message", "set the description without opening an editor (repeatable)")
+	fs.Var(&messages, "m", "set the description without opening an editor (shorthand)")
+
 	if err := fs.Parse(args); err != nil {
 		_, _ = fmt.Fprintln(ctx.Err)
 		_, _ = fmt.Fprintln(ctx.Err, describeUsage(ctx.AppName))
@@ -34,7 +37,7 @@ func RunDescribe(args []string, ctx CommandContext) int {
 	idStr := rest[0]
 
 	// Get paths and verify tasks directory exists
-	paths, err := config.GetPaths(ctx.Path)
+	paths, err := config.GetPaths(ctx.workspace())
 	if err != nil {
 		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
 		return 1
@@ -46,81 +49,35 @@ func RunDescribe(args []string, ctx CommandContext) int {
 	}
 
 	// Load and resolve task
-	st := store.NewFileStore(paths.ThreadsDir)
+	st := newStore(paths)
 	t, err := st.ResolveID(idStr)
 	if err != nil {
 		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
 		return 1
 	}
 
-	// Get current description
-	currentDesc := t.Description
-
-	// Get editor
-	editor := getEditor()
-
-	// Create temporary file
-	tmpFile, err := os.CreateTemp("", "tk-describe-*.txt")
-	if err != nil {
-		_, _ = fmt.Fprintf(ctx.Err, "Error: failed to create temporary file: %v\n", err)
-		return 1
-	}
-	tmpPath := tmpFile.Name()
-	defer os.Remove(tmpPath) // Clean up temp file
-
-	// Write current description to temp file
-	if currentDesc != "" {
-		if _, err := tmpFile.WriteString(currentDesc); err != nil {
-			_, _ = fmt.Fprintf(ctx.Err, "Error: failed to write to temporary file: %v\n", err)
+	var newDesc string
+	if len(messages) > 0 {
+		// Each -m is its own paragraph, as with git commit -m
+		newDesc = strings.Join(messages, "\n\n")
+	} else {
+		edited, err := editDescription(ctx, t.Description)
+		if err != nil {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
 			return 1
 		}
-	}
-	if err := tmpFile.Close(); err != nil {
-		_, _ = fmt.Fprintf(ctx.Err, "Error: failed to close temporary file: %v\n", err)
-		return 1
-	}
-
-	// Launch editor
-	// Split editor command to handle cases like "code --wait" or "vim -f"
-	editorParts := strings.Fields(editor)
-	if len(editorParts) == 0 {
-		editorParts = []string{"vi"}
-	}
-
-	// Append the temp file path as the last argument
-	cmd := exec.Command(editorParts[0], append(editorParts[1:], tmpPath)...)
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
 
-	if err := cmd.Run(); err != nil {
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			_, _ = fmt.Fprintf(ctx.Err, "Error: editor exited with code %d; description unchanged.\n", exitErr.ExitCode())
-			return 1
+		// If empty after stripping, leave description unchanged
+		if strings.TrimSpace(edited) == "" {
+			_, _ = fmt.Fprintln(ctx.Out, "Empty description; leaving existing description unchanged.")
+			return 0
 		}
-		_, _ = fmt.Fprintf(ctx.Err, "Error: failed to run editor: %v\n", err)
-		return 1
-	}
-
-	// Read edited content
-	newText, err := os.ReadFile(tmpPath)
-	if err != nil {
-		_, _ = fmt.Fprintf(ctx.Err, "Error: failed to read edited file: %v\n", err)
-		return 1
-	}
-
-	newTextStr := string(newText)
-	newTextStripped := strings.TrimSpace(newTextStr)
-
-	// If empty after stripping, leave description unchanged
-	if newTextStripped == "" {
-		_, _ = fmt.Fprintln(ctx.Out, "Empty description; leaving existing description unchanged.")
-		return 0
+		newDesc = edited
 	}
 
 	// Update task description (preserve trailing newlines, but strip trailing whitespace from each line)
-	t.Description = strings.TrimRight(newTextStr, " \t\n\r")
-	t.UpdatedAt = time.Now().UTC()
+	t.Description = strings.TrimRight(newDesc, " \t\n\r")
+	t.UpdatedAt = ctx.clock().Now().UTC()
 
 	// Save task
 	if err := st.Save(t); err != nil {
@@ -138,20 +95,33 @@ func RunDescribe(args []string, ctx CommandContext) int {
 	return 0
 }
 
+// editDescription opens the user's editor on the current description and
+// returns the edited text.
+func editDescription(ctx CommandContext, currentDesc string) (string, error) {
+	content, err := ctx.editor(getEditor(), "tk-describe-*.txt").Capture([]byte(currentDesc))
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return "", fmt.Errorf("editor exited with code %d; description unchanged", exitErr.ExitCode())
+		}
+		return "", err
+	}
+	return string(content), nil
+}
+
 func describeUsage(app string) string {
 	return fmt.Sprintf(`Usage:
-  %s describe <id>
+  %s describe [-m <text>]... <id>
 
-`, app)
-}
+Opens an editor on the task description: $TK_EDITOR, $VISUAL or $EDITOR,
+whichever is set first, else the editor config key, else vi. Saving an
+empty file leaves the description unchanged. GUI editors such as code and
+subl get their wait flag if it is missing; TK_EDITOR="code --wait" sets it
+explicitly.
 
-// getEditor returns the editor command to use, checking EDITOR, VISUAL, or defaulting to "vi".
-func getEditor() string {
-	if editor := os.Getenv("EDITOR"); editor != "" {
-		return editor
-	}
-	if editor := os.Getenv("VISUAL"); editor != "" {
-		return editor
-	}
-	return "vi"
+Flags:
+  -m, --message <text>  set the description without opening an editor;
+                        repeat to add paragraphs separated by blank lines
+
+`, app)
 }
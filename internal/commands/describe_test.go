@@ -0,0 +1,16 @@
+package commands
+
+import "testing"
+
+func TestRunDescribeMessage(t *testing.T) {
+	setupWorkspace(t)
+	orig := addAndLoad(t, []string{"-d", "old", "describe me"})
+
+	ctx, _, errOut := newTestContext()
+	if code := RunDescribe([]string{"-m", "first", "--message", "second", orig.ID}, ctx); code != 0 {
+		t.Fatalf("RunDescribe() exit code = %d, stderr: %s", code, errOut.String())
+	}
+	if got := loadOnlyTask(t).Description; got != "first\n\nsecond" {
+		t.Errorf("Description = %q, want %q", got, "first\n\nsecond")
+	}
+}
@@ -0,0 +1,374 @@
+package commands
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/sjatkinson/threadkeeper/internal/config"
+	"github.com/sjatkinson/threadkeeper/internal/store"
+	"github.com/sjatkinson/threadkeeper/internal/task"
+)
+
+// Doctor checks.
+const (
+	checkCorruptThread   = "corrupt-thread"
+	checkDuplicateShort  = "duplicate-short-id"
+	checkMissingShort    = "missing-short-id"
+	checkMissingBlob     = "missing-blob"
+	checkOrphanBlob      = "orphan-blob"
+	checkMisplacedThread = "misplaced-thread"
+)
+
+// doctorChecks lists the checks in the order they are reported.
+var doctorChecks = []string{
+	checkCorruptThread,
+	checkDuplicateShort,
+	checkMissingShort,
+	checkMissingBlob,
+	checkOrphanBlob,
+	checkMisplacedThread,
+}
+
+// doctorIssue is a single workspace health problem.
+type doctorIssue struct {
+	Check  string
+	Detail string
+	// Fixable issues are repaired by --fix.
+	Fixable bool
+}
+
+func RunDoctor(args []string, ctx CommandContext) int {
+	fs := flag.NewFlagSet(ctx.AppName+" doctor", flag.ContinueOnError)
+	fs.SetOutput(ctx.Err)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintln(ctx.Err, doctorUsage(ctx.AppName))
+	}
+
+	var fix bool
+	fs.BoolVar(&fix, "fix", false, "repair missing short_ids and misplaced threads")
+
+	if err := fs.Parse(args); err != nil {
+		_, _ = fmt.Fprintln(ctx.Err)
+		_, _ = fmt.Fprintln(ctx.Err, doctorUsage(ctx.AppName))
+		return 2
+	}
+
+	if len(fs.Args()) != 0 {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: unexpected arguments\n")
+		_, _ = fmt.Fprintln(ctx.Err, doctorUsage(ctx.AppName))
+		return 2
+	}
+
+	// Get paths and verify threads directory exists
+	paths, err := config.GetPaths(ctx.workspace())
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+
+	if _, err := os.Stat(paths.ThreadsDir); err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: threads directory does not exist at %s. Run '%s init' first.\n", paths.ThreadsDir, ctx.AppName)
+		return 1
+	}
+
+	st := newStore(paths)
+	issues, err := diagnoseWorkspace(st, paths)
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+
+	if fix && hasFixableIssue(issues) {
+		if err := st.WithLock(func() error {
+			return fixWorkspace(st)
+		}); err != nil {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+			return 1
+		}
+		fixed := issues
+		if issues, err = diagnoseWorkspace(st, paths); err != nil {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+			return 1
+		}
+		_, _ = fmt.Fprintf(ctx.Out, "Fixed %d issues.\n", len(fixed)-len(issues))
+	}
+
+	if len(issues) == 0 {
+		_, _ = fmt.Fprintln(ctx.Out, "No problems found.")
+		return 0
+	}
+
+	displayDoctorIssues(ctx.Out, issues, fix)
+	return 1
+}
+
+// diagnoseWorkspace runs every doctor check against the workspace and returns
+// the issues found, ordered by check.
+func diagnoseWorkspace(st *store.FileStore, paths config.Paths) ([]doctorIssue, error) {
+	tasks, skipped, err := st.LoadAllWithSkipped()
+	if err != nil {
+		return nil, err
+	}
+
+	var issues []doctorIssue
+	for _, s := range skipped {
+		issues = append(issues, doctorIssue{Check: checkCorruptThread, Detail: fmt.Sprintf("%s: %v", s.Path, s.Err)})
+	}
+
+	issues = append(issues, checkShortIDs(tasks)...)
+
+	// Thread directories as found on disk, which may differ from ThreadDir
+	// for misplaced threads
+	dirs, err := threadDirsOnDisk(paths.ThreadsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	shared := make(map[string]bool)
+	for _, t := range tasks {
+		threadDir, ok := dirs[t.ID]
+		if !ok {
+			threadDir = st.ThreadDir(t.ID)
+		}
+		blobIssues, err := checkBlobs(t.ID, threadDir, paths.BlobsDir, shared)
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, blobIssues...)
+	}
+	sharedIssues, err := checkSharedBlobs(st, paths.BlobsDir, shared)
+	if err != nil {
+		return nil, err
+	}
+	issues = append(issues, sharedIssues...)
+
+	ids := make([]string, 0, len(dirs))
+	for id := range dirs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		if want := st.ThreadDir(id); filepath.Clean(dirs[id]) != filepath.Clean(want) {
+			issues = append(issues, doctorIssue{
+				Check:   checkMisplacedThread,
+				Detail:  fmt.Sprintf("%s is in %s, want %s", id, dirs[id], want),
+				Fixable: true,
+			})
+		}
+	}
+
+	rank := make(map[string]int, len(doctorChecks))
+	for i, check := range doctorChecks {
+		rank[check] = i
+	}
+	sort.SliceStable(issues, func(i, j int) bool {
+		return rank[issues[i].Check] < rank[issues[j].Check]
+	})
+	return issues, nil
+}
+
+// checkShortIDs reports open tasks sharing a short_id and open tasks without one.
+func checkShortIDs(tasks []*task.Task) []doctorIssue {
+	var issues []doctorIssue
+	for _, t := range tasks {
+		if t.Status == task.StatusOpen && t.ShortID == nil {
+			issues = append(issues, doctorIssue{Check: checkMissingShort, Detail: fmt.Sprintf("open task %s has no short_id", t.ID), Fixable: true})
+		}
+	}
+	for _, c := range store.FindShortIDConflicts(tasks) {
+		issues = append(issues, doctorIssue{Check: checkDuplicateShort, Detail: fmt.Sprintf("short_id %d is used by %v (run reindex)", c.ShortID, c.TaskIDs)})
+	}
+	return issues
+}
+
+// checkBlobs reports current attachments whose blob is missing, and blobs left
+// in the thread's own blobs directory that no attachment event refers to.
+// Shared store paths referenced by the thread's events are added to shared.
+func checkBlobs(threadID, threadDir, blobsDir string, shared map[string]bool) ([]doctorIssue, error) {
+	events, err := loadAttachments(threadDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load attachments for %s: %w", threadID, err)
+	}
+
+	var issues []doctorIssue
+	for _, event := range computeCurrentAttachments(events) {
+		if event.Att.Blob == nil {
+			continue
+		}
+		p := blobPath(blobsDir, threadDir, *event.Att.Blob)
+		if p == "" {
+			continue
+		}
+		if _, err := os.Stat(p); err != nil {
+			issues = append(issues, doctorIssue{Check: checkMissingBlob, Detail: fmt.Sprintf("%s attachment %s (%s) has no blob at %s", threadID, event.Att.AttID, event.Att.Name, p)})
+		}
+	}
+
+	// Any event keeps its blob referenced, so removed attachments stay in history
+	referenced := make(map[string]bool)
+	for _, event := range events {
+		if event.Att.Blob != nil {
+			if p := blobPathIn(threadBlobsDir(threadDir), *event.Att.Blob); p != "" {
+				referenced[filepath.Clean(p)] = true
+			}
+			if p := blobPathIn(blobsDir, *event.Att.Blob); p != "" {
+				shared[filepath.Clean(p)] = true
+			}
+		}
+	}
+
+	err = filepath.WalkDir(threadBlobsDir(threadDir), func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || referenced[filepath.Clean(path)] {
+			return nil
+		}
+		issues = append(issues, doctorIssue{Check: checkOrphanBlob, Detail: fmt.Sprintf("%s blob %s is not referenced by any attachment", threadID, path)})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan blobs for %s: %w", threadID, err)
+	}
+	return issues, nil
+}
+
+// checkSharedBlobs reports blobs in the workspace store that neither a thread
+// nor a trashed thread refers to. referenced holds the paths collected by
+// checkBlobs.
+func checkSharedBlobs(st *store.FileStore, blobsDir string, referenced map[string]bool) ([]doctorIssue, error) {
+	trashed, err := st.LoadTrash()
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range trashed {
+		events, err := loadAttachments(filepath.Join(st.TrashDir(), t.ID))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load attachments for trashed %s: %w", t.ID, err)
+		}
+		for _, event := range events {
+			if event.Att.Blob != nil {
+				if p := blobPathIn(blobsDir, *event.Att.Blob); p != "" {
+					referenced[filepath.Clean(p)] = true
+				}
+			}
+		}
+	}
+
+	var issues []doctorIssue
+	err = filepath.WalkDir(blobsDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || referenced[filepath.Clean(path)] {
+			return nil
+		}
+		issues = append(issues, doctorIssue{Check: checkOrphanBlob, Detail: fmt.Sprintf("blob %s is not referenced by any attachment", path)})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan blob store: %w", err)
+	}
+	return issues, nil
+}
+
+// threadDirsOnDisk maps each thread ID (directory name) to the directory it
+// was found in, scanning every bucket regardless of width.
+func threadDirsOnDisk(threadsDir string) (map[string]string, error) {
+	buckets, err := os.ReadDir(threadsDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read threads directory: %w", err)
+	}
+
+	dirs := make(map[string]string)
+	for _, bucket := range buckets {
+		if !bucket.IsDir() {
+			continue
+		}
+		bucketPath := filepath.Join(threadsDir, bucket.Name())
+		threads, err := os.ReadDir(bucketPath)
+		if err != nil {
+			// Unreadable buckets are reported by the corrupt-thread check
+			continue
+		}
+		for _, thread := range threads {
+			if thread.IsDir() {
+				dirs[thread.Name()] = filepath.Join(bucketPath, thread.Name())
+			}
+		}
+	}
+	return dirs, nil
+}
+
+// fixWorkspace repairs the fixable issues: it moves misplaced threads into
+// their buckets and assigns short_ids to open tasks missing one. Callers must
+// hold the workspace lock.
+func fixWorkspace(st *store.FileStore) error {
+	if _, err := st.Rebucket(); err != nil {
+		return err
+	}
+
+	tasks, err := st.LoadAll()
+	if err != nil {
+		return err
+	}
+	for _, t := range tasks {
+		if err := st.EnsureShortID(t); err != nil {
+			return fmt.Errorf("failed to assign short_id to task %s: %w", t.ID, err)
+		}
+	}
+	return nil
+}
+
+func hasFixableIssue(issues []doctorIssue) bool {
+	for _, issue := range issues {
+		if issue.Fixable {
+			return true
+		}
+	}
+	return false
+}
+
+func displayDoctorIssues(out io.Writer, issues []doctorIssue, fixAttempted bool) {
+	fixable := 0
+	for _, issue := range issues {
+		_, _ = fmt.Fprintf(out, "%s: %s\n", issue.Check, issue.Detail)
+		if issue.Fixable {
+			fixable++
+		}
+	}
+	_, _ = fmt.Fprintf(out, "\n%d issues found.", len(issues))
+	if fixable > 0 && !fixAttempted {
+		_, _ = fmt.Fprintf(out, " Run with --fix to repair %d of them.", fixable)
+	}
+	_, _ = fmt.Fprintln(out)
+}
+
+func doctorUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s doctor [--fix]
+
+Checks workspace health and exits 1 if any problem is found:
+  corrupt-thread      thread.json that cannot be read or parsed
+  duplicate-short-id  open tasks sharing a short_id (fix with reindex)
+  missing-short-id    open tasks without a short_id            [fixable]
+  missing-blob        note attachments whose blob is missing
+  orphan-blob         blobs no attachment refers to
+  misplaced-thread    thread directories in the wrong bucket   [fixable]
+
+Flags:
+  --fix          repair the fixable problems, then report what remains
+
+`, app)
+}
@@ -0,0 +1,75 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sjatkinson/threadkeeper/internal/config"
+)
+
+func TestRunDoctor(t *testing.T) {
+	tmpDir := setupWorkspace(t)
+	tk := addAndLoad(t, []string{"healthy"})
+
+	ctx, out, errOut := newTestContext()
+	if code := RunDoctor(nil, ctx); code != 0 {
+		t.Fatalf("RunDoctor() on a clean workspace exit code = %d, stdout: %s, stderr: %s", code, out.String(), errOut.String())
+	}
+
+	paths, err := config.GetPaths("")
+	if err != nil {
+		t.Fatalf("GetPaths() error = %v", err)
+	}
+	st := newStore(paths)
+
+	// Drop the short_id, move the thread to a wrong bucket, and leave an orphan blob
+	tk.ShortID = nil
+	if err := st.Save(tk); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	misplaced := filepath.Join(tmpDir, "threads", "zz", tk.ID)
+	if err := os.MkdirAll(filepath.Dir(misplaced), 0755); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	if err := os.Rename(st.ThreadDir(tk.ID), misplaced); err != nil {
+		t.Fatalf("Rename() error = %v", err)
+	}
+	orphan := filepath.Join(misplaced, "blobs", "sha256", "ab", "cd", "abcdef")
+	if err := os.MkdirAll(filepath.Dir(orphan), 0755); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	if err := os.WriteFile(orphan, []byte("x"), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	ctx, out, _ = newTestContext()
+	if code := RunDoctor(nil, ctx); code != 1 {
+		t.Errorf("RunDoctor() exit code = %d, want 1", code)
+	}
+	for _, check := range []string{checkMissingShort, checkOrphanBlob, checkMisplacedThread} {
+		if !strings.Contains(out.String(), check+":") {
+			t.Errorf("stdout = %q, want a %s issue", out.String(), check)
+		}
+	}
+
+	// --fix repairs all but the orphan blob
+	ctx, out, errOut = newTestContext()
+	if code := RunDoctor([]string{"--fix"}, ctx); code != 1 {
+		t.Errorf("RunDoctor(--fix) exit code = %d, want 1, stderr: %s", code, errOut.String())
+	}
+	if strings.Contains(out.String(), checkMissingShort+":") || strings.Contains(out.String(), checkMisplacedThread+":") {
+		t.Errorf("stdout after --fix = %q, want fixable issues gone", out.String())
+	}
+	if !strings.Contains(out.String(), checkOrphanBlob+":") {
+		t.Errorf("stdout after --fix = %q, want orphan blob still reported", out.String())
+	}
+	fixed, err := st.GetByID(tk.ID)
+	if err != nil {
+		t.Fatalf("GetByID() after --fix error = %v", err)
+	}
+	if fixed.ShortID == nil {
+		t.Errorf("ShortID after --fix = nil, want assigned")
+	}
+}
@@ -4,10 +4,8 @@ import (
 	"flag"
 	"fmt"
 	"os"
-	"time"
 
 	"github.com/sjatkinson/threadkeeper/internal/config"
-	"github.com/sjatkinson/threadkeeper/internal/store"
 	"github.com/sjatkinson/threadkeeper/internal/task"
 )
 
@@ -18,6 +16,9 @@ func RunDone(args []string, ctx CommandContext) int {
 		_, _ = fmt.Fprintln(ctx.Err, doneUsage(ctx.AppName))
 	}
 
+	var sel bulkSelection
+	sel.register(fs)
+
 	if err := fs.Parse(args); err != nil {
 		_, _ = fmt.Fprintln(ctx.Err)
 		_, _ = fmt.Fprintln(ctx.Err, doneUsage(ctx.AppName))
@@ -25,13 +26,17 @@ func RunDone(args []string, ctx CommandContext) int {
 	}
 
 	ids := fs.Args()
-	if len(ids) == 0 {
-		_, _ = fmt.Fprintf(ctx.Err, "Error: missing argument: task ID required\n")
+	if len(ids) > 0 && sel.active() {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: task IDs cannot be combined with filter flags\n")
+		return 2
+	}
+	if len(ids) == 0 && !sel.active() {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: missing argument: task ID or filter flag required\n")
 		return 2
 	}
 
 	// Get paths and verify tasks directory exists
-	paths, err := config.GetPaths(ctx.Path)
+	paths, err := config.GetPaths(ctx.workspace())
 	if err != nil {
 		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
 		return 1
@@ -42,9 +47,15 @@ func RunDone(args []string, ctx CommandContext) int {
 		return 1
 	}
 
-	// Load and resolve tasks
-	st := store.NewFileStore(paths.ThreadsDir)
+	// Load and resolve tasks, or select them by filter
+	st := newStore(paths)
 	var tasks []*task.Task
+	if sel.active() {
+		var code int
+		if tasks, code = selectBulkTasks(st, ctx, sel, task.StatusDone, "complete"); code != 0 {
+			return code
+		}
+	}
 	for _, idStr := range ids {
 		t, err := st.ResolveID(idStr)
 		if err != nil {
@@ -55,7 +66,9 @@ func RunDone(args []string, ctx CommandContext) int {
 	}
 
 	// Mark each task as done
-	now := time.Now().UTC()
+	now := ctx.clock().Now().UTC()
+	rec := newOpRecorder("done")
+	defer rec.commit(st, paths, ctx)
 	for _, t := range tasks {
 		// Capture short_id before removing it for output
 		sidStr := "?"
@@ -63,8 +76,11 @@ func RunDone(args []string, ctx CommandContext) int {
 			sidStr = fmt.Sprintf("%d", *t.ShortID)
 		}
 
+		snap := snapshotTask(t)
 		t.Status = task.StatusDone
 		t.UpdatedAt = now
+		t.CompletedAt = &now
+		t.StopTimer(now)
 		// Remove short_id since it's only for open tasks
 		t.ShortID = nil
 
@@ -72,16 +88,34 @@ func RunDone(args []string, ctx CommandContext) int {
 			_, _ = fmt.Fprintf(ctx.Err, "Error: failed to save task %s: %v\n", t.ID, err)
 			return 1
 		}
+		rec.add(t.ID, snap)
 
-		_, _ = fmt.Fprintf(ctx.Out, "Marked task %s (%s) as done\n", sidStr, t.ID)
+		ctx.Success("Marked task %s (%s) as done\n", sidStr, t.ID)
 	}
 
+	if sel.active() {
+		ctx.Success("Marked %d task(s) as done\n", len(tasks))
+	}
+
+	autoReindex(st, ctx, rec)
 	return 0
 }
 
 func doneUsage(app string) string {
 	return fmt.Sprintf(`Usage:
   %s done <id> [<id> ...]
+  %s done [flags]
+
+Instead of IDs, select tasks with the same filters as list. Tasks that are
+already done are skipped. Changing more than %d tasks this way asks for
+confirmation on a terminal and requires --force elsewhere.
+
+Flags:
+  -a, --all                     select tasks of any status (default: only open)
+  -p, --project <name>          select by project
+  --status <open|done|archived> select by status
+  --tag <tag>                   select by tag (repeat to AND tags)
+  --force                       don't ask for confirmation
 
-`, app)
+`, app, app, BulkConfirmThreshold)
 }
@@ -0,0 +1,90 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sjatkinson/threadkeeper/internal/config"
+	"github.com/sjatkinson/threadkeeper/internal/date"
+	"github.com/sjatkinson/threadkeeper/internal/task"
+)
+
+func TestRunDoneRecordsCompletedAt(t *testing.T) {
+	setupWorkspace(t)
+
+	ctx, _, errOut := newTestContext()
+	if code := RunAdd([]string{"finish me"}, ctx); code != 0 {
+		t.Fatalf("RunAdd() exit code = %d, stderr: %s", code, errOut.String())
+	}
+
+	paths, err := config.GetPaths("")
+	if err != nil {
+		t.Fatalf("GetPaths() error = %v", err)
+	}
+	st := newStore(paths)
+	loadOne := func() *task.Task {
+		tasks, err := st.LoadAll()
+		if err != nil || len(tasks) != 1 {
+			t.Fatalf("LoadAll() = %d tasks, err = %v", len(tasks), err)
+		}
+		return tasks[0]
+	}
+	id := loadOne().ID
+
+	now := time.Date(2026, 5, 1, 9, 30, 0, 0, time.UTC)
+	ctx, _, errOut = newTestContext()
+	ctx.Clock = date.FixedClock{FixedTime: now}
+	if code := RunDone([]string{id}, ctx); code != 0 {
+		t.Fatalf("RunDone() exit code = %d, stderr: %s", code, errOut.String())
+	}
+	if got := loadOne().CompletedAt; got == nil || !got.Equal(now) {
+		t.Errorf("CompletedAt after done = %v, want %v", got, now)
+	}
+
+	ctx, _, errOut = newTestContext()
+	if code := RunReopen([]string{id}, ctx); code != 0 {
+		t.Fatalf("RunReopen() exit code = %d, stderr: %s", code, errOut.String())
+	}
+	if got := loadOne().CompletedAt; got != nil {
+		t.Errorf("CompletedAt after reopen = %v, want nil", got)
+	}
+}
+
+func TestRunDoneByFilter(t *testing.T) {
+	setupWorkspace(t)
+	add := func(args ...string) {
+		ctx, _, errOut := newTestContext()
+		if code := RunAdd(args, ctx); code != 0 {
+			t.Fatalf("RunAdd() exit code = %d, stderr: %s", code, errOut.String())
+		}
+	}
+	for i := 0; i < BulkConfirmThreshold+1; i++ {
+		add("--force", "--project", "migration", "step")
+	}
+	add("--project", "other", "unrelated")
+
+	ctx, _, _ := newTestContext()
+	if code := RunDone([]string{"--project", "migration", "1"}, ctx); code != 2 {
+		t.Errorf("RunDone(ids and filter) exit code = %d, want 2", code)
+	}
+
+	// Above the threshold without a terminal, --force is required
+	ctx, _, errOut := newTestContext()
+	ctx.Stdin = strings.NewReader("")
+	if code := RunDone([]string{"--project", "migration"}, ctx); code != 1 || !strings.Contains(errOut.String(), "--force") {
+		t.Errorf("RunDone() = %d, stderr %q, want 1 asking for --force", code, errOut.String())
+	}
+
+	ctx, out, errOut := newTestContext()
+	if code := RunDone([]string{"--force", "--project", "migration"}, ctx); code != 0 {
+		t.Fatalf("RunDone() exit code = %d, stderr: %s", code, errOut.String())
+	}
+	if want := fmt.Sprintf("Marked %d task(s) as done", BulkConfirmThreshold+1); !strings.Contains(out.String(), want) {
+		t.Errorf("RunDone() stdout = %q, want %q", out.String(), want)
+	}
+	if got := openShortIDs(t); len(got) != 1 {
+		t.Errorf("open tasks after bulk done = %v, want only the other project", got)
+	}
+}
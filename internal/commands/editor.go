@@ -0,0 +1,132 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/sjatkinson/threadkeeper/internal/config"
+)
+
+// Editor captures text interactively, starting from initial content.
+type Editor interface {
+	Capture(initial []byte) ([]byte, error)
+}
+
+// ExecEditor runs an external editor command on a temporary file.
+type ExecEditor struct {
+	// Command is the editor command line, e.g. "vim" or "code --wait".
+	Command string
+	// Pattern is the os.CreateTemp pattern for the temporary file.
+	Pattern string
+}
+
+// Capture writes initial to a temporary file, opens the editor on it, and
+// returns the saved content. A failing editor is returned as an error
+// wrapping the *exec.ExitError.
+func (e ExecEditor) Capture(initial []byte) ([]byte, error) {
+	// Create temporary file
+	tmpFile, err := os.CreateTemp("", e.Pattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tmpPath := tmpFile.Name()
+	defer os.Remove(tmpPath) // Clean up temp file
+
+	if _, err := tmpFile.Write(initial); err != nil {
+		_ = tmpFile.Close()
+		return nil, fmt.Errorf("failed to write temp file: %w", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close temp file: %w", err)
+	}
+
+	// Split editor command to handle cases like "code --wait" or "vim -f"
+	editorParts := strings.Fields(e.Command)
+	if len(editorParts) == 0 {
+		editorParts = []string{"vi"}
+	}
+	editorParts = withWaitFlag(editorParts)
+
+	// Append the temp file path as the last argument
+	cmd := exec.Command(editorParts[0], append(editorParts[1:], tmpPath)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("editor exited with error: %w", err)
+	}
+
+	// Read the file content
+	content, err := os.ReadFile(tmpPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read edited file: %w", err)
+	}
+	return content, nil
+}
+
+// guiWaitFlags maps GUI editors that return before the file is closed to
+// the flags that make them wait. The first flag is the one withWaitFlag
+// adds.
+var guiWaitFlags = map[string][]string{
+	"code":          {"--wait", "-w"},
+	"code-insiders": {"--wait", "-w"},
+	"codium":        {"--wait", "-w"},
+	"cursor":        {"--wait", "-w"},
+	"subl":          {"-w", "--wait"},
+	"mate":          {"-w", "--wait"},
+	"zed":           {"--wait", "-w"},
+	"gvim":          {"-f", "--nofork"},
+	"mvim":          {"-f", "--nofork"},
+}
+
+// withWaitFlag adds the wait flag to a GUI editor command line that lacks
+// one. Without it the editor returns at once, the file is read before the
+// user has typed anything, and the edit is silently cancelled as empty.
+func withWaitFlag(parts []string) []string {
+	name := strings.TrimSuffix(filepath.Base(parts[0]), ".exe")
+	flags, ok := guiWaitFlags[name]
+	if !ok {
+		return parts
+	}
+	for _, arg := range parts[1:] {
+		for _, flag := range flags {
+			if arg == flag {
+				return parts
+			}
+		}
+	}
+	return append([]string{parts[0], flags[0]}, parts[1:]...)
+}
+
+// editor returns the context editor, falling back to an ExecEditor running
+// command on a temporary file named after pattern.
+func (ctx CommandContext) editor(command, pattern string) Editor {
+	if ctx.Editor == nil {
+		return ExecEditor{Command: command, Pattern: pattern}
+	}
+	return ctx.Editor
+}
+
+// editorEnvVars are the environment variables naming the user's editor, in
+// the order getEditor checks them.
+var editorEnvVars = []string{"TK_EDITOR", "VISUAL", "EDITOR"}
+
+// getEditor returns the editor command line to use: the first of
+// $TK_EDITOR, $VISUAL and $EDITOR that is set, then the editor config key,
+// then "vi". The result may hold arguments, such as "code --wait";
+// ExecEditor splits it.
+func getEditor() string {
+	for _, name := range editorEnvVars {
+		if editor := strings.TrimSpace(os.Getenv(name)); editor != "" {
+			return editor
+		}
+	}
+	if editor, _ := config.LoadEditor(); editor != "" {
+		return editor
+	}
+	return "vi"
+}
@@ -0,0 +1,226 @@
+package commands
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeEditor records the content it was opened with and returns a canned
+// result instead of launching a process.
+type fakeEditor struct {
+	result  []byte
+	err     error
+	initial []byte
+}
+
+func (e *fakeEditor) Capture(initial []byte) ([]byte, error) {
+	e.initial = initial
+	return e.result, e.err
+}
+
+func TestStripNoteHeader(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"untouched header", noteHeader, ""},
+		{"body after header", noteHeader + "hello\nworld\n", "hello\nworld\n"},
+		{"heading in body is kept", noteHeader + "# Title\nbody", "# Title\nbody"},
+		{"header deleted", "just text", "just text"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stripNoteHeader(tt.content); got != tt.want {
+				t.Errorf("stripNoteHeader(%q) = %q, want %q", tt.content, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunAttachNoteWithEditor(t *testing.T) {
+	tmpDir := setupWorkspace(t)
+	tk := addAndLoad(t, []string{"notes"})
+
+	// Leaving only the header cancels without error
+	ed := &fakeEditor{result: []byte(noteHeader)}
+	ctx, _, errOut := newTestContext()
+	ctx.Editor = ed
+	if code := RunAttach([]string{"note", "--id", tk.ID}, ctx); code != 0 {
+		t.Fatalf("RunAttach() exit code = %d, stderr: %s", code, errOut.String())
+	}
+	if !strings.Contains(errOut.String(), "cancelled") {
+		t.Errorf("stderr = %q, want cancellation message", errOut.String())
+	}
+	if string(ed.initial) != noteHeader {
+		t.Errorf("editor opened with %q, want the note header", ed.initial)
+	}
+
+	ed = &fakeEditor{result: []byte(noteHeader + "remember this\n")}
+	ctx, out, errOut := newTestContext()
+	ctx.Editor = ed
+	if code := RunAttach([]string{"note", "--id", tk.ID}, ctx); code != 0 {
+		t.Fatalf("RunAttach() exit code = %d, stderr: %s", code, errOut.String())
+	}
+	if !strings.Contains(out.String(), "Attached note") {
+		t.Errorf("stdout = %q, want attach confirmation", out.String())
+	}
+
+	paths, err := filepath.Glob(filepath.Join(tmpDir, "blobs", "sha256", "*", "*", "*"))
+	if err != nil || len(paths) != 1 {
+		t.Fatalf("blobs = %v, err = %v, want exactly one", paths, err)
+	}
+	content, err := os.ReadFile(paths[0])
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if string(content) != "remember this\n" {
+		t.Errorf("blob content = %q, want header stripped", content)
+	}
+}
+
+func TestRunDescribeWithEditor(t *testing.T) {
+	setupWorkspace(t)
+	tk := addAndLoad(t, []string{"-d", "before", "describe me"})
+
+	ed := &fakeEditor{result: []byte("after\n\n")}
+	ctx, _, errOut := newTestContext()
+	ctx.Editor = ed
+	if code := RunDescribe([]string{tk.ID}, ctx); code != 0 {
+		t.Fatalf("RunDescribe() exit code = %d, stderr: %s", code, errOut.String())
+	}
+	if string(ed.initial) != "before" {
+		t.Errorf("editor opened with %q, want %q", ed.initial, "before")
+	}
+	if got := loadOnlyTask(t).Description; got != "after" {
+		t.Errorf("Description = %q, want %q", got, "after")
+	}
+
+	// An empty result leaves the description unchanged
+	ctx, out, errOut := newTestContext()
+	ctx.Editor = &fakeEditor{result: []byte("  \n")}
+	if code := RunDescribe([]string{tk.ID}, ctx); code != 0 {
+		t.Fatalf("RunDescribe() exit code = %d, stderr: %s", code, errOut.String())
+	}
+	if !strings.Contains(out.String(), "unchanged") {
+		t.Errorf("stdout = %q, want unchanged message", out.String())
+	}
+	if got := loadOnlyTask(t).Description; got != "after" {
+		t.Errorf("Description = %q, want %q", got, "after")
+	}
+}
+
+func TestRunAddWithEditor(t *testing.T) {
+	setupWorkspace(t)
+
+	ed := &fakeEditor{result: []byte("First paragraph.\n\nSecond paragraph.\n\n")}
+	ctx, _, errOut := newTestContext()
+	ctx.Editor = ed
+	if code := RunAdd([]string{"--edit", "-d", "draft", "rich task"}, ctx); code != 0 {
+		t.Fatalf("RunAdd() exit code = %d, stderr: %s", code, errOut.String())
+	}
+	if string(ed.initial) != "draft" {
+		t.Errorf("editor opened with %q, want %q", ed.initial, "draft")
+	}
+	if got, want := loadOnlyTask(t).Description, "First paragraph.\n\nSecond paragraph."; got != want {
+		t.Errorf("Description = %q, want %q", got, want)
+	}
+}
+
+func TestRunAddWithEditor_Empty(t *testing.T) {
+	setupWorkspace(t)
+
+	ctx, _, errOut := newTestContext()
+	ctx.Editor = &fakeEditor{result: []byte("  \n")}
+	if code := RunAdd([]string{"--edit", "bare task"}, ctx); code != 0 {
+		t.Fatalf("RunAdd() exit code = %d, stderr: %s", code, errOut.String())
+	}
+	if got := loadOnlyTask(t).Description; got != "" {
+		t.Errorf("Description = %q, want empty", got)
+	}
+}
+
+func TestRunAddWithEditor_Fails(t *testing.T) {
+	workspace := setupWorkspace(t)
+
+	ctx, _, errOut := newTestContext()
+	ctx.Editor = &fakeEditor{err: errors.New("boom")}
+	if code := RunAdd([]string{"--edit", "lost task"}, ctx); code != 1 {
+		t.Fatalf("RunAdd() exit code = %d, want 1", code)
+	}
+	if !strings.Contains(errOut.String(), "task not added") {
+		t.Errorf("stderr = %q, want 'task not added'", errOut.String())
+	}
+	matches, _ := filepath.Glob(filepath.Join(workspace, "threads", "*", "*", "thread.json"))
+	if len(matches) != 0 {
+		t.Errorf("found %d thread files, want none", len(matches))
+	}
+}
+
+func TestGetEditor(t *testing.T) {
+	tests := []struct {
+		name   string
+		env    map[string]string
+		config string
+		want   string
+	}{
+		{"default", nil, "", "vi"},
+		{"EDITOR", map[string]string{"EDITOR": "nano"}, "", "nano"},
+		{"VISUAL over EDITOR", map[string]string{"VISUAL": "code --wait", "EDITOR": "nano"}, "", "code --wait"},
+		{"TK_EDITOR over all", map[string]string{"TK_EDITOR": "hx", "VISUAL": "code --wait", "EDITOR": "nano"}, "", "hx"},
+		{"blank is unset", map[string]string{"TK_EDITOR": " ", "EDITOR": "nano"}, "", "nano"},
+		{"config over default", nil, `editor = "code --wait"`, "code --wait"},
+		{"env over config", map[string]string{"EDITOR": "nano"}, `editor = "code --wait"`, "nano"},
+		{"blank config", nil, `editor = "  "`, "vi"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ws := setupWorkspace(t)
+			writeConfig(t, ws, tt.config)
+			for _, name := range editorEnvVars {
+				t.Setenv(name, tt.env[name])
+			}
+			if got := getEditor(); got != tt.want {
+				t.Errorf("getEditor() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecEditor_MultiWordCommand(t *testing.T) {
+	ed := ExecEditor{Command: "sed -i s/before/after/", Pattern: "tk-test-*.txt"}
+	got, err := ed.Capture([]byte("before\n"))
+	if err != nil {
+		t.Fatalf("Capture() error = %v", err)
+	}
+	if string(got) != "after\n" {
+		t.Errorf("Capture() = %q, want %q", got, "after\n")
+	}
+}
+
+func TestWithWaitFlag(t *testing.T) {
+	tests := []struct {
+		command string
+		want    string
+	}{
+		{"vim", "vim"},
+		{"code", "code --wait"},
+		{"code --wait", "code --wait"},
+		{"code -w", "code -w"},
+		{"code --new-window", "code --wait --new-window"},
+		{"/usr/local/bin/subl", "/usr/local/bin/subl -w"},
+		{"subl --wait", "subl --wait"},
+		{"gvim", "gvim -f"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			got := strings.Join(withWaitFlag(strings.Fields(tt.command)), " ")
+			if got != tt.want {
+				t.Errorf("withWaitFlag(%q) = %q, want %q", tt.command, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,226 @@
+package commands
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/sjatkinson/threadkeeper/internal/config"
+	"github.com/sjatkinson/threadkeeper/internal/store"
+	"github.com/sjatkinson/threadkeeper/internal/task"
+)
+
+// noProjectHeading groups tasks without a project in exports.
+const noProjectHeading = "No project"
+
+func RunExport(args []string, ctx CommandContext) int {
+	if len(args) == 0 {
+		_, _ = fmt.Fprintln(ctx.Err, exportUsage(ctx.AppName))
+		return 2
+	}
+
+	switch args[0] {
+	case "markdown", "md":
+		return runExportMarkdown(args[1:], ctx)
+	case "ics":
+		return runExportICS(args[1:], ctx)
+	default:
+		_, _ = fmt.Fprintf(ctx.Err, "Error: invalid export format %q (must be 'markdown' or 'ics')\n", args[0])
+		_, _ = fmt.Fprintln(ctx.Err)
+		_, _ = fmt.Fprintln(ctx.Err, exportUsage(ctx.AppName))
+		return 2
+	}
+}
+
+func runExportMarkdown(args []string, ctx CommandContext) int {
+	fs := flag.NewFlagSet(ctx.AppName+" export markdown", flag.ContinueOnError)
+	fs.SetOutput(ctx.Err)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintln(ctx.Err, exportUsage(ctx.AppName))
+	}
+
+	var (
+		all     bool
+		project string
+	)
+	fs.BoolVar(&all, "all", false, "export all tasks")
+	fs.BoolVar(&all, "a", false, "export all tasks (shorthand)")
+	fs.StringVar(&project, "project", "", "filter by project")
+	fs.StringVar(&project, "p", "", "filter by project (shorthand)")
+
+	if err := fs.Parse(args); err != nil {
+		_, _ = fmt.Fprintln(ctx.Err)
+		_, _ = fmt.Fprintln(ctx.Err, exportUsage(ctx.AppName))
+		return 2
+	}
+
+	if len(fs.Args()) != 0 {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: unexpected arguments\n")
+		_, _ = fmt.Fprintln(ctx.Err, exportUsage(ctx.AppName))
+		return 2
+	}
+
+	st, filtered, code := loadExportTasks(ctx, taskFilter{All: all, Project: project})
+	if code != 0 {
+		return code
+	}
+
+	// Collect current attachments per task
+	attachments := make(map[string][]AttachmentEvent)
+	for _, t := range filtered {
+		events, err := loadAttachments(st.ThreadDir(t.ID))
+		if err != nil {
+			if !os.IsNotExist(err) {
+				_, _ = fmt.Fprintf(ctx.Err, "Warning: failed to load attachments for %s: %v\n", t.ID, err)
+			}
+			continue
+		}
+		if current := computeCurrentAttachments(events); len(current) > 0 {
+			attachments[t.ID] = current
+		}
+	}
+
+	dateLayout, err := config.LoadDisplayDateFormat()
+	if err != nil {
+		dateLayout = config.DisplayLayoutISO // Default on error
+	}
+
+	renderMarkdown(ctx.Out, filtered, attachments, dateLayout)
+	return 0
+}
+
+// loadExportTasks loads the workspace and returns the tasks matching f.
+// On failure it reports the error and returns a non-zero exit code.
+func loadExportTasks(ctx CommandContext, f taskFilter) (*store.FileStore, []*task.Task, int) {
+	// Get paths and verify threads directory exists
+	paths, err := config.GetPaths(ctx.workspace())
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return nil, nil, 1
+	}
+
+	if _, err := os.Stat(paths.ThreadsDir); err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: threads directory does not exist at %s. Run '%s init' first.\n", paths.ThreadsDir, ctx.AppName)
+		return nil, nil, 1
+	}
+
+	st := newStore(paths)
+	tasks, err := st.LoadAll()
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return nil, nil, 1
+	}
+
+	// Ensure open tasks have short_ids, as list does
+	for _, t := range tasks {
+		if t.Status == task.StatusOpen {
+			_ = st.EnsureShortID(t)
+		}
+	}
+
+	return st, filterTasks(tasks, f), 0
+}
+
+// renderMarkdown writes tasks as a GitHub-flavored markdown checklist grouped
+// by project. Projects are sorted by name; tasks without a project come last.
+func renderMarkdown(out io.Writer, tasks []*task.Task, attachments map[string][]AttachmentEvent, dateLayout string) {
+	if len(tasks) == 0 {
+		_, _ = fmt.Fprintln(out, "_No tasks._")
+		return
+	}
+
+	groups := make(map[string][]*task.Task)
+	var projects []string
+	for _, t := range tasks {
+		if _, ok := groups[t.Project]; !ok {
+			projects = append(projects, t.Project)
+		}
+		groups[t.Project] = append(groups[t.Project], t)
+	}
+	sort.Slice(projects, func(i, j int) bool {
+		// Empty project sorts last
+		if projects[i] == "" {
+			return false
+		}
+		if projects[j] == "" {
+			return true
+		}
+		return projects[i] < projects[j]
+	})
+
+	for i, p := range projects {
+		if i > 0 {
+			_, _ = fmt.Fprintln(out)
+		}
+		heading := p
+		if heading == "" {
+			heading = noProjectHeading
+		}
+		_, _ = fmt.Fprintf(out, "## %s\n\n", heading)
+
+		for _, t := range groups[p] {
+			_, _ = fmt.Fprintln(out, markdownTaskLine(t, dateLayout))
+			for _, att := range attachments[t.ID] {
+				_, _ = fmt.Fprintf(out, "  - %s\n", markdownAttachment(att))
+			}
+		}
+	}
+}
+
+// markdownTaskLine formats one checklist item.
+func markdownTaskLine(t *task.Task, dateLayout string) string {
+	box := "[ ]"
+	if t.Status != task.StatusOpen {
+		box = "[x]"
+	}
+
+	parts := []string{"-", box}
+	if t.Status == task.StatusOpen && t.ShortID != nil {
+		parts = append(parts, fmt.Sprintf("`%d`", *t.ShortID))
+	}
+	parts = append(parts, t.Title)
+	if t.DueAt != nil {
+		parts = append(parts, fmt.Sprintf("(due %s)", t.DueAt.Format(dateLayout)))
+	}
+	if t.Status == task.StatusArchived {
+		parts = append(parts, "(archived)")
+	}
+	for _, tag := range t.Tags {
+		parts = append(parts, "#"+tag)
+	}
+	return strings.Join(parts, " ")
+}
+
+// markdownAttachment formats an attachment as a nested bullet body.
+func markdownAttachment(att AttachmentEvent) string {
+	if att.Att.Kind == "link" {
+		label := att.Att.Label
+		if label == "" {
+			label = att.Att.URL
+		}
+		return fmt.Sprintf("link: [%s](%s)", label, att.Att.URL)
+	}
+	return fmt.Sprintf("%s: %s", att.Att.Kind, att.Att.Name)
+}
+
+func exportUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s export markdown [flags]
+  %s export ics [--all]
+
+Formats:
+  markdown   checklist grouped by project, with due dates, #tags, and
+             attachments as nested bullets
+  ics        iCalendar feed with one VTODO per task that has a due date
+
+Output goes to stdout.
+
+Flags:
+  -a, --all                   export all tasks (default: only open)
+  -p, --project <name>        filter by project (markdown only)
+
+`, app, app)
+}
@@ -0,0 +1,147 @@
+package commands
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/sjatkinson/threadkeeper/internal/task"
+)
+
+// icsMaxLineOctets is the RFC 5545 limit for a content line, excluding CRLF.
+const icsMaxLineOctets = 75
+
+// icsTimestampLayout is the UTC DATE-TIME form used for DTSTAMP, CREATED and COMPLETED.
+const icsTimestampLayout = "20060102T150405Z"
+
+func runExportICS(args []string, ctx CommandContext) int {
+	fs := flag.NewFlagSet(ctx.AppName+" export ics", flag.ContinueOnError)
+	fs.SetOutput(ctx.Err)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintln(ctx.Err, exportUsage(ctx.AppName))
+	}
+
+	var all bool
+	fs.BoolVar(&all, "all", false, "export all tasks")
+	fs.BoolVar(&all, "a", false, "export all tasks (shorthand)")
+
+	if err := fs.Parse(args); err != nil {
+		_, _ = fmt.Fprintln(ctx.Err)
+		_, _ = fmt.Fprintln(ctx.Err, exportUsage(ctx.AppName))
+		return 2
+	}
+
+	if len(fs.Args()) != 0 {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: unexpected arguments\n")
+		_, _ = fmt.Fprintln(ctx.Err, exportUsage(ctx.AppName))
+		return 2
+	}
+
+	_, tasks, code := loadExportTasks(ctx, taskFilter{All: all})
+	if code != 0 {
+		return code
+	}
+
+	renderICS(ctx.Out, tasks, ctx.clock().Now(), icsTodo)
+	return 0
+}
+
+// icsComponent is the iCalendar component renderICS writes for each task.
+type icsComponent string
+
+const (
+	// icsTodo is a to-do with the due date as DUE, and the task's status.
+	icsTodo icsComponent = "VTODO"
+	// icsEvent is an all-day event on the due date, for calendars that show
+	// events but not to-dos. Events carry no task status.
+	icsEvent icsComponent = "VEVENT"
+)
+
+// renderICS writes an iCalendar feed with one component per task that has a
+// due date; tasks without one are skipped. Lines are folded per RFC 5545 and
+// end in CRLF.
+func renderICS(out io.Writer, tasks []*task.Task, now time.Time, component icsComponent) {
+	stamp := now.UTC().Format(icsTimestampLayout)
+
+	writeICSLine(out, "BEGIN:VCALENDAR")
+	writeICSLine(out, "VERSION:2.0")
+	writeICSLine(out, "PRODID:-//threadkeeper//tk export ics//EN")
+	for _, t := range tasks {
+		if t.DueAt == nil {
+			continue
+		}
+
+		writeICSLine(out, "BEGIN:"+string(component))
+		writeICSLine(out, "UID:"+t.ID)
+		writeICSLine(out, "DTSTAMP:"+stamp)
+		writeICSLine(out, "CREATED:"+t.CreatedAt.UTC().Format(icsTimestampLayout))
+		writeICSLine(out, "SUMMARY:"+escapeICSText(t.Title))
+		// Due dates are whole days, so emit a DATE rather than a DATE-TIME
+		if component == icsEvent {
+			writeICSLine(out, "DTSTART;VALUE=DATE:"+t.DueAt.UTC().Format("20060102"))
+			writeICSLine(out, "DTEND;VALUE=DATE:"+t.DueAt.UTC().AddDate(0, 0, 1).Format("20060102"))
+		} else {
+			writeICSLine(out, "DUE;VALUE=DATE:"+t.DueAt.UTC().Format("20060102"))
+		}
+
+		var categories []string
+		if t.Project != "" {
+			categories = append(categories, escapeICSText(t.Project))
+		}
+		for _, tag := range t.Tags {
+			categories = append(categories, escapeICSText(tag))
+		}
+		if len(categories) > 0 {
+			writeICSLine(out, "CATEGORIES:"+strings.Join(categories, ","))
+		}
+
+		if component == icsTodo {
+			switch t.Status {
+			case task.StatusOpen:
+				writeICSLine(out, "STATUS:NEEDS-ACTION")
+			case task.StatusDone:
+				// Tasks completed before completed_at existed fall back to UpdatedAt
+				completed := t.UpdatedAt
+				if t.CompletedAt != nil {
+					completed = *t.CompletedAt
+				}
+				writeICSLine(out, "STATUS:COMPLETED")
+				writeICSLine(out, "COMPLETED:"+completed.UTC().Format(icsTimestampLayout))
+			}
+		}
+		writeICSLine(out, "END:"+string(component))
+	}
+	writeICSLine(out, "END:VCALENDAR")
+}
+
+// icsTextEscaper escapes values of the RFC 5545 TEXT type.
+var icsTextEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// escapeICSText escapes a value of the RFC 5545 TEXT type.
+func escapeICSText(s string) string {
+	return icsTextEscaper.Replace(s)
+}
+
+// writeICSLine writes a content line folded at 75 octets, never splitting a
+// UTF-8 sequence. Continuation lines start with a single space.
+func writeICSLine(out io.Writer, line string) {
+	var b strings.Builder
+	limit := icsMaxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// The leading space counts toward the next line's limit
+		limit = icsMaxLineOctets - 1
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+	_, _ = io.WriteString(out, b.String())
+}
@@ -0,0 +1,113 @@
+package commands
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/sjatkinson/threadkeeper/internal/config"
+	"github.com/sjatkinson/threadkeeper/internal/task"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	due := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+	one, two := 1, 2
+	tasks := []*task.Task{
+		{ID: "A", Title: "loose end", Status: task.StatusOpen, ShortID: &two, Tags: []string{}},
+		{ID: "B", Title: "ship it", Status: task.StatusOpen, Project: "web", ShortID: &one, DueAt: &due, Tags: []string{"release", "prio:High"}},
+		{ID: "C", Title: "write docs", Status: task.StatusDone, Project: "api", Tags: []string{}},
+	}
+	attachments := map[string][]AttachmentEvent{
+		"B": {
+			{Op: "add", Att: Attachment{Kind: "note", Name: "plan.md"}},
+			{Op: "add", Att: Attachment{Kind: "link", URL: "https://example.com/pr/1", Label: "PR"}},
+		},
+	}
+
+	var buf bytes.Buffer
+	renderMarkdown(&buf, tasks, attachments, config.DisplayLayoutISO)
+
+	want := "## api\n\n" +
+		"- [x] write docs\n" +
+		"\n## web\n\n" +
+		"- [ ] `1` ship it (due 2026-03-09) #release #prio:High\n" +
+		"  - note: plan.md\n" +
+		"  - link: [PR](https://example.com/pr/1)\n" +
+		"\n## No project\n\n" +
+		"- [ ] `2` loose end\n"
+	if got := buf.String(); got != want {
+		t.Errorf("renderMarkdown() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRenderICS(t *testing.T) {
+	due := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+	created := time.Date(2026, 3, 1, 8, 30, 0, 0, time.UTC)
+	finished := time.Date(2026, 3, 8, 17, 0, 0, 0, time.UTC)
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	tasks := []*task.Task{
+		{ID: "A", Title: "no due date", Status: task.StatusOpen, CreatedAt: created, Tags: []string{}},
+		{ID: "B", Title: "ship it, finally", Status: task.StatusOpen, Project: "web", DueAt: &due, CreatedAt: created, Tags: []string{"release"}},
+		{ID: "C", Title: "write docs", Status: task.StatusDone, DueAt: &due, CreatedAt: created, UpdatedAt: finished, Tags: []string{}},
+	}
+
+	var buf bytes.Buffer
+	renderICS(&buf, tasks, now, icsTodo)
+
+	want := "BEGIN:VCALENDAR\r\n" +
+		"VERSION:2.0\r\n" +
+		"PRODID:-//threadkeeper//tk export ics//EN\r\n" +
+		"BEGIN:VTODO\r\n" +
+		"UID:B\r\n" +
+		"DTSTAMP:20260310T120000Z\r\n" +
+		"CREATED:20260301T083000Z\r\n" +
+		"SUMMARY:ship it\\, finally\r\n" +
+		"DUE;VALUE=DATE:20260309\r\n" +
+		"CATEGORIES:web,release\r\n" +
+		"STATUS:NEEDS-ACTION\r\n" +
+		"END:VTODO\r\n" +
+		"BEGIN:VTODO\r\n" +
+		"UID:C\r\n" +
+		"DTSTAMP:20260310T120000Z\r\n" +
+		"CREATED:20260301T083000Z\r\n" +
+		"SUMMARY:write docs\r\n" +
+		"DUE;VALUE=DATE:20260309\r\n" +
+		"STATUS:COMPLETED\r\n" +
+		"COMPLETED:20260308T170000Z\r\n" +
+		"END:VTODO\r\n" +
+		"END:VCALENDAR\r\n"
+	if got := buf.String(); got != want {
+		t.Errorf("renderICS() =\n%q\nwant:\n%q", got, want)
+	}
+}
+
+func TestWriteICSLine_Folds(t *testing.T) {
+	var buf bytes.Buffer
+	writeICSLine(&buf, "SUMMARY:"+strings.Repeat("é", 80))
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n")
+	if len(lines) < 2 {
+		t.Fatalf("writeICSLine() did not fold: %q", buf.String())
+	}
+	var unfolded string
+	for i, line := range lines {
+		if len(line) > icsMaxLineOctets {
+			t.Errorf("line %d is %d octets, want <= %d", i, len(line), icsMaxLineOctets)
+		}
+		if !utf8.ValidString(line) {
+			t.Errorf("line %d splits a UTF-8 sequence: %q", i, line)
+		}
+		if i > 0 {
+			if !strings.HasPrefix(line, " ") {
+				t.Errorf("continuation line %d = %q, want leading space", i, line)
+			}
+			line = line[1:]
+		}
+		unfolded += line
+	}
+	if want := "SUMMARY:" + strings.Repeat("é", 80); unfolded != want {
+		t.Errorf("unfolded = %q, want %q", unfolded, want)
+	}
+}
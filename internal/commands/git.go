@@ -0,0 +1,168 @@
+package commands
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/sjatkinson/threadkeeper/internal/config"
+	"github.com/sjatkinson/threadkeeper/internal/store"
+)
+
+// gitExcludes keeps workspace files that are private to one machine out of
+// auto-commits: the LoadAll cache and its temp files, and the lock file.
+var gitExcludes = []string{
+	":(exclude)" + store.IndexFileName + "*",
+	":(exclude)" + store.LockFileName,
+}
+
+// AutoCommit commits any changes in the workspace to git when the
+// git_autocommit config key is set, after command ran with args.
+// It does nothing when the workspace is not in a git repository or nothing
+// changed. Failures are reported as warnings: the command that made the
+// change has already succeeded.
+func AutoCommit(ctx CommandContext, command string, args []string) {
+	enabled, err := config.LoadGitAutocommit()
+	if err != nil || !enabled {
+		return
+	}
+
+	paths, err := config.GetPaths(ctx.workspace())
+	if err != nil {
+		return
+	}
+	if err := checkGitWorkspace(paths.Workspace); err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Warning: git auto-commit skipped: %v\n", err)
+		return
+	}
+
+	if err := gitCommitWorkspace(paths.Workspace, autoCommitMessage(ctx.AppName, command, args)); err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Warning: git auto-commit failed: %v\n", err)
+	}
+}
+
+// maxCommitSubject keeps generated commit subjects to a conventional length.
+const maxCommitSubject = 72
+
+// autoCommitMessage describes a command invocation as a one-line commit
+// subject, e.g. "tk: done 3".
+func autoCommitMessage(app, command string, args []string) string {
+	msg := strings.Join(strings.Fields(app+": "+command+" "+strings.Join(args, " ")), " ")
+	if r := []rune(msg); len(r) > maxCommitSubject {
+		msg = string(r[:maxCommitSubject-3]) + "..."
+	}
+	return msg
+}
+
+// gitCommitWorkspace stages and commits everything under dir except
+// gitExcludes. A clean tree is not an error.
+func gitCommitWorkspace(dir, message string) error {
+	pathspec := append([]string{"--", "."}, gitExcludes...)
+
+	status, err := runGit(dir, append([]string{"status", "--porcelain"}, pathspec...)...)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(status) == "" {
+		return nil
+	}
+
+	if _, err := runGit(dir, append([]string{"add", "-A"}, pathspec...)...); err != nil {
+		return err
+	}
+	// Limit the commit to the workspace so unrelated staged files are left alone
+	_, err = runGit(dir, "commit", "-q", "-m", message, "--", ".")
+	return err
+}
+
+// checkGitWorkspace reports why dir can't be committed to, or nil if it can.
+func checkGitWorkspace(dir string) error {
+	if _, err := exec.LookPath("git"); err != nil {
+		return errors.New("git is not on PATH")
+	}
+	if out, err := runGit(dir, "rev-parse", "--is-inside-work-tree"); err != nil || strings.TrimSpace(out) != "true" {
+		return fmt.Errorf("workspace %s is not in a git repository", dir)
+	}
+	return nil
+}
+
+// runGit runs git in dir and returns its stdout. Errors include git's stderr.
+func runGit(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return stdout.String(), nil
+}
+
+func RunSync(args []string, ctx CommandContext) int {
+	fs := flag.NewFlagSet(ctx.AppName+" sync", flag.ContinueOnError)
+	fs.SetOutput(ctx.Err)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintln(ctx.Err, syncUsage(ctx.AppName))
+	}
+
+	if err := fs.Parse(args); err != nil {
+		_, _ = fmt.Fprintln(ctx.Err)
+		_, _ = fmt.Fprintln(ctx.Err, syncUsage(ctx.AppName))
+		return 2
+	}
+
+	if len(fs.Args()) != 0 {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: unexpected arguments\n")
+		_, _ = fmt.Fprintln(ctx.Err, syncUsage(ctx.AppName))
+		return 2
+	}
+
+	paths, err := config.GetPaths(ctx.workspace())
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+
+	if _, err := os.Stat(paths.Workspace); err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: workspace does not exist at %s. Run '%s init' first.\n", paths.Workspace, ctx.AppName)
+		return 1
+	}
+
+	if err := checkGitWorkspace(paths.Workspace); err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+
+	for _, gitArgs := range [][]string{{"pull", "--rebase"}, {"push"}} {
+		cmd := exec.Command("git", append([]string{"-C", paths.Workspace}, gitArgs...)...)
+		cmd.Stdout = ctx.Out
+		cmd.Stderr = ctx.Err
+		if err := cmd.Run(); err != nil {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: git %s failed: %v\n", strings.Join(gitArgs, " "), err)
+			return 1
+		}
+	}
+
+	ctx.Success("Workspace synced\n")
+	return 0
+}
+
+func syncUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s sync
+
+Runs 'git pull --rebase' and then 'git push' in the workspace. The
+workspace must be in a git repository with an upstream configured.
+
+Set git_autocommit = true in config.toml to have commands that change
+tasks commit the workspace after each change.
+
+`, app)
+}
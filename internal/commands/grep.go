@@ -0,0 +1,154 @@
+package commands
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/sjatkinson/threadkeeper/internal/config"
+	"github.com/sjatkinson/threadkeeper/internal/task"
+)
+
+func RunGrep(args []string, ctx CommandContext) int {
+	fs := flag.NewFlagSet(ctx.AppName+" grep", flag.ContinueOnError)
+	fs.SetOutput(ctx.Err)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintln(ctx.Err, grepUsage(ctx.AppName))
+	}
+
+	var (
+		threadID string
+		useRegex bool
+		since    string
+		until    string
+	)
+	fs.StringVar(&threadID, "id", "", "only search notes on this thread")
+	fs.BoolVar(&useRegex, "regex", false, "treat pattern as a regular expression")
+	fs.StringVar(&since, "since", "", "only threads updated since a date or within a window (7d, 2w, 12h)")
+	fs.StringVar(&until, "until", "", "only threads updated before a date or window")
+
+	if err := fs.Parse(args); err != nil {
+		_, _ = fmt.Fprintln(ctx.Err)
+		_, _ = fmt.Fprintln(ctx.Err, grepUsage(ctx.AppName))
+		return 2
+	}
+
+	rest := fs.Args()
+	if len(rest) != 1 {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: expected exactly one pattern\n")
+		return 2
+	}
+
+	match, err := newTextMatcher(rest[0], useRegex)
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: invalid regex: %v\n", err)
+		return 2
+	}
+
+	window, code := parseTimeWindow(since, until, ctx)
+	if code != 0 {
+		return code
+	}
+
+	// Get paths and verify threads directory exists
+	paths, err := config.GetPaths(ctx.workspace())
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+
+	if _, err := os.Stat(paths.ThreadsDir); err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: threads directory does not exist at %s. Run '%s init' first.\n", paths.ThreadsDir, ctx.AppName)
+		return 1
+	}
+
+	st := newStore(paths)
+	var tasks []*task.Task
+	if threadID != "" {
+		t, err := st.ResolveID(threadID)
+		if err != nil {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+			return 1
+		}
+		tasks = []*task.Task{t}
+	} else {
+		tasks, err = st.LoadAll()
+		if err != nil {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+			return 1
+		}
+	}
+
+	// Narrow by updated_at before reading any blobs
+	for _, t := range window.filter(tasks) {
+		threadDir := st.ThreadDir(t.ID)
+		events, err := loadAttachments(threadDir)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				_, _ = fmt.Fprintf(ctx.Err, "Warning: failed to load attachments for %s: %v\n", t.ID, err)
+			}
+			continue
+		}
+
+		for _, att := range computeCurrentAttachments(events) {
+			// Only notes have content; links and blob-less notes are skipped
+			if att.Att.Kind != "note" || att.Att.Blob == nil {
+				continue
+			}
+			path := blobPath(paths.BlobsDir, threadDir, *att.Att.Blob)
+			if path == "" {
+				continue
+			}
+			content, err := os.ReadFile(path)
+			if err != nil {
+				if !os.IsNotExist(err) {
+					_, _ = fmt.Fprintf(ctx.Err, "Warning: failed to read note %s on %s: %v\n", att.Att.AttID, t.ID, err)
+				}
+				continue
+			}
+			grepLines(ctx.Out, t.ID, att.Att.AttID, content, match)
+		}
+	}
+
+	return 0
+}
+
+// grepLines prints each matching line of content as
+// "<thread-id>:<att-id>:<line>:<text>", like grep -n.
+func grepLines(out io.Writer, threadID, attID string, content []byte, match func(string) bool) {
+	sc := bufio.NewScanner(bytes.NewReader(content))
+	sc.Buffer(make([]byte, 0, 64*1024), len(content)+1)
+	lineNo := 0
+	for sc.Scan() {
+		lineNo++
+		line := sc.Text()
+		if match(line) {
+			_, _ = fmt.Fprintf(out, "%s:%s:%d:%s\n", threadID, attID, lineNo, line)
+		}
+	}
+}
+
+func grepUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s grep [flags] <pattern>
+
+Searches the contents of note attachments and prints matching lines as
+<thread-id>:<att-id>:<line>:<text>. Matching is a case-insensitive
+literal by default. Link attachments are skipped.
+
+Flags:
+  --id <id>       only search notes on this thread
+  --regex         treat the pattern as a Go regular expression
+  --since <when>  only threads updated since a date, or within a window
+                  such as 7d, 2w or 12h
+  --until <when>  only threads updated before the end of a date, or before
+                  the start of a window
+
+Dates accept the same input as --due on add (e.g. today, 2026-03-01), in
+the timezone config key.
+
+`, app)
+}
@@ -0,0 +1,71 @@
+package commands
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/sjatkinson/threadkeeper/internal/config"
+)
+
+func TestGrepLines(t *testing.T) {
+	content := []byte("intro\nMigration plan\nsteps\nrollback the migration\n")
+	match, err := newTextMatcher("migration", false)
+	if err != nil {
+		t.Fatalf("newTextMatcher() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	grepLines(&buf, "T1", "A1", content, match)
+
+	want := "T1:A1:2:Migration plan\nT1:A1:4:rollback the migration\n"
+	if got := buf.String(); got != want {
+		t.Errorf("grepLines() = %q, want %q", got, want)
+	}
+}
+
+func TestRunGrep_SkipsLinksAndMissingBlobs(t *testing.T) {
+	setupWorkspace(t)
+
+	ctx, _, errOut := newTestContext()
+	if code := RunAdd([]string{"notes"}, ctx); code != 0 {
+		t.Fatalf("RunAdd() exit code = %d, stderr: %s", code, errOut.String())
+	}
+	paths, err := config.GetPaths("")
+	if err != nil {
+		t.Fatalf("GetPaths() error = %v", err)
+	}
+	st := newStore(paths)
+	tk, err := st.ResolveID("1")
+	if err != nil {
+		t.Fatalf("ResolveID() error = %v", err)
+	}
+	dir := st.ThreadDir(tk.ID)
+
+	hash, size, err := storeBlob(paths.BlobsDir, []byte("find the needle here\n"))
+	if err != nil {
+		t.Fatalf("storeBlob() error = %v", err)
+	}
+	ts := time.Now().UTC().Format(time.RFC3339)
+	for _, ev := range []AttachmentEvent{
+		{Op: "add", TS: ts, Att: Attachment{AttID: "NOTE", Kind: "note", Name: "n", Blob: &BlobRef{Algo: "sha256", Hash: hash}, Size: size}},
+		{Op: "add", TS: ts, Att: Attachment{AttID: "GONE", Kind: "note", Name: "g", Blob: &BlobRef{Algo: "sha256", Hash: "ffff0000"}}},
+		{Op: "add", TS: ts, Att: Attachment{AttID: "LINK", Kind: "link", URL: "https://example.com/needle"}},
+	} {
+		if err := appendAttachmentEvent(st, dir, ev); err != nil {
+			t.Fatalf("appendAttachmentEvent() error = %v", err)
+		}
+	}
+
+	ctx, out, errOut := newTestContext()
+	if code := RunGrep([]string{"--id", "1", "NEEDLE"}, ctx); code != 0 {
+		t.Fatalf("RunGrep() exit code = %d, stderr: %s", code, errOut.String())
+	}
+	want := tk.ID + ":NOTE:1:find the needle here\n"
+	if got := out.String(); got != want {
+		t.Errorf("RunGrep() output = %q, want %q", got, want)
+	}
+	if errOut.Len() != 0 {
+		t.Errorf("RunGrep() stderr = %q, want empty", errOut.String())
+	}
+}
@@ -0,0 +1,302 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+
+	"github.com/sjatkinson/threadkeeper/internal/config"
+	"github.com/sjatkinson/threadkeeper/internal/date"
+	"github.com/sjatkinson/threadkeeper/internal/task"
+)
+
+// NewAPIHandler returns the read-only HTTP API over ctx's workspace:
+//
+//	GET /tasks                       tasks matching list-style query filters
+//	GET /tasks/{id}                  one task and its current attachments
+//	GET /tasks/{id}/notes/{att}      a note's content, by index or att_id
+//
+// Every request loads from disk, so the API always reflects the workspace.
+func NewAPIHandler(ctx CommandContext) http.Handler {
+	api := &apiHandler{ctx: ctx}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /tasks", api.listTasks)
+	mux.HandleFunc("GET /tasks/{id}", api.getTask)
+	mux.HandleFunc("GET /tasks/{id}/notes/{att}", api.getNote)
+	return mux
+}
+
+type apiHandler struct {
+	ctx CommandContext
+}
+
+// apiTask is the GET /tasks/{id} response.
+type apiTask struct {
+	Task        *task.Task      `json:"task"`
+	Attachments []apiAttachment `json:"attachments"`
+}
+
+// apiAttachment is a current attachment with the 1-based index that
+// show and open use for it.
+type apiAttachment struct {
+	Index int    `json:"index"`
+	TS    string `json:"ts"`
+	Attachment
+}
+
+// apiError is the body of every non-2xx response.
+type apiError struct {
+	Error string `json:"error"`
+}
+
+func (a *apiHandler) listTasks(w http.ResponseWriter, r *http.Request) {
+	paths, ok := a.paths(w)
+	if !ok {
+		return
+	}
+
+	q := r.URL.Query()
+	f, limit, err := a.queryFilter(q)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tasks, err := loadAllTasks(newStore(paths), a.ctx)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	filtered := filterTasks(tasks, f)
+	if limit > 0 && limit < len(filtered) {
+		filtered = filtered[:limit]
+	}
+	if filtered == nil {
+		filtered = []*task.Task{}
+	}
+	writeAPIJSON(w, filtered)
+}
+
+func (a *apiHandler) getTask(w http.ResponseWriter, r *http.Request) {
+	paths, t, ok := a.lookupTask(w, r)
+	if !ok {
+		return
+	}
+	st := newStore(paths)
+
+	events, err := loadAttachments(st.ThreadDir(t.ID))
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, fmt.Sprintf("failed to load attachments: %v", err))
+		return
+	}
+
+	resp := apiTask{Task: t, Attachments: []apiAttachment{}}
+	for i, e := range computeCurrentAttachments(events) {
+		resp.Attachments = append(resp.Attachments, apiAttachment{Index: i + 1, TS: e.TS, Attachment: e.Att})
+	}
+	writeAPIJSON(w, resp)
+}
+
+func (a *apiHandler) getNote(w http.ResponseWriter, r *http.Request) {
+	paths, t, ok := a.lookupTask(w, r)
+	if !ok {
+		return
+	}
+	st := newStore(paths)
+
+	threadDir := st.ThreadDir(t.ID)
+	events, err := loadAttachments(threadDir)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, fmt.Sprintf("failed to load attachments: %v", err))
+		return
+	}
+
+	// Match open: a number is a 1-based index, anything else an att_id
+	current := computeCurrentAttachments(events)
+	var target *Attachment
+	ref := r.PathValue("att")
+	if n, err := strconv.Atoi(ref); err == nil {
+		if n >= 1 && n <= len(current) {
+			target = &current[n-1].Att
+		}
+	} else {
+		for i := range current {
+			if current[i].Att.AttID == ref {
+				target = &current[i].Att
+				break
+			}
+		}
+	}
+	if target == nil {
+		writeAPIError(w, http.StatusNotFound, fmt.Sprintf("attachment %q not found", ref))
+		return
+	}
+	if target.Kind != "note" || target.Blob == nil {
+		writeAPIError(w, http.StatusNotFound, fmt.Sprintf("attachment %q is not a note", ref))
+		return
+	}
+
+	path := blobPath(paths.BlobsDir, threadDir, *target.Blob)
+	if path == "" {
+		writeAPIError(w, http.StatusInternalServerError, fmt.Sprintf("unsupported blob algorithm %q", target.Blob.Algo))
+		return
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, fmt.Sprintf("failed to open blob: %v", err))
+		return
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, fmt.Sprintf("failed to open blob: %v", err))
+		return
+	}
+	if target.MediaType != "" {
+		w.Header().Set("Content-Type", target.MediaType)
+	}
+	http.ServeContent(w, r, target.Name, info.ModTime(), f)
+}
+
+// lookupTask finds the task named by the request's {id}, writing an error
+// response when there is none. An id that is neither a short_id nor a
+// durable ID is refused before the workspace is touched, and the lookup
+// never assigns a short_id, so GETs can't read outside the threads
+// directory or change it.
+func (a *apiHandler) lookupTask(w http.ResponseWriter, r *http.Request) (config.Paths, *task.Task, bool) {
+	id := r.PathValue("id")
+	if _, err := strconv.Atoi(id); err != nil && !task.IsValidID(id) {
+		writeAPIError(w, http.StatusNotFound, fmt.Sprintf("task %q not found", id))
+		return config.Paths{}, nil, false
+	}
+
+	paths, ok := a.paths(w)
+	if !ok {
+		return config.Paths{}, nil, false
+	}
+	t, err := newStore(paths).LookupID(id)
+	if err != nil {
+		writeAPIError(w, http.StatusNotFound, err.Error())
+		return config.Paths{}, nil, false
+	}
+	return paths, t, true
+}
+
+// paths resolves the workspace, writing an error response when it is missing.
+func (a *apiHandler) paths(w http.ResponseWriter) (config.Paths, bool) {
+	paths, err := config.GetPaths(a.ctx.workspace())
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return config.Paths{}, false
+	}
+	if _, err := os.Stat(paths.ThreadsDir); err != nil {
+		writeAPIError(w, http.StatusInternalServerError, fmt.Sprintf("threads directory does not exist at %s", paths.ThreadsDir))
+		return config.Paths{}, false
+	}
+	return paths, true
+}
+
+// queryFilter builds a taskFilter from query parameters named after the list
+// flags: all, project, status, limit, tag, any-tag, not-tag, tag-key,
+// tag-val, overdue, due-today, due-before, due-after, created-before and
+// created-after. tag, any-tag and not-tag may be repeated.
+func (a *apiHandler) queryFilter(q url.Values) (taskFilter, int, error) {
+	boolParam := func(name string) (bool, error) {
+		v := q.Get(name)
+		if v == "" {
+			return false, nil
+		}
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return false, fmt.Errorf("%s: invalid boolean %q", name, v)
+		}
+		return b, nil
+	}
+
+	f := taskFilter{
+		Status:  q.Get("status"),
+		Project: q.Get("project"),
+		Tags:    q["tag"],
+		AnyTags: splitTagList(q["any-tag"]),
+		NotTags: splitTagList(q["not-tag"]),
+		TagKey:  q.Get("tag-key"),
+		TagVal:  q.Get("tag-val"),
+	}
+	if err := f.validateTags(); err != nil {
+		return taskFilter{}, 0, err
+	}
+	var err error
+	if f.All, err = boolParam("all"); err != nil {
+		return taskFilter{}, 0, err
+	}
+	if f.Overdue, err = boolParam("overdue"); err != nil {
+		return taskFilter{}, 0, err
+	}
+	if f.DueToday, err = boolParam("due-today"); err != nil {
+		return taskFilter{}, 0, err
+	}
+
+	limit := 0
+	if v := q.Get("limit"); v != "" {
+		if limit, err = strconv.Atoi(v); err != nil || limit < 0 {
+			return taskFilter{}, 0, fmt.Errorf("limit: invalid number %q", v)
+		}
+	}
+
+	// Resolve dates in the configured timezone, as list does
+	dateParams := []struct {
+		name string
+		dst  *string
+	}{
+		{"due-before", &f.DueBefore},
+		{"due-after", &f.DueAfter},
+		{"created-before", &f.CreatedBefore},
+		{"created-after", &f.CreatedAfter},
+	}
+	needsDates := f.Overdue || f.DueToday
+	for _, dp := range dateParams {
+		needsDates = needsDates || q.Get(dp.name) != ""
+	}
+	if needsDates {
+		tz, err := config.LoadTimezone()
+		if err != nil {
+			return taskFilter{}, 0, err
+		}
+		locale, err := config.LoadDateLocale()
+		if err != nil {
+			locale = config.DateLocaleISO // Default on error
+		}
+		f.Location = tz
+		f.Today = a.ctx.clock().Now().In(tz).Format(dueDateLayout)
+
+		for _, dp := range dateParams {
+			v := q.Get(dp.name)
+			if v == "" {
+				continue
+			}
+			canonical, err := date.ParseDate(v, locale, a.ctx.clock(), tz)
+			if err != nil {
+				return taskFilter{}, 0, fmt.Errorf("%s: %v", dp.name, err)
+			}
+			*dp.dst = canonical
+		}
+	}
+
+	return f, limit, nil
+}
+
+func writeAPIJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeAPIError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(apiError{Error: msg})
+}
@@ -0,0 +1,185 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sjatkinson/threadkeeper/internal/config"
+	"github.com/sjatkinson/threadkeeper/internal/task"
+)
+
+func TestAPIHandler(t *testing.T) {
+	setupWorkspace(t)
+	for _, args := range [][]string{
+		{"--tag", "work", "write report"},
+		{"plant tomatoes"},
+	} {
+		ctx, _, errOut := newTestContext()
+		if code := RunAdd(args, ctx); code != 0 {
+			t.Fatalf("RunAdd(%v) exit code = %d, stderr: %s", args, code, errOut.String())
+		}
+	}
+	ctx, _, errOut := newTestContext()
+	if code := RunAttach([]string{"note", "--id", "1", "--name", "outline", "--message", "# Outline"}, ctx); code != 0 {
+		t.Fatalf("RunAttach() exit code = %d, stderr: %s", code, errOut.String())
+	}
+
+	ctx, _, _ = newTestContext()
+	srv := httptest.NewServer(NewAPIHandler(ctx))
+	defer srv.Close()
+
+	get := func(path string, wantStatus int) *http.Response {
+		t.Helper()
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+		if resp.StatusCode != wantStatus {
+			t.Fatalf("GET %s status = %d, want %d", path, resp.StatusCode, wantStatus)
+		}
+		return resp
+	}
+
+	resp := get("/tasks?tag=work", http.StatusOK)
+	var tasks []*task.Task
+	if err := json.NewDecoder(resp.Body).Decode(&tasks); err != nil {
+		t.Fatalf("decode /tasks: %v", err)
+	}
+	resp.Body.Close()
+	if len(tasks) != 1 || tasks[0].Title != "write report" {
+		t.Fatalf("GET /tasks?tag=work = %+v, want the work task", tasks)
+	}
+
+	resp = get("/tasks/1", http.StatusOK)
+	var detail struct {
+		Task        *task.Task `json:"task"`
+		Attachments []struct {
+			Index int    `json:"index"`
+			Name  string `json:"name"`
+		} `json:"attachments"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&detail); err != nil {
+		t.Fatalf("decode /tasks/1: %v", err)
+	}
+	resp.Body.Close()
+	if detail.Task.ID != tasks[0].ID || len(detail.Attachments) != 1 || detail.Attachments[0].Name != "outline" {
+		t.Fatalf("GET /tasks/1 = %+v, want the work task with its note", detail)
+	}
+
+	resp = get("/tasks/1/notes/1", http.StatusOK)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if got := resp.Header.Get("Content-Type"); got != "text/markdown" {
+		t.Errorf("note Content-Type = %q, want text/markdown", got)
+	}
+	if string(body) != "# Outline" {
+		t.Errorf("note body = %q, want %q", body, "# Outline")
+	}
+
+	get("/tasks/99", http.StatusNotFound).Body.Close()
+	get("/tasks/1/notes/2", http.StatusNotFound).Body.Close()
+	get("/tasks?limit=x", http.StatusBadRequest).Body.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, srv.URL+"/tasks", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST /tasks: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("POST /tasks status = %d, want %d", resp.StatusCode, http.StatusMethodNotAllowed)
+	}
+}
+
+func TestAPIHandler_LookupsAreReadOnly(t *testing.T) {
+	ws := setupWorkspace(t)
+	tk := addAndLoad(t, []string{"write report"})
+
+	paths, err := config.GetPaths("")
+	if err != nil {
+		t.Fatalf("GetPaths() error = %v", err)
+	}
+	st := newStore(paths)
+
+	// An open task without a short_id, which ResolveID would assign and save
+	tk.ShortID = nil
+	if err := st.Save(tk); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	// A thread outside the threads directory that "../evil" would reach
+	evil := *tk
+	evil.ID = "../evil"
+	evil.Title = "outside the workspace"
+	data, err := json.Marshal(&evil)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(st.ThreadFile(evil.ID)), 0755); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	if err := os.WriteFile(st.ThreadFile(evil.ID), data, 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	// threadFiles returns the content of every thread.json in the workspace
+	threadFiles := func() map[string][]byte {
+		files := make(map[string][]byte)
+		err := filepath.WalkDir(filepath.Join(ws, "threads"), func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() || d.Name() != "thread.json" {
+				return err
+			}
+			files[path], err = os.ReadFile(path)
+			return err
+		})
+		if err != nil {
+			t.Fatalf("WalkDir() error = %v", err)
+		}
+		return files
+	}
+	before := threadFiles()
+
+	ctx, _, _ := newTestContext()
+	srv := httptest.NewServer(NewAPIHandler(ctx))
+	defer srv.Close()
+
+	for path, want := range map[string]int{
+		"/tasks/" + tk.ID:                           http.StatusOK,
+		"/tasks/..%2Fevil":                          http.StatusNotFound,
+		"/tasks/..%2Fevil/notes/1":                  http.StatusNotFound,
+		"/tasks/..%2F..%2Fevil":                     http.StatusNotFound,
+		"/tasks/%2E%2E%2Fevil":                      http.StatusNotFound,
+		"/tasks/" + tk.ID[:3] + "%2F..%2F..%2Fevil": http.StatusNotFound,
+		"/tasks/1":                                  http.StatusNotFound, // no short_id yet
+	} {
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Errorf("GET %s status = %d, want %d (body %s)", path, resp.StatusCode, want, body)
+		}
+		if bytes.Contains(body, []byte(evil.Title)) {
+			t.Errorf("GET %s returned the thread outside the workspace", path)
+		}
+	}
+
+	after := threadFiles()
+	if len(after) != len(before) {
+		t.Fatalf("thread files after GETs = %d, want %d", len(after), len(before))
+	}
+	for path, data := range before {
+		if !bytes.Equal(after[path], data) {
+			t.Errorf("GET changed %s", path)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package commands
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/sjatkinson/threadkeeper/internal/config"
+	"github.com/sjatkinson/threadkeeper/internal/store"
+	"github.com/sjatkinson/threadkeeper/internal/task"
+)
+
+// importCounts summarizes what an import did.
+type importCounts struct {
+	Created     int
+	Overwritten int
+	Skipped     int
+}
+
+func RunImport(args []string, ctx CommandContext) int {
+	if len(args) == 0 {
+		_, _ = fmt.Fprintln(ctx.Err, importUsage(ctx.AppName))
+		return 2
+	}
+
+	switch args[0] {
+	case "json":
+		return runImportJSON(args[1:], ctx)
+	default:
+		_, _ = fmt.Fprintf(ctx.Err, "Error: invalid import format %q (must be 'json')\n", args[0])
+		_, _ = fmt.Fprintln(ctx.Err)
+		_, _ = fmt.Fprintln(ctx.Err, importUsage(ctx.AppName))
+		return 2
+	}
+}
+
+func runImportJSON(args []string, ctx CommandContext) int {
+	fs := flag.NewFlagSet(ctx.AppName+" import json", flag.ContinueOnError)
+	fs.SetOutput(ctx.Err)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintln(ctx.Err, importUsage(ctx.AppName))
+	}
+
+	var (
+		path  string
+		merge bool
+	)
+	fs.StringVar(&path, "path", "", "read tasks from file (default: stdin)")
+	fs.BoolVar(&merge, "merge", false, "skip tasks whose ID already exists")
+
+	if err := fs.Parse(args); err != nil {
+		_, _ = fmt.Fprintln(ctx.Err)
+		_, _ = fmt.Fprintln(ctx.Err, importUsage(ctx.AppName))
+		return 2
+	}
+
+	if len(fs.Args()) != 0 {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: unexpected arguments\n")
+		_, _ = fmt.Fprintln(ctx.Err, importUsage(ctx.AppName))
+		return 2
+	}
+
+	// Read input
+	var in io.Reader = ctx.stdin()
+	if path != "" {
+		f, err := os.Open(path)
+		if err != nil {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+			return 1
+		}
+		defer f.Close()
+		in = f
+	}
+
+	// Parse and validate everything before touching the workspace
+	tasks, err := parseImportTasks(in, ctx)
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+
+	// Get paths and verify threads directory exists
+	paths, err := config.GetPaths(ctx.workspace())
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+
+	if _, err := os.Stat(paths.ThreadsDir); err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: threads directory does not exist at %s. Run '%s init' first.\n", paths.ThreadsDir, ctx.AppName)
+		return 1
+	}
+
+	st := newStore(paths)
+	var counts importCounts
+	if err := st.WithLock(func() error {
+		var err error
+		counts, err = importTasks(st, tasks, merge)
+		return err
+	}); err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		_, _ = fmt.Fprintf(ctx.Err, "Partial import: %d created, %d overwritten, %d skipped\n", counts.Created, counts.Overwritten, counts.Skipped)
+		return 1
+	}
+
+	_, _ = fmt.Fprintf(ctx.Out, "Imported %d tasks: %d created, %d overwritten, %d skipped\n",
+		counts.Created+counts.Overwritten, counts.Created, counts.Overwritten, counts.Skipped)
+	return 0
+}
+
+// parseImportTasks decodes a JSON array of tasks, assigns IDs to tasks that
+// lack one, and validates statuses and IDs.
+func parseImportTasks(in io.Reader, ctx CommandContext) ([]*task.Task, error) {
+	var tasks []*task.Task
+	if err := json.NewDecoder(in).Decode(&tasks); err != nil {
+		return nil, fmt.Errorf("malformed JSON (expected an array of tasks): %w", err)
+	}
+
+	now := ctx.clock().Now().UTC()
+	for i, t := range tasks {
+		if t == nil {
+			return nil, fmt.Errorf("task %d: null entry", i+1)
+		}
+		if t.ID == "" {
+			id, err := task.GenerateID()
+			if err != nil {
+				return nil, fmt.Errorf("task %d: failed to generate ID: %w", i+1, err)
+			}
+			t.ID = id
+		} else if !isValidImportID(t.ID) {
+			return nil, fmt.Errorf("task %d: invalid id %q", i+1, t.ID)
+		}
+		if t.CreatedAt.IsZero() {
+			t.CreatedAt = now
+		}
+		t.Normalize()
+		if !task.IsValidStatus(t.Status) {
+			return nil, fmt.Errorf("task %d (%s): invalid status %q", i+1, t.ID, t.Status)
+		}
+	}
+	return tasks, nil
+}
+
+// isValidImportID reports whether id is safe to use as a thread directory
+// name. Generated IDs are base32, so anything else is rejected.
+func isValidImportID(id string) bool {
+	return strings.Trim(id, "ABCDEFGHIJKLMNOPQRSTUVWXYZ234567") == ""
+}
+
+// importTasks saves tasks into the store. Existing IDs are overwritten, or
+// skipped in merge mode. An imported short_id is kept only when no other task
+// already uses it; open tasks without one get the next free short_id.
+// Callers must hold the workspace lock.
+func importTasks(st *store.FileStore, tasks []*task.Task, merge bool) (importCounts, error) {
+	var counts importCounts
+
+	existing, err := st.LoadAll()
+	if err != nil {
+		return counts, err
+	}
+	exists := make(map[string]bool, len(existing))
+	shortIDOwner := make(map[int]string)
+	for _, t := range existing {
+		exists[t.ID] = true
+		if t.ShortID != nil {
+			shortIDOwner[*t.ShortID] = t.ID
+		}
+	}
+
+	for _, t := range tasks {
+		if exists[t.ID] && merge {
+			counts.Skipped++
+			continue
+		}
+		if t.ShortID != nil {
+			if owner, ok := shortIDOwner[*t.ShortID]; ok && owner != t.ID {
+				t.ShortID = nil
+			}
+		}
+		if t.Status == task.StatusOpen && t.ShortID == nil {
+			next, err := st.GenerateNextShortID()
+			if err != nil {
+				return counts, fmt.Errorf("failed to generate short_id: %w", err)
+			}
+			t.ShortID = &next
+		}
+
+		if err := st.Save(t); err != nil {
+			return counts, fmt.Errorf("failed to save task %s: %w", t.ID, err)
+		}
+		if t.ShortID != nil {
+			shortIDOwner[*t.ShortID] = t.ID
+		}
+		if exists[t.ID] {
+			counts.Overwritten++
+		} else {
+			counts.Created++
+			exists[t.ID] = true
+		}
+	}
+	return counts, nil
+}
+
+func importUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s import json [--path <file>] [--merge]
+
+Reads a JSON array of tasks (the thread.json schema) from --path or stdin
+and saves each one. Tasks without an id get a new one.
+
+Flags:
+  --path <file>   read from file instead of stdin
+  --merge         skip tasks whose ID already exists (default: overwrite)
+
+`, app)
+}
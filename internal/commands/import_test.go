@@ -0,0 +1,110 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sjatkinson/threadkeeper/internal/config"
+)
+
+func TestRunImportJSON(t *testing.T) {
+	setupWorkspace(t)
+
+	ctx, _, errOut := newTestContext()
+	if code := RunAdd([]string{"original", "title"}, ctx); code != 0 {
+		t.Fatalf("RunAdd() exit code = %d, stderr: %s", code, errOut.String())
+	}
+	paths, err := config.GetPaths("")
+	if err != nil {
+		t.Fatalf("GetPaths() error = %v", err)
+	}
+	st := newStore(paths)
+	existing, err := st.ResolveID("1")
+	if err != nil {
+		t.Fatalf("ResolveID() error = %v", err)
+	}
+
+	input := `[
+		{"id": "` + existing.ID + `", "title": "replaced title", "status": "open", "short_id": 1, "tags": []},
+		{"title": "brand new", "status": "done", "created_at": "2026-01-05T09:00:00Z", "tags": ["Imported"]}
+	]`
+
+	tests := []struct {
+		name      string
+		args      []string
+		wantOut   string
+		wantTitle string
+	}{
+		{"merge skips existing", []string{"json", "--merge"}, "1 created, 0 overwritten, 1 skipped", "original title"},
+		{"default overwrites", []string{"json"}, "1 created, 1 overwritten, 0 skipped", "replaced title"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, out, errOut := newTestContext()
+			ctx.Stdin = strings.NewReader(input)
+			if code := RunImport(tt.args, ctx); code != 0 {
+				t.Fatalf("RunImport() exit code = %d, stderr: %s", code, errOut.String())
+			}
+			if !strings.Contains(out.String(), tt.wantOut) {
+				t.Errorf("RunImport() output = %q, want to contain %q", out.String(), tt.wantOut)
+			}
+			got, err := st.GetByID(existing.ID)
+			if err != nil {
+				t.Fatalf("GetByID() error = %v", err)
+			}
+			if got.Title != tt.wantTitle {
+				t.Errorf("Title = %q, want %q", got.Title, tt.wantTitle)
+			}
+		})
+	}
+
+	// The task without an id gets a fresh ID on every run
+	tasks, err := st.LoadAll()
+	if err != nil {
+		t.Fatalf("LoadAll() error = %v", err)
+	}
+	var imported int
+	for _, tk := range tasks {
+		if tk.Title == "brand new" {
+			imported++
+			if len(tk.Tags) != 1 || tk.Tags[0] != "imported" {
+				t.Errorf("Tags = %v, want [imported]", tk.Tags)
+			}
+		}
+	}
+	if imported != 2 {
+		t.Errorf("found %d imported id-less tasks, want 2 (one per run)", imported)
+	}
+}
+
+func TestRunImportJSON_Invalid(t *testing.T) {
+	setupWorkspace(t)
+
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{"malformed JSON", `[{"title": "x"`, "malformed JSON"},
+		{"not an array", `{"title": "x"}`, "malformed JSON"},
+		{"invalid status", `[{"title": "x", "status": "paused"}]`, "invalid status"},
+		{"unsafe id", `[{"id": "../escape", "title": "x"}]`, "invalid id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, out, errOut := newTestContext()
+			ctx.Stdin = strings.NewReader(tt.input)
+			if code := RunImport([]string{"json"}, ctx); code != 1 {
+				t.Errorf("RunImport() exit code = %d, want 1", code)
+			}
+			if !strings.Contains(errOut.String(), tt.wantErr) {
+				t.Errorf("RunImport() stderr = %q, want to contain %q", errOut.String(), tt.wantErr)
+			}
+			if out.Len() != 0 {
+				t.Errorf("RunImport() stdout = %q, want empty", out.String())
+			}
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 
 	"github.com/sjatkinson/threadkeeper/internal/config"
+	"github.com/sjatkinson/threadkeeper/internal/date"
 )
 
 // CommandContext provides the context needed for command execution.
@@ -17,6 +18,46 @@ type CommandContext struct {
 	Out     io.Writer
 	Err     io.Writer
 	Path    string
+	Clock   date.Clock // nil means the system clock
+	Stdin   io.Reader  // nil means os.Stdin
+	Editor  Editor     // nil means the user's $EDITOR
+
+	WorkspacePath string // set by the global --workspace/-w flag; wins over Path
+	Quiet         bool   // suppress success messages; errors still go to Err
+	Verbose       bool   // report extra diagnostics (e.g. skipped files) on Err
+}
+
+// Success prints a confirmation line to Out unless Quiet is set.
+func (ctx CommandContext) Success(format string, args ...any) {
+	if ctx.Quiet {
+		return
+	}
+	_, _ = fmt.Fprintf(ctx.Out, format, args...)
+}
+
+// workspace returns the custom workspace path to pass to config.GetPaths,
+// preferring WorkspacePath over Path. Empty means the default workspace.
+func (ctx CommandContext) workspace() string {
+	if ctx.WorkspacePath != "" {
+		return ctx.WorkspacePath
+	}
+	return ctx.Path
+}
+
+// clock returns the context clock, falling back to the system clock.
+func (ctx CommandContext) clock() date.Clock {
+	if ctx.Clock == nil {
+		return date.RealClock{}
+	}
+	return ctx.Clock
+}
+
+// stdin returns the context input, falling back to os.Stdin.
+func (ctx CommandContext) stdin() io.Reader {
+	if ctx.Stdin == nil {
+		return os.Stdin
+	}
+	return ctx.Stdin
 }
 
 func RunInit(args []string, ctx CommandContext) int {
@@ -39,7 +80,7 @@ func RunInit(args []string, ctx CommandContext) int {
 		return 2
 	}
 
-	paths, err := config.GetPaths(ctx.Path)
+	paths, err := config.GetPaths(ctx.workspace())
 	if err != nil {
 		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
 		return 1
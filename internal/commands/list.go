@@ -5,14 +5,17 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sort"
 	"strings"
+	"text/template"
+	"time"
 
 	"github.com/sjatkinson/threadkeeper/internal/config"
-	"github.com/sjatkinson/threadkeeper/internal/store"
+	"github.com/sjatkinson/threadkeeper/internal/date"
 	"github.com/sjatkinson/threadkeeper/internal/task"
 )
 
-func RunList(args []string, ctx CommandContext) int {
+func RunList(args []string, ctx CommandContext) (code int) {
 	fs := flag.NewFlagSet(ctx.AppName+" list", flag.ContinueOnError)
 	fs.SetOutput(ctx.Err)
 	fs.Usage = func() {
@@ -20,11 +23,29 @@ func RunList(args []string, ctx CommandContext) int {
 	}
 
 	var (
-		all     bool
-		project string
-		status  string
-		limit   int
-		tag     string
+		all      bool
+		project  string
+		status   string
+		limit    int
+		offset   int
+		tags     stringList
+		anyTags  stringList
+		notTags  stringList
+		tagKey   string
+		tagVal   string
+		dups     bool
+		format   string
+		noPager  bool
+		overdue  bool
+		dueToday bool
+		groupBy  string
+		relative bool
+		summary  bool
+
+		dueBefore     string
+		dueAfter      string
+		createdBefore string
+		createdAfter  string
 	)
 
 	fs.BoolVar(&all, "all", false, "show all tasks")
@@ -34,7 +55,24 @@ func RunList(args []string, ctx CommandContext) int {
 	fs.StringVar(&status, "status", "", "filter by status (open|done|archived)")
 	fs.IntVar(&limit, "limit", 0, "limit number of tasks")
 	fs.IntVar(&limit, "n", 0, "limit number of tasks (shorthand)")
-	fs.StringVar(&tag, "tag", "", "filter by tag")
+	fs.IntVar(&offset, "offset", 0, "skip the first n matching tasks")
+	fs.Var(&tags, "tag", "filter by tag (repeatable; all must match)")
+	fs.Var(&anyTags, "any-tag", "comma-separated tags; any must match (repeatable)")
+	fs.Var(&notTags, "not-tag", "exclude tasks with this tag (repeatable)")
+	fs.StringVar(&tagKey, "tag-key", "", "filter by key of a key:value tag")
+	fs.StringVar(&tagVal, "tag-val", "", "filter by key:value tag")
+	fs.BoolVar(&dups, "flag-dups", false, "mark open tasks that share a title")
+	fs.StringVar(&format, "format", "", "print each task with a template or preset (oneline, detailed)")
+	fs.BoolVar(&noPager, "no-pager", false, "don't pipe output through the pager")
+	fs.BoolVar(&overdue, "overdue", false, "only open tasks due before today")
+	fs.BoolVar(&dueToday, "due-today", false, "only tasks due today")
+	fs.StringVar(&groupBy, "group-by", "", "group tasks under headers (project|tag|status)")
+	fs.BoolVar(&relative, "relative", false, "also show how far off due dates are")
+	fs.BoolVar(&summary, "summary", false, "end with a count of open, overdue and due-this-week tasks")
+	fs.StringVar(&dueBefore, "due-before", "", "only tasks due on or before date")
+	fs.StringVar(&dueAfter, "due-after", "", "only tasks due on or after date")
+	fs.StringVar(&createdBefore, "created-before", "", "only tasks created on or before date")
+	fs.StringVar(&createdAfter, "created-after", "", "only tasks created on or after date")
 
 	if err := fs.Parse(args); err != nil {
 		_, _ = fmt.Fprintln(ctx.Err)
@@ -48,8 +86,71 @@ func RunList(args []string, ctx CommandContext) int {
 		return 2
 	}
 
+	if format != "" && dups {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: --format cannot be combined with --flag-dups\n")
+		return 2
+	}
+	if format != "" && summary {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: --format cannot be combined with --summary\n")
+		return 2
+	}
+
+	// The [list] config section fills in flags not given on the command line
+	defaults, _ := config.LoadListDefaults()
+	given := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { given[f.Name] = true })
+	if !given["all"] && !given["a"] {
+		all = defaults.ShowAll
+	}
+	if !given["relative"] {
+		relative = defaults.RelativeDates
+	}
+	if !given["limit"] && !given["n"] {
+		limit = defaults.Limit
+	}
+	groupBySource := "--group-by"
+	if !given["group-by"] && format == "" && defaults.GroupBy != "" {
+		groupBy, groupBySource = defaults.GroupBy, "group_by in [list] config"
+	}
+
+	if offset < 0 {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: --offset must not be negative\n")
+		return 2
+	}
+
+	switch groupBy {
+	case "", groupByProject, groupByTag, groupByStatus:
+	default:
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %s must be %s, %s or %s\n", groupBySource, groupByProject, groupByTag, groupByStatus)
+		return 2
+	}
+	if format != "" && groupBy != "" {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: --format cannot be combined with --group-by\n")
+		return 2
+	}
+
+	// Load display date format from config
+	dateLayout, err := config.LoadDisplayDateFormat()
+	if err != nil {
+		dateLayout = config.DisplayLayoutISO // Default on error
+	}
+
+	// Compile the template before loading anything, so a bad one prints nothing
+	var tmpl *template.Template
+	if format != "" {
+		tmpl, err = parseListFormat(format, dateLayout)
+		if err != nil {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: --format: %v\n", err)
+			return 2
+		}
+	}
+
+	pg := startPager(ctx, noPager)
+	defer pg.Close()
+	ctx.Out = pg
+
 	// Get paths and verify tasks directory exists
-	paths, err := config.GetPaths(ctx.Path)
+	paths, err := config.GetPaths(ctx.workspace())
 	if err != nil {
 		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
 		return 1
@@ -61,7 +162,7 @@ func RunList(args []string, ctx CommandContext) int {
 	}
 
 	// Load all tasks
-	st := store.NewFileStore(paths.ThreadsDir)
+	st := newStore(paths)
 	tasks, err := st.LoadAll()
 	if err != nil {
 		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
@@ -76,32 +177,149 @@ func RunList(args []string, ctx CommandContext) int {
 	}
 
 	// Reload to get updated tasks with short_ids
-	tasks, err = st.LoadAll()
+	tasks, err = loadAllTasks(st, ctx)
 	if err != nil {
 		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
 		return 1
 	}
 
+	// The summary covers the whole workspace, whatever the filters, and
+	// follows the list however it ends, as long as it ends successfully
+	if summary {
+		tz, err := config.LoadTimezone()
+		if err != nil {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+			return 1
+		}
+		s := summarizeDue(tasks, date.Today(ctx.clock(), tz))
+		defer func() {
+			if code == 0 {
+				_, _ = fmt.Fprintf(ctx.Out, "\n%s\n", s)
+			}
+		}()
+	}
+
 	if len(tasks) == 0 {
-		_, _ = fmt.Fprintln(ctx.Out, "No tasks found.")
+		if tmpl == nil {
+			_, _ = fmt.Fprintln(ctx.Out, "No tasks found.")
+		}
 		return 0
 	}
 
+	f := taskFilter{
+		All:      all,
+		Status:   status,
+		Project:  project,
+		Tags:     tags,
+		AnyTags:  splitTagList(anyTags),
+		NotTags:  splitTagList(notTags),
+		Overdue:  overdue,
+		DueToday: dueToday,
+		TagKey:   tagKey,
+		TagVal:   tagVal,
+	}
+	if err := f.validateTags(); err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 2
+	}
+
+	// Resolve dates in the configured timezone for date filters
+	dateFlags := []struct {
+		name  string
+		value string
+		dst   *string
+	}{
+		{"--due-before", dueBefore, &f.DueBefore},
+		{"--due-after", dueAfter, &f.DueAfter},
+		{"--created-before", createdBefore, &f.CreatedBefore},
+		{"--created-after", createdAfter, &f.CreatedAfter},
+	}
+	needsDates := overdue || dueToday || relative
+	var today time.Time
+	for _, df := range dateFlags {
+		needsDates = needsDates || df.value != ""
+	}
+	if needsDates {
+		tz, err := config.LoadTimezone()
+		if err != nil {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+			return 1
+		}
+		locale, err := config.LoadDateLocale()
+		if err != nil {
+			locale = config.DateLocaleISO // Default on error
+		}
+		f.Location = tz
+		f.Today = ctx.clock().Now().In(tz).Format(dueDateLayout)
+		if relative {
+			today = date.Today(ctx.clock(), tz)
+		}
+
+		for _, df := range dateFlags {
+			if df.value == "" {
+				continue
+			}
+			canonical, err := date.ParseDate(df.value, locale, ctx.clock(), tz)
+			if err != nil {
+				_, _ = fmt.Fprintf(ctx.Err, "Error: %s: %v\n", df.name, err)
+				return 2
+			}
+			*df.dst = canonical
+		}
+	}
+
 	// Filter tasks
-	filtered := filterTasks(tasks, all, status, project, tag)
+	filtered := filterTasks(tasks, f)
 
 	if len(filtered) == 0 {
-		_, _ = fmt.Fprintln(ctx.Out, "No tasks found.")
+		if tmpl == nil {
+			// An unknown --tag is more often a typo than a genuinely empty result
+			known := knownTags(tasks)
+			for _, tag := range task.NormalizeTags(f.Tags) {
+				if s := suggestTag(tag, known); s != "" {
+					_, _ = fmt.Fprintf(ctx.Out, "No tasks found. Did you mean --tag %s?\n", s)
+					return 0
+				}
+			}
+			_, _ = fmt.Fprintln(ctx.Out, "No tasks found.")
+		}
 		return 0
 	}
 
-	// Apply limit
+	// Find duplicates before paging, so a pair split across pages is flagged
+	opts := displayOptions{DateLayout: dateLayout, Today: today}
+	if dups {
+		opts.Dups = findDuplicateTitles(filtered)
+	}
+
+	// Apply offset, then limit, so --offset 20 --limit 20 is the third page
+	if offset > 0 {
+		if offset >= len(filtered) {
+			if tmpl == nil {
+				_, _ = fmt.Fprintln(ctx.Out, "No tasks found.")
+			}
+			return 0
+		}
+		filtered = filtered[offset:]
+	}
 	if limit > 0 && limit < len(filtered) {
 		filtered = filtered[:limit]
 	}
 
+	if tmpl != nil {
+		if err := displayTasksFormat(ctx.Out, filtered, tmpl); err != nil {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: --format: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
 	// Display tasks
-	displayTasks(ctx.Out, filtered)
+	if groupBy != "" {
+		displayTaskGroups(ctx.Out, groupTasks(filtered, groupBy), opts)
+		return 0
+	}
+	displayTasks(ctx.Out, filtered, opts)
 
 	return 0
 }
@@ -115,31 +333,190 @@ Flags:
   -p, --project <name>        filter by project
   --status <open|done|archived> filter by status
   -n, --limit <n>             limit number of tasks
-  --tag <tag>                 filter by tag (normalized)
+  --offset <n>                skip the first n matching tasks; with
+                              --limit, pages through the list
+  --tag <tag>                 filter by tag (normalized); repeat to
+                              require all of the given tags
+  --any-tag <a,b>             require at least one of the listed tags
+                              (comma-separated, repeatable)
+  --not-tag <tag>             exclude tasks with this tag (repeatable,
+                              commas allowed)
+  --tag-key <key>             filter by key of a key:value tag (e.g. sprint)
+  --tag-val <key:value>       filter by key:value tag (e.g. sprint:42)
+  --flag-dups                 mark open tasks whose title duplicates another
+  --format <tmpl>             print each task with a Go text/template over
+                              the task, or a preset: oneline, detailed
+  --no-pager                  don't pipe output through the pager
+  --overdue                   only open tasks due before today
+  --due-today                 only tasks due today
+  --due-before <date>         only tasks due on or before date
+  --due-after <date>          only tasks due on or after date
+  --created-before <date>     only tasks created on or before date
+  --created-after <date>      only tasks created on or after date
+  --group-by <field>          print tasks under a header per project, tag
+                              or status
+  --relative                  after each open task's due date, say how
+                              far off it is (due in 3 days, overdue 2 days)
+  --summary                   end with "N open, M overdue, K due this
+                              week" for the whole workspace
+
+Tag filters combine with AND: a task is listed only if it has every
+--tag, at least one --any-tag, and no --not-tag. --not-tag wins when a
+tag appears in both an inclusion flag and --not-tag.
+
+"Today" for --overdue, --due-today and --relative uses the timezone
+config key.
+Range dates accept the same input as --due on add (e.g. today, eow, +7).
+
+A [list] section in config.toml sets defaults for flags not given on the
+command line: show_all (--all), relative_dates (--relative), group_by
+(--group-by, ignored with --format) and limit (--limit). Override a
+boolean default with e.g. --all=false.
+
+--group-by tag lists a task under each of its tags. Tasks without a
+project or tag are listed last, under "(no project)" or "(no tag)".
+
+On a terminal, output longer than the screen goes through the pager
+config key or $PAGER, if either is set.
+
+--format templates see the task's fields (.ID, .ShortID, .Title,
+.Description, .Status, .Project, .Tags, .DueAt, .CreatedAt, ...) and the
+helpers short, due and tags, which print a short ID, a due date in the
+date_format layout, and comma-joined tags; each prints nothing when
+unset. "No tasks found." is not printed when --format is set.
+  %s list --format '{{short .ShortID}} {{.Title}} {{.Project}}'
+  %s list --format '{{.ID}} {{due .DueAt}} {{tags .Tags}}'
+
+`, app, app, app)
+}
 
-`, app)
+// dueDateLayout formats due dates as comparable calendar days.
+const dueDateLayout = "2006-01-02"
+
+// taskFilter holds the criteria used by filterTasks.
+// Zero values mean "no filter" for that field.
+type taskFilter struct {
+	All      bool     // include non-open tasks when Status is empty
+	Status   string   // exact status match
+	Project  string   // exact project match
+	Tags     []string // task must carry all of these tags
+	AnyTags  []string // task must carry at least one of these tags
+	NotTags  []string // task must carry none of these tags
+	Overdue  bool     // open and due strictly before Today
+	DueToday bool     // due exactly on Today
+	Today    string   // YYYY-MM-DD in the configured timezone; needed by the due filters
+	TagKey   string   // task must carry a key:value tag with this key
+	TagVal   string   // task must carry this exact key:value tag
+
+	// Inclusive YYYY-MM-DD bounds. Due bounds exclude tasks without a due date.
+	DueBefore     string
+	DueAfter      string
+	CreatedBefore string
+	CreatedAfter  string
+	Location      *time.Location // timezone for created dates; nil means time.Local
 }
 
-// filterTasks filters tasks based on the provided criteria.
-func filterTasks(tasks []*task.Task, all bool, statusFilter, projectFilter, tagFilter string) []*task.Task {
-	var filtered []*task.Task
+// validateTags rejects tag filters that are empty after normalizing, which
+// would otherwise be dropped and silently widen the filter.
+func (f taskFilter) validateTags() error {
+	tags := append(append(append([]string{}, f.Tags...), f.AnyTags...), f.NotTags...)
+	if f.TagVal != "" {
+		tags = append(tags, f.TagVal)
+	}
+	return task.ValidateTags(tags)
+}
 
-	// Normalize tag filter
-	var normalizedTagFilter string
-	if tagFilter != "" {
-		normalized := task.NormalizeTags([]string{tagFilter})
-		if len(normalized) > 0 {
-			normalizedTagFilter = normalized[0]
+// normalizeTagFilter normalizes a single tag filter value.
+func normalizeTagFilter(tag string) string {
+	if tag == "" {
+		return ""
+	}
+	normalized := task.NormalizeTags([]string{tag})
+	if len(normalized) == 0 {
+		return ""
+	}
+	return normalized[0]
+}
+
+// inDateRange reports whether day lies within [after, before]; empty bounds are open.
+// All values are YYYY-MM-DD, which compare correctly as strings.
+func inDateRange(day, after, before string) bool {
+	if after != "" && day < after {
+		return false
+	}
+	if before != "" && day > before {
+		return false
+	}
+	return true
+}
+
+// hasAllTags reports whether tags contains every tag in want exactly.
+func hasAllTags(tags, want []string) bool {
+	for _, tag := range want {
+		if !hasTag(tags, tag) {
+			return false
 		}
 	}
+	return true
+}
+
+// hasAnyTag reports whether tags contains at least one tag in want.
+func hasAnyTag(tags, want []string) bool {
+	for _, tag := range want {
+		if hasTag(tags, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitTagList flattens comma-separated flag values into single tags.
+func splitTagList(values []string) []string {
+	var tags []string
+	for _, v := range values {
+		tags = append(tags, strings.Split(v, ",")...)
+	}
+	return tags
+}
+
+// hasTag reports whether tags contains tag exactly.
+func hasTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// hasTagKey reports whether tags contains a key:value tag with the given key.
+func hasTagKey(tags []string, key string) bool {
+	for _, t := range tags {
+		if k, _, ok := task.SplitTag(t); ok && k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// filterTasks filters tasks based on the provided criteria.
+func filterTasks(tasks []*task.Task, f taskFilter) []*task.Task {
+	var filtered []*task.Task
+
+	// Normalize tag filters
+	normalizedTagFilters := task.NormalizeTags(f.Tags)
+	normalizedAnyTags := task.NormalizeTags(f.AnyTags)
+	normalizedNotTags := task.NormalizeTags(f.NotTags)
+	normalizedTagVal := normalizeTagFilter(f.TagVal)
+	normalizedTagKey := strings.ToLower(strings.TrimSpace(f.TagKey))
 
 	for _, t := range tasks {
 		// Status filter
-		if statusFilter != "" {
-			if string(t.Status) != statusFilter {
+		if f.Status != "" {
+			if string(t.Status) != f.Status {
 				continue
 			}
-		} else if !all {
+		} else if !f.All {
 			// Default: only show open tasks
 			if t.Status != task.StatusOpen {
 				continue
@@ -147,20 +524,61 @@ func filterTasks(tasks []*task.Task, all bool, statusFilter, projectFilter, tagF
 		}
 
 		// Project filter
-		if projectFilter != "" && t.Project != projectFilter {
+		if f.Project != "" && t.Project != f.Project {
 			continue
 		}
 
-		// Tag filter (exact match in normalized tags)
-		if normalizedTagFilter != "" {
-			found := false
-			for _, tag := range t.Tags {
-				if tag == normalizedTagFilter {
-					found = true
-					break
-				}
+		// Tag filters (exact match in normalized tags). A task must pass
+		// every mode: all of --tag, any of --any-tag, none of --not-tag.
+		if !hasAllTags(t.Tags, normalizedTagFilters) {
+			continue
+		}
+		if len(normalizedAnyTags) > 0 && !hasAnyTag(t.Tags, normalizedAnyTags) {
+			continue
+		}
+		if hasAnyTag(t.Tags, normalizedNotTags) {
+			continue
+		}
+
+		// Key:value tag filters
+		if normalizedTagKey != "" && !hasTagKey(t.Tags, normalizedTagKey) {
+			continue
+		}
+		if normalizedTagVal != "" && !hasTag(t.Tags, normalizedTagVal) {
+			continue
+		}
+
+		// Due-date filters; tasks without a due date never match
+		if f.Overdue || f.DueToday {
+			if t.DueAt == nil {
+				continue
 			}
-			if !found {
+			// due_at holds a calendar date at midnight UTC, so its UTC date
+			// is the due day regardless of the configured timezone
+			due := t.DueAt.UTC().Format(dueDateLayout)
+			if f.Overdue && (t.Status != task.StatusOpen || due >= f.Today) {
+				continue
+			}
+			if f.DueToday && due != f.Today {
+				continue
+			}
+		}
+
+		// Date ranges (inclusive on the boundary day)
+		if f.DueBefore != "" || f.DueAfter != "" {
+			if t.DueAt == nil {
+				continue
+			}
+			if !inDateRange(t.DueAt.UTC().Format(dueDateLayout), f.DueAfter, f.DueBefore) {
+				continue
+			}
+		}
+		if f.CreatedBefore != "" || f.CreatedAfter != "" {
+			loc := f.Location
+			if loc == nil {
+				loc = time.Local
+			}
+			if !inDateRange(t.CreatedAt.In(loc).Format(dueDateLayout), f.CreatedAfter, f.CreatedBefore) {
 				continue
 			}
 		}
@@ -171,8 +589,157 @@ func filterTasks(tasks []*task.Task, all bool, statusFilter, projectFilter, tagF
 	return filtered
 }
 
+// normalizeTitle normalizes a title for duplicate comparison:
+// case-insensitive with surrounding and repeated whitespace collapsed.
+func normalizeTitle(title string) string {
+	return strings.Join(strings.Fields(strings.ToLower(title)), " ")
+}
+
+// findDuplicateTitles returns the IDs of open tasks whose normalized title
+// is shared with at least one other open task in the given set.
+func findDuplicateTitles(tasks []*task.Task) map[string]bool {
+	groups := make(map[string][]string)
+	for _, t := range tasks {
+		if t.Status != task.StatusOpen {
+			continue
+		}
+		key := normalizeTitle(t.Title)
+		groups[key] = append(groups[key], t.ID)
+	}
+
+	dups := make(map[string]bool)
+	for _, ids := range groups {
+		if len(ids) < 2 {
+			continue
+		}
+		for _, id := range ids {
+			dups[id] = true
+		}
+	}
+	return dups
+}
+
+// displayOptions controls optional parts of the list line format.
+type displayOptions struct {
+	DateLayout string          // Go time layout for due dates
+	Dups       map[string]bool // task IDs to mark with "(dup)"
+	Today      time.Time       // if set, open tasks' due dates also say how far off they are
+}
+
+// formatDue formats t's due date with layout, followed by e.g. "(due in 3
+// days)" when today (see date.Today) is set and t is still open.
+func formatDue(t *task.Task, layout string, today time.Time) string {
+	s := t.DueAt.Format(layout)
+	if !today.IsZero() && t.Status == task.StatusOpen {
+		s += " (" + date.RelativeDue(*t.DueAt, today) + ")"
+	}
+	return s
+}
+
+// shortDuration formats d without zero trailing units: 2h, 1h30m, 45m.
+func shortDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// Fields list --group-by can group on.
+const (
+	groupByProject = "project"
+	groupByTag     = "tag"
+	groupByStatus  = "status"
+)
+
+// groupTasks groups tasks by project, tag or status, keeping their order
+// within each group. Projects and tags are sorted by name, with tasks
+// missing one last; a task with several tags is in each of their groups.
+// Statuses go open, done, archived.
+func groupTasks(tasks []*task.Task, by string) []taskGroup {
+	var none string
+	switch by {
+	case groupByProject:
+		none = "(no project)"
+	case groupByTag:
+		none = "(no tag)"
+	}
+
+	byHeader := make(map[string]*taskGroup)
+	var headers []string
+	add := func(header string, t *task.Task) {
+		g, ok := byHeader[header]
+		if !ok {
+			g = &taskGroup{Header: header}
+			byHeader[header] = g
+			headers = append(headers, header)
+		}
+		g.Tasks = append(g.Tasks, t)
+	}
+	for _, t := range tasks {
+		switch by {
+		case groupByProject:
+			add(t.Project, t)
+		case groupByTag:
+			if len(t.Tags) == 0 {
+				add("", t)
+			}
+			for _, tag := range t.Tags {
+				add(tag, t)
+			}
+		case groupByStatus:
+			add(string(t.Status), t)
+		}
+	}
+
+	statusOrder := map[string]int{
+		string(task.StatusOpen):     0,
+		string(task.StatusDone):     1,
+		string(task.StatusArchived): 2,
+	}
+	sort.SliceStable(headers, func(i, j int) bool {
+		a, b := headers[i], headers[j]
+		if by == groupByStatus {
+			return statusOrder[a] < statusOrder[b]
+		}
+		if (a == "") != (b == "") {
+			return b == ""
+		}
+		return a < b
+	})
+
+	groups := make([]taskGroup, 0, len(headers))
+	for _, h := range headers {
+		g := *byHeader[h]
+		if g.Header == "" {
+			g.Header = none
+		}
+		groups = append(groups, g)
+	}
+	return groups
+}
+
+// displayTaskGroups prints each group as a header followed by its list
+// lines, with a blank line between groups.
+func displayTaskGroups(out io.Writer, groups []taskGroup, opts displayOptions) {
+	for i, g := range groups {
+		if i > 0 {
+			_, _ = fmt.Fprintln(out)
+		}
+		_, _ = fmt.Fprintf(out, "%s:\n", g.Header)
+		displayTasks(out, g.Tasks, opts)
+	}
+}
+
 // displayTasks displays tasks in list format.
-func displayTasks(out io.Writer, tasks []*task.Task) {
+func displayTasks(out io.Writer, tasks []*task.Task, opts displayOptions) {
+	if opts.DateLayout == "" {
+		opts.DateLayout = config.DisplayLayoutISO
+	}
+
 	flagMap := map[task.Status]string{
 		task.StatusOpen:     " ",
 		task.StatusDone:     "x",
@@ -203,7 +770,7 @@ func displayTasks(out io.Writer, tasks []*task.Task) {
 
 		// Add due date
 		if t.DueAt != nil {
-			line += fmt.Sprintf("  due %s", t.DueAt.Format("2006-01-02"))
+			line += "  due " + formatDue(t, opts.DateLayout, opts.Today)
 		}
 
 		// Add tags
@@ -215,6 +782,16 @@ func displayTasks(out io.Writer, tasks []*task.Task) {
 			line += fmt.Sprintf("  [%s]", strings.Join(tagStrs, ","))
 		}
 
+		// Add estimate
+		if t.Estimate > 0 {
+			line += " ~" + shortDuration(t.Estimate)
+		}
+
+		// Mark duplicate titles
+		if opts.Dups[t.ID] {
+			line += "  (dup)"
+		}
+
 		_, _ = fmt.Fprintln(out, line)
 	}
 }
@@ -0,0 +1,649 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sjatkinson/threadkeeper/internal/date"
+	"github.com/sjatkinson/threadkeeper/internal/task"
+)
+
+// filterIDs returns the IDs of the filtered tasks, in order.
+func filterIDs(tasks []*task.Task) []string {
+	ids := make([]string, 0, len(tasks))
+	for _, t := range tasks {
+		ids = append(ids, t.ID)
+	}
+	return ids
+}
+
+func TestFilterTasks_KeyValueTags(t *testing.T) {
+	now := time.Now().UTC()
+	tasks := []*task.Task{
+		{ID: "a", Status: task.StatusOpen, CreatedAt: now, Tags: task.NormalizeTags([]string{"Sprint:42", "bug"})},
+		{ID: "b", Status: task.StatusOpen, CreatedAt: now, Tags: task.NormalizeTags([]string{"sprint:43"})},
+		{ID: "c", Status: task.StatusOpen, CreatedAt: now, Tags: task.NormalizeTags([]string{"sprint"})},
+		{ID: "d", Status: task.StatusOpen, CreatedAt: now, Tags: task.NormalizeTags([]string{"owner:Alice"})},
+	}
+
+	tests := []struct {
+		name   string
+		filter taskFilter
+		want   []string
+	}{
+		{"tag-key matches any value", taskFilter{TagKey: "sprint"}, []string{"a", "b"}},
+		{"tag-key is case-insensitive", taskFilter{TagKey: "SPRINT"}, []string{"a", "b"}},
+		{"tag-key does not match plain tag", taskFilter{TagKey: "bug"}, []string{}},
+		{"tag-val matches exact pair", taskFilter{TagVal: "sprint:42"}, []string{"a"}},
+		{"tag-val normalizes key", taskFilter{TagVal: "Owner:Alice"}, []string{"d"}},
+		{"tag-val keeps value case", taskFilter{TagVal: "owner:alice"}, []string{}},
+		{"plain tag still matches", taskFilter{Tags: []string{"sprint"}}, []string{"c"}},
+		{"tag and tag-key compose", taskFilter{Tags: []string{"bug"}, TagKey: "sprint"}, []string{"a"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := filterIDs(filterTasks(tasks, tt.filter))
+			if len(got) != len(tt.want) {
+				t.Fatalf("filterTasks() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("filterTasks() = %v, want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestFilterTasks_MultipleTagsAND(t *testing.T) {
+	now := time.Now().UTC()
+	tasks := []*task.Task{
+		{ID: "a", Status: task.StatusOpen, CreatedAt: now, Tags: task.NormalizeTags([]string{"bug", "urgent"})},
+		{ID: "b", Status: task.StatusOpen, CreatedAt: now, Tags: task.NormalizeTags([]string{"bug"})},
+		{ID: "c", Status: task.StatusOpen, CreatedAt: now, Tags: task.NormalizeTags([]string{"urgent", "docs"})},
+	}
+
+	tests := []struct {
+		name string
+		tags []string
+		want []string
+	}{
+		{"no tag filter", nil, []string{"a", "b", "c"}},
+		{"single tag unchanged", []string{"bug"}, []string{"a", "b"}},
+		{"two tags AND", []string{"bug", "urgent"}, []string{"a"}},
+		{"filters are normalized", []string{" BUG ", "Urgent"}, []string{"a"}},
+		{"no task has all", []string{"bug", "docs"}, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := filterIDs(filterTasks(tasks, taskFilter{Tags: tt.tags}))
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("filterTasks(tags=%v) = %v, want %v", tt.tags, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterTasks_AnyAndNotTags(t *testing.T) {
+	now := time.Now().UTC()
+	tasks := []*task.Task{
+		{ID: "a", Status: task.StatusOpen, CreatedAt: now, Tags: task.NormalizeTags([]string{"bug", "urgent"})},
+		{ID: "b", Status: task.StatusOpen, CreatedAt: now, Tags: task.NormalizeTags([]string{"bug"})},
+		{ID: "c", Status: task.StatusOpen, CreatedAt: now, Tags: task.NormalizeTags([]string{"docs"})},
+		{ID: "d", Status: task.StatusOpen, CreatedAt: now, Tags: []string{}},
+	}
+
+	tests := []struct {
+		name   string
+		filter taskFilter
+		want   []string
+	}{
+		{"any-tag", taskFilter{AnyTags: []string{"urgent", "docs"}}, []string{"a", "c"}},
+		{"any-tag normalized", taskFilter{AnyTags: []string{"DOCS"}}, []string{"c"}},
+		{"not-tag", taskFilter{NotTags: []string{"bug"}}, []string{"c", "d"}},
+		{"not-tag repeatable", taskFilter{NotTags: []string{"bug", "docs"}}, []string{"d"}},
+		{"all three compose", taskFilter{Tags: []string{"bug"}, AnyTags: []string{"urgent", "docs"}, NotTags: []string{"docs"}}, []string{"a"}},
+		{"not-tag wins over tag", taskFilter{Tags: []string{"bug"}, NotTags: []string{"bug"}}, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := filterIDs(filterTasks(tasks, tt.filter))
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("filterTasks() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterTasks_DueDates(t *testing.T) {
+	now := time.Now().UTC()
+	day := func(s string) *time.Time {
+		d, err := time.Parse(dueDateLayout, s)
+		if err != nil {
+			t.Fatalf("bad fixture date %q: %v", s, err)
+		}
+		return &d
+	}
+	tasks := []*task.Task{
+		{ID: "late", Status: task.StatusOpen, CreatedAt: now, DueAt: day("2026-03-09"), Tags: []string{}},
+		{ID: "today", Status: task.StatusOpen, CreatedAt: now, DueAt: day("2026-03-10"), Tags: []string{}},
+		{ID: "later", Status: task.StatusOpen, CreatedAt: now, DueAt: day("2026-03-11"), Tags: []string{}},
+		{ID: "undated", Status: task.StatusOpen, CreatedAt: now, Tags: []string{}},
+		{ID: "late-done", Status: task.StatusDone, CreatedAt: now, DueAt: day("2026-03-01"), Tags: []string{}},
+	}
+
+	tests := []struct {
+		name   string
+		filter taskFilter
+		want   []string
+	}{
+		{"overdue", taskFilter{Overdue: true, Today: "2026-03-10"}, []string{"late"}},
+		{"overdue ignores done even with --all", taskFilter{All: true, Overdue: true, Today: "2026-03-10"}, []string{"late"}},
+		{"due today", taskFilter{DueToday: true, Today: "2026-03-10"}, []string{"today"}},
+		{"both never match", taskFilter{Overdue: true, DueToday: true, Today: "2026-03-10"}, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := filterIDs(filterTasks(tasks, tt.filter))
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("filterTasks() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterTasks_DateRanges(t *testing.T) {
+	day := func(s string) *time.Time {
+		d, err := time.Parse(dueDateLayout, s)
+		if err != nil {
+			t.Fatalf("bad fixture date %q: %v", s, err)
+		}
+		return &d
+	}
+	tasks := []*task.Task{
+		{ID: "a", Status: task.StatusOpen, CreatedAt: time.Date(2026, 3, 1, 23, 30, 0, 0, time.UTC), DueAt: day("2026-03-09"), Tags: []string{}},
+		{ID: "b", Status: task.StatusOpen, CreatedAt: time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC), DueAt: day("2026-03-10"), Tags: []string{}},
+		{ID: "c", Status: task.StatusOpen, CreatedAt: time.Date(2026, 3, 3, 8, 0, 0, 0, time.UTC), Tags: []string{}},
+	}
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		filter taskFilter
+		want   []string
+	}{
+		{"due-before includes boundary", taskFilter{DueBefore: "2026-03-09"}, []string{"a"}},
+		{"due-after includes boundary", taskFilter{DueAfter: "2026-03-10"}, []string{"b"}},
+		{"due range excludes undated", taskFilter{DueAfter: "2026-03-01", DueBefore: "2026-03-31"}, []string{"a", "b"}},
+		{"created-after includes boundary", taskFilter{CreatedAfter: "2026-03-02", Location: time.UTC}, []string{"b", "c"}},
+		{"created-before includes boundary", taskFilter{CreatedBefore: "2026-03-02", Location: time.UTC}, []string{"a", "b"}},
+		{"created day uses timezone", taskFilter{CreatedAfter: "2026-03-02", CreatedBefore: "2026-03-02", Location: berlin}, []string{"a", "b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := filterIDs(filterTasks(tasks, tt.filter))
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("filterTasks() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunList_DateShortcuts(t *testing.T) {
+	setupWorkspace(t)
+	// Monday noon UTC, so today is 2026-03-09 in any common timezone
+	clock := date.FixedClock{FixedTime: time.Date(2026, 3, 9, 12, 0, 0, 0, time.UTC)}
+
+	for _, args := range [][]string{
+		{"--due", "today", "due monday"},
+		{"--due", "2026-03-15", "due sunday"},
+		{"--due", "2026-03-16", "due next monday"},
+	} {
+		ctx, _, errOut := newTestContext()
+		ctx.Clock = clock
+		if code := RunAdd(args, ctx); code != 0 {
+			t.Fatalf("RunAdd() exit code = %d, stderr: %s", code, errOut.String())
+		}
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    []string
+		notWant []string
+	}{
+		{"eow", []string{"--due-before", "eow"}, []string{"due monday", "due sunday"}, []string{"due next monday"}},
+		{"+N range", []string{"--due-after", "+1", "--due-before", "+7"}, []string{"due sunday", "due next monday"}, []string{"due monday"}},
+		{"today", []string{"--created-after", "today"}, []string{"due monday", "due sunday", "due next monday"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, out, errOut := newTestContext()
+			ctx.Clock = clock
+			if code := RunList(tt.args, ctx); code != 0 {
+				t.Fatalf("RunList() exit code = %d, stderr: %s", code, errOut.String())
+			}
+			for _, title := range tt.want {
+				if !strings.Contains(out.String(), title) {
+					t.Errorf("list output missing %q:\n%s", title, out.String())
+				}
+			}
+			for _, title := range tt.notWant {
+				if strings.Contains(out.String(), title+" (") {
+					t.Errorf("list output unexpectedly has %q:\n%s", title, out.String())
+				}
+			}
+		})
+	}
+}
+
+func TestRunList_InvalidRangeDate(t *testing.T) {
+	setupWorkspace(t)
+	ctx, _, errOut := newTestContext()
+	if code := RunAdd([]string{"anything"}, ctx); code != 0 {
+		t.Fatalf("RunAdd() exit code = %d, stderr: %s", code, errOut.String())
+	}
+
+	ctx, _, errOut = newTestContext()
+	if code := RunList([]string{"--due-before", "someday"}, ctx); code != 2 {
+		t.Errorf("RunList() exit code = %d, want 2", code)
+	}
+	if !strings.Contains(errOut.String(), "--due-before") {
+		t.Errorf("stderr = %q, want it to name the flag", errOut.String())
+	}
+}
+
+func TestRunList_FlagDupsAcrossPages(t *testing.T) {
+	setupWorkspace(t)
+	for _, title := range []string{"fix login", "write docs", "Fix Login"} {
+		ctx, _, errOut := newTestContext()
+		if code := RunAdd([]string{"--force", title}, ctx); code != 0 {
+			t.Fatalf("RunAdd(%q) exit code = %d, stderr: %s", title, code, errOut.String())
+		}
+	}
+
+	// One task per page: both halves of the pair must still be marked
+	marked := 0
+	for offset := 0; offset < 3; offset++ {
+		args := []string{"--flag-dups", "--limit", "1", "--offset", strconv.Itoa(offset)}
+		ctx, out, errOut := newTestContext()
+		if code := RunList(args, ctx); code != 0 {
+			t.Fatalf("RunList(%v) exit code = %d, stderr: %s", args, code, errOut.String())
+		}
+		if strings.Contains(out.String(), "(dup)") {
+			marked++
+		}
+	}
+	if marked != 2 {
+		t.Errorf("pages with a (dup) marker = %d, want 2", marked)
+	}
+}
+
+func TestSplitTagList(t *testing.T) {
+	got := splitTagList([]string{"a,b", "c"})
+	if strings.Join(got, "|") != "a|b|c" {
+		t.Errorf("splitTagList() = %v, want [a b c]", got)
+	}
+}
+
+func TestDisplayTasks_FlagDups(t *testing.T) {
+	now := time.Now().UTC()
+	sid1, sid2, sid3 := 1, 2, 3
+	tasks := []*task.Task{
+		{ID: "AAA1", Title: "Fix login", Status: task.StatusOpen, CreatedAt: now, ShortID: &sid1, Tags: []string{}},
+		{ID: "BBB2", Title: "  fix   LOGIN ", Status: task.StatusOpen, CreatedAt: now, ShortID: &sid2, Tags: []string{}},
+		{ID: "CCC3", Title: "Write docs", Status: task.StatusOpen, CreatedAt: now, ShortID: &sid3, Tags: []string{}},
+		{ID: "DDD4", Title: "Write docs", Status: task.StatusDone, CreatedAt: now, Tags: []string{}},
+	}
+
+	var out bytes.Buffer
+	displayTasks(&out, tasks, displayOptions{Dups: findDuplicateTitles(tasks)})
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("displayTasks() printed %d lines, want 4:\n%s", len(lines), out.String())
+	}
+
+	wantDup := map[string]bool{"AAA1": true, "BBB2": true, "CCC3": false, "DDD4": false}
+	for _, line := range lines {
+		for id, want := range wantDup {
+			if !strings.Contains(line, id) {
+				continue
+			}
+			if got := strings.HasSuffix(line, "(dup)"); got != want {
+				t.Errorf("line for %s has dup marker = %v, want %v: %q", id, got, want, line)
+			}
+		}
+	}
+}
+
+func TestRunList_VerboseReportsSkippedThreads(t *testing.T) {
+	tmpDir := setupWorkspace(t)
+	addAndLoad(t, []string{"fine"})
+
+	corrupt := filepath.Join(tmpDir, "threads", "zz", "ZZZZZZZZZZZZZZZZZZZZZZZZZZ")
+	if err := os.MkdirAll(corrupt, 0755); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(corrupt, "thread.json"), []byte("{"), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	ctx, _, errOut := newTestContext()
+	if code := RunList(nil, ctx); code != 0 {
+		t.Fatalf("RunList() exit code = %d, stderr: %s", code, errOut.String())
+	}
+	if errOut.Len() != 0 {
+		t.Errorf("stderr without --verbose = %q, want empty", errOut.String())
+	}
+
+	ctx, _, errOut = newTestContext()
+	ctx.Verbose = true
+	if code := RunList(nil, ctx); code != 0 {
+		t.Fatalf("RunList() exit code = %d, stderr: %s", code, errOut.String())
+	}
+	if !strings.Contains(errOut.String(), "Warning: skipped unreadable thread") {
+		t.Errorf("stderr = %q, want skipped thread warning", errOut.String())
+	}
+}
+
+func TestRunList_Format(t *testing.T) {
+	setupWorkspace(t)
+	for i, args := range [][]string{
+		{"--project", "home", "--tag", "a", "--tag", "b", "--due", "2026-03-04", "paint fence"},
+		{"call plumber"},
+	} {
+		ctx, _, errOut := newTestContext()
+		ctx.Clock = date.FixedClock{FixedTime: time.Date(2026, 3, 1, 9, i, 0, 0, time.UTC)}
+		if code := RunAdd(args, ctx); code != 0 {
+			t.Fatalf("RunAdd(%v) exit code = %d, stderr: %s", args, code, errOut.String())
+		}
+	}
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"{{short .ShortID}} {{.Title}} {{.Project}}", "1 paint fence home\n2 call plumber \n"},
+		{"{{.Title}}|{{due .DueAt}}|{{tags .Tags}}", "paint fence|2026-03-04|a,b\ncall plumber||\n"},
+		{"oneline", "1 paint fence\n2 call plumber\n"},
+	}
+	for _, tt := range tests {
+		ctx, out, errOut := newTestContext()
+		if code := RunList([]string{"--format", tt.format}, ctx); code != 0 {
+			t.Fatalf("RunList(--format %q) exit code = %d, stderr: %s", tt.format, code, errOut.String())
+		}
+		if got := out.String(); got != tt.want {
+			t.Errorf("RunList(--format %q) = %q, want %q", tt.format, got, tt.want)
+		}
+	}
+
+	// Optional fields may be used directly when the listed tasks have them
+	ctx, out, errOut := newTestContext()
+	if code := RunList([]string{"--project", "home", "--format", `{{.DueAt.Format "Jan 2"}} {{.Title}}`}, ctx); code != 0 {
+		t.Fatalf("RunList(--format .DueAt.Format) exit code = %d, stderr: %s", code, errOut.String())
+	}
+	if got, want := out.String(), "Mar 4 paint fence\n"; got != want {
+		t.Errorf("RunList(--format .DueAt.Format) = %q, want %q", got, want)
+	}
+
+	for _, bad := range []string{"{{.Title", "{{.NoSuchField}}"} {
+		ctx, out, _ := newTestContext()
+		if code := RunList([]string{"--format", bad}, ctx); code != 2 || out.Len() != 0 {
+			t.Errorf("RunList(--format %q) = %d, output %q; want exit 2 and no output", bad, code, out.String())
+		}
+	}
+}
+
+func TestGroupTasks(t *testing.T) {
+	tasks := []*task.Task{
+		{ID: "a", Project: "work", Tags: []string{"b", "a"}, Status: task.StatusDone},
+		{ID: "b", Status: task.StatusOpen},
+		{ID: "c", Project: "home", Tags: []string{"a"}, Status: task.StatusArchived},
+		{ID: "d", Project: "work", Status: task.StatusOpen},
+	}
+
+	tests := []struct {
+		by   string
+		want []string // "header: ids"
+	}{
+		{groupByProject, []string{"home: c", "work: a d", "(no project): b"}},
+		{groupByTag, []string{"a: a c", "b: a", "(no tag): b d"}},
+		{groupByStatus, []string{"open: b d", "done: a", "archived: c"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, g := range groupTasks(tasks, tt.by) {
+			var ids []string
+			for _, tk := range g.Tasks {
+				ids = append(ids, tk.ID)
+			}
+			got = append(got, g.Header+": "+strings.Join(ids, " "))
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("groupTasks(%s) = %q, want %q", tt.by, got, tt.want)
+		}
+	}
+}
+
+func TestRunList_GroupBy(t *testing.T) {
+	setupWorkspace(t)
+	for _, args := range [][]string{
+		{"--project", "home", "paint fence"},
+		{"call plumber"},
+	} {
+		ctx, _, errOut := newTestContext()
+		if code := RunAdd(args, ctx); code != 0 {
+			t.Fatalf("RunAdd(%v) exit code = %d, stderr: %s", args, code, errOut.String())
+		}
+	}
+
+	ctx, out, errOut := newTestContext()
+	if code := RunList([]string{"--group-by", "project"}, ctx); code != 0 {
+		t.Fatalf("RunList(--group-by project) exit code = %d, stderr: %s", code, errOut.String())
+	}
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != 5 || lines[0] != "home:" || !strings.Contains(lines[1], "paint fence") ||
+		lines[2] != "" || lines[3] != "(no project):" || !strings.Contains(lines[4], "call plumber") {
+		t.Errorf("RunList(--group-by project) output:\n%s", out.String())
+	}
+
+	for _, args := range [][]string{
+		{"--group-by", "due"},
+		{"--group-by", "tag", "--format", "oneline"},
+	} {
+		ctx, _, _ := newTestContext()
+		if code := RunList(args, ctx); code != 2 {
+			t.Errorf("RunList(%v) exit code = %d, want 2", args, code)
+		}
+	}
+}
+
+func TestRunList_Offset(t *testing.T) {
+	setupWorkspace(t)
+	for i, title := range []string{"one", "two", "three", "four", "five"} {
+		ctx, _, errOut := newTestContext()
+		ctx.Clock = date.FixedClock{FixedTime: time.Date(2026, 3, 1, 9, i, 0, 0, time.UTC)}
+		if code := RunAdd([]string{title}, ctx); code != 0 {
+			t.Fatalf("RunAdd(%q) exit code = %d, stderr: %s", title, code, errOut.String())
+		}
+	}
+
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"--offset", "2", "--limit", "2"}, "three\nfour\n"},
+		{[]string{"--offset", "4"}, "five\n"},
+		{[]string{"--offset", "5"}, ""},
+	}
+	for _, tt := range tests {
+		ctx, out, errOut := newTestContext()
+		args := append([]string{"--format", "{{.Title}}"}, tt.args...)
+		if code := RunList(args, ctx); code != 0 {
+			t.Fatalf("RunList(%v) exit code = %d, stderr: %s", args, code, errOut.String())
+		}
+		if got := out.String(); got != tt.want {
+			t.Errorf("RunList(%v) = %q, want %q", args, got, tt.want)
+		}
+	}
+
+	ctx, out, _ := newTestContext()
+	if code := RunList([]string{"--offset", "10"}, ctx); code != 0 || out.String() != "No tasks found.\n" {
+		t.Errorf("RunList(--offset 10) = %d, %q; want 0, \"No tasks found.\"", code, out.String())
+	}
+	ctx, _, _ = newTestContext()
+	if code := RunList([]string{"--offset", "-1"}, ctx); code != 2 {
+		t.Errorf("RunList(--offset -1) exit code = %d, want 2", code)
+	}
+}
+
+func TestRunListShow_RelativeDue(t *testing.T) {
+	setupWorkspace(t)
+	clock := testClock()
+	for _, args := range [][]string{
+		{"--due", "2026-03-13", "pay rent"},
+		{"--due", "2026-03-08", "file taxes"},
+	} {
+		ctx, _, errOut := newTestContext()
+		ctx.Clock = clock
+		if code := RunAdd(args, ctx); code != 0 {
+			t.Fatalf("RunAdd(%v) exit code = %d, stderr: %s", args, code, errOut.String())
+		}
+	}
+
+	ctx, out, errOut := newTestContext()
+	ctx.Clock = clock
+	if code := RunList([]string{"--relative"}, ctx); code != 0 {
+		t.Fatalf("RunList(--relative) exit code = %d, stderr: %s", code, errOut.String())
+	}
+	for _, want := range []string{"due 2026-03-13 (due in 3 days)", "due 2026-03-08 (overdue 2 days)"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("RunList(--relative) output missing %q:\n%s", want, out.String())
+		}
+	}
+
+	ctx, out, _ = newTestContext()
+	ctx.Clock = clock
+	if code := RunList(nil, ctx); code != 0 || strings.Contains(out.String(), "(due in") {
+		t.Errorf("RunList() = %d, output:\n%s\nwant absolute due dates only", code, out.String())
+	}
+
+	ctx, out, errOut = newTestContext()
+	ctx.Clock = clock
+	if code := RunShow([]string{"1"}, ctx); code != 0 {
+		t.Fatalf("RunShow() exit code = %d, stderr: %s", code, errOut.String())
+	}
+	if !strings.Contains(out.String(), "Due: 2026-03-13 (due in 3 days)") {
+		t.Errorf("RunShow() output:\n%s\nwant the relative due date", out.String())
+	}
+}
+
+func TestRunList_ConfigDefaults(t *testing.T) {
+	ws := setupWorkspace(t)
+	writeConfig(t, ws, "[list]\nshow_all = true\nlimit = 1\n")
+	for i, title := range []string{"open task", "finished task"} {
+		ctx, _, errOut := newTestContext()
+		ctx.Clock = date.FixedClock{FixedTime: time.Date(2026, 3, 1, 9, i, 0, 0, time.UTC)}
+		if code := RunAdd([]string{title}, ctx); code != 0 {
+			t.Fatalf("RunAdd(%q) exit code = %d, stderr: %s", title, code, errOut.String())
+		}
+	}
+	ctx, _, errOut := newTestContext()
+	if code := RunDone([]string{"2"}, ctx); code != 0 {
+		t.Fatalf("RunDone() exit code = %d, stderr: %s", code, errOut.String())
+	}
+
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{nil, []string{"open task"}},
+		{[]string{"--limit", "0"}, []string{"open task", "finished task"}},
+		{[]string{"--all=false", "-n", "5"}, []string{"open task"}},
+	}
+	for _, tt := range tests {
+		ctx, out, errOut := newTestContext()
+		args := append([]string{"--format", "{{.Title}}"}, tt.args...)
+		if code := RunList(args, ctx); code != 0 {
+			t.Fatalf("RunList(%v) exit code = %d, stderr: %s", args, code, errOut.String())
+		}
+		got := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+		sort.Strings(got)
+		sort.Strings(tt.want)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("RunList(%v) = %q, want %q", args, got, tt.want)
+		}
+	}
+
+	writeConfig(t, ws, "[list]\ngroup_by = \"due\"\n")
+	ctx, _, errOut = newTestContext()
+	if code := RunList(nil, ctx); code != 2 || !strings.Contains(errOut.String(), "[list] config") {
+		t.Errorf("RunList() with bad group_by = %d, stderr %q; want 2 naming the config", code, errOut.String())
+	}
+}
+
+func TestRunListStats_Summary(t *testing.T) {
+	setupWorkspace(t)
+	clock := testClock()
+	for _, args := range [][]string{
+		{"--due", "2026-03-09", "--project", "web", "late"},
+		{"--due", "2026-03-12", "soon"},
+		{"--project", "web", "someday"},
+	} {
+		ctx, _, errOut := newTestContext()
+		ctx.Clock = clock
+		if code := RunAdd(args, ctx); code != 0 {
+			t.Fatalf("RunAdd(%v) exit code = %d, stderr: %s", args, code, errOut.String())
+		}
+	}
+
+	// The list is filtered; the summary still covers the workspace
+	ctx, out, errOut := newTestContext()
+	ctx.Clock = clock
+	if code := RunList([]string{"--summary", "--project", "web"}, ctx); code != 0 {
+		t.Fatalf("RunList() exit code = %d, stderr: %s", code, errOut.String())
+	}
+	if got := out.String(); !strings.Contains(got, "someday") || strings.Contains(got, "soon") || !strings.HasSuffix(got, "\n3 open, 1 overdue, 1 due this week\n") {
+		t.Errorf("list --summary output = %q", got)
+	}
+
+	ctx, _, _ = newTestContext()
+	if code := RunList([]string{"--summary", "--format", "oneline"}, ctx); code != 2 {
+		t.Errorf("list --summary --format exit code = %d, want 2", code)
+	}
+
+	ctx, out, errOut = newTestContext()
+	ctx.Clock = clock
+	if code := RunStats([]string{"--summary", "--json", "--project", "web"}, ctx); code != 0 {
+		t.Fatalf("RunStats() exit code = %d, stderr: %s", code, errOut.String())
+	}
+	var got struct {
+		Buckets []statBucket `json:"buckets"`
+		Summary dueSummary   `json:"summary"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("unmarshal stats: %v\n%s", err, out.String())
+	}
+	if len(got.Buckets) != 1 || got.Buckets[0].Count != 2 || got.Summary != (dueSummary{Open: 2, Overdue: 1}) {
+		t.Errorf("stats --summary --json = %+v", got)
+	}
+}
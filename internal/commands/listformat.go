@@ -0,0 +1,94 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/sjatkinson/threadkeeper/internal/task"
+)
+
+// listFormatPresets are the named shortcuts accepted by list --format.
+var listFormatPresets = map[string]string{
+	"oneline":  `{{short .ShortID}} {{.Title}}`,
+	"detailed": `{{short .ShortID}} {{.ID}} [{{.Status}}] {{.Title}}{{with .Project}} project:{{.}}{{end}}{{with due .DueAt}} due:{{.}}{{end}}{{with tags .Tags}} tags:{{.}}{{end}}`,
+}
+
+// listFormatSample is the task parseListFormat tries a template on. Every
+// optional field is set, so templates that use one directly, such as
+// {{.DueAt.Format "2006-01-02"}}, are accepted.
+func listFormatSample() *task.Task {
+	now := time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)
+	sid := 1
+	return &task.Task{
+		ID:          "sample",
+		Title:       "sample",
+		Status:      task.StatusOpen,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		DueAt:       &now,
+		Project:     "sample",
+		Tags:        []string{"sample"},
+		ShortID:     &sid,
+		CompletedAt: &now,
+		StartedAt:   &now,
+	}
+}
+
+// parseListFormat compiles a list --format value, either a preset name or
+// a text/template over task.Task. The template is run once against a
+// sample task so references to unknown fields fail here, before any task
+// is printed.
+func parseListFormat(format, dateLayout string) (*template.Template, error) {
+	if preset, ok := listFormatPresets[format]; ok {
+		format = preset
+	}
+
+	funcs := template.FuncMap{
+		// short prints a short ID, or nothing for tasks without one
+		"short": func(sid *int) string {
+			if sid == nil {
+				return ""
+			}
+			return strconv.Itoa(*sid)
+		},
+		// due prints a due date in the configured layout, or nothing
+		"due": func(d *time.Time) string {
+			if d == nil {
+				return ""
+			}
+			return d.Format(dateLayout)
+		},
+		// tags joins tags with commas
+		"tags": func(tags []string) string {
+			return strings.Join(tags, ",")
+		},
+	}
+
+	tmpl, err := template.New("format").Funcs(funcs).Parse(format)
+	if err != nil {
+		return nil, err
+	}
+	if err := tmpl.Execute(io.Discard, listFormatSample()); err != nil {
+		return nil, err
+	}
+	return tmpl, nil
+}
+
+// displayTasksFormat prints one line per task using tmpl.
+func displayTasksFormat(out io.Writer, tasks []*task.Task, tmpl *template.Template) error {
+	var buf bytes.Buffer
+	for _, t := range tasks {
+		buf.Reset()
+		if err := tmpl.Execute(&buf, t); err != nil {
+			return fmt.Errorf("task %s: %w", t.ID, err)
+		}
+		line := strings.TrimSuffix(buf.String(), "\n")
+		_, _ = fmt.Fprintln(out, line)
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package commands
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/sjatkinson/threadkeeper/internal/config"
+	"github.com/sjatkinson/threadkeeper/internal/task"
+)
+
+// logEvent is one line of a thread's timeline.
+type logEvent struct {
+	At   time.Time
+	Text string
+}
+
+func RunLog(args []string, ctx CommandContext) int {
+	fs := flag.NewFlagSet(ctx.AppName+" log", flag.ContinueOnError)
+	fs.SetOutput(ctx.Err)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintln(ctx.Err, logUsage(ctx.AppName))
+	}
+
+	if err := fs.Parse(args); err != nil {
+		_, _ = fmt.Fprintln(ctx.Err)
+		_, _ = fmt.Fprintln(ctx.Err, logUsage(ctx.AppName))
+		return 2
+	}
+
+	rest := fs.Args()
+	if len(rest) != 1 {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: missing argument: task ID required\n")
+		_, _ = fmt.Fprintln(ctx.Err, logUsage(ctx.AppName))
+		return 2
+	}
+
+	// Get paths and verify threads directory exists
+	paths, err := config.GetPaths(ctx.workspace())
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+
+	if _, err := os.Stat(paths.ThreadsDir); err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: threads directory does not exist at %s. Run '%s init' first.\n", paths.ThreadsDir, ctx.AppName)
+		return 1
+	}
+
+	st := newStore(paths)
+	t, err := st.ResolveID(rest[0])
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+
+	attachments, err := loadAttachments(st.ThreadDir(t.ID))
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Warning: failed to read attachments: %v\n", err)
+	}
+	ops, err := loadOps(paths)
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Warning: failed to read %s: %v\n", opsLogName, err)
+	}
+
+	dateLayout, err := config.LoadDisplayDateFormat()
+	if err != nil {
+		dateLayout = config.DisplayLayoutISO // Default on error
+	}
+
+	displayLog(ctx.Out, threadLog(t, attachments, ops), dateLayout)
+	return 0
+}
+
+// threadLog builds t's timeline, oldest first, from its timestamps, its
+// attachment events and the ops.jsonl entries that touched it. The
+// completed and last-updated times are left out when an attachment or op
+// event already accounts for that moment.
+func threadLog(t *task.Task, attachments []AttachmentEvent, ops []opEntry) []logEvent {
+	events := []logEvent{{At: t.CreatedAt, Text: "created"}}
+
+	for _, ev := range attachments {
+		at, err := time.Parse(time.RFC3339, ev.TS)
+		if err != nil {
+			continue
+		}
+		verb := "attached"
+		if ev.Op == "remove" {
+			verb = "removed"
+		}
+		name := ev.Att.Name
+		if name == "" {
+			name = ev.Att.URL
+		}
+		events = append(events, logEvent{At: at, Text: fmt.Sprintf("%s %s %s %s", verb, ev.Att.Kind, ev.Att.AttID, name)})
+	}
+
+	for _, op := range ops {
+		if !slices.Contains(op.IDs, t.ID) {
+			continue
+		}
+		at, err := time.Parse(time.RFC3339, op.TS)
+		if err != nil {
+			continue
+		}
+		text := op.Command
+		if op.Command == opUndo {
+			text = fmt.Sprintf("undo (of operation %d)", op.Undoes)
+		}
+		events = append(events, logEvent{At: at, Text: text})
+	}
+
+	seen := func(at time.Time) bool {
+		for _, e := range events {
+			if e.At.Truncate(time.Second).Equal(at.Truncate(time.Second)) {
+				return true
+			}
+		}
+		return false
+	}
+	if t.CompletedAt != nil && !seen(*t.CompletedAt) {
+		events = append(events, logEvent{At: *t.CompletedAt, Text: "completed"})
+	}
+	if t.UpdatedAt.After(t.CreatedAt) && !seen(t.UpdatedAt) {
+		events = append(events, logEvent{At: t.UpdatedAt, Text: "last updated"})
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].At.Before(events[j].At)
+	})
+	return events
+}
+
+func displayLog(out io.Writer, events []logEvent, dateLayout string) {
+	layout := dateLayout
+	if !layoutHasTime(layout) {
+		layout += " 15:04Z"
+	}
+	for _, e := range events {
+		_, _ = fmt.Fprintf(out, "%s  %s\n", e.At.UTC().Format(layout), e.Text)
+	}
+}
+
+func logUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s log <id>
+
+Prints a timeline of one thread, oldest first: when it was created, each
+attachment added or removed, every recorded operation on it (done,
+update, undo, ...) and when it was completed and last updated.
+
+`, app)
+}
@@ -0,0 +1,66 @@
+package commands
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sjatkinson/threadkeeper/internal/date"
+)
+
+func TestRunLog(t *testing.T) {
+	setupWorkspace(t)
+	base := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	at := func(minutes int) CommandContext {
+		ctx, _, _ := newTestContext()
+		ctx.Clock = date.FixedClock{FixedTime: base.Add(time.Duration(minutes) * time.Minute)}
+		return ctx
+	}
+
+	if code := RunAdd([]string{"logged task"}, at(0)); code != 0 {
+		t.Fatalf("RunAdd() exit code = %d", code)
+	}
+	tk := loadOnlyTask(t)
+	if code := RunAttach([]string{"note", "--id", tk.ID, "--name", "findings", "--message", "body"}, at(10)); code != 0 {
+		t.Fatalf("RunAttach() exit code = %d", code)
+	}
+	if code := RunUpdate([]string{"--title", "renamed", tk.ID}, at(20)); code != 0 {
+		t.Fatalf("RunUpdate() exit code = %d", code)
+	}
+	if code := RunDone([]string{tk.ID}, at(30)); code != 0 {
+		t.Fatalf("RunDone() exit code = %d", code)
+	}
+
+	ctx, out, errOut := newTestContext()
+	if code := RunLog([]string{tk.ID}, ctx); code != 0 {
+		t.Fatalf("RunLog() exit code = %d, stderr: %s", code, errOut.String())
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	want := []string{
+		"2026-03-10 09:00Z  created",
+		"2026-03-10 09:10Z  attached note ",
+		"2026-03-10 09:20Z  update",
+		"2026-03-10 09:30Z  done",
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("log has %d lines, want %d:\n%s", len(lines), len(want), out.String())
+	}
+	for i, w := range want {
+		if !strings.HasPrefix(lines[i], w) {
+			t.Errorf("line %d = %q, want prefix %q", i, lines[i], w)
+		}
+	}
+	if !strings.HasSuffix(lines[1], " findings") {
+		t.Errorf("attachment line = %q, want the note name", lines[1])
+	}
+}
+
+func TestDisplayLog_LayoutWithTime(t *testing.T) {
+	var out bytes.Buffer
+	events := []logEvent{{At: time.Date(2026, 3, 10, 9, 30, 0, 0, time.UTC), Text: "created"}}
+	displayLog(&out, events, "2006-01-02 15:04")
+	if got, want := out.String(), "2026-03-10 09:30  created\n"; got != want {
+		t.Errorf("displayLog() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,113 @@
+package commands
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// ANSI SGR sequences used by styleMarkdown.
+const (
+	ansiReset     = "\x1b[0m"
+	ansiBold      = "\x1b[1m"
+	ansiNormal    = "\x1b[22m"
+	ansiDim       = "\x1b[2m"
+	ansiItalic    = "\x1b[3m"
+	ansiNoItalic  = "\x1b[23m"
+	ansiUnderline = "\x1b[4m"
+	ansiNoUnder   = "\x1b[24m"
+	ansiCyan      = "\x1b[36m"
+	ansiNoColor   = "\x1b[39m"
+)
+
+var (
+	mdHeading   = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	mdBullet    = regexp.MustCompile(`^(\s*)[-*+]\s+(.*)$`)
+	mdOrdered   = regexp.MustCompile(`^(\s*)(\d+[.)])\s+(.*)$`)
+	mdRule      = regexp.MustCompile(`^\s*([-*_])(\s*([-*_])){2,}\s*$`)
+	mdBold      = regexp.MustCompile(`\*\*(.+?)\*\*|__(.+?)__`)
+	mdItalic    = regexp.MustCompile(`\*([^*\s][^*]*?)\*|\b_([^_\s][^_]*?)_\b`)
+	mdLink      = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	mdCodeSpans = regexp.MustCompile("`[^`]+`")
+)
+
+// styleMarkdown writes src to w styled for a terminal: headings in bold,
+// **bold** and *italic* text, `code` and fenced code blocks in cyan,
+// bulleted lists with bullets, block quotes with a bar and links as their
+// text followed by the URL. Anything else passes through unchanged, so
+// unusual Markdown degrades to the original text.
+func styleMarkdown(w io.Writer, src string) error {
+	bw := bufio.NewWriter(w)
+	sc := bufio.NewScanner(strings.NewReader(src))
+	sc.Buffer(make([]byte, 0, 64*1024), len(src)+1)
+
+	inFence := false
+	for sc.Scan() {
+		line := sc.Text()
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			_, _ = fmt.Fprintf(bw, "    %s%s%s\n", ansiCyan, line, ansiNoColor)
+			continue
+		}
+
+		switch {
+		case mdHeading.MatchString(line):
+			m := mdHeading.FindStringSubmatch(line)
+			style := ansiBold
+			if len(m[1]) == 1 {
+				style = ansiBold + ansiUnderline
+			}
+			_, _ = fmt.Fprintf(bw, "%s%s%s\n", style, renderInline(m[2]), ansiReset)
+		case mdRule.MatchString(line):
+			_, _ = fmt.Fprintf(bw, "%s%s%s\n", ansiDim, strings.Repeat("─", 40), ansiReset)
+		case mdBullet.MatchString(line):
+			m := mdBullet.FindStringSubmatch(line)
+			_, _ = fmt.Fprintf(bw, "%s  • %s\n", m[1], renderInline(m[2]))
+		case mdOrdered.MatchString(line):
+			m := mdOrdered.FindStringSubmatch(line)
+			_, _ = fmt.Fprintf(bw, "%s  %s %s\n", m[1], m[2], renderInline(m[3]))
+		case strings.HasPrefix(trimmed, ">"):
+			quote := strings.TrimSpace(strings.TrimPrefix(trimmed, ">"))
+			_, _ = fmt.Fprintf(bw, "%s│%s %s%s%s\n", ansiDim, ansiReset, ansiItalic, renderInline(quote), ansiNoItalic)
+		default:
+			_, _ = fmt.Fprintln(bw, renderInline(line))
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// renderInline styles the inline Markdown in one line. Code spans are
+// styled as-is, without looking for emphasis inside them.
+func renderInline(s string) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range mdCodeSpans.FindAllStringIndex(s, -1) {
+		b.WriteString(renderEmphasis(s[last:loc[0]]))
+		b.WriteString(ansiCyan + s[loc[0]+1:loc[1]-1] + ansiNoColor)
+		last = loc[1]
+	}
+	b.WriteString(renderEmphasis(s[last:]))
+	return b.String()
+}
+
+// renderEmphasis styles links, bold and italic text in s.
+func renderEmphasis(s string) string {
+	s = mdLink.ReplaceAllString(s, ansiUnderline+"$1"+ansiNoUnder+" "+ansiDim+"($2)"+ansiNormal)
+	s = mdBold.ReplaceAllStringFunc(s, func(m string) string {
+		return ansiBold + m[2:len(m)-2] + ansiNormal
+	})
+	s = mdItalic.ReplaceAllStringFunc(s, func(m string) string {
+		return ansiItalic + m[1:len(m)-1] + ansiNoItalic
+	})
+	return s
+}
@@ -0,0 +1,36 @@
+package commands
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestStyleMarkdown(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"heading", "# Title\n## Sub ##\n", ansiBold + ansiUnderline + "Title" + ansiReset + "\n" + ansiBold + "Sub" + ansiReset + "\n"},
+		{"bold and italic", "a **b** and *c*\n", "a " + ansiBold + "b" + ansiNormal + " and " + ansiItalic + "c" + ansiNoItalic + "\n"},
+		{"snake_case is not italic", "use my_var_name\n", "use my_var_name\n"},
+		{"code span keeps emphasis markers", "run `a **b**`\n", "run " + ansiCyan + "a **b**" + ansiNoColor + "\n"},
+		{"bullets", "- one\n  * two\n", "  • one\n    • two\n"},
+		{"ordered list", "1. first\n", "  1. first\n"},
+		{"fenced code", "```go\nx := *p\n```\n", "    " + ansiCyan + "x := *p" + ansiNoColor + "\n"},
+		{"link", "see [docs](https://x.io)\n", "see " + ansiUnderline + "docs" + ansiNoUnder + " " + ansiDim + "(https://x.io)" + ansiNormal + "\n"},
+		{"plain text", "just text\n", "just text\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := styleMarkdown(&buf, tt.src); err != nil {
+				t.Fatalf("styleMarkdown() error = %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("styleMarkdown(%q) = %q, want %q", tt.src, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package commands
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/sjatkinson/threadkeeper/internal/config"
+	"github.com/sjatkinson/threadkeeper/internal/task"
+)
+
+func RunMigrate(args []string, ctx CommandContext) int {
+	fs := flag.NewFlagSet(ctx.AppName+" migrate", flag.ContinueOnError)
+	fs.SetOutput(ctx.Err)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintln(ctx.Err, migrateUsage(ctx.AppName))
+	}
+
+	var dryRun bool
+	fs.BoolVar(&dryRun, "dry-run", false, "report what would be migrated without writing")
+
+	if err := fs.Parse(args); err != nil {
+		_, _ = fmt.Fprintln(ctx.Err)
+		_, _ = fmt.Fprintln(ctx.Err, migrateUsage(ctx.AppName))
+		return 2
+	}
+
+	if len(fs.Args()) != 0 {
+		_, _ = fmt.Fprintln(ctx.Err, migrateUsage(ctx.AppName))
+		return 2
+	}
+
+	// Get paths and verify threads directory exists
+	paths, err := config.GetPaths(ctx.workspace())
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+
+	if _, err := os.Stat(paths.ThreadsDir); err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: threads directory does not exist at %s. Run '%s init' first.\n", paths.ThreadsDir, ctx.AppName)
+		return 1
+	}
+
+	st := newStore(paths)
+	migrated, total, err := st.Migrate(dryRun)
+	if err != nil {
+		if dryRun {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		} else {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: %v (migrated %d thread files before failing)\n", err, migrated)
+		}
+		return 1
+	}
+
+	switch {
+	case migrated == 0:
+		_, _ = fmt.Fprintf(ctx.Out, "All %d thread files are at schema version %d.\n", total, task.SchemaVersion)
+	case dryRun:
+		_, _ = fmt.Fprintf(ctx.Out, "Would migrate %d of %d thread files to schema version %d.\n", migrated, total, task.SchemaVersion)
+	default:
+		_, _ = fmt.Fprintf(ctx.Out, "Migrated %d of %d thread files to schema version %d.\n", migrated, total, task.SchemaVersion)
+	}
+	return 0
+}
+
+func migrateUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s migrate [--dry-run]
+
+Rewrites thread files, including those in the trash, that were written in
+an older schema version so they match the current one. Older files are
+still readable without migrating; this makes the upgrade permanent.
+Files from a newer version of %s are never touched: upgrade %s instead.
+
+Schema versions:
+  1   files without a schema_version field
+  2   done tasks always have completed_at (back-filled from updated_at)
+
+Flags:
+  --dry-run   report how many files would change without writing
+
+`, app, app, app)
+}
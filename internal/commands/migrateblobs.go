@@ -0,0 +1,165 @@
+package commands
+
+import (
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sjatkinson/threadkeeper/internal/config"
+)
+
+func RunMigrateBlobs(args []string, ctx CommandContext) int {
+	fs := flag.NewFlagSet(ctx.AppName+" migrate-blobs", flag.ContinueOnError)
+	fs.SetOutput(ctx.Err)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintln(ctx.Err, migrateBlobsUsage(ctx.AppName))
+	}
+
+	if err := fs.Parse(args); err != nil {
+		_, _ = fmt.Fprintln(ctx.Err)
+		_, _ = fmt.Fprintln(ctx.Err, migrateBlobsUsage(ctx.AppName))
+		return 2
+	}
+
+	if len(fs.Args()) != 0 {
+		_, _ = fmt.Fprintln(ctx.Err, migrateBlobsUsage(ctx.AppName))
+		return 2
+	}
+
+	// Get paths and verify threads directory exists
+	paths, err := config.GetPaths(ctx.workspace())
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+
+	if _, err := os.Stat(paths.ThreadsDir); err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: threads directory does not exist at %s. Run '%s init' first.\n", paths.ThreadsDir, ctx.AppName)
+		return 1
+	}
+
+	st := newStore(paths)
+	var moved, duplicates int
+	err = st.WithLock(func() error {
+		dirs, err := threadDirsOnDisk(paths.ThreadsDir)
+		if err != nil {
+			return err
+		}
+		ids := make([]string, 0, len(dirs))
+		for id := range dirs {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+
+		for _, id := range ids {
+			m, d, err := hoistThreadBlobs(dirs[id], paths.BlobsDir)
+			moved += m
+			duplicates += d
+			if err != nil {
+				return fmt.Errorf("thread %s: %w", id, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v (moved %d blobs before failing)\n", err, moved)
+		return 1
+	}
+
+	if moved == 0 && duplicates == 0 {
+		_, _ = fmt.Fprintln(ctx.Out, "All blobs are already in the shared store.")
+		return 0
+	}
+
+	_, _ = fmt.Fprintf(ctx.Out, "Moved %d blobs into %s; removed %d duplicate copies.\n", moved, paths.BlobsDir, duplicates)
+	return 0
+}
+
+// hoistThreadBlobs moves the blobs in a thread's own blobs directory into the
+// shared store under blobsDir. A blob already in the store is the same
+// content, so the thread's copy is deleted. Files that don't look like
+// blobs are left where they are. Returns the number of blobs moved and
+// duplicates removed.
+func hoistThreadBlobs(threadDir, blobsDir string) (int, int, error) {
+	root := threadBlobsDir(threadDir)
+	moved, duplicates := 0, 0
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		// Expect <root>/<algo>/<first2>/<next2>/<hash>
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		parts := strings.Split(filepath.ToSlash(rel), "/")
+		if len(parts) != 4 {
+			return nil
+		}
+		dst := blobPathIn(blobsDir, BlobRef{Algo: parts[0], Hash: parts[3]})
+		if dst == "" || dst != filepath.Join(blobsDir, rel) {
+			return nil
+		}
+
+		if _, err := os.Stat(dst); err == nil {
+			if err := os.Remove(path); err != nil {
+				return fmt.Errorf("failed to remove duplicate blob: %w", err)
+			}
+			duplicates++
+			return nil
+		}
+		if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			return fmt.Errorf("failed to create blob directory: %w", err)
+		}
+		if err := os.Rename(path, dst); err != nil {
+			return fmt.Errorf("failed to move blob: %w", err)
+		}
+		moved++
+		return nil
+	})
+	if err != nil {
+		return moved, duplicates, err
+	}
+
+	removeEmptyDirs(root)
+	return moved, duplicates, nil
+}
+
+// removeEmptyDirs removes dir and its subdirectories, deepest first, as far
+// as they are empty. Errors are ignored: a directory that still holds files
+// simply stays.
+func removeEmptyDirs(dir string) {
+	var dirs []string
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && d.IsDir() {
+			dirs = append(dirs, path)
+		}
+		return nil
+	})
+	for i := len(dirs) - 1; i >= 0; i-- {
+		_ = os.Remove(dirs[i])
+	}
+}
+
+func migrateBlobsUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s migrate-blobs
+
+Moves note blobs from each thread's own blobs directory into the shared
+store at <workspace>/blobs, so identical content is kept once. Attachment
+logs are not rewritten: they refer to blobs by hash, which doesn't change.
+Blobs of trashed threads stay with them.
+
+`, app)
+}
@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sjatkinson/threadkeeper/internal/config"
+)
+
+func TestRunMigrateBlobs(t *testing.T) {
+	tmpDir := setupWorkspace(t)
+	for _, title := range []string{"first", "second"} {
+		ctx, _, errOut := newTestContext()
+		if code := RunAdd([]string{title}, ctx); code != 0 {
+			t.Fatalf("RunAdd() exit code = %d, stderr: %s", code, errOut.String())
+		}
+	}
+
+	paths, err := config.GetPaths("")
+	if err != nil {
+		t.Fatalf("GetPaths() error = %v", err)
+	}
+	st := newStore(paths)
+	tasks, err := st.LoadAll()
+	if err != nil || len(tasks) != 2 {
+		t.Fatalf("LoadAll() = %d tasks, err = %v", len(tasks), err)
+	}
+
+	// Per-thread blobs as written before the shared store: the same content
+	// on both threads, plus one unique blob
+	var shared BlobRef
+	for i, tk := range tasks {
+		dir := threadBlobsDir(st.ThreadDir(tk.ID))
+		hash, _, err := storeBlob(dir, []byte("same on both\n"))
+		if err != nil {
+			t.Fatalf("storeBlob() error = %v", err)
+		}
+		shared = BlobRef{Algo: "sha256", Hash: hash}
+		if i == 0 {
+			if _, _, err := storeBlob(dir, []byte("only here\n")); err != nil {
+				t.Fatalf("storeBlob() error = %v", err)
+			}
+		}
+	}
+
+	ctx, out, errOut := newTestContext()
+	if code := RunMigrateBlobs(nil, ctx); code != 0 {
+		t.Fatalf("RunMigrateBlobs() exit code = %d, stderr: %s", code, errOut.String())
+	}
+	if !strings.Contains(out.String(), "Moved 2 blobs") || !strings.Contains(out.String(), "removed 1 duplicate") {
+		t.Errorf("stdout = %q, want 2 moved and 1 duplicate removed", out.String())
+	}
+
+	blobs, _ := filepath.Glob(filepath.Join(tmpDir, "blobs", "sha256", "*", "*", "*"))
+	if len(blobs) != 2 {
+		t.Errorf("shared blobs = %v, want 2", blobs)
+	}
+	for _, tk := range tasks {
+		if _, err := os.Stat(threadBlobsDir(st.ThreadDir(tk.ID))); !os.IsNotExist(err) {
+			t.Errorf("thread %s still has a blobs directory (err = %v)", tk.ID, err)
+		}
+		if p := blobPath(paths.BlobsDir, st.ThreadDir(tk.ID), shared); p != blobPathIn(paths.BlobsDir, shared) {
+			t.Errorf("blobPath() = %q, want the shared store", p)
+		}
+	}
+
+	ctx, out, _ = newTestContext()
+	if code := RunMigrateBlobs(nil, ctx); code != 0 || !strings.Contains(out.String(), "already") {
+		t.Errorf("second RunMigrateBlobs() = %d, %q; want nothing to do", code, out.String())
+	}
+}
@@ -0,0 +1,180 @@
+package commands
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/sjatkinson/threadkeeper/internal/config"
+)
+
+func RunMvAtt(args []string, ctx CommandContext) int {
+	fs := flag.NewFlagSet(ctx.AppName+" mv-att", flag.ContinueOnError)
+	fs.SetOutput(ctx.Err)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintln(ctx.Err, mvAttUsage(ctx.AppName))
+	}
+
+	var (
+		from     string
+		to       string
+		attIndex int
+		attID    string
+	)
+	fs.StringVar(&from, "from", "", "thread ID to move the attachment from")
+	fs.StringVar(&to, "to", "", "thread ID to move the attachment to")
+	fs.IntVar(&attIndex, "att", 0, "attachment index (1-based)")
+	fs.StringVar(&attID, "att-id", "", "attachment ID (alternative to --att)")
+
+	if err := fs.Parse(args); err != nil {
+		_, _ = fmt.Fprintln(ctx.Err)
+		_, _ = fmt.Fprintln(ctx.Err, mvAttUsage(ctx.AppName))
+		return 2
+	}
+
+	if len(fs.Args()) != 0 {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: unexpected arguments\n")
+		_, _ = fmt.Fprintln(ctx.Err, mvAttUsage(ctx.AppName))
+		return 2
+	}
+
+	if from == "" || to == "" {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: both --from and --to are required\n")
+		return 2
+	}
+
+	if attIndex == 0 && attID == "" {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: must specify either --att <index> or --att-id <id>\n")
+		return 2
+	}
+
+	if attIndex != 0 && attID != "" {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: cannot specify both --att and --att-id\n")
+		return 2
+	}
+
+	// Get paths and verify threads directory exists
+	paths, err := config.GetPaths(ctx.workspace())
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+
+	if _, err := os.Stat(paths.ThreadsDir); err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: threads directory does not exist at %s. Run '%s init' first.\n", paths.ThreadsDir, ctx.AppName)
+		return 1
+	}
+
+	// Resolve both threads
+	st := newStore(paths)
+	src, err := st.ResolveID(from)
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+	dst, err := st.ResolveID(to)
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+	if src.ID == dst.ID {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: --from and --to are the same thread\n")
+		return 2
+	}
+
+	srcDir := st.ThreadDir(src.ID)
+	dstDir := st.ThreadDir(dst.ID)
+
+	// Find the attachment in the source's current set
+	events, err := loadAttachments(srcDir)
+	if err != nil && !os.IsNotExist(err) {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: failed to load attachments: %v\n", err)
+		return 1
+	}
+	currentAtts := computeCurrentAttachments(events)
+
+	var target *AttachmentEvent
+	if attID != "" {
+		for i := range currentAtts {
+			if currentAtts[i].Att.AttID == attID {
+				target = &currentAtts[i]
+				break
+			}
+		}
+		if target == nil {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: attachment with ID %q not found\n", attID)
+			return 1
+		}
+	} else {
+		if attIndex < 1 {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: attachment index must be >= 1\n")
+			return 2
+		}
+		if attIndex > len(currentAtts) {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: attachment index %d out of range (max: %d)\n", attIndex, len(currentAtts))
+			return 1
+		}
+		target = &currentAtts[attIndex-1]
+	}
+
+	// Make sure the note content is in the shared blob store, since a blob
+	// written before the store was shared only exists under the source
+	// thread; content addressing keeps the hash unchanged
+	if target.Att.Blob != nil {
+		srcBlob := blobPath(paths.BlobsDir, srcDir, *target.Att.Blob)
+		if srcBlob == "" {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: unsupported blob algorithm %q\n", target.Att.Blob.Algo)
+			return 1
+		}
+		content, err := os.ReadFile(srcBlob)
+		if err != nil {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: failed to read blob: %v\n", err)
+			return 1
+		}
+		if _, _, err := storeBlob(paths.BlobsDir, content); err != nil {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: failed to store blob: %v\n", err)
+			return 1
+		}
+	}
+
+	// Add to the destination before removing from the source, so a failure
+	// in between leaves the attachment on both threads rather than neither.
+	// The source blob is left in place since history still references it.
+	now := ctx.clock().Now().UTC()
+	ts := now.Format(time.RFC3339)
+	if err := appendAttachmentEvent(st, dstDir, AttachmentEvent{Op: "add", TS: ts, Att: target.Att}); err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: failed to append attachment event: %v\n", err)
+		return 1
+	}
+	if err := appendAttachmentEvent(st, srcDir, AttachmentEvent{Op: "remove", TS: ts, Att: target.Att}); err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: failed to append attachment event: %v\n", err)
+		return 1
+	}
+
+	for _, id := range []string{dst.ID, src.ID} {
+		if err := updateThreadAttachmentsLog(st, id, now); err != nil {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: failed to update thread.json: %v\n", err)
+			return 1
+		}
+	}
+
+	_, _ = fmt.Fprintf(ctx.Out, "Moved %s %q from %s to %s\n", target.Att.Kind, target.Att.Name, src.ID, dst.ID)
+	return 0
+}
+
+func mvAttUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s mv-att --from <thread-id> (--att <index> | --att-id <id>) --to <thread-id>
+
+Moves an attachment to another thread, keeping its name, media type, and
+attachment ID. Note content is copied into the destination's blob store.
+
+Flags:
+  --from <id>       thread that currently has the attachment
+  --to <id>         thread to move it to
+  --att <index>     attachment index (1-based, from 'show' output)
+  --att-id <id>     attachment ID (alternative to --att)
+
+`, app)
+}
@@ -0,0 +1,88 @@
+package commands
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/sjatkinson/threadkeeper/internal/config"
+)
+
+func TestRunMvAtt_MovesNote(t *testing.T) {
+	setupWorkspace(t)
+
+	for _, title := range []string{"wrong thread", "right thread"} {
+		ctx, _, errOut := newTestContext()
+		if code := RunAdd([]string{title}, ctx); code != 0 {
+			t.Fatalf("RunAdd() exit code = %d, stderr: %s", code, errOut.String())
+		}
+	}
+
+	paths, err := config.GetPaths("")
+	if err != nil {
+		t.Fatalf("GetPaths() error = %v", err)
+	}
+	st := newStore(paths)
+	src, err := st.ResolveID("1")
+	if err != nil {
+		t.Fatalf("ResolveID(1) error = %v", err)
+	}
+	dst, err := st.ResolveID("2")
+	if err != nil {
+		t.Fatalf("ResolveID(2) error = %v", err)
+	}
+	srcDir, dstDir := st.ThreadDir(src.ID), st.ThreadDir(dst.ID)
+
+	// File a note on the wrong thread, with its blob in the thread's own
+	// directory as written before blobs were shared
+	content := []byte("# misfiled note\n")
+	hash, size, err := storeBlob(threadBlobsDir(srcDir), content)
+	if err != nil {
+		t.Fatalf("storeBlob() error = %v", err)
+	}
+	note := Attachment{
+		AttID:     "ATT1",
+		Kind:      "note",
+		Name:      "plan.md",
+		MediaType: "text/markdown",
+		Blob:      &BlobRef{Algo: "sha256", Hash: hash},
+		Size:      size,
+	}
+	event := AttachmentEvent{Op: "add", TS: time.Now().UTC().Format(time.RFC3339), Att: note}
+	if err := appendAttachmentEvent(st, srcDir, event); err != nil {
+		t.Fatalf("appendAttachmentEvent() error = %v", err)
+	}
+
+	ctx, _, errOut := newTestContext()
+	if code := RunMvAtt([]string{"--from", "1", "--att", "1", "--to", "2"}, ctx); code != 0 {
+		t.Fatalf("RunMvAtt() exit code = %d, stderr: %s", code, errOut.String())
+	}
+
+	srcEvents, err := loadAttachments(srcDir)
+	if err != nil {
+		t.Fatalf("loadAttachments(src) error = %v", err)
+	}
+	if got := computeCurrentAttachments(srcEvents); len(got) != 0 {
+		t.Errorf("source still has %d current attachments, want 0", len(got))
+	}
+
+	dstEvents, err := loadAttachments(dstDir)
+	if err != nil {
+		t.Fatalf("loadAttachments(dst) error = %v", err)
+	}
+	got := computeCurrentAttachments(dstEvents)
+	if len(got) != 1 {
+		t.Fatalf("destination has %d current attachments, want 1", len(got))
+	}
+	if got[0].Att.AttID != note.AttID || got[0].Att.Name != note.Name || got[0].Att.MediaType != note.MediaType {
+		t.Errorf("moved attachment = %+v, want %+v", got[0].Att, note)
+	}
+
+	data, err := os.ReadFile(blobPath(paths.BlobsDir, dstDir, *got[0].Att.Blob))
+	if err != nil {
+		t.Fatalf("blob not readable from destination: %v", err)
+	}
+	if string(data) != string(content) {
+		t.Errorf("blob content = %q, want %q", data, content)
+	}
+}
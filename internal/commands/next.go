@@ -0,0 +1,126 @@
+package commands
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/sjatkinson/threadkeeper/internal/config"
+	"github.com/sjatkinson/threadkeeper/internal/date"
+	"github.com/sjatkinson/threadkeeper/internal/task"
+)
+
+func RunNext(args []string, ctx CommandContext) int {
+	fs := flag.NewFlagSet(ctx.AppName+" next", flag.ContinueOnError)
+	fs.SetOutput(ctx.Err)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintln(ctx.Err, nextUsage(ctx.AppName))
+	}
+
+	var (
+		project string
+		tags    stringList
+	)
+	fs.StringVar(&project, "project", "", "only consider tasks in this project")
+	fs.StringVar(&project, "p", "", "only consider tasks in this project (shorthand)")
+	fs.Var(&tags, "tag", "only consider tasks with this tag (repeatable; all must match)")
+
+	if err := fs.Parse(args); err != nil {
+		_, _ = fmt.Fprintln(ctx.Err)
+		_, _ = fmt.Fprintln(ctx.Err, nextUsage(ctx.AppName))
+		return 2
+	}
+
+	if len(fs.Args()) != 0 {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: unexpected arguments\n")
+		_, _ = fmt.Fprintln(ctx.Err, nextUsage(ctx.AppName))
+		return 2
+	}
+
+	// Get paths and verify threads directory exists
+	paths, err := config.GetPaths(ctx.workspace())
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+
+	if _, err := os.Stat(paths.ThreadsDir); err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: threads directory does not exist at %s. Run '%s init' first.\n", paths.ThreadsDir, ctx.AppName)
+		return 1
+	}
+
+	st := newStore(paths)
+	tasks, err := loadAllTasks(st, ctx)
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+
+	t := pickNext(filterTasks(tasks, taskFilter{Project: project, Tags: tags}))
+	if t == nil {
+		_, _ = fmt.Fprintln(ctx.Out, "No open tasks found.")
+		return 0
+	}
+	_ = st.EnsureShortID(t) // so the header shows a short_id to act on
+
+	attachments, err := loadAttachments(st.ThreadDir(t.ID))
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Warning: failed to load attachments: %v\n", err)
+		attachments = []AttachmentEvent{}
+	}
+
+	dateLayout, err := config.LoadDisplayDateFormat()
+	if err != nil {
+		dateLayout = config.DisplayLayoutISO // Default on error
+	}
+	// Due dates also say how far off they are, in the timezone config key
+	tz, err := config.LoadTimezone()
+	if err != nil {
+		tz = time.Local // Default on error
+	}
+	today := date.Today(ctx.clock(), tz)
+
+	displayContextual(ctx.Out, t, attachments, ctx.AppName, dateLayout, today)
+	return 0
+}
+
+// pickNext returns the most urgent of tasks: the earliest due date first,
+// which puts overdue tasks ahead of everything, then tasks without a due
+// date, oldest created first. Returns nil when tasks is empty.
+func pickNext(tasks []*task.Task) *task.Task {
+	if len(tasks) == 0 {
+		return nil
+	}
+	sorted := append([]*task.Task(nil), tasks...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if (a.DueAt == nil) != (b.DueAt == nil) {
+			return a.DueAt != nil
+		}
+		if a.DueAt != nil && !a.DueAt.Equal(*b.DueAt) {
+			return a.DueAt.Before(*b.DueAt)
+		}
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return a.ID < b.ID
+	})
+	return sorted[0]
+}
+
+func nextUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s next [-p <project>] [--tag <tag>]...
+
+Shows the single most urgent open task, in the same format as show:
+overdue tasks first, then the soonest due, then the oldest task without
+a due date.
+
+Flags:
+  -p, --project <name>   only consider tasks in this project
+  --tag <tag>            only consider tasks with this tag (repeat to AND tags)
+
+`, app)
+}
@@ -0,0 +1,65 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sjatkinson/threadkeeper/internal/task"
+)
+
+func TestPickNext(t *testing.T) {
+	base := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	day := func(n int) *time.Time {
+		d := base.AddDate(0, 0, n)
+		return &d
+	}
+	mk := func(id string, due *time.Time, createdDay int) *task.Task {
+		return &task.Task{ID: id, Status: task.StatusOpen, DueAt: due, CreatedAt: base.AddDate(0, 0, createdDay)}
+	}
+
+	tests := []struct {
+		name  string
+		tasks []*task.Task
+		want  string
+	}{
+		{"none", nil, ""},
+		{"earliest due wins", []*task.Task{mk("later", day(5), 0), mk("sooner", day(2), 1)}, "sooner"},
+		{"due beats undated", []*task.Task{mk("undated", nil, 0), mk("due", day(30), 1)}, "due"},
+		{"oldest undated", []*task.Task{mk("new", nil, 2), mk("old", nil, 1)}, "old"},
+		{"same due falls back to created", []*task.Task{mk("b", day(3), 2), mk("a", day(3), 1)}, "a"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := pickNext(tt.tasks)
+			if (got == nil) != (tt.want == "") || (got != nil && got.ID != tt.want) {
+				t.Errorf("pickNext() = %v, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunNext_Project(t *testing.T) {
+	setupWorkspace(t)
+	for _, args := range [][]string{
+		{"--due", "2020-01-01", "urgent elsewhere"},
+		{"--project", "home", "tidy up"},
+	} {
+		ctx, _, errOut := newTestContext()
+		if code := RunAdd(args, ctx); code != 0 {
+			t.Fatalf("RunAdd(%v) exit code = %d, stderr: %s", args, code, errOut.String())
+		}
+	}
+	ctx, out, _ := newTestContext()
+	if code := RunNext(nil, ctx); code != 0 || !strings.Contains(out.String(), "Task 1 ") {
+		t.Errorf("RunNext() = %d, %q; want the overdue task 1", code, out.String())
+	}
+	ctx, out, _ = newTestContext()
+	if code := RunNext([]string{"-p", "home"}, ctx); code != 0 || !strings.Contains(out.String(), "Task 2 ") {
+		t.Errorf("RunNext(-p home) = %d, %q; want the home task 2", code, out.String())
+	}
+	ctx, out, _ = newTestContext()
+	if code := RunNext([]string{"-p", "none"}, ctx); code != 0 || !strings.Contains(out.String(), "No open tasks") {
+		t.Errorf("RunNext(-p none) = %d, %q; want no tasks", code, out.String())
+	}
+}
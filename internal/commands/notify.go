@@ -0,0 +1,121 @@
+package commands
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/sjatkinson/threadkeeper/internal/config"
+	"github.com/sjatkinson/threadkeeper/internal/date"
+	"github.com/sjatkinson/threadkeeper/internal/task"
+)
+
+func RunNotify(args []string, ctx CommandContext) int {
+	fs := flag.NewFlagSet(ctx.AppName+" notify", flag.ContinueOnError)
+	fs.SetOutput(ctx.Err)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintln(ctx.Err, notifyUsage(ctx.AppName))
+	}
+
+	var days int
+	fs.IntVar(&days, "days", 1, "number of days ahead to include")
+
+	if err := fs.Parse(args); err != nil {
+		_, _ = fmt.Fprintln(ctx.Err)
+		_, _ = fmt.Fprintln(ctx.Err, notifyUsage(ctx.AppName))
+		return 2
+	}
+
+	if len(fs.Args()) != 0 {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: unexpected arguments\n")
+		_, _ = fmt.Fprintln(ctx.Err, notifyUsage(ctx.AppName))
+		return 2
+	}
+
+	if days < 0 {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: --days must not be negative\n")
+		return 2
+	}
+
+	// Get paths and verify threads directory exists
+	paths, err := config.GetPaths(ctx.workspace())
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+
+	if _, err := os.Stat(paths.ThreadsDir); err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: threads directory does not exist at %s. Run '%s init' first.\n", paths.ThreadsDir, ctx.AppName)
+		return 1
+	}
+
+	tz, err := config.LoadTimezone()
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+
+	st := newStore(paths)
+	tasks, err := loadAllTasks(st, ctx)
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+
+	// Same window as agenda: open tasks due on or before the last day
+	today := date.Today(ctx.clock(), tz)
+	due := filterTasks(tasks, taskFilter{
+		DueBefore: today.AddDate(0, 0, days).Format(dueDateLayout),
+	})
+
+	displayNotify(ctx.Out, due, today)
+	return 0
+}
+
+// displayNotify prints one line per task, soonest due first:
+//
+//	<id>: <title> (<how far off>)
+//
+// where <id> is the short ID, or the canonical ID if the task has none.
+// Nothing is printed for no tasks.
+func displayNotify(out io.Writer, tasks []*task.Task, today time.Time) {
+	sorted := append([]*task.Task(nil), tasks...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].DueAt.Before(*sorted[j].DueAt)
+	})
+
+	for _, t := range sorted {
+		id := t.ID
+		if t.ShortID != nil {
+			id = strconv.Itoa(*t.ShortID)
+		}
+		_, _ = fmt.Fprintf(out, "%s: %s (%s)\n", id, t.Title, date.RelativeDue(*t.DueAt, today))
+	}
+}
+
+func notifyUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s notify [--days <n>]
+
+Prints one line per open task that is overdue or due within the next n
+days, soonest first, for piping into notify-send, osascript or mail:
+
+  <id>: <title> (<due>)
+
+such as "3: Renew passport (due tomorrow)" or "7: File taxes (overdue 2
+days)". <id> is the short ID, or the full ID if the task has none. Days
+are computed in the timezone config key. Prints nothing and exits 0 when
+nothing is due.
+
+Flags:
+  --days <n>    how many days ahead to include (default 1; 0 means today)
+
+Example crontab entry:
+  0 9 * * * %s notify | xargs -r -d '\n' -n1 notify-send "Due"
+
+`, app, app)
+}
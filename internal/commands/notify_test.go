@@ -0,0 +1,47 @@
+package commands
+
+import "testing"
+
+func TestRunNotify(t *testing.T) {
+	setupWorkspace(t)
+
+	clock := testClock()
+
+	ctx, out, errOut := newTestContext()
+	ctx.Clock = clock
+	if code := RunNotify(nil, ctx); code != 0 || out.Len() != 0 {
+		t.Fatalf("RunNotify() on an empty workspace = %q (exit %d), want no output; stderr: %s", out.String(), code, errOut.String())
+	}
+
+	for _, args := range [][]string{
+		{"--due", "2026-03-11", "soon"},
+		{"--due", "2026-03-08", "late"},
+		{"--due", "2026-03-14", "later"},
+		{"undated"},
+	} {
+		ctx, _, errOut := newTestContext()
+		ctx.Clock = clock
+		if code := RunAdd(args, ctx); code != 0 {
+			t.Fatalf("RunAdd(%v) exit code = %d, stderr: %s", args, code, errOut.String())
+		}
+	}
+
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{nil, "2: late (overdue 2 days)\n1: soon (due tomorrow)\n"},
+		{[]string{"--days", "0"}, "2: late (overdue 2 days)\n"},
+		{[]string{"--days", "7"}, "2: late (overdue 2 days)\n1: soon (due tomorrow)\n3: later (due in 4 days)\n"},
+	}
+	for _, tt := range tests {
+		ctx, out, errOut := newTestContext()
+		ctx.Clock = clock
+		if code := RunNotify(tt.args, ctx); code != 0 {
+			t.Fatalf("RunNotify(%v) exit code = %d, stderr: %s", tt.args, code, errOut.String())
+		}
+		if got := out.String(); got != tt.want {
+			t.Errorf("RunNotify(%v) = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
@@ -3,13 +3,13 @@ package commands
 import (
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"runtime"
 	"strings"
 
 	"github.com/sjatkinson/threadkeeper/internal/config"
-	"github.com/sjatkinson/threadkeeper/internal/store"
 )
 
 // FileOpener abstracts platform-specific file opening.
@@ -26,7 +26,8 @@ func detectPlatform() string {
 
 // newFileOpener creates a platform-specific file opener.
 // Returns an error if the platform is not supported.
-func newFileOpener() (FileOpener, error) {
+// It is a variable so tests can substitute a fake opener.
+var newFileOpener = func() (FileOpener, error) {
 	platform := detectPlatform()
 	switch platform {
 	case "darwin":
@@ -133,7 +134,7 @@ func RunOpen(args []string, ctx CommandContext) int {
 	}
 
 	// Get paths and verify threads directory exists
-	paths, err := config.GetPaths(ctx.Path)
+	paths, err := config.GetPaths(ctx.workspace())
 	if err != nil {
 		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
 		return 1
@@ -145,7 +146,7 @@ func RunOpen(args []string, ctx CommandContext) int {
 	}
 
 	// Resolve thread ID
-	st := store.NewFileStore(paths.ThreadsDir)
+	st := newStore(paths)
 	t, err := st.ResolveID(threadIDStr)
 	if err != nil {
 		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
@@ -153,7 +154,7 @@ func RunOpen(args []string, ctx CommandContext) int {
 	}
 
 	// Get thread directory path
-	threadDir := store.ThreadPath(paths.ThreadsDir, t.ID)
+	threadDir := st.ThreadDir(t.ID)
 
 	// Load attachments
 	attachments, err := loadAttachments(threadDir)
@@ -228,7 +229,7 @@ func RunOpen(args []string, ctx CommandContext) int {
 		return 1
 	}
 
-	blobPath := blobPath(threadDir, *target.Att.Blob)
+	blobPath := blobPath(paths.BlobsDir, threadDir, *target.Att.Blob)
 	if blobPath == "" {
 		_, _ = fmt.Fprintf(ctx.Err, "Error: unsupported blob algorithm %q\n", target.Att.Blob.Algo)
 		return 1
@@ -250,6 +251,17 @@ func RunOpen(args []string, ctx CommandContext) int {
 		return 0
 	}
 
+	// Blobs have no extension, so viewers can't tell an image is one; open
+	// a copy named with the right extension instead
+	openPath := blobPath
+	if isImageMediaType(target.Att.MediaType) {
+		openPath, err = copyBlobWithExtension(blobPath, mediaTypeExtension(target.Att.MediaType))
+		if err != nil {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: failed to copy image for opening: %v\n", err)
+			return 1
+		}
+	}
+
 	// Open file using platform-specific opener
 	opener, err := newFileOpener()
 	if err != nil {
@@ -257,7 +269,7 @@ func RunOpen(args []string, ctx CommandContext) int {
 		return 1
 	}
 
-	if err := opener.OpenFile(blobPath); err != nil {
+	if err := opener.OpenFile(openPath); err != nil {
 		_, _ = fmt.Fprintf(ctx.Err, "Error: failed to open file: %v\n", err)
 		return 1
 	}
@@ -265,11 +277,37 @@ func RunOpen(args []string, ctx CommandContext) int {
 	return 0
 }
 
+// copyBlobWithExtension copies the blob at path to a new temp file ending
+// in ext and returns its path. The copy is not removed: the opener may
+// still be reading it after open returns.
+func copyBlobWithExtension(path, ext string) (string, error) {
+	in, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = in.Close() }()
+
+	out, err := os.CreateTemp("", "tk-att-*"+ext)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return "", err
+	}
+	if err := out.Close(); err != nil {
+		return "", err
+	}
+	return out.Name(), nil
+}
+
 func openUsage(app string) string {
 	return fmt.Sprintf(`Usage:
   %s open [--att <index> | --att-id <id>] [--print-path] <thread-id>
 
-Open an attachment from a thread.
+Open an attachment from a thread. Image notes (by the media type detected
+when they were attached) open from a temporary copy with the matching file
+extension, so image viewers recognize them.
 
 Flags:
   --att <index>     attachment index (1-based, from 'show' output)
@@ -0,0 +1,175 @@
+package commands
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sjatkinson/threadkeeper/internal/config"
+	"github.com/sjatkinson/threadkeeper/internal/store"
+	"github.com/sjatkinson/threadkeeper/internal/task"
+)
+
+// opsLogName is the append-only operation log at the workspace root.
+const opsLogName = "ops.jsonl"
+
+// opUndo is the command recorded when an operation is undone.
+const opUndo = "undo"
+
+// opEntry is one line of ops.jsonl. Mutating commands record the full
+// thread.json of every task they changed, as it was before the change, so
+// undo can restore it (or recreate it, for remove).
+//
+// Entries are numbered by Seq. An undo is itself an entry whose Undoes
+// names the Seq it reversed, so the log can later support multi-level undo
+// by walking back past undone entries.
+type opEntry struct {
+	Seq     int               `json:"seq"`
+	TS      string            `json:"ts"` // RFC3339 UTC timestamp
+	Command string            `json:"command"`
+	IDs     []string          `json:"ids"`
+	Before  []json.RawMessage `json:"before,omitempty"`
+	Undoes  int               `json:"undoes,omitempty"`
+}
+
+// opRecorder collects the before-state of tasks a command changes.
+type opRecorder struct {
+	command string
+	ids     []string
+	before  []json.RawMessage
+}
+
+func newOpRecorder(command string) *opRecorder {
+	return &opRecorder{command: command}
+}
+
+// snapshotTask returns t's current state for opRecorder.add; call it before
+// mutating t.
+func snapshotTask(t *task.Task) json.RawMessage {
+	data, err := json.Marshal(t)
+	if err != nil {
+		// Task always marshals; an empty snapshot just makes it unrestorable
+		return nil
+	}
+	return data
+}
+
+// add records that the task with before-state snap was changed.
+func (r *opRecorder) add(id string, snap json.RawMessage) {
+	if snap == nil {
+		return
+	}
+	r.ids = append(r.ids, id)
+	r.before = append(r.before, snap)
+}
+
+// commit appends the recorded operation to the log, warning on ctx.Err if
+// it can't be written (the command itself already succeeded).
+func (r *opRecorder) commit(st *store.FileStore, paths config.Paths, ctx CommandContext) {
+	if len(r.ids) == 0 {
+		return
+	}
+	entry := opEntry{
+		TS:      ctx.clock().Now().UTC().Format(time.RFC3339),
+		Command: r.command,
+		IDs:     r.ids,
+		Before:  r.before,
+	}
+	if err := appendOp(st, paths, entry); err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Warning: failed to record %s for undo: %v\n", r.command, err)
+	}
+}
+
+// appendOp assigns entry the next Seq and appends it to ops.jsonl under the
+// workspace lock.
+func appendOp(st *store.FileStore, paths config.Paths, entry opEntry) error {
+	return st.WithLock(func() error {
+		entries, err := loadOps(paths)
+		if err != nil {
+			return err
+		}
+		entry.Seq = 1
+		if len(entries) > 0 {
+			entry.Seq = entries[len(entries)-1].Seq + 1
+		}
+
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return fmt.Errorf("failed to marshal operation: %w", err)
+		}
+
+		f, err := os.OpenFile(opsLogPath(paths), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return fmt.Errorf("failed to open %s: %w", opsLogName, err)
+		}
+		if _, err := f.Write(append(data, '\n')); err != nil {
+			_ = f.Close()
+			return fmt.Errorf("failed to write %s: %w", opsLogName, err)
+		}
+		return f.Close()
+	})
+}
+
+// loadOps reads every entry of ops.jsonl, skipping malformed lines.
+// A missing log has no entries.
+func loadOps(paths config.Paths) ([]opEntry, error) {
+	f, err := os.Open(opsLogPath(paths))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []opEntry
+	scanner := bufio.NewScanner(f)
+	// Entries hold whole task files, so allow long lines (up to 16MB)
+	const maxCapacity = 16 * 1024 * 1024
+	scanner.Buffer(make([]byte, 0, 64*1024), maxCapacity)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var entry opEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", opsLogName, err)
+	}
+	return entries, nil
+}
+
+// lastUndoableOp returns the most recent non-undo entry, or nil if there is
+// none or it has already been undone. Only a single level of undo is
+// supported for now.
+func lastUndoableOp(entries []opEntry) *opEntry {
+	undone := make(map[int]bool)
+	for _, e := range entries {
+		if e.Command == opUndo {
+			undone[e.Undoes] = true
+		}
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].Command == opUndo {
+			continue
+		}
+		if undone[entries[i].Seq] {
+			return nil
+		}
+		return &entries[i]
+	}
+	return nil
+}
+
+func opsLogPath(paths config.Paths) string {
+	return filepath.Join(paths.Workspace, opsLogName)
+}
@@ -0,0 +1,104 @@
+package commands
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/sjatkinson/threadkeeper/internal/config"
+)
+
+// defaultPagerHeight is the screen height assumed when $LINES is unset.
+const defaultPagerHeight = 24
+
+// isOutputTerminal reports whether w is an interactive terminal.
+// It is a variable so tests can simulate a TTY.
+var isOutputTerminal = func(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// pager collects a command's output and, on Close, shows it through the
+// configured pager. Use it by replacing ctx.Out:
+//
+//	pg := startPager(ctx, noPager)
+//	defer pg.Close()
+//	ctx.Out = pg
+//
+// When paging doesn't apply, writes go straight through to the original
+// output and Close does nothing.
+type pager struct {
+	out     io.Writer
+	command []string
+	buf     bytes.Buffer
+}
+
+// startPager returns a pager for ctx.Out. Paging applies only when noPager
+// is false, ctx.Out is a terminal, and a pager is set by the pager config
+// key or $PAGER ("cat" counts as none).
+func startPager(ctx CommandContext, noPager bool) *pager {
+	p := &pager{out: ctx.Out}
+	if noPager || !isOutputTerminal(ctx.Out) {
+		return p
+	}
+
+	command, _ := config.LoadPager()
+	if command == "" {
+		command = os.Getenv("PAGER")
+	}
+	if parts := strings.Fields(command); len(parts) > 0 && parts[0] != "cat" {
+		p.command = parts
+	}
+	return p
+}
+
+func (p *pager) Write(b []byte) (int, error) {
+	if p.command == nil {
+		return p.out.Write(b)
+	}
+	return p.buf.Write(b)
+}
+
+// Close shows the collected output. Output that fits on one screen, or that
+// the pager fails on, is written directly.
+func (p *pager) Close() {
+	if p.command == nil {
+		return
+	}
+	if bytes.Count(p.buf.Bytes(), []byte("\n")) < screenHeight() {
+		_, _ = p.out.Write(p.buf.Bytes())
+		return
+	}
+
+	cmd := exec.Command(p.command[0], p.command[1:]...)
+	cmd.Stdin = bytes.NewReader(p.buf.Bytes())
+	cmd.Stdout = p.out
+	cmd.Stderr = os.Stderr
+	// Like git: quit if one screen, keep colors, don't clear the screen
+	if os.Getenv("LESS") == "" {
+		cmd.Env = append(os.Environ(), "LESS=FRX")
+	}
+	if err := cmd.Start(); err != nil {
+		_, _ = p.out.Write(p.buf.Bytes())
+		return
+	}
+	_ = cmd.Wait()
+}
+
+// screenHeight is the terminal height from $LINES, or defaultPagerHeight.
+func screenHeight() int {
+	if n, err := strconv.Atoi(os.Getenv("LINES")); err == nil && n > 0 {
+		return n
+	}
+	return defaultPagerHeight
+}
@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+// fakeTerminal makes every writer look like a terminal for the test.
+func fakeTerminal(t *testing.T) {
+	t.Helper()
+	orig := isOutputTerminal
+	isOutputTerminal = func(io.Writer) bool { return true }
+	t.Cleanup(func() { isOutputTerminal = orig })
+}
+
+func TestRunList_Pager(t *testing.T) {
+	setupWorkspace(t)
+	ctx, _, _ := newTestContext()
+	if code := RunAdd([]string{"paged task"}, ctx); code != 0 {
+		t.Fatalf("add failed: %d", code)
+	}
+
+	fakeTerminal(t)
+	t.Setenv("PAGER", "sed s/^/>/")
+
+	tests := []struct {
+		name  string
+		args  []string
+		lines string
+		paged bool
+	}{
+		{name: "long output is paged", args: nil, lines: "1", paged: true},
+		{name: "--no-pager", args: []string{"--no-pager"}, lines: "1", paged: false},
+		{name: "short output", args: nil, lines: "", paged: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("LINES", tt.lines)
+			ctx, out, errOut := newTestContext()
+			if code := RunList(tt.args, ctx); code != 0 {
+				t.Fatalf("list failed: %d, stderr: %s", code, errOut.String())
+			}
+			got := out.String()
+			if !strings.Contains(got, "paged task") {
+				t.Fatalf("expected task in output, got %q", got)
+			}
+			if paged := strings.HasPrefix(got, ">"); paged != tt.paged {
+				t.Errorf("paged = %v, want %v; output %q", paged, tt.paged, got)
+			}
+		})
+	}
+}
+
+func TestRunList_PagerCatIsNone(t *testing.T) {
+	setupWorkspace(t)
+	ctx, _, _ := newTestContext()
+	if code := RunAdd([]string{"plain task"}, ctx); code != 0 {
+		t.Fatalf("add failed: %d", code)
+	}
+
+	fakeTerminal(t)
+	t.Setenv("PAGER", "cat")
+	t.Setenv("LINES", "1")
+
+	ctx, out, _ := newTestContext()
+	if code := RunList(nil, ctx); code != 0 {
+		t.Fatalf("list failed: %d", code)
+	}
+	if !strings.Contains(out.String(), "plain task") {
+		t.Errorf("expected task in output, got %q", out.String())
+	}
+}
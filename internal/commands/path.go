@@ -1,14 +1,25 @@
 package commands
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 
 	"github.com/sjatkinson/threadkeeper/internal/config"
-	"github.com/sjatkinson/threadkeeper/internal/store"
 )
 
+// threadPaths is the --json output of path.
+type threadPaths struct {
+	ID             string `json:"id"`
+	ShortID        *int   `json:"short_id,omitempty"`
+	Dir            string `json:"dir"`
+	ThreadJSON     string `json:"thread_json"`
+	AttachmentsLog string `json:"attachments_log"`
+}
+
 func RunPath(args []string, ctx CommandContext) int {
 	fs := flag.NewFlagSet(ctx.AppName+" path", flag.ContinueOnError)
 	fs.SetOutput(ctx.Err)
@@ -16,6 +27,15 @@ func RunPath(args []string, ctx CommandContext) int {
 		_, _ = fmt.Fprintln(ctx.Err, pathUsage(ctx.AppName))
 	}
 
+	var noNewline bool
+	var nul bool
+	var reveal bool
+	var asJSON bool
+	fs.BoolVar(&noNewline, "no-newline", false, "print the path without a trailing newline")
+	fs.BoolVar(&nul, "0", false, "terminate the path with a NUL byte instead of a newline")
+	fs.BoolVar(&reveal, "reveal", false, "open the directory in the file manager instead of printing it")
+	fs.BoolVar(&asJSON, "json", false, "print the thread's paths as JSON")
+
 	if err := fs.Parse(args); err != nil {
 		_, _ = fmt.Fprintln(ctx.Err)
 		_, _ = fmt.Fprintln(ctx.Err, pathUsage(ctx.AppName))
@@ -33,10 +53,19 @@ func RunPath(args []string, ctx CommandContext) int {
 		return 2
 	}
 
+	if reveal && (noNewline || nul) {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: --reveal cannot be combined with --no-newline or -0\n")
+		return 2
+	}
+	if asJSON && (reveal || noNewline || nul) {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: --json cannot be combined with --reveal, --no-newline or -0\n")
+		return 2
+	}
+
 	threadID := threadIDs[0]
 
 	// Get paths and verify threads directory exists
-	paths, err := config.GetPaths(ctx.Path)
+	paths, err := config.GetPaths(ctx.workspace())
 	if err != nil {
 		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
 		return 1
@@ -48,7 +77,7 @@ func RunPath(args []string, ctx CommandContext) int {
 	}
 
 	// Resolve ID (handles both durable IDs and short IDs)
-	st := store.NewFileStore(paths.ThreadsDir)
+	st := newStore(paths)
 	t, err := st.ResolveID(threadID)
 	if err != nil {
 		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
@@ -56,20 +85,70 @@ func RunPath(args []string, ctx CommandContext) int {
 	}
 
 	// Resolve thread path using the durable ID
-	threadPath := store.ThreadPath(paths.ThreadsDir, t.ID)
+	threadPath := st.ThreadDir(t.ID)
+
+	if asJSON {
+		enc := json.NewEncoder(ctx.Out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(threadPaths{
+			ID:             t.ID,
+			ShortID:        t.ShortID,
+			Dir:            threadPath,
+			ThreadJSON:     st.ThreadFile(t.ID),
+			AttachmentsLog: filepath.Join(threadPath, "attachments.jsonl"),
+		}); err != nil {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	if reveal {
+		opener, err := newFileOpener()
+		if err == nil {
+			if err := opener.OpenFile(threadPath); err != nil {
+				_, _ = fmt.Fprintf(ctx.Err, "Error: failed to open directory: %v\n", err)
+				return 1
+			}
+			return 0
+		}
+		// Still give the user something they can cd into
+		_, _ = fmt.Fprintf(ctx.Err, "Warning: cannot reveal the directory (%v); printing its path\n", err)
+	}
 
-	// Print only the path, followed by a newline (no extra text)
-	_, _ = fmt.Fprintf(ctx.Out, "%s\n", threadPath)
+	// Print only the path (no extra text)
+	writePath(ctx.Out, threadPath, noNewline, nul)
 
 	return 0
 }
 
+// writePath writes p followed by its terminator: a newline by default,
+// nothing with noNewline, or a NUL byte with nul (which wins if both are set).
+func writePath(out io.Writer, p string, noNewline, nul bool) {
+	term := "\n"
+	switch {
+	case nul:
+		term = "\x00"
+	case noNewline:
+		term = ""
+	}
+	_, _ = io.WriteString(out, p+term)
+}
+
 func pathUsage(app string) string {
 	return fmt.Sprintf(`Usage:
-  %s path <thread-id>
+  %s path [--no-newline | -0 | --reveal | --json] <thread-id>
 
 Prints the canonical filesystem path for the thread directory.
 Accepts either a durable thread ID or a short ID.
 
+Flags:
+  --no-newline   omit the trailing newline (for "$(...)")
+  -0             terminate with a NUL byte instead (for xargs -0)
+  --reveal       open the directory in the OS file manager instead of
+                 printing it (prints it with a warning where unsupported)
+  --json         print the durable ID, short ID, directory, thread.json
+                 and attachments.jsonl paths as JSON
+
 `, app)
 }
@@ -0,0 +1,191 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sjatkinson/threadkeeper/internal/config"
+)
+
+func TestPathOutput_Terminators(t *testing.T) {
+	setupWorkspace(t)
+
+	ctx, _, errOut := newTestContext()
+	if code := RunAdd([]string{"path", "target"}, ctx); code != 0 {
+		t.Fatalf("RunAdd() exit code = %d, stderr: %s", code, errOut.String())
+	}
+
+	paths, err := config.GetPaths("")
+	if err != nil {
+		t.Fatalf("GetPaths() error = %v", err)
+	}
+	st := newStore(paths)
+	tk, err := st.ResolveID("1")
+	if err != nil {
+		t.Fatalf("ResolveID() error = %v", err)
+	}
+	dir := st.ThreadDir(tk.ID)
+
+	tests := []struct {
+		name string
+		run  func([]string, CommandContext) int
+		args []string
+		want string
+	}{
+		{"path default", RunPath, []string{"1"}, dir + "\n"},
+		{"path --no-newline", RunPath, []string{"--no-newline", "1"}, dir},
+		{"path -0", RunPath, []string{"-0", "1"}, dir + "\x00"},
+		{"path -0 wins over --no-newline", RunPath, []string{"--no-newline", "-0", "1"}, dir + "\x00"},
+		{"show --path-only", RunShow, []string{"--path-only", "1"}, dir + "\n"},
+		{"show --path-only --no-newline", RunShow, []string{"--path-only", "--no-newline", "1"}, dir},
+		{"show --path-only -0", RunShow, []string{"--path-only", "-0", "1"}, dir + "\x00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, out, errOut := newTestContext()
+			if code := tt.run(tt.args, ctx); code != 0 {
+				t.Fatalf("exit code = %d, stderr: %s", code, errOut.String())
+			}
+			if got := out.String(); got != tt.want {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunShow_NoNewlineRequiresPathOnly(t *testing.T) {
+	setupWorkspace(t)
+
+	ctx, out, _ := newTestContext()
+	if code := RunShow([]string{"--no-newline", "1"}, ctx); code != 2 {
+		t.Errorf("RunShow() exit code = %d, want 2", code)
+	}
+	if out.Len() != 0 {
+		t.Errorf("RunShow() wrote %q to stdout, want nothing", out.String())
+	}
+}
+
+// fakeOpener records what it was asked to open.
+type fakeOpener struct{ opened []string }
+
+func (o *fakeOpener) OpenFile(path string) error { o.opened = append(o.opened, path); return nil }
+func (o *fakeOpener) OpenURL(url string) error   { o.opened = append(o.opened, url); return nil }
+
+func TestRunPath_Reveal(t *testing.T) {
+	setupWorkspace(t)
+
+	ctx, _, errOut := newTestContext()
+	if code := RunAdd([]string{"reveal me"}, ctx); code != 0 {
+		t.Fatalf("RunAdd() exit code = %d, stderr: %s", code, errOut.String())
+	}
+	paths, err := config.GetPaths("")
+	if err != nil {
+		t.Fatalf("GetPaths() error = %v", err)
+	}
+	st := newStore(paths)
+	tk, err := st.ResolveID("1")
+	if err != nil {
+		t.Fatalf("ResolveID() error = %v", err)
+	}
+	dir := st.ThreadDir(tk.ID)
+
+	orig := newFileOpener
+	t.Cleanup(func() { newFileOpener = orig })
+
+	fake := &fakeOpener{}
+	newFileOpener = func() (FileOpener, error) { return fake, nil }
+	ctx, out, errOut := newTestContext()
+	if code := RunPath([]string{"--reveal", "1"}, ctx); code != 0 {
+		t.Fatalf("RunPath(--reveal) exit code = %d, stderr: %s", code, errOut.String())
+	}
+	if out.Len() != 0 || len(fake.opened) != 1 || fake.opened[0] != dir {
+		t.Errorf("RunPath(--reveal) printed %q and opened %v, want only %s opened", out.String(), fake.opened, dir)
+	}
+
+	// Unsupported platforms fall back to printing
+	newFileOpener = func() (FileOpener, error) { return nil, errors.New("unsupported platform: plan9") }
+	ctx, out, errOut = newTestContext()
+	if code := RunPath([]string{"--reveal", "1"}, ctx); code != 0 || out.String() != dir+"\n" || !strings.Contains(errOut.String(), "Warning:") {
+		t.Errorf("RunPath(--reveal) unsupported = %d, out %q, stderr %q; want the path and a warning", code, out.String(), errOut.String())
+	}
+
+	ctx, _, _ = newTestContext()
+	if code := RunPath([]string{"--reveal", "-0", "1"}, ctx); code != 2 {
+		t.Errorf("RunPath(--reveal -0) exit code = %d, want 2", code)
+	}
+}
+
+func TestRunPath_JSON(t *testing.T) {
+	setupWorkspace(t)
+
+	ctx, _, errOut := newTestContext()
+	if code := RunAdd([]string{"json paths"}, ctx); code != 0 {
+		t.Fatalf("RunAdd() exit code = %d, stderr: %s", code, errOut.String())
+	}
+	paths, err := config.GetPaths("")
+	if err != nil {
+		t.Fatalf("GetPaths() error = %v", err)
+	}
+	st := newStore(paths)
+	tk, err := st.ResolveID("1")
+	if err != nil {
+		t.Fatalf("ResolveID() error = %v", err)
+	}
+
+	ctx, out, errOut := newTestContext()
+	if code := RunPath([]string{"--json", "1"}, ctx); code != 0 {
+		t.Fatalf("RunPath(--json) exit code = %d, stderr: %s", code, errOut.String())
+	}
+	var got threadPaths
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("RunPath(--json) output %q is not JSON: %v", out.String(), err)
+	}
+	want := threadPaths{
+		ID:             tk.ID,
+		ShortID:        got.ShortID,
+		Dir:            st.ThreadDir(tk.ID),
+		ThreadJSON:     st.ThreadFile(tk.ID),
+		AttachmentsLog: filepath.Join(st.ThreadDir(tk.ID), "attachments.jsonl"),
+	}
+	if got != want || got.ShortID == nil || *got.ShortID != 1 {
+		t.Errorf("RunPath(--json) = %+v, want %+v with short_id 1", got, want)
+	}
+}
+
+func TestRunPath_ShortIDMatchesDurableID(t *testing.T) {
+	setupWorkspace(t)
+
+	ctx, _, errOut := newTestContext()
+	if code := RunAdd([]string{"by either id"}, ctx); code != 0 {
+		t.Fatalf("RunAdd() exit code = %d, stderr: %s", code, errOut.String())
+	}
+	paths, err := config.GetPaths("")
+	if err != nil {
+		t.Fatalf("GetPaths() error = %v", err)
+	}
+	tk, err := newStore(paths).ResolveID("1")
+	if err != nil {
+		t.Fatalf("ResolveID() error = %v", err)
+	}
+
+	var outs []string
+	for _, id := range []string{"1", tk.ID} {
+		ctx, out, errOut := newTestContext()
+		if code := RunPath([]string{id}, ctx); code != 0 {
+			t.Fatalf("RunPath(%s) exit code = %d, stderr: %s", id, code, errOut.String())
+		}
+		outs = append(outs, out.String())
+	}
+	if outs[0] != outs[1] {
+		t.Errorf("path 1 = %q, path %s = %q; want the same directory", outs[0], tk.ID, outs[1])
+	}
+
+	ctx, out, errOut := newTestContext()
+	if code := RunPath([]string{"42"}, ctx); code != 1 || out.Len() != 0 || !strings.Contains(errOut.String(), "Error:") {
+		t.Errorf("RunPath(42) = %d, out %q, stderr %q; want an error and no path", code, out.String(), errOut.String())
+	}
+}
@@ -0,0 +1,136 @@
+package commands
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/sjatkinson/threadkeeper/internal/config"
+	"github.com/sjatkinson/threadkeeper/internal/store"
+)
+
+func RunProject(args []string, ctx CommandContext) int {
+	if len(args) == 0 {
+		_, _ = fmt.Fprintln(ctx.Err, projectUsage(ctx.AppName))
+		return 2
+	}
+
+	switch args[0] {
+	case "rename":
+		return runProjectRename(args[1:], ctx)
+	default:
+		_, _ = fmt.Fprintf(ctx.Err, "Error: invalid project subcommand %q (must be 'rename')\n", args[0])
+		_, _ = fmt.Fprintln(ctx.Err)
+		_, _ = fmt.Fprintln(ctx.Err, projectUsage(ctx.AppName))
+		return 2
+	}
+}
+
+func runProjectRename(args []string, ctx CommandContext) int {
+	fs := flag.NewFlagSet(ctx.AppName+" project rename", flag.ContinueOnError)
+	fs.SetOutput(ctx.Err)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintln(ctx.Err, projectUsage(ctx.AppName))
+	}
+
+	var dryRun bool
+	fs.BoolVar(&dryRun, "dry-run", false, "report how many tasks would change without saving")
+
+	if err := fs.Parse(args); err != nil {
+		_, _ = fmt.Fprintln(ctx.Err)
+		_, _ = fmt.Fprintln(ctx.Err, projectUsage(ctx.AppName))
+		return 2
+	}
+
+	rest := fs.Args()
+	if len(rest) != 2 {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: expected <old> and <new> project names\n")
+		_, _ = fmt.Fprintln(ctx.Err, projectUsage(ctx.AppName))
+		return 2
+	}
+
+	oldProject, newProject := rest[0], strings.TrimSpace(rest[1])
+	if oldProject == "" || newProject == "" {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: project names must not be empty\n")
+		return 2
+	}
+	if oldProject == newProject {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: old and new projects are the same (%q)\n", oldProject)
+		return 2
+	}
+
+	// Get paths and verify threads directory exists
+	paths, err := config.GetPaths(ctx.workspace())
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+
+	if _, err := os.Stat(paths.ThreadsDir); err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: threads directory does not exist at %s. Run '%s init' first.\n", paths.ThreadsDir, ctx.AppName)
+		return 1
+	}
+
+	st := newStore(paths)
+	var changed int
+	if err := st.WithLock(func() error {
+		var err error
+		changed, err = renameProject(st, oldProject, newProject, dryRun, ctx)
+		return err
+	}); err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+
+	if dryRun {
+		_, _ = fmt.Fprintf(ctx.Out, "Would rename project %q to %q on %d tasks\n", oldProject, newProject, changed)
+		return 0
+	}
+	_, _ = fmt.Fprintf(ctx.Out, "Renamed project %q to %q on %d tasks\n", oldProject, newProject, changed)
+	return 0
+}
+
+// renameProject sets Project to newProject on every task whose project is
+// exactly oldProject and saves them, unless dryRun is set. Returns the
+// number of tasks changed (or that would change). Callers must hold the
+// workspace lock.
+func renameProject(st *store.FileStore, oldProject, newProject string, dryRun bool, ctx CommandContext) (int, error) {
+	tasks, err := st.LoadAll()
+	if err != nil {
+		return 0, err
+	}
+
+	now := ctx.clock().Now().UTC()
+	changed := 0
+	for _, t := range tasks {
+		if t.Project != oldProject {
+			continue
+		}
+		changed++
+		if dryRun {
+			continue
+		}
+
+		t.Project = newProject
+		t.UpdatedAt = now
+		if err := st.Save(t); err != nil {
+			return changed - 1, fmt.Errorf("failed to save task %s: %w", t.ID, err)
+		}
+	}
+	return changed, nil
+}
+
+func projectUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s project rename [--dry-run] <old> <new>
+
+Sets the project to <new> on every task whose project is <old>. Projects
+are matched by exact, case-sensitive string comparison (unlike tags, they
+are not lowercased), so "Work" does not match "work".
+
+Flags:
+  --dry-run      report how many tasks would change without saving
+
+`, app)
+}
@@ -0,0 +1,63 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sjatkinson/threadkeeper/internal/config"
+)
+
+func TestRunProjectRename(t *testing.T) {
+	setupWorkspace(t)
+
+	for _, args := range [][]string{
+		{"--project", "work", "one"},
+		{"--project", "work", "two"},
+		{"--project", "Work", "three"},
+		{"four"},
+	} {
+		ctx, _, errOut := newTestContext()
+		if code := RunAdd(args, ctx); code != 0 {
+			t.Fatalf("RunAdd() exit code = %d, stderr: %s", code, errOut.String())
+		}
+	}
+
+	projectsByTitle := func() map[string]string {
+		paths, err := config.GetPaths("")
+		if err != nil {
+			t.Fatalf("GetPaths() error = %v", err)
+		}
+		tasks, err := newStore(paths).LoadAll()
+		if err != nil {
+			t.Fatalf("LoadAll() error = %v", err)
+		}
+		m := make(map[string]string)
+		for _, tk := range tasks {
+			m[tk.Title] = tk.Project
+		}
+		return m
+	}
+
+	ctx, out, errOut := newTestContext()
+	if code := RunProject([]string{"rename", "--dry-run", "work", "job"}, ctx); code != 0 {
+		t.Fatalf("RunProject() exit code = %d, stderr: %s", code, errOut.String())
+	}
+	if !strings.Contains(out.String(), "on 2 tasks") {
+		t.Errorf("dry run output = %q, want it to report 2 tasks", out.String())
+	}
+	if got := projectsByTitle(); got["one"] != "work" {
+		t.Errorf("dry run changed project on %q to %q", "one", got["one"])
+	}
+
+	ctx, _, errOut = newTestContext()
+	if code := RunProject([]string{"rename", "work", "job"}, ctx); code != 0 {
+		t.Fatalf("RunProject() exit code = %d, stderr: %s", code, errOut.String())
+	}
+	got := projectsByTitle()
+	want := map[string]string{"one": "job", "two": "job", "three": "Work", "four": ""}
+	for title, project := range want {
+		if got[title] != project {
+			t.Errorf("project on %q = %q, want %q", title, got[title], project)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package commands
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/sjatkinson/threadkeeper/internal/config"
+	"github.com/sjatkinson/threadkeeper/internal/task"
+)
+
+// projectCount is the number of tasks in a project.
+type projectCount struct {
+	Project string `json:"project"`
+	Count   int    `json:"count"`
+}
+
+func RunProjects(args []string, ctx CommandContext) int {
+	fs := flag.NewFlagSet(ctx.AppName+" projects", flag.ContinueOnError)
+	fs.SetOutput(ctx.Err)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintln(ctx.Err, projectsUsage(ctx.AppName))
+	}
+
+	var (
+		openOnly bool
+		asJSON   bool
+	)
+	fs.BoolVar(&openOnly, "open", false, "only count open tasks")
+	fs.BoolVar(&asJSON, "json", false, "print JSON instead of a table")
+
+	if err := fs.Parse(args); err != nil {
+		_, _ = fmt.Fprintln(ctx.Err)
+		_, _ = fmt.Fprintln(ctx.Err, projectsUsage(ctx.AppName))
+		return 2
+	}
+
+	if len(fs.Args()) != 0 {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: unexpected arguments\n")
+		_, _ = fmt.Fprintln(ctx.Err, projectsUsage(ctx.AppName))
+		return 2
+	}
+
+	// Get paths and verify threads directory exists
+	paths, err := config.GetPaths(ctx.workspace())
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+
+	if _, err := os.Stat(paths.ThreadsDir); err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: threads directory does not exist at %s. Run '%s init' first.\n", paths.ThreadsDir, ctx.AppName)
+		return 1
+	}
+
+	st := newStore(paths)
+	tasks, err := st.LoadAll()
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+
+	counts := countProjects(filterTasks(tasks, taskFilter{All: !openOnly}))
+
+	if asJSON {
+		enc := json.NewEncoder(ctx.Out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(counts); err != nil {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	if len(counts) == 0 {
+		_, _ = fmt.Fprintln(ctx.Out, "No projects found.")
+		return 0
+	}
+
+	displayProjectCounts(ctx.Out, counts)
+	return 0
+}
+
+// countProjects counts tasks per distinct non-empty project, sorted by
+// project name. Projects are compared by exact string match.
+func countProjects(tasks []*task.Task) []projectCount {
+	byProject := make(map[string]int)
+	for _, t := range tasks {
+		if t.Project == "" {
+			continue
+		}
+		byProject[t.Project]++
+	}
+
+	counts := make([]projectCount, 0, len(byProject))
+	for project, n := range byProject {
+		counts = append(counts, projectCount{Project: project, Count: n})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		return counts[i].Project < counts[j].Project
+	})
+	return counts
+}
+
+func displayProjectCounts(out io.Writer, counts []projectCount) {
+	width := 0
+	for _, c := range counts {
+		width = max(width, len(c.Project))
+	}
+	for _, c := range counts {
+		_, _ = fmt.Fprintf(out, "%-*s  %4d\n", width, c.Project, c.Count)
+	}
+}
+
+func projectsUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s projects [--open] [--json]
+
+Lists every non-empty project with the number of tasks in it, sorted by
+name. Project names are case-sensitive: "Work" and "work" are distinct.
+
+Flags:
+  --open         only count open tasks (default: all tasks)
+  --json         print [{"project": ..., "count": ...}] as JSON
+
+`, app)
+}
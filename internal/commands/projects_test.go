@@ -0,0 +1,23 @@
+package commands
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sjatkinson/threadkeeper/internal/task"
+)
+
+func TestCountProjects(t *testing.T) {
+	tasks := []*task.Task{
+		{ID: "a", Project: "work"},
+		{ID: "b", Project: "Work"},
+		{ID: "c", Project: "work"},
+		{ID: "d", Project: ""},
+	}
+
+	got := countProjects(tasks)
+	want := []projectCount{{"Work", 1}, {"work", 2}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("countProjects() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,67 @@
+package commands
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/sjatkinson/threadkeeper/internal/config"
+)
+
+func RunRebucket(args []string, ctx CommandContext) int {
+	fs := flag.NewFlagSet(ctx.AppName+" rebucket", flag.ContinueOnError)
+	fs.SetOutput(ctx.Err)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintln(ctx.Err, rebucketUsage(ctx.AppName))
+	}
+
+	if err := fs.Parse(args); err != nil {
+		_, _ = fmt.Fprintln(ctx.Err)
+		_, _ = fmt.Fprintln(ctx.Err, rebucketUsage(ctx.AppName))
+		return 2
+	}
+
+	if len(fs.Args()) != 0 {
+		_, _ = fmt.Fprintln(ctx.Err, rebucketUsage(ctx.AppName))
+		return 2
+	}
+
+	// Get paths and verify threads directory exists
+	paths, err := config.GetPaths(ctx.workspace())
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+
+	if _, err := os.Stat(paths.ThreadsDir); err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: threads directory does not exist at %s. Run '%s init' first.\n", paths.ThreadsDir, ctx.AppName)
+		return 1
+	}
+
+	// Move threads into buckets matching the configured width
+	st := newStore(paths)
+	moved, err := st.Rebucket()
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v (moved %d threads before failing)\n", err, moved)
+		return 1
+	}
+
+	if moved == 0 {
+		_, _ = fmt.Fprintf(ctx.Out, "All threads already use bucket width %d.\n", paths.BucketWidth)
+		return 0
+	}
+
+	_, _ = fmt.Fprintf(ctx.Out, "Moved %d threads into bucket width %d.\n", moved, paths.BucketWidth)
+	return 0
+}
+
+func rebucketUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s rebucket
+
+Moves thread directories into buckets matching the bucket_width config
+(default 2). Run this after changing bucket_width on an existing workspace;
+until then threads in old buckets cannot be found by ID.
+
+`, app)
+}
@@ -0,0 +1,146 @@
+package commands
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/sjatkinson/threadkeeper/internal/config"
+	"github.com/sjatkinson/threadkeeper/internal/task"
+)
+
+// defaultRecentDays is how far back recent looks without --days.
+const defaultRecentDays = 7
+
+func RunRecent(args []string, ctx CommandContext) int {
+	fs := flag.NewFlagSet(ctx.AppName+" recent", flag.ContinueOnError)
+	fs.SetOutput(ctx.Err)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintln(ctx.Err, recentUsage(ctx.AppName))
+	}
+
+	var (
+		days    int
+		status  string
+		project string
+	)
+	fs.IntVar(&days, "days", defaultRecentDays, "how many days back to look, counting today")
+	fs.StringVar(&status, "status", "", "only tasks with this status (open|done|archived)")
+	fs.StringVar(&project, "project", "", "only tasks in this project")
+	fs.StringVar(&project, "p", "", "only tasks in this project (shorthand)")
+
+	if err := fs.Parse(args); err != nil {
+		_, _ = fmt.Fprintln(ctx.Err)
+		_, _ = fmt.Fprintln(ctx.Err, recentUsage(ctx.AppName))
+		return 2
+	}
+
+	if len(fs.Args()) != 0 {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: unexpected arguments\n")
+		_, _ = fmt.Fprintln(ctx.Err, recentUsage(ctx.AppName))
+		return 2
+	}
+
+	if days < 1 {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: --days must be at least 1\n")
+		return 2
+	}
+	if status != "" && !task.IsValidStatus(task.Status(status)) {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: invalid --status %q (must be open, done, or archived)\n", status)
+		return 2
+	}
+
+	// Get paths and verify threads directory exists
+	paths, err := config.GetPaths(ctx.workspace())
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+
+	if _, err := os.Stat(paths.ThreadsDir); err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: threads directory does not exist at %s. Run '%s init' first.\n", paths.ThreadsDir, ctx.AppName)
+		return 1
+	}
+
+	tz, err := config.LoadTimezone()
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+
+	st := newStore(paths)
+	tasks, err := loadAllTasks(st, ctx)
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+
+	// Recent covers every status unless --status narrows it
+	filtered := filterTasks(tasks, taskFilter{All: true, Status: status, Project: project})
+	recent := recentTasks(filtered, recentCutoff(ctx.clock().Now(), days, tz))
+	if len(recent) == 0 {
+		_, _ = fmt.Fprintln(ctx.Out, "No tasks found.")
+		return 0
+	}
+
+	dateLayout, err := config.LoadDisplayDateFormat()
+	if err != nil {
+		dateLayout = config.DisplayLayoutISO // Default on error
+	}
+
+	displayTasks(ctx.Out, recent, displayOptions{DateLayout: dateLayout})
+	return 0
+}
+
+// recentCutoff is midnight in tz at the start of the window of days days
+// ending today: with days = 1 the window is just today.
+func recentCutoff(now time.Time, days int, tz *time.Location) time.Time {
+	local := now.In(tz)
+	return time.Date(local.Year(), local.Month(), local.Day()-(days-1), 0, 0, 0, 0, tz)
+}
+
+// lastTouched is when t was last created or updated.
+func lastTouched(t *task.Task) time.Time {
+	if t.UpdatedAt.After(t.CreatedAt) {
+		return t.UpdatedAt
+	}
+	return t.CreatedAt
+}
+
+// recentTasks returns the tasks touched at or after cutoff, most recently
+// touched first.
+func recentTasks(tasks []*task.Task, cutoff time.Time) []*task.Task {
+	var recent []*task.Task
+	for _, t := range tasks {
+		if !lastTouched(t).Before(cutoff) {
+			recent = append(recent, t)
+		}
+	}
+	sort.SliceStable(recent, func(i, j int) bool {
+		a, b := lastTouched(recent[i]), lastTouched(recent[j])
+		if !a.Equal(b) {
+			return a.After(b)
+		}
+		return recent[i].ID < recent[j].ID
+	})
+	return recent
+}
+
+func recentUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s recent [--days <n>] [--status <status>] [-p <project>]
+
+Lists tasks created or updated in the last <n> days (default %d, counting
+today), most recently touched first. Days start at midnight in the
+timezone config key. Covers tasks of every status unless --status is
+given.
+
+Flags:
+  --days <n>                    days to look back, counting today
+  --status <open|done|archived> only tasks with this status
+  -p, --project <name>          only tasks in this project
+
+`, app, defaultRecentDays)
+}
@@ -0,0 +1,68 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sjatkinson/threadkeeper/internal/date"
+)
+
+func TestRunRecent(t *testing.T) {
+	setupWorkspace(t)
+	now := testClock().FixedTime
+	add := func(daysAgo int, args ...string) {
+		t.Helper()
+		ctx, _, errOut := newTestContext()
+		ctx.Clock = date.FixedClock{FixedTime: now.AddDate(0, 0, -daysAgo)}
+		if code := RunAdd(args, ctx); code != 0 {
+			t.Fatalf("RunAdd() exit code = %d, stderr: %s", code, errOut.String())
+		}
+	}
+	add(10, "ancient")
+	add(10, "revived")
+	add(3, "-p", "work", "midweek")
+	add(0, "fresh")
+
+	// Touching an old task brings it back into view
+	ctx, _, _ := newTestContext()
+	ctx.Clock = date.FixedClock{FixedTime: now.Add(-time.Minute)}
+	if code := RunDone([]string{"2"}, ctx); code != 0 {
+		t.Fatalf("RunDone() exit code = %d", code)
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{"default week, newest first", nil, []string{"fresh", "revived", "midweek"}},
+		{"today only", []string{"--days", "1"}, []string{"fresh", "revived"}},
+		{"status", []string{"--status", "done"}, []string{"revived"}},
+		{"project", []string{"-p", "work"}, []string{"midweek"}},
+		{"long window", []string{"--days", "30", "--status", "open"}, []string{"fresh", "midweek", "ancient"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, out, errOut := newTestContext()
+			ctx.Clock = date.FixedClock{FixedTime: now}
+			if code := RunRecent(tt.args, ctx); code != 0 {
+				t.Fatalf("RunRecent() exit code = %d, stderr: %s", code, errOut.String())
+			}
+			lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+			if len(lines) != len(tt.want) {
+				t.Fatalf("got %d lines, want %d:\n%s", len(lines), len(tt.want), out.String())
+			}
+			for i, title := range tt.want {
+				if !strings.Contains(lines[i], title) {
+					t.Errorf("line %d = %q, want %q", i, lines[i], title)
+				}
+			}
+		})
+	}
+
+	ctx, _, _ = newTestContext()
+	if code := RunRecent([]string{"--days", "0"}, ctx); code != 2 {
+		t.Errorf("--days 0 exit code = %d, want 2", code)
+	}
+}
@@ -3,7 +3,9 @@ package commands
 import (
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"strconv"
 
 	"github.com/sjatkinson/threadkeeper/internal/config"
 	"github.com/sjatkinson/threadkeeper/internal/store"
@@ -17,6 +19,18 @@ func RunReindex(args []string, ctx CommandContext) int {
 		_, _ = fmt.Fprintln(ctx.Err, reindexUsage(ctx.AppName))
 	}
 
+	var (
+		yes          bool
+		dryRun       bool
+		stable       bool
+		rebuildCache bool
+	)
+	fs.BoolVar(&yes, "yes", false, "renumber without asking")
+	fs.BoolVar(&yes, "y", false, "renumber without asking (shorthand)")
+	fs.BoolVar(&dryRun, "dry-run", false, "show what would be renumbered without saving")
+	fs.BoolVar(&stable, "stable", false, "keep existing short IDs and only fill in missing ones")
+	fs.BoolVar(&rebuildCache, "rebuild-cache", false, "rebuild the task index instead of renumbering")
+
 	if err := fs.Parse(args); err != nil {
 		_, _ = fmt.Fprintln(ctx.Err)
 		_, _ = fmt.Fprintln(ctx.Err, reindexUsage(ctx.AppName))
@@ -28,8 +42,13 @@ func RunReindex(args []string, ctx CommandContext) int {
 		return 2
 	}
 
+	if rebuildCache && (yes || dryRun || stable) {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: --rebuild-cache cannot be combined with --yes, --dry-run or --stable\n")
+		return 2
+	}
+
 	// Get paths and verify tasks directory exists
-	paths, err := config.GetPaths(ctx.Path)
+	paths, err := config.GetPaths(ctx.workspace())
 	if err != nil {
 		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
 		return 1
@@ -40,9 +59,61 @@ func RunReindex(args []string, ctx CommandContext) int {
 		return 1
 	}
 
+	st := newStore(paths)
+
+	if rebuildCache {
+		n, err := st.RebuildIndex()
+		if err != nil {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: failed to rebuild index: %v\n", err)
+			return 1
+		}
+		ctx.Success("Rebuilt index for %d tasks\n", n)
+		return 0
+	}
+
+	// Preview the renumbering to report it or ask before applying
+	if dryRun || (!yes && isTerminal(ctx.stdin())) {
+		tasks, err := st.LoadAll()
+		if err != nil {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: failed to load tasks: %v\n", err)
+			return 1
+		}
+		changes := planReindex(tasks, stable)
+
+		if dryRun {
+			displayReindexPlan(ctx.Out, changes)
+			return 0
+		}
+
+		if len(changes) > 0 {
+			prompt := fmt.Sprintf("Renumber %d tasks?", len(changes))
+			if !confirm(ctx, prompt) {
+				_, _ = fmt.Fprintln(ctx.Err, "Aborted; no tasks were renumbered.")
+				return 1
+			}
+		}
+	}
+
+	// Hold the workspace lock for the whole load-renumber-save cycle so
+	// no other tk process writes in between
+	code := 0
+	if err := st.WithLock(func() error {
+		code = reindexTasks(st, ctx, stable)
+		return nil
+	}); err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+
+	return code
+}
+
+// reindexTasks renumbers active tasks 1..N, or with stable fills in
+// missing short IDs around the existing ones; callers must hold the
+// workspace lock.
+func reindexTasks(st *store.FileStore, ctx CommandContext, stable bool) int {
 	// Load all tasks
-	st := store.NewFileStore(paths.ThreadsDir)
-	tasks, err := st.LoadAll()
+	tasks, err := loadAllTasks(st, ctx)
 	if err != nil {
 		_, _ = fmt.Fprintf(ctx.Err, "Error: failed to load tasks: %v\n", err)
 		return 1
@@ -53,50 +124,196 @@ func RunReindex(args []string, ctx CommandContext) int {
 		return 0
 	}
 
-	// Filter active tasks (already sorted by created_at then id from LoadAll)
-	var activeTasks []*task.Task
-	for _, t := range tasks {
-		if t.Status == task.StatusOpen {
-			activeTasks = append(activeTasks, t)
+	// Either mode resolves collisions: only one task keeps a shared number
+	conflicts := store.FindShortIDConflicts(tasks)
+
+	count, changed, err := applyReindex(st, tasks, stable, nil)
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+
+	for _, c := range conflicts {
+		_, _ = fmt.Fprintf(ctx.Out, "Resolved duplicate short_id %d shared by %d tasks\n", c.ShortID, len(c.TaskIDs))
+	}
+
+	switch {
+	case count == 0:
+		_, _ = fmt.Fprintf(ctx.Out, "No active tasks to reindex.\n")
+	case stable:
+		_, _ = fmt.Fprintf(ctx.Out, "Reindexed %d active tasks, changing %d short IDs\n", count, changed)
+	default:
+		_, _ = fmt.Fprintf(ctx.Out, "Reindexed %d active tasks with short IDs 1..%d\n", count, count)
+	}
+
+	return 0
+}
+
+// applyReindex gives tasks (in LoadAll order) the short IDs from
+// reindexAssignments and saves the ones that change, calling before on each
+// first if it is not nil. It returns the number of open tasks and how many
+// short IDs changed. Callers must hold the workspace lock.
+func applyReindex(st *store.FileStore, tasks []*task.Task, stable bool, before func(*task.Task)) (count, changed int, err error) {
+	for i, next := range reindexAssignments(tasks, stable) {
+		t := tasks[i]
+		if next != nil {
+			count++
+		}
+		if sameShortID(t.ShortID, next) {
+			continue
+		}
+		if before != nil {
+			before(t)
+		}
+		t.ShortID = next
+		if err := st.Save(t); err != nil {
+			return count, changed, fmt.Errorf("failed to save task %s: %w", t.ID, err)
 		}
+		changed++
 	}
+	return count, changed, nil
+}
 
-	// Assign 1..N short_ids to active tasks
-	sid := 1
-	for _, t := range activeTasks {
-		sidVal := sid
-		t.ShortID = &sidVal
-		sid++
+// autoReindex renumbers open tasks 1..N when the auto_reindex config key is
+// set, for commands that close or reopen tasks. Renumbered tasks are added
+// to rec, unless it is nil, so undoing the command restores their short IDs
+// too. Failures are reported as warnings: the command has already succeeded.
+func autoReindex(st *store.FileStore, ctx CommandContext, rec *opRecorder) {
+	enabled, err := config.LoadAutoReindex()
+	if err != nil || !enabled {
+		return
 	}
 
-	// Remove short_id from non-active tasks
-	for _, t := range tasks {
-		if t.Status != task.StatusOpen {
-			t.ShortID = nil
+	var changed int
+	if err := st.WithLock(func() error {
+		tasks, err := loadAllTasks(st, ctx)
+		if err != nil {
+			return err
+		}
+		var before func(*task.Task)
+		if rec != nil {
+			before = func(t *task.Task) { rec.add(t.ID, snapshotTask(t)) }
 		}
+		_, changed, err = applyReindex(st, tasks, false, before)
+		return err
+	}); err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Warning: auto-reindex failed: %v\n", err)
+		return
+	}
+	if changed > 0 {
+		ctx.Success("Renumbered %d open tasks (auto_reindex)\n", changed)
 	}
+}
 
-	// Save all tasks back
-	for _, t := range tasks {
-		if err := st.Save(t); err != nil {
-			_, _ = fmt.Fprintf(ctx.Err, "Error: failed to save task %s: %v\n", t.ID, err)
-			return 1
+// reindexAssignments returns the short_id each of tasks (in LoadAll order)
+// should end up with; closed tasks get nil. By default open tasks are
+// numbered 1..N in order. With stable, an open task keeps its short_id
+// unless an earlier open task already holds it, and the tasks left over
+// take the lowest free numbers in order, so only those tasks change.
+func reindexAssignments(tasks []*task.Task, stable bool) []*int {
+	assigned := make([]*int, len(tasks))
+	if !stable {
+		sid := 0
+		for i, t := range tasks {
+			if t.Status == task.StatusOpen {
+				sid++
+				n := sid
+				assigned[i] = &n
+			}
 		}
+		return assigned
 	}
 
-	count := len(activeTasks)
-	if count > 0 {
-		_, _ = fmt.Fprintf(ctx.Out, "Reindexed %d active tasks with short IDs 1..%d\n", count, count)
-	} else {
-		_, _ = fmt.Fprintf(ctx.Out, "No active tasks to reindex.\n")
+	taken := make(map[int]bool)
+	for i, t := range tasks {
+		if t.Status == task.StatusOpen && t.ShortID != nil && *t.ShortID > 0 && !taken[*t.ShortID] {
+			n := *t.ShortID
+			assigned[i] = &n
+			taken[n] = true
+		}
 	}
+	next := 1
+	for i, t := range tasks {
+		if t.Status != task.StatusOpen || assigned[i] != nil {
+			continue
+		}
+		for taken[next] {
+			next++
+		}
+		n := next
+		assigned[i] = &n
+		taken[n] = true
+	}
+	return assigned
+}
 
-	return 0
+// reindexChange is a task whose short_id reindex would change.
+type reindexChange struct {
+	Task *task.Task
+	Old  *int // nil if the task has no short_id yet
+	New  *int // nil if the short_id would be removed
+}
+
+// planReindex returns the short_id changes reindex would make, without
+// modifying tasks. tasks must be in LoadAll order.
+func planReindex(tasks []*task.Task, stable bool) []reindexChange {
+	var changes []reindexChange
+	for i, next := range reindexAssignments(tasks, stable) {
+		if t := tasks[i]; !sameShortID(t.ShortID, next) {
+			changes = append(changes, reindexChange{Task: t, Old: t.ShortID, New: next})
+		}
+	}
+	return changes
+}
+
+func sameShortID(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// displayReindexPlan prints the changes planned by a dry run.
+func displayReindexPlan(out io.Writer, changes []reindexChange) {
+	if len(changes) == 0 {
+		_, _ = fmt.Fprintln(out, "Short IDs are already in order; nothing to renumber.")
+		return
+	}
+	_, _ = fmt.Fprintf(out, "Would renumber %d tasks:\n", len(changes))
+	for _, c := range changes {
+		_, _ = fmt.Fprintf(out, "  %4s -> %-4s  %s\n", formatShortID(c.Old), formatShortID(c.New), c.Task.Title)
+	}
+}
+
+func formatShortID(id *int) string {
+	if id == nil {
+		return "-"
+	}
+	return strconv.Itoa(*id)
 }
 
 func reindexUsage(app string) string {
 	return fmt.Sprintf(`Usage:
-  %s reindex
+  %s reindex [--yes] [--dry-run] [--stable]
+  %s reindex --rebuild-cache
+
+Reassigns short IDs 1..N to open tasks. On a terminal, asks for
+confirmation first when any short ID would change.
+
+By default every open task is renumbered in creation order, so the
+numbers are compact but one task added out of order can shift all of
+them. --stable keeps each task's short ID unless another task holds the
+same number, and gives the lowest free numbers to tasks without one:
+fewer IDs change, but gaps left by closed tasks stay.
+
+Set auto_reindex = true in config.toml to renumber open tasks 1..N
+automatically after done, archive and reopen.
+
+Flags:
+  -y, --yes          renumber without asking
+  --dry-run          show what would be renumbered without saving
+  --stable           keep existing short IDs and only fill in missing ones
+  --rebuild-cache    rebuild the task index (.index.json) instead of renumbering
 
-`, app)
+`, app, app)
 }
@@ -0,0 +1,257 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/sjatkinson/threadkeeper/internal/config"
+	"github.com/sjatkinson/threadkeeper/internal/task"
+)
+
+// openShortIDs returns the sorted short_ids of open tasks in the workspace.
+func openShortIDs(t *testing.T) []int {
+	t.Helper()
+	paths, err := config.GetPaths("")
+	if err != nil {
+		t.Fatalf("GetPaths() error = %v", err)
+	}
+	tasks, err := newStore(paths).LoadAll()
+	if err != nil {
+		t.Fatalf("LoadAll() error = %v", err)
+	}
+	var ids []int
+	for _, tk := range tasks {
+		if tk.Status == task.StatusOpen && tk.ShortID != nil {
+			ids = append(ids, *tk.ShortID)
+		}
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+func TestRunReindex_Confirm(t *testing.T) {
+	orig := isTerminal
+	isTerminal = func(io.Reader) bool { return true }
+	t.Cleanup(func() { isTerminal = orig })
+
+	tests := []struct {
+		name       string
+		args       []string
+		input      string
+		wantCode   int
+		wantIDs    []int
+		wantPrompt bool
+	}{
+		{"confirm yes", []string{}, "y\n", 0, []int{1, 2}, true},
+		{"confirm no", []string{}, "n\n", 1, []int{2, 3}, true},
+		{"eof aborts", []string{}, "", 1, []int{2, 3}, true},
+		{"--yes skips prompt", []string{"--yes"}, "", 0, []int{1, 2}, false},
+		{"--dry-run saves nothing", []string{"--dry-run"}, "", 0, []int{2, 3}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupWorkspace(t)
+			for _, title := range []string{"first", "second", "third"} {
+				ctx, _, errOut := newTestContext()
+				if code := RunAdd([]string{title}, ctx); code != 0 {
+					t.Fatalf("RunAdd() exit code = %d, stderr: %s", code, errOut.String())
+				}
+			}
+			ctx, _, errOut := newTestContext()
+			if code := RunDone([]string{"1"}, ctx); code != 0 {
+				t.Fatalf("RunDone() exit code = %d, stderr: %s", code, errOut.String())
+			}
+
+			ctx, _, errOut = newTestContext()
+			ctx.Stdin = strings.NewReader(tt.input)
+			if code := RunReindex(tt.args, ctx); code != tt.wantCode {
+				t.Errorf("RunReindex() exit code = %d, want %d (stderr: %s)", code, tt.wantCode, errOut.String())
+			}
+
+			// The exact count depends on LoadAll order, which is by ID for
+			// tasks created in the same second
+			gotPrompt := strings.Contains(errOut.String(), "[y/N]")
+			if gotPrompt != tt.wantPrompt {
+				t.Errorf("prompt shown = %v, want %v (stderr: %q)", gotPrompt, tt.wantPrompt, errOut.String())
+			}
+
+			got := openShortIDs(t)
+			if len(got) != len(tt.wantIDs) || got[0] != tt.wantIDs[0] || got[1] != tt.wantIDs[1] {
+				t.Errorf("open short IDs = %v, want %v", got, tt.wantIDs)
+			}
+		})
+	}
+}
+
+func TestRunReindex_ResolvesShortIDCollisions(t *testing.T) {
+	setupWorkspace(t)
+	for _, title := range []string{"one", "two", "three"} {
+		ctx, _, errOut := newTestContext()
+		if code := RunAdd([]string{title}, ctx); code != 0 {
+			t.Fatalf("RunAdd() exit code = %d, stderr: %s", code, errOut.String())
+		}
+	}
+
+	// Simulate a crash that left two open tasks with the same short_id
+	paths, err := config.GetPaths("")
+	if err != nil {
+		t.Fatalf("GetPaths() error = %v", err)
+	}
+	st := newStore(paths)
+	third, err := st.ResolveID("3")
+	if err != nil {
+		t.Fatalf("ResolveID() error = %v", err)
+	}
+	one := 1
+	third.ShortID = &one
+	if err := st.Save(third); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	conflicts, err := st.CheckShortIDs()
+	if err != nil {
+		t.Fatalf("CheckShortIDs() error = %v", err)
+	}
+	if len(conflicts) != 1 || conflicts[0].ShortID != 1 || len(conflicts[0].TaskIDs) != 2 {
+		t.Fatalf("CheckShortIDs() = %v, want one conflict on short_id 1", conflicts)
+	}
+	if _, err := st.ResolveID("1"); err == nil {
+		t.Errorf("ResolveID(1) with a collision succeeded, want ambiguity error")
+	}
+
+	ctx, out, errOut := newTestContext()
+	ctx.Stdin = strings.NewReader("")
+	if code := RunReindex([]string{"--yes"}, ctx); code != 0 {
+		t.Fatalf("RunReindex() exit code = %d, stderr: %s", code, errOut.String())
+	}
+	if !strings.Contains(out.String(), "Resolved duplicate short_id 1") {
+		t.Errorf("stdout = %q, want the collision reported", out.String())
+	}
+	if got := openShortIDs(t); !reflect.DeepEqual(got, []int{1, 2, 3}) {
+		t.Errorf("short_ids after reindex = %v, want [1 2 3]", got)
+	}
+	if conflicts, _ := st.CheckShortIDs(); len(conflicts) != 0 {
+		t.Errorf("CheckShortIDs() after reindex = %v, want none", conflicts)
+	}
+}
+
+func TestPlanReindex_DryRunOutput(t *testing.T) {
+	sid := func(n int) *int { return &n }
+	tasks := []*task.Task{
+		{ID: "a", Title: "kept", Status: task.StatusOpen, ShortID: sid(1)},
+		{ID: "b", Title: "stale done", Status: task.StatusDone, ShortID: sid(2)},
+		{ID: "c", Title: "moves up", Status: task.StatusOpen, ShortID: sid(5)},
+		{ID: "d", Title: "new number", Status: task.StatusOpen},
+	}
+
+	var out strings.Builder
+	displayReindexPlan(&out, planReindex(tasks, false))
+	want := "Would renumber 3 tasks:\n" +
+		"     2 -> -     stale done\n" +
+		"     5 -> 2     moves up\n" +
+		"     - -> 3     new number\n"
+	if out.String() != want {
+		t.Errorf("dry-run output:\n%s\nwant:\n%s", out.String(), want)
+	}
+	for _, tk := range tasks {
+		if tk.ID == "d" && tk.ShortID != nil {
+			t.Errorf("planReindex modified task %s", tk.ID)
+		}
+	}
+}
+
+func TestPlanReindex_Stable(t *testing.T) {
+	sid := func(n int) *int { return &n }
+	tasks := []*task.Task{
+		{ID: "a", Title: "keeps 4", Status: task.StatusOpen, ShortID: sid(4)},
+		{ID: "b", Title: "stale done", Status: task.StatusDone, ShortID: sid(1)},
+		{ID: "c", Title: "keeps 2", Status: task.StatusOpen, ShortID: sid(2)},
+		{ID: "d", Title: "collides with a", Status: task.StatusOpen, ShortID: sid(4)},
+		{ID: "e", Title: "unnumbered", Status: task.StatusOpen},
+	}
+
+	var out strings.Builder
+	displayReindexPlan(&out, planReindex(tasks, true))
+	want := "Would renumber 3 tasks:\n" +
+		"     1 -> -     stale done\n" +
+		"     4 -> 1     collides with a\n" +
+		"     - -> 3     unnumbered\n"
+	if out.String() != want {
+		t.Errorf("stable dry-run output:\n%s\nwant:\n%s", out.String(), want)
+	}
+}
+
+func TestRunReindex_Stable(t *testing.T) {
+	setupWorkspace(t)
+	for _, title := range []string{"first", "second", "third"} {
+		ctx, _, errOut := newTestContext()
+		if code := RunAdd([]string{title}, ctx); code != 0 {
+			t.Fatalf("RunAdd() exit code = %d, stderr: %s", code, errOut.String())
+		}
+	}
+	ctx, _, errOut := newTestContext()
+	if code := RunDone([]string{"2"}, ctx); code != 0 {
+		t.Fatalf("RunDone() exit code = %d, stderr: %s", code, errOut.String())
+	}
+
+	ctx, out, errOut := newTestContext()
+	ctx.Stdin = strings.NewReader("")
+	if code := RunReindex([]string{"--yes", "--stable"}, ctx); code != 0 {
+		t.Fatalf("RunReindex() exit code = %d, stderr: %s", code, errOut.String())
+	}
+	if got := openShortIDs(t); !reflect.DeepEqual(got, []int{1, 3}) {
+		t.Errorf("short_ids after stable reindex = %v, want [1 3]", got)
+	}
+	if !strings.Contains(out.String(), "changing 0 short IDs") {
+		t.Errorf("stdout = %q, want no short IDs changed", out.String())
+	}
+
+	ctx, _, errOut = newTestContext()
+	if code := RunReindex([]string{"--stable", "--rebuild-cache"}, ctx); code != 2 {
+		t.Errorf("RunReindex(--stable --rebuild-cache) exit code = %d, want 2 (stderr: %s)", code, errOut.String())
+	}
+}
+
+func TestAutoReindex(t *testing.T) {
+	for _, enabled := range []bool{false, true} {
+		t.Run(fmt.Sprintf("auto_reindex=%v", enabled), func(t *testing.T) {
+			ws := setupWorkspace(t)
+			writeConfig(t, ws, fmt.Sprintf("auto_reindex = %v\n", enabled))
+			for _, title := range []string{"first", "second", "third"} {
+				ctx, _, errOut := newTestContext()
+				if code := RunAdd([]string{title}, ctx); code != 0 {
+					t.Fatalf("RunAdd() exit code = %d, stderr: %s", code, errOut.String())
+				}
+			}
+
+			ctx, _, errOut := newTestContext()
+			if code := RunDone([]string{"1"}, ctx); code != 0 {
+				t.Fatalf("RunDone() exit code = %d, stderr: %s", code, errOut.String())
+			}
+			want := []int{2, 3}
+			if enabled {
+				want = []int{1, 2}
+			}
+			if got := openShortIDs(t); !reflect.DeepEqual(got, want) {
+				t.Errorf("short_ids after done = %v, want %v", got, want)
+			}
+			if !enabled {
+				return
+			}
+
+			// Undoing the done also restores the renumbered short IDs
+			ctx, _, errOut = newTestContext()
+			if code := RunUndo([]string{}, ctx); code != 0 {
+				t.Fatalf("RunUndo() exit code = %d, stderr: %s", code, errOut.String())
+			}
+			if got := openShortIDs(t); !reflect.DeepEqual(got, []int{1, 2, 3}) {
+				t.Errorf("short_ids after undo = %v, want [1 2 3]", got)
+			}
+		})
+	}
+}
@@ -5,10 +5,8 @@ import (
 	"fmt"
 	"os"
 	"strings"
-	"time"
 
 	"github.com/sjatkinson/threadkeeper/internal/config"
-	"github.com/sjatkinson/threadkeeper/internal/store"
 	"github.com/sjatkinson/threadkeeper/internal/task"
 )
 
@@ -33,7 +31,7 @@ func RunReopen(args []string, ctx CommandContext) int {
 	}
 
 	// Get paths and verify tasks directory exists
-	paths, err := config.GetPaths(ctx.Path)
+	paths, err := config.GetPaths(ctx.workspace())
 	if err != nil {
 		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
 		return 1
@@ -45,7 +43,7 @@ func RunReopen(args []string, ctx CommandContext) int {
 	}
 
 	// Validate all IDs first - abort if any are missing
-	st := store.NewFileStore(paths.ThreadsDir)
+	st := newStore(paths)
 	var tasks []*task.Task
 	var missingIDs []string
 
@@ -65,7 +63,7 @@ func RunReopen(args []string, ctx CommandContext) int {
 	}
 
 	// Reopen each task
-	now := time.Now().UTC()
+	now := ctx.clock().Now().UTC()
 	for _, t := range tasks {
 		// If already active (open), treat as no-op
 		if t.Status == task.StatusOpen {
@@ -75,6 +73,8 @@ func RunReopen(args []string, ctx CommandContext) int {
 		// Change from inactive state to active
 		t.Status = task.StatusOpen
 		t.UpdatedAt = now
+		t.CompletedAt = nil
+		t.StopTimer(now)
 
 		// Ensure the task has a short_id (open tasks should have short_ids)
 		if err := st.EnsureShortID(t); err != nil {
@@ -92,9 +92,10 @@ func RunReopen(args []string, ctx CommandContext) int {
 		if t.ShortID != nil {
 			sidStr = fmt.Sprintf("%d", *t.ShortID)
 		}
-		_, _ = fmt.Fprintf(ctx.Out, "Reopened task %s (%s)\n", sidStr, t.ID)
+		ctx.Success("Reopened task %s (%s)\n", sidStr, t.ID)
 	}
 
+	autoReindex(st, ctx, nil)
 	return 0
 }
 
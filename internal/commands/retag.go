@@ -0,0 +1,233 @@
+package commands
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/sjatkinson/threadkeeper/internal/config"
+	"github.com/sjatkinson/threadkeeper/internal/store"
+	"github.com/sjatkinson/threadkeeper/internal/task"
+)
+
+func RunRetag(args []string, ctx CommandContext) int {
+	fs := flag.NewFlagSet(ctx.AppName+" retag", flag.ContinueOnError)
+	fs.SetOutput(ctx.Err)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintln(ctx.Err, retagUsage(ctx.AppName))
+	}
+
+	var (
+		sel          bulkSelection
+		addTags      updateStringList
+		removeTags   updateStringList
+		setProject   string
+		clearProject bool
+	)
+	sel.register(fs)
+	fs.Var(&addTags, "add-tag", "repeatable tag to add")
+	fs.Var(&removeTags, "remove-tag", "repeatable tag to remove")
+	fs.StringVar(&setProject, "set-project", "", "move tasks to this project")
+	fs.BoolVar(&clearProject, "clear-project", false, "remove the project")
+
+	if err := fs.Parse(args); err != nil {
+		_, _ = fmt.Fprintln(ctx.Err)
+		_, _ = fmt.Fprintln(ctx.Err, retagUsage(ctx.AppName))
+		return 2
+	}
+
+	if len(fs.Args()) != 0 {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: unexpected arguments; select tasks with filter flags, or use '%s update' for task IDs\n", ctx.AppName)
+		return 2
+	}
+	if !sel.active() {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: missing filter: --project, --tag, --status or --all required\n")
+		return 2
+	}
+	if len(addTags) == 0 && len(removeTags) == 0 && setProject == "" && !clearProject {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: nothing to change. Provide --add-tag/--remove-tag/--set-project/--clear-project.\n")
+		return 2
+	}
+	if clearProject && setProject != "" {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: --set-project and --clear-project cannot be used together\n")
+		return 2
+	}
+	if err := task.ValidateTags(append(append([]string{}, addTags...), removeTags...)); err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 2
+	}
+
+	// Get paths and verify threads directory exists
+	paths, err := config.GetPaths(ctx.workspace())
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+
+	if _, err := os.Stat(paths.ThreadsDir); err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: threads directory does not exist at %s. Run '%s init' first.\n", paths.ThreadsDir, ctx.AppName)
+		return 1
+	}
+
+	st := newStore(paths)
+	all, err := loadAllTasks(st, ctx)
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+
+	// Only tasks that would change count against the confirmation
+	// threshold. The prompt comes before taking the lock, so this plan is
+	// only an estimate
+	edit := retagEdit{
+		add:          task.NormalizeTags([]string(addTags)),
+		remove:       task.NormalizeTags([]string(removeTags)),
+		setProject:   setProject,
+		clearProject: clearProject,
+	}
+	if code := confirmBulk(ctx, sel, len(planRetag(all, sel.filter(), edit)), "retag"); code != 0 {
+		return code
+	}
+
+	// Plan again from a fresh load under the lock, so edits other tk
+	// processes made in the meantime are kept
+	var changes []retagChange
+	now := ctx.clock().Now().UTC()
+	if err := st.WithLock(func() error {
+		current, err := st.LoadAll()
+		if err != nil {
+			return err
+		}
+		changes = planRetag(current, sel.filter(), edit)
+		return saveRetag(st, changes, now)
+	}); err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+
+	rec := newOpRecorder("retag")
+	for _, c := range changes {
+		rec.add(c.task.ID, c.before)
+
+		sidStr := "?"
+		if c.task.ShortID != nil {
+			sidStr = fmt.Sprintf("%d", *c.task.ShortID)
+		}
+		ctx.Success("Updated task %s (%s)\n", sidStr, c.task.ID)
+	}
+	rec.commit(st, paths, ctx)
+
+	ctx.Success("Retagged %d task(s)\n", len(changes))
+	return 0
+}
+
+// retagEdit is the change retag makes to every selected task.
+type retagEdit struct {
+	add, remove  []string // normalized
+	setProject   string
+	clearProject bool
+}
+
+// apply makes e's change to t and reports whether t changed.
+func (e retagEdit) apply(t *task.Task) bool {
+	changed := false
+	if newTags, tagsChanged := applyTagChanges(t.Tags, e.add, e.remove); tagsChanged {
+		t.Tags = newTags
+		changed = true
+	}
+	if e.setProject != "" && e.setProject != t.Project {
+		t.Project = e.setProject
+		changed = true
+	}
+	if e.clearProject && t.Project != "" {
+		t.Project = ""
+		changed = true
+	}
+	return changed
+}
+
+// retagChange is a task changed in memory by retag, with its state before.
+type retagChange struct {
+	task   *task.Task
+	before json.RawMessage
+}
+
+// planRetag applies e in memory to the tasks matching f and returns those
+// that changed.
+func planRetag(tasks []*task.Task, f taskFilter, e retagEdit) []retagChange {
+	var changes []retagChange
+	for _, t := range filterTasks(tasks, f) {
+		snap := snapshotTask(t)
+		if e.apply(t) {
+			changes = append(changes, retagChange{task: t, before: snap})
+		}
+	}
+	return changes
+}
+
+// saveRetag saves every changed task. If one fails, the tasks already saved
+// are put back as they were, so retag changes all of them or none. Callers
+// must hold the workspace lock.
+func saveRetag(st *store.FileStore, changes []retagChange, now time.Time) error {
+	for i, c := range changes {
+		c.task.UpdatedAt = now
+		if err := st.Save(c.task); err != nil {
+			if restoreErr := restoreRetag(st, changes[:i]); restoreErr != nil {
+				return fmt.Errorf("failed to save task %s: %w; %v", c.task.ID, err, restoreErr)
+			}
+			return fmt.Errorf("failed to save task %s: %w; no tasks were changed", c.task.ID, err)
+		}
+	}
+	return nil
+}
+
+// restoreRetag saves changes back in their state before retag, returning
+// the first failure.
+func restoreRetag(st *store.FileStore, changes []retagChange) error {
+	var first error
+	for _, c := range changes {
+		var before task.Task
+		err := json.Unmarshal(c.before, &before)
+		if err == nil {
+			before.Normalize()
+			err = st.Save(&before)
+		}
+		if err != nil && first == nil {
+			first = fmt.Errorf("failed to restore task %s: %w", c.task.ID, err)
+		}
+	}
+	return first
+}
+
+func retagUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s retag [filter flags] [--add-tag <tag>]... [--remove-tag <tag>]...
+           [--set-project <name> | --clear-project]
+
+Changes the tags and project of every task the filters select, using the
+same filters as list. Either every selected task is saved or, if a save
+fails, none is. Changing more than %d tasks asks for confirmation on a
+terminal and requires --force elsewhere. Use update to change tasks by ID.
+
+Filter flags (at least one is required):
+  -a, --all                     select tasks of any status (default: only open)
+  -p, --project <name>          select by project
+  --status <open|done|archived> select by status
+  --tag <tag>                   select by tag (repeat to AND tags)
+
+Flags:
+  --add-tag <tag>        add a tag (repeatable)
+  --remove-tag <tag>     remove a tag (repeatable)
+  --set-project <name>   move the tasks to project <name>
+  --clear-project        remove the project (not with --set-project)
+  --force                don't ask for confirmation
+
+Examples:
+  %s retag --project old --set-project new
+  %s retag --tag urgent --add-tag q3 --remove-tag urgent
+  %s retag --all --project infra --add-tag ops
+
+`, app, BulkConfirmThreshold, app, app, app)
+}
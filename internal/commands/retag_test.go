@@ -0,0 +1,151 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/sjatkinson/threadkeeper/internal/config"
+)
+
+func TestRunRetag(t *testing.T) {
+	setupWorkspace(t)
+	add := func(args ...string) {
+		ctx, _, errOut := newTestContext()
+		if code := RunAdd(args, ctx); code != 0 {
+			t.Fatalf("RunAdd() exit code = %d, stderr: %s", code, errOut.String())
+		}
+	}
+	for i := 0; i < BulkConfirmThreshold+1; i++ {
+		add("--force", "--project", "old", "--tag", "urgent", "step")
+	}
+	add("--project", "other", "--tag", "urgent", "unrelated")
+
+	// projectTags returns "project:tags" for each task, by title
+	projectTags := func() map[string][]string {
+		paths, err := config.GetPaths("")
+		if err != nil {
+			t.Fatalf("GetPaths() error = %v", err)
+		}
+		tasks, err := newStore(paths).LoadAll()
+		if err != nil {
+			t.Fatalf("LoadAll() error = %v", err)
+		}
+		got := make(map[string][]string)
+		for _, tk := range tasks {
+			got[tk.Title] = append(got[tk.Title], tk.Project+":"+strings.Join(tk.Tags, ","))
+		}
+		return got
+	}
+
+	for _, args := range [][]string{
+		{"--add-tag", "q3"},                          // no filter
+		{"--project", "old"},                         // nothing to change
+		{"--project", "old", "--add-tag", "q3", "1"}, // IDs are for update
+		{"--project", "old", "--set-project", "new", "--clear-project"},
+	} {
+		ctx, _, _ := newTestContext()
+		if code := RunRetag(args, ctx); code != 2 {
+			t.Errorf("RunRetag(%v) exit code = %d, want 2", args, code)
+		}
+	}
+
+	// Above the threshold without a terminal, --force is required
+	retagArgs := []string{"--project", "old", "--set-project", "new", "--add-tag", "q3", "--remove-tag", "urgent"}
+	ctx, _, errOut := newTestContext()
+	ctx.Stdin = strings.NewReader("")
+	if code := RunRetag(retagArgs, ctx); code != 1 || !strings.Contains(errOut.String(), "--force") {
+		t.Errorf("RunRetag() = %d, stderr %q, want 1 asking for --force", code, errOut.String())
+	}
+	if got := projectTags()["step"][0]; got != "old:urgent" {
+		t.Errorf("task after refused retag = %q, want unchanged", got)
+	}
+
+	ctx, out, errOut := newTestContext()
+	if code := RunRetag(append(retagArgs, "--force"), ctx); code != 0 {
+		t.Fatalf("RunRetag() exit code = %d, stderr: %s", code, errOut.String())
+	}
+	if want := fmt.Sprintf("Retagged %d task(s)", BulkConfirmThreshold+1); !strings.Contains(out.String(), want) {
+		t.Errorf("RunRetag() stdout = %q, want %q", out.String(), want)
+	}
+	got := projectTags()
+	for _, step := range got["step"] {
+		if step != "new:q3" {
+			t.Errorf("retagged task = %q, want %q", step, "new:q3")
+		}
+	}
+	if got["unrelated"][0] != "other:urgent" {
+		t.Errorf("unselected task = %q, want unchanged", got["unrelated"][0])
+	}
+
+	// Tasks that already match count as unchanged
+	ctx, out, _ = newTestContext()
+	if code := RunRetag([]string{"--project", "new", "--add-tag", "q3"}, ctx); code != 0 || !strings.Contains(out.String(), "Retagged 0 task(s)") {
+		t.Errorf("RunRetag() no-op = %d, stdout %q, want 0 tasks retagged", code, out.String())
+	}
+
+	// One undo reverts the whole retag
+	ctx, _, errOut = newTestContext()
+	if code := RunUndo([]string{"--force"}, ctx); code != 0 {
+		t.Fatalf("RunUndo() exit code = %d, stderr: %s", code, errOut.String())
+	}
+	for _, step := range projectTags()["step"] {
+		if step != "old:urgent" {
+			t.Errorf("task after undo = %q, want %q", step, "old:urgent")
+		}
+	}
+}
+
+// editingReader answers a prompt with "y" after running edit, standing in
+// for another tk process writing while the user reads the prompt.
+type editingReader struct {
+	edit func()
+	done bool
+}
+
+func (r *editingReader) Read(p []byte) (int, error) {
+	if r.done {
+		return 0, io.EOF
+	}
+	r.done = true
+	r.edit()
+	return copy(p, "y\n"), nil
+}
+
+func TestRunRetag_KeepsEditsMadeDuringPrompt(t *testing.T) {
+	setupWorkspace(t)
+	for i := 0; i < BulkConfirmThreshold+1; i++ {
+		ctx, _, errOut := newTestContext()
+		if code := RunAdd([]string{"--force", "--project", "old", "step"}, ctx); code != 0 {
+			t.Fatalf("RunAdd() exit code = %d, stderr: %s", code, errOut.String())
+		}
+	}
+
+	orig := isTerminal
+	isTerminal = func(io.Reader) bool { return true }
+	t.Cleanup(func() { isTerminal = orig })
+
+	ctx, _, errOut := newTestContext()
+	ctx.Stdin = &editingReader{edit: func() {
+		uctx, _, uerr := newTestContext()
+		if code := RunUpdate([]string{"--title", "renamed meanwhile", "1"}, uctx); code != 0 {
+			t.Errorf("RunUpdate() exit code = %d, stderr: %s", code, uerr.String())
+		}
+	}}
+	if code := RunRetag([]string{"--project", "old", "--add-tag", "q3"}, ctx); code != 0 {
+		t.Fatalf("RunRetag() exit code = %d, stderr: %s", code, errOut.String())
+	}
+
+	paths, err := config.GetPaths("")
+	if err != nil {
+		t.Fatalf("GetPaths() error = %v", err)
+	}
+	tk, err := newStore(paths).ResolveID("1")
+	if err != nil {
+		t.Fatalf("ResolveID() error = %v", err)
+	}
+	if tk.Title != "renamed meanwhile" || strings.Join(tk.Tags, ",") != "q3" {
+		t.Errorf("task 1 = %q %v, want the concurrent rename kept and q3 added", tk.Title, tk.Tags)
+	}
+}
@@ -6,7 +6,6 @@ import (
 	"os"
 
 	"github.com/sjatkinson/threadkeeper/internal/config"
-	"github.com/sjatkinson/threadkeeper/internal/store"
 	"github.com/sjatkinson/threadkeeper/internal/task"
 )
 
@@ -17,8 +16,12 @@ func RunRemove(args []string, ctx CommandContext) int {
 		_, _ = fmt.Fprintln(ctx.Err, removeUsage(ctx.AppName))
 	}
 
-	var force bool
-	fs.BoolVar(&force, "force", false, "actually delete (required)")
+	var (
+		force     bool
+		permanent bool
+	)
+	fs.BoolVar(&force, "force", false, "don't ask for confirmation")
+	fs.BoolVar(&permanent, "permanent", false, "delete permanently instead of moving to the trash")
 
 	if err := fs.Parse(args); err != nil {
 		_, _ = fmt.Fprintln(ctx.Err)
@@ -32,14 +35,8 @@ func RunRemove(args []string, ctx CommandContext) int {
 		return 2
 	}
 
-	// Require --force flag
-	if !force {
-		_, _ = fmt.Fprintf(ctx.Err, "Error: remove is a hard delete and requires --force\n")
-		return 1
-	}
-
 	// Get paths and verify threads directory exists
-	paths, err := config.GetPaths(ctx.Path)
+	paths, err := config.GetPaths(ctx.workspace())
 	if err != nil {
 		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
 		return 1
@@ -51,7 +48,7 @@ func RunRemove(args []string, ctx CommandContext) int {
 	}
 
 	// Load and resolve tasks
-	st := store.NewFileStore(paths.ThreadsDir)
+	st := newStore(paths)
 	var tasks []*task.Task
 	for _, idStr := range ids {
 		t, err := st.ResolveID(idStr)
@@ -62,9 +59,28 @@ func RunRemove(args []string, ctx CommandContext) int {
 		tasks = append(tasks, t)
 	}
 
-	// Delete each thread directory
+	// Like rm -i: ask on a terminal, and require --force everywhere else
+	if !force {
+		if !isTerminal(ctx.stdin()) {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: remove needs confirmation; pass --force to remove without asking\n")
+			return 1
+		}
+		prompt := fmt.Sprintf("Move %d task(s) to the trash?", len(tasks))
+		if permanent {
+			prompt = fmt.Sprintf("Delete %d task(s)?", len(tasks))
+		}
+		if !confirm(ctx, prompt) {
+			_, _ = fmt.Fprintln(ctx.Err, "Aborted; no tasks were removed.")
+			return 1
+		}
+	}
+
+	// Trashed threads come back with 'trash restore'; only permanent deletes
+	// need the op log
+	rec := newOpRecorder("remove")
+	defer rec.commit(st, paths, ctx)
 	for _, t := range tasks {
-		threadDir := store.ThreadPath(paths.ThreadsDir, t.ID)
+		threadDir := st.ThreadDir(t.ID)
 		if _, err := os.Stat(threadDir); err != nil {
 			if os.IsNotExist(err) {
 				_, _ = fmt.Fprintf(ctx.Err, "Error: thread directory for task %s not found; skipping\n", t.ID)
@@ -74,15 +90,27 @@ func RunRemove(args []string, ctx CommandContext) int {
 			continue
 		}
 
+		sidStr := "?"
+		if t.ShortID != nil {
+			sidStr = fmt.Sprintf("%d", *t.ShortID)
+		}
+
+		if !permanent {
+			if err := st.Trash(t.ID); err != nil {
+				_, _ = fmt.Fprintf(ctx.Err, "Error: failed to trash thread %s: %v\n", t.ID, err)
+				continue
+			}
+			_, _ = fmt.Fprintf(ctx.Out, "Moved task %s (%s) to the trash\n", sidStr, t.ID)
+			continue
+		}
+
+		snap := snapshotTask(t)
 		if err := os.RemoveAll(threadDir); err != nil {
 			_, _ = fmt.Fprintf(ctx.Err, "Error: failed to remove thread %s: %v\n", t.ID, err)
 			continue
 		}
+		rec.add(t.ID, snap)
 
-		sidStr := "?"
-		if t.ShortID != nil {
-			sidStr = fmt.Sprintf("%d", *t.ShortID)
-		}
 		_, _ = fmt.Fprintf(ctx.Out, "Removed task %s (%s)\n", sidStr, t.ID)
 	}
 
@@ -91,10 +119,15 @@ func RunRemove(args []string, ctx CommandContext) int {
 
 func removeUsage(app string) string {
 	return fmt.Sprintf(`Usage:
-  %s remove --force <id> [<id> ...]
+  %s remove [--force] [--permanent] <id> [<id> ...]
+
+Moves each task's thread directory, attachments included, into the trash.
+Use '%s trash restore <id>' to bring it back. On a terminal remove asks
+for confirmation first; elsewhere it requires --force.
 
 Flags:
-  --force        actually delete (required)
+  --force        don't ask for confirmation
+  --permanent    delete permanently instead (undo recreates only thread.json)
 
-`, app)
+`, app, app)
 }
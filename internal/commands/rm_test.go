@@ -0,0 +1,48 @@
+package commands
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestRunRemove_Confirm(t *testing.T) {
+	orig := isTerminal
+	t.Cleanup(func() { isTerminal = orig })
+
+	tests := []struct {
+		name     string
+		args     []string
+		terminal bool
+		input    string
+		wantCode int
+		wantGone bool
+	}{
+		{"confirm yes", nil, true, "y\n", 0, true},
+		{"confirm no", nil, true, "n\n", 1, false},
+		{"eof aborts", nil, true, "", 1, false},
+		{"not a terminal", nil, false, "y\n", 1, false},
+		{"force skips prompt", []string{"--force"}, false, "", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupWorkspace(t)
+			tk := addAndLoad(t, []string{"doomed"})
+			isTerminal = func(io.Reader) bool { return tt.terminal }
+
+			ctx, _, errOut := newTestContext()
+			ctx.Stdin = strings.NewReader(tt.input)
+			args := append(append([]string{}, tt.args...), tk.ID)
+			if code := RunRemove(args, ctx); code != tt.wantCode {
+				t.Fatalf("RunRemove() exit code = %d, want %d, stderr: %s", code, tt.wantCode, errOut.String())
+			}
+
+			ctx, out, _ := newTestContext()
+			RunList([]string{}, ctx)
+			if gone := !strings.Contains(out.String(), "doomed"); gone != tt.wantGone {
+				t.Errorf("task removed = %v, want %v", gone, tt.wantGone)
+			}
+		})
+	}
+}
@@ -0,0 +1,270 @@
+package commands
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sjatkinson/threadkeeper/internal/config"
+	"github.com/sjatkinson/threadkeeper/internal/date"
+	"github.com/sjatkinson/threadkeeper/internal/task"
+)
+
+func RunSearch(args []string, ctx CommandContext) int {
+	fs := flag.NewFlagSet(ctx.AppName+" search", flag.ContinueOnError)
+	fs.SetOutput(ctx.Err)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintln(ctx.Err, searchUsage(ctx.AppName))
+	}
+
+	var (
+		all      bool
+		useRegex bool
+		asJSON   bool
+		since    string
+		until    string
+	)
+	fs.BoolVar(&all, "all", false, "search all tasks")
+	fs.BoolVar(&all, "a", false, "search all tasks (shorthand)")
+	fs.BoolVar(&useRegex, "regex", false, "treat query as a regular expression")
+	fs.BoolVar(&asJSON, "json", false, "print matching tasks as JSON")
+	fs.StringVar(&since, "since", "", "only tasks updated since a date or within a window (7d, 2w, 12h)")
+	fs.StringVar(&until, "until", "", "only tasks updated before a date or window")
+
+	if err := fs.Parse(args); err != nil {
+		_, _ = fmt.Fprintln(ctx.Err)
+		_, _ = fmt.Fprintln(ctx.Err, searchUsage(ctx.AppName))
+		return 2
+	}
+
+	rest := fs.Args()
+	if len(rest) == 0 {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: missing argument: search query required\n")
+		return 2
+	}
+	query := strings.Join(rest, " ")
+
+	match, err := newTextMatcher(query, useRegex)
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: invalid regex: %v\n", err)
+		return 2
+	}
+
+	window, code := parseTimeWindow(since, until, ctx)
+	if code != 0 {
+		return code
+	}
+
+	// Get paths and verify threads directory exists
+	paths, err := config.GetPaths(ctx.workspace())
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+
+	if _, err := os.Stat(paths.ThreadsDir); err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: threads directory does not exist at %s. Run '%s init' first.\n", paths.ThreadsDir, ctx.AppName)
+		return 1
+	}
+
+	st := newStore(paths)
+	tasks, err := st.LoadAll()
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+
+	// Ensure open tasks have short_ids (for display)
+	for _, t := range tasks {
+		if t.Status == task.StatusOpen {
+			_ = st.EnsureShortID(t)
+		}
+	}
+
+	results := searchTasks(window.filter(filterTasks(tasks, taskFilter{All: all})), match)
+
+	if asJSON {
+		if results == nil {
+			results = []*task.Task{}
+		}
+		enc := json.NewEncoder(ctx.Out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	if len(results) == 0 {
+		_, _ = fmt.Fprintln(ctx.Out, "No tasks found.")
+		return 0
+	}
+
+	dateLayout, err := config.LoadDisplayDateFormat()
+	if err != nil {
+		dateLayout = config.DisplayLayoutISO // Default on error
+	}
+
+	displayTasks(ctx.Out, results, displayOptions{DateLayout: dateLayout})
+	return 0
+}
+
+// newTextMatcher returns a predicate for query: a case-insensitive substring
+// match, or a regexp match when useRegex is set.
+func newTextMatcher(query string, useRegex bool) (func(string) bool, error) {
+	if useRegex {
+		re, err := regexp.Compile(query)
+		if err != nil {
+			return nil, err
+		}
+		return re.MatchString, nil
+	}
+
+	needle := strings.ToLower(query)
+	return func(s string) bool {
+		return strings.Contains(strings.ToLower(s), needle)
+	}, nil
+}
+
+// timeWindow limits tasks by updated_at for --since and --until. A zero
+// bound is open.
+type timeWindow struct {
+	since time.Time // updated at or after
+	until time.Time // updated before
+}
+
+// filter returns the tasks updated within w.
+func (w timeWindow) filter(tasks []*task.Task) []*task.Task {
+	if w.since.IsZero() && w.until.IsZero() {
+		return tasks
+	}
+	var kept []*task.Task
+	for _, t := range tasks {
+		if !w.since.IsZero() && t.UpdatedAt.Before(w.since) {
+			continue
+		}
+		if !w.until.IsZero() && !t.UpdatedAt.Before(w.until) {
+			continue
+		}
+		kept = append(kept, t)
+	}
+	return kept
+}
+
+// parseTimeWindow resolves --since and --until, reporting errors on
+// ctx.Err. It returns a non-zero exit code on failure.
+func parseTimeWindow(since, until string, ctx CommandContext) (timeWindow, int) {
+	var w timeWindow
+	if since == "" && until == "" {
+		return w, 0
+	}
+
+	tz, err := config.LoadTimezone()
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return w, 1
+	}
+	locale, err := config.LoadDateLocale()
+	if err != nil {
+		locale = config.DateLocaleISO // Default on error
+	}
+
+	for _, b := range []struct {
+		name     string
+		spec     string
+		endOfDay bool
+		dst      *time.Time
+	}{
+		{"--since", since, false, &w.since},
+		{"--until", until, true, &w.until},
+	} {
+		if b.spec == "" {
+			continue
+		}
+		*b.dst, err = parseTimeBound(b.spec, b.endOfDay, ctx.clock(), tz, locale)
+		if err != nil {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: %s: %v\n", b.name, err)
+			return w, 2
+		}
+	}
+	return w, 0
+}
+
+// windowPattern matches a window counted back from now: 7d, 2w or 12h.
+var windowPattern = regexp.MustCompile(`^(\d+)([dwh])$`)
+
+// parseTimeBound resolves spec to an instant. A window like 7d or 2w
+// starts at midnight in tz that many days back, counting today (as recent
+// --days does); 12h is twelve hours before now. Anything else is a date as
+// --due accepts it, at midnight in tz, or the midnight after it when
+// endOfDay is set so the whole day is included.
+func parseTimeBound(spec string, endOfDay bool, clock date.Clock, tz *time.Location, locale config.DateLocale) (time.Time, error) {
+	now := clock.Now()
+	if m := windowPattern.FindStringSubmatch(spec); m != nil {
+		n, err := strconv.Atoi(m[1])
+		if err != nil || n < 1 {
+			return time.Time{}, fmt.Errorf("invalid window %q", spec)
+		}
+		switch m[2] {
+		case "h":
+			return now.Add(-time.Duration(n) * time.Hour), nil
+		case "w":
+			n *= 7
+		}
+		return recentCutoff(now, n, tz), nil
+	}
+
+	canonical, err := date.ParseDate(spec, locale, clock, tz)
+	if err != nil {
+		return time.Time{}, err
+	}
+	day, err := time.ParseInLocation(dueDateLayout, canonical, tz)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if endOfDay {
+		day = day.AddDate(0, 0, 1)
+	}
+	return day, nil
+}
+
+// searchTasks returns the tasks whose title or description matches.
+func searchTasks(tasks []*task.Task, match func(string) bool) []*task.Task {
+	var results []*task.Task
+	for _, t := range tasks {
+		if match(t.Title) || match(t.Description) {
+			results = append(results, t)
+		}
+	}
+	return results
+}
+
+func searchUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s search [flags] <query>
+
+Finds tasks whose title or description contains the query
+(case-insensitive). Searches open tasks unless --all is given.
+
+Flags:
+  -a, --all      search all tasks (default: only open)
+  --regex        treat the query as a Go regular expression
+                 (case-sensitive; prefix with (?i) to ignore case)
+  --json         print matching tasks as JSON
+  --since <when>  only tasks updated since a date, or within a window
+                  such as 7d, 2w or 12h
+  --until <when>  only tasks updated before the end of a date, or before
+                  the start of a window
+
+Dates accept the same input as --due on add (e.g. today, 2026-03-01), in
+the timezone config key. 7d means since midnight six days ago, as
+recent --days 7 does.
+
+`, app)
+}
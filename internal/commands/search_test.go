@@ -0,0 +1,99 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sjatkinson/threadkeeper/internal/config"
+	"github.com/sjatkinson/threadkeeper/internal/date"
+	"github.com/sjatkinson/threadkeeper/internal/task"
+)
+
+func TestSearchTasks(t *testing.T) {
+	tasks := []*task.Task{
+		{ID: "a", Title: "Plan the Migration", Tags: []string{}},
+		{ID: "b", Title: "write docs", Description: "cover the db migration steps", Tags: []string{}},
+		{ID: "c", Title: "fix bug 42", Tags: []string{}},
+	}
+
+	tests := []struct {
+		name     string
+		query    string
+		useRegex bool
+		want     []string
+		wantErr  bool
+	}{
+		{"substring is case-insensitive", "MIGRATION", false, []string{"a", "b"}, false},
+		{"matches description", "db migration", false, []string{"b"}, false},
+		{"regex special chars are literal by default", "bug 4.", false, []string{}, false},
+		{"regex", `bug \d+`, true, []string{"c"}, false},
+		{"regex is case-sensitive", "migration", true, []string{"b"}, false},
+		{"invalid regex", "(", true, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, err := newTextMatcher(tt.query, tt.useRegex)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newTextMatcher() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			got := filterIDs(searchTasks(tasks, match))
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("searchTasks(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTimeBound(t *testing.T) {
+	clock := date.FixedClock{FixedTime: time.Date(2026, 3, 10, 15, 30, 0, 0, time.UTC)}
+
+	tests := []struct {
+		spec     string
+		endOfDay bool
+		want     time.Time
+		wantErr  bool
+	}{
+		{"1d", false, time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC), false},
+		{"7d", false, time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC), false},
+		{"2w", false, time.Date(2026, 2, 25, 0, 0, 0, 0, time.UTC), false},
+		{"12h", false, time.Date(2026, 3, 10, 3, 30, 0, 0, time.UTC), false},
+		{"2026-03-01", false, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), false},
+		{"2026-03-01", true, time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), false},
+		{"0d", false, time.Time{}, true},
+		{"soonish", false, time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := parseTimeBound(tt.spec, tt.endOfDay, clock, time.UTC, config.DateLocaleISO)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseTimeBound(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("parseTimeBound(%q) = %v, want %v", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTimeWindowFilter(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 12, 0, 0, 0, time.UTC) }
+	tasks := []*task.Task{
+		{ID: "a", UpdatedAt: day(1)},
+		{ID: "b", UpdatedAt: day(5)},
+		{ID: "c", UpdatedAt: day(9)},
+	}
+
+	w := timeWindow{since: day(2), until: day(9)}
+	if got := strings.Join(filterIDs(w.filter(tasks)), ","); got != "b" {
+		t.Errorf("filter() = %v, want b", got)
+	}
+	if got := strings.Join(filterIDs(timeWindow{}.filter(tasks)), ","); got != "a,b,c" {
+		t.Errorf("open filter() = %v, want a,b,c", got)
+	}
+}
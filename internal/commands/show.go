@@ -9,11 +9,12 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/sjatkinson/threadkeeper/internal/config"
-	"github.com/sjatkinson/threadkeeper/internal/store"
+	"github.com/sjatkinson/threadkeeper/internal/date"
 	"github.com/sjatkinson/threadkeeper/internal/task"
 )
 
@@ -29,6 +30,20 @@ func RunShow(args []string, ctx CommandContext) int {
 	fs.BoolVar(&full, "full", false, "show full metadata and history")
 	fs.BoolVar(&all, "all", false, "show full metadata (deprecated, use --full)")
 
+	var pathOnly bool
+	var noNewline bool
+	var nul bool
+	var noPager bool
+	fs.BoolVar(&pathOnly, "path-only", false, "print only the thread directory path")
+	fs.BoolVar(&noNewline, "no-newline", false, "with --path-only, omit the trailing newline")
+	fs.BoolVar(&nul, "0", false, "with --path-only, terminate with a NUL byte")
+	fs.BoolVar(&noPager, "no-pager", false, "don't pipe output through the pager")
+
+	var note string
+	var raw bool
+	fs.StringVar(&note, "note", "", "print a note attachment, by index or attachment ID")
+	fs.BoolVar(&raw, "raw", false, "with --note, print the note's bytes without styling")
+
 	if err := fs.Parse(args); err != nil {
 		_, _ = fmt.Fprintln(ctx.Err)
 		_, _ = fmt.Fprintln(ctx.Err, showUsage(ctx.AppName))
@@ -43,8 +58,22 @@ func RunShow(args []string, ctx CommandContext) int {
 
 	idStr := rest[0]
 
+	if (noNewline || nul) && !pathOnly {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: --no-newline and -0 require --path-only\n")
+		return 2
+	}
+
+	if raw && note == "" {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: --raw requires --note\n")
+		return 2
+	}
+	if note != "" && (pathOnly || full || all) {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: --note cannot be combined with --full or --path-only\n")
+		return 2
+	}
+
 	// Get paths and verify tasks directory exists
-	paths, err := config.GetPaths(ctx.Path)
+	paths, err := config.GetPaths(ctx.workspace())
 	if err != nil {
 		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
 		return 1
@@ -56,7 +85,7 @@ func RunShow(args []string, ctx CommandContext) int {
 	}
 
 	// Load and resolve task
-	st := store.NewFileStore(paths.ThreadsDir)
+	st := newStore(paths)
 	t, err := st.ResolveID(idStr)
 	if err != nil {
 		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
@@ -64,7 +93,16 @@ func RunShow(args []string, ctx CommandContext) int {
 	}
 
 	// Get thread directory path
-	threadDir := store.ThreadPath(paths.ThreadsDir, t.ID)
+	threadDir := st.ThreadDir(t.ID)
+
+	if pathOnly {
+		writePath(ctx.Out, threadDir, noNewline, nul)
+		return 0
+	}
+
+	if note != "" {
+		return showNote(ctx, paths.BlobsDir, threadDir, note, raw, noPager)
+	}
 
 	// Load attachments
 	attachments, err := loadAttachments(threadDir)
@@ -77,6 +115,22 @@ func RunShow(args []string, ctx CommandContext) int {
 		attachments = []AttachmentEvent{}
 	}
 
+	// Load display date format from config
+	dateLayout, err := config.LoadDisplayDateFormat()
+	if err != nil {
+		dateLayout = config.DisplayLayoutISO // Default on error
+	}
+	// Due dates also say how far off they are, in the timezone config key
+	tz, err := config.LoadTimezone()
+	if err != nil {
+		tz = time.Local // Default on error
+	}
+	today := date.Today(ctx.clock(), tz)
+
+	pg := startPager(ctx, noPager)
+	defer pg.Close()
+	ctx.Out = pg
+
 	// Display based on mode
 	if full || all {
 		// In full mode, load with metadata to show malformed line warnings
@@ -87,23 +141,119 @@ func RunShow(args []string, ctx CommandContext) int {
 		} else if err == nil {
 			attachments = attResult.Events
 		}
-		displayFull(ctx.Out, t, attachments, attResult.MalformedLine)
+		displayFull(ctx.Out, t, attachments, attResult.MalformedLine, dateLayout, ctx.clock().Now(), today)
 	} else {
-		displayContextual(ctx.Out, t, attachments, ctx.AppName)
+		displayContextual(ctx.Out, t, attachments, ctx.AppName, dateLayout, today)
 	}
 
 	return 0
 }
 
+// showNote prints the content of one note attachment, chosen by 1-based
+// index or attachment ID. Markdown notes are styled when ctx.Out is a
+// terminal and render_markdown is not turned off; otherwise, or with raw,
+// the blob's bytes are written unchanged so pipes stay clean.
+func showNote(ctx CommandContext, blobsDir, threadDir, spec string, raw, noPager bool) int {
+	events, err := loadAttachments(threadDir)
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: failed to load attachments: %v\n", err)
+		return 1
+	}
+	current := computeCurrentAttachments(events)
+
+	var target *AttachmentEvent
+	if n, err := strconv.Atoi(spec); err == nil {
+		if n < 1 || n > len(current) {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: attachment index %d out of range (max: %d)\n", n, len(current))
+			return 1
+		}
+		target = &current[n-1]
+	} else {
+		for i := range current {
+			if current[i].Att.AttID == spec {
+				target = &current[i]
+				break
+			}
+		}
+		if target == nil {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: attachment with ID %q not found\n", spec)
+			return 1
+		}
+	}
+
+	if target.Att.Kind != "note" {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: attachment %s is a %s, not a note\n", target.Att.AttID, target.Att.Kind)
+		return 1
+	}
+	if target.Att.Blob == nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: note attachment has no blob reference\n")
+		return 1
+	}
+	path := blobPath(blobsDir, threadDir, *target.Att.Blob)
+	if path == "" {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: unsupported blob algorithm %q\n", target.Att.Blob.Algo)
+		return 1
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: failed to read note: %v\n", err)
+		return 1
+	}
+
+	// Decide before the pager replaces ctx.Out
+	render := false
+	if !raw && isOutputTerminal(ctx.Out) && isMarkdownNote(target.Att.MediaType) {
+		render, _ = config.LoadRenderMarkdown()
+	}
+
+	pg := startPager(ctx, noPager)
+	defer pg.Close()
+
+	if render {
+		err = styleMarkdown(pg, string(content))
+	} else {
+		_, err = pg.Write(content)
+	}
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: failed to write note: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// isMarkdownNote reports whether a note's media type is Markdown. Notes
+// recorded before media types were detected have none and are Markdown.
+func isMarkdownNote(mediaType string) bool {
+	return mediaType == "" || strings.HasPrefix(mediaType, "text/markdown")
+}
+
 func showUsage(app string) string {
 	return fmt.Sprintf(`Usage:
-  %s show [--full] <id>
+  %s show [--full] [--no-pager] [--path-only [--no-newline | -0]] <id>
+  %s show --note <att> [--raw] [--no-pager] <id>
 
 Flags:
   --full         show full metadata and history
   --all          show full metadata (deprecated, use --full)
+  --no-pager     don't pipe output through the pager
+  --path-only    print only the thread directory path
+  --no-newline   with --path-only, omit the trailing newline
+  -0             with --path-only, terminate with a NUL byte
+  --note <att>   print a note attachment instead, by index (as listed by
+                 attachments) or attachment ID
+  --raw          with --note, print the note's bytes without styling
+
+An open task's due date is followed by how far off it is, e.g. "(due in
+3 days)" or "(overdue 2 days)", using the timezone config key.
 
-`, app)
+On a terminal, output longer than the screen goes through the pager
+config key or $PAGER, if either is set.
+
+With --note, a Markdown note printed to a terminal is styled: headings,
+bold, lists and code blocks. Set render_markdown = false in the config to
+turn this off. Output to a pipe or file is always the raw note.
+
+`, app, app)
 }
 
 // loadAttachmentsResult holds both parsed events and metadata about parsing.
@@ -169,25 +319,28 @@ func loadAttachmentsWithMetadata(threadDir string) (*loadAttachmentsResult, erro
 // sorted by timestamp (stable ordering for indexing).
 // Handles add/remove operations: only attachments that have been added and not removed are returned.
 func computeCurrentAttachments(events []AttachmentEvent) []AttachmentEvent {
-	active := make(map[string]AttachmentEvent) // keyed by att_id
+	active := make(map[string]int) // att_id to index of its add event
 
-	for _, event := range events {
+	for i, event := range events {
 		switch event.Op {
 		case "add":
-			active[event.Att.AttID] = event
+			active[event.Att.AttID] = i
 		case "remove":
 			delete(active, event.Att.AttID)
 		}
 	}
 
-	// Convert to slice and sort by timestamp
+	// Convert to slice in log order, then sort by timestamp
 	result := make([]AttachmentEvent, 0, len(active))
-	for _, event := range active {
-		result = append(result, event)
+	for i, event := range events {
+		if idx, ok := active[event.Att.AttID]; ok && event.Op == "add" && idx == i {
+			result = append(result, event)
+		}
 	}
 
-	// Sort by TS (RFC3339 string comparison works for chronological order)
-	sort.Slice(result, func(i, j int) bool {
+	// Sort by TS (RFC3339 string comparison works for chronological order);
+	// attachments added in the same second keep their order in the log
+	sort.SliceStable(result, func(i, j int) bool {
 		return result[i].TS < result[j].TS
 	})
 
@@ -196,7 +349,7 @@ func computeCurrentAttachments(events []AttachmentEvent) []AttachmentEvent {
 
 // displayAttachmentsHistory displays all attachment events in chronological order.
 // This is used in full view to show complete history including removed attachments.
-func displayAttachmentsHistory(out io.Writer, events []AttachmentEvent) {
+func displayAttachmentsHistory(out io.Writer, events []AttachmentEvent, dateLayout string) {
 	if len(events) == 0 {
 		_, _ = fmt.Fprintln(out, "(no attachment events)")
 		return
@@ -233,17 +386,41 @@ func displayAttachmentsHistory(out io.Writer, events []AttachmentEvent) {
 			sizeStr = "-"
 		}
 
-		created := formatAttachmentDate(event.TS)
+		created := formatAttachmentDate(event.TS, dateLayout)
+
+		// Images are marked with their type after the last column
+		if isImageMediaType(event.Att.MediaType) {
+			created += "  " + event.Att.MediaType
+		}
 
 		_, _ = fmt.Fprintf(out, "%-2d %-8s  %-12s  %-6s  %-24s  %-6s  %s\n",
 			i+1, op, truncatedID, kind, name, sizeStr, created)
 	}
 }
 
-// blobPath computes the filesystem path for a blob given thread directory and BlobRef.
+// blobPath computes the filesystem path for a blob referenced by a thread.
+// Blobs live in the workspace store under blobsDir; a blob written before
+// the store was shared is found in the thread's own blobs directory until
+// migrate-blobs moves it.
 // Returns empty string if algorithm is not supported.
-// Path format: <thread-dir>/blobs/<algo>/<first2>/<next2>/<hash>
-func blobPath(threadDir string, blob BlobRef) string {
+func blobPath(blobsDir, threadDir string, blob BlobRef) string {
+	shared := blobPathIn(blobsDir, blob)
+	if shared == "" {
+		return ""
+	}
+	if _, err := os.Stat(shared); err != nil {
+		legacy := blobPathIn(threadBlobsDir(threadDir), blob)
+		if _, err := os.Stat(legacy); err == nil {
+			return legacy
+		}
+	}
+	return shared
+}
+
+// blobPathIn computes the path for a blob under a blobs root.
+// Returns empty string if algorithm is not supported.
+// Path format: <root>/<algo>/<first2>/<next2>/<hash>
+func blobPathIn(root string, blob BlobRef) string {
 	if blob.Algo != "sha256" {
 		return "" // Unknown algorithm
 	}
@@ -252,11 +429,17 @@ func blobPath(threadDir string, blob BlobRef) string {
 	}
 	first2 := blob.Hash[0:2]
 	next2 := blob.Hash[2:4]
-	return filepath.Join(threadDir, "blobs", "sha256", first2, next2, blob.Hash)
+	return filepath.Join(root, "sha256", first2, next2, blob.Hash)
+}
+
+// threadBlobsDir is where a thread kept its own blobs before they moved to
+// the shared workspace store.
+func threadBlobsDir(threadDir string) string {
+	return filepath.Join(threadDir, "blobs")
 }
 
 // displayContextual shows a contextual glance: header with key fields, description if present, attachments if present.
-func displayContextual(out io.Writer, t *task.Task, attachments []AttachmentEvent, appName string) {
+func displayContextual(out io.Writer, t *task.Task, attachments []AttachmentEvent, appName string, dateLayout string, today time.Time) {
 	// Header: Task ID
 	var headerParts []string
 	if t.ShortID != nil {
@@ -272,7 +455,7 @@ func displayContextual(out io.Writer, t *task.Task, attachments []AttachmentEven
 		metaParts = append(metaParts, fmt.Sprintf("Project: %s", t.Project))
 	}
 	if t.DueAt != nil {
-		metaParts = append(metaParts, fmt.Sprintf("Due: %s", t.DueAt.Format("2006-01-02")))
+		metaParts = append(metaParts, fmt.Sprintf("Due: %s", formatDue(t, dateLayout, today)))
 	}
 	if len(metaParts) > 0 {
 		_, _ = fmt.Fprintf(out, "%s\n", strings.Join(metaParts, " | "))
@@ -320,7 +503,7 @@ func displayContextual(out io.Writer, t *task.Task, attachments []AttachmentEven
 				displayText = name
 			}
 
-			created := formatAttachmentDate(att.TS)
+			created := formatAttachmentDate(att.TS, dateLayout)
 
 			// Format: "N. displayText (kind, date)  open: tk open <id> --att N"
 			_, _ = fmt.Fprintf(out, "%d. %s (%s, %s)  open: %s open %s --att %d\n",
@@ -352,7 +535,9 @@ func truncateID(id string) string {
 }
 
 // formatAttachmentDate formats a timestamp for attachment display.
-func formatAttachmentDate(tsStr string) string {
+// dateLayout controls the date portion; the time is shown as HH:MM UTC
+// unless the layout already includes one.
+func formatAttachmentDate(tsStr string, dateLayout string) string {
 	if tsStr == "" {
 		return "-"
 	}
@@ -360,11 +545,34 @@ func formatAttachmentDate(tsStr string) string {
 	if err != nil {
 		return "-"
 	}
-	return ts.Format("2006-01-02 15:04Z")
+	if layoutHasTime(dateLayout) {
+		return ts.UTC().Format(dateLayout)
+	}
+	return ts.UTC().Format(dateLayout + " 15:04Z")
+}
+
+// formatTimestamp formats a created/updated timestamp for display.
+// The ISO layout keeps full RFC3339 output; other layouts show date plus
+// time, unless the layout already includes one.
+func formatTimestamp(ts time.Time, dateLayout string) string {
+	if dateLayout == config.DisplayLayoutISO {
+		return ts.Format(time.RFC3339)
+	}
+	if layoutHasTime(dateLayout) {
+		return ts.Format(dateLayout)
+	}
+	return ts.Format(dateLayout + " 15:04:05Z07:00")
+}
+
+// layoutHasTime reports whether a date_format layout prints the time of
+// day, which is when two times on the same date format differently.
+func layoutHasTime(layout string) bool {
+	day := time.Date(2006, 1, 2, 0, 0, 0, 0, time.UTC)
+	return day.Format(layout) != day.Add(15*time.Hour+4*time.Minute+5*time.Second).Format(layout)
 }
 
 // displayAttachmentsTable displays attachments in a compact table format.
-func displayAttachmentsTable(out io.Writer, attachments []AttachmentEvent) {
+func displayAttachmentsTable(out io.Writer, attachments []AttachmentEvent, dateLayout string) {
 	// Compute current attachments (handles add/remove operations)
 	currentAtts := computeCurrentAttachments(attachments)
 
@@ -373,8 +581,20 @@ func displayAttachmentsTable(out io.Writer, attachments []AttachmentEvent) {
 		return
 	}
 
+	// Links add a URL column after CREATED, which then needs a fixed width
+	hasLinks := false
+	createdWidth := len("CREATED")
+	for _, att := range currentAtts {
+		hasLinks = hasLinks || att.Att.Kind == "link"
+		createdWidth = max(createdWidth, len(formatAttachmentDate(att.TS, dateLayout)))
+	}
+
 	// Print header
-	_, _ = fmt.Fprintf(out, "#  %-12s  %-6s  %-24s  %-6s  %s\n", "ID", "KIND", "NAME", "SIZE", "CREATED")
+	if hasLinks {
+		_, _ = fmt.Fprintf(out, "#  %-12s  %-6s  %-24s  %-6s  %-*s  %s\n", "ID", "KIND", "NAME", "SIZE", createdWidth, "CREATED", "URL")
+	} else {
+		_, _ = fmt.Fprintf(out, "#  %-12s  %-6s  %-24s  %-6s  %s\n", "ID", "KIND", "NAME", "SIZE", "CREATED")
+	}
 
 	// Print each attachment
 	for i, att := range currentAtts {
@@ -390,15 +610,24 @@ func displayAttachmentsTable(out io.Writer, attachments []AttachmentEvent) {
 			sizeStr = "-"
 		}
 
-		created := formatAttachmentDate(att.TS)
+		created := formatAttachmentDate(att.TS, dateLayout)
 
+		if hasLinks {
+			url := att.Att.URL
+			if url == "" {
+				url = "-"
+			}
+			_, _ = fmt.Fprintf(out, "%-2d %-12s  %-6s  %-24s  %-6s  %-*s  %s\n",
+				i+1, truncatedID, kind, name, sizeStr, createdWidth, created, url)
+			continue
+		}
 		_, _ = fmt.Fprintf(out, "%-2d %-12s  %-6s  %-24s  %-6s  %s\n",
 			i+1, truncatedID, kind, name, sizeStr, created)
 	}
 }
 
 // displayFull shows full metadata and details.
-func displayFull(out io.Writer, t *task.Task, attachments []AttachmentEvent, malformedLineCount int) {
+func displayFull(out io.Writer, t *task.Task, attachments []AttachmentEvent, malformedLineCount int, dateLayout string, now, today time.Time) {
 	// Status flag mapping
 	flagMap := map[task.Status]string{
 		task.StatusOpen:     " ",
@@ -437,7 +666,7 @@ func displayFull(out io.Writer, t *task.Task, attachments []AttachmentEvent, mal
 
 	// Due date
 	if t.DueAt != nil {
-		_, _ = fmt.Fprintf(out, "Due    : %s\n", t.DueAt.Format("2006-01-02"))
+		_, _ = fmt.Fprintf(out, "Due    : %s\n", formatDue(t, dateLayout, today))
 	}
 
 	// Tags
@@ -451,12 +680,32 @@ func displayFull(out io.Writer, t *task.Task, attachments []AttachmentEvent, mal
 
 	// Created timestamp
 	if !t.CreatedAt.IsZero() {
-		_, _ = fmt.Fprintf(out, "Created: %s\n", t.CreatedAt.Format(time.RFC3339))
+		_, _ = fmt.Fprintf(out, "Created: %s\n", formatTimestamp(t.CreatedAt, dateLayout))
 	}
 
 	// Updated timestamp
 	if !t.UpdatedAt.IsZero() {
-		_, _ = fmt.Fprintf(out, "Updated: %s\n", t.UpdatedAt.Format(time.RFC3339))
+		_, _ = fmt.Fprintf(out, "Updated: %s\n", formatTimestamp(t.UpdatedAt, dateLayout))
+	}
+
+	// Completed timestamp
+	if t.CompletedAt != nil {
+		_, _ = fmt.Fprintf(out, "Completed: %s\n", formatTimestamp(*t.CompletedAt, dateLayout))
+	}
+
+	// Time tracking; a running timer counts up to now
+	if t.StartedAt != nil {
+		_, _ = fmt.Fprintf(out, "Started: %s (timer running)\n", formatTimestamp(*t.StartedAt, dateLayout))
+	}
+	if t.Estimate > 0 {
+		_, _ = fmt.Fprintf(out, "Estimate: %s\n", t.Estimate)
+	}
+	if spent := t.TotalTimeSpent(now); spent > 0 {
+		if t.Estimate > 0 {
+			_, _ = fmt.Fprintf(out, "Spent  : %s (%d%% of estimate)\n", spent, spent*100/t.Estimate)
+		} else {
+			_, _ = fmt.Fprintf(out, "Spent  : %s\n", spent)
+		}
 	}
 
 	// Title
@@ -484,6 +733,6 @@ func displayFull(out io.Writer, t *task.Task, attachments []AttachmentEvent, mal
 		_, _ = fmt.Fprintln(out, "(no attachments)")
 	} else {
 		// Full view shows all events (history), not just current attachments
-		displayAttachmentsHistory(out, attachments)
+		displayAttachmentsHistory(out, attachments, dateLayout)
 	}
 }
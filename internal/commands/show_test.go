@@ -6,6 +6,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/sjatkinson/threadkeeper/internal/config"
 )
 
 func TestComputeCurrentAttachments(t *testing.T) {
@@ -236,6 +238,27 @@ func TestComputeCurrentAttachments(t *testing.T) {
 	}
 }
 
+func TestFormatAttachmentDate(t *testing.T) {
+	tests := []struct {
+		layout string
+		want   string
+	}{
+		{config.DisplayLayoutISO, "2026-03-10 09:30Z"},
+		{config.DisplayLayoutUS, "03/10/2026 09:30Z"},
+		{"2006-01-02 15:04", "2026-03-10 09:30"},
+		{"Jan 2 3:04PM", "Mar 10 9:30AM"},
+	}
+
+	for _, tt := range tests {
+		if got := formatAttachmentDate("2026-03-10T09:30:00Z", tt.layout); got != tt.want {
+			t.Errorf("formatAttachmentDate(%q) = %q, want %q", tt.layout, got, tt.want)
+		}
+	}
+	if got := formatTimestamp(time.Date(2026, 3, 10, 9, 30, 0, 0, time.UTC), "2006-01-02 15:04"); got != "2026-03-10 09:30" {
+		t.Errorf("formatTimestamp() = %q, want the layout's own time only", got)
+	}
+}
+
 func TestBlobPath(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -320,7 +343,7 @@ func TestBlobPath(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := blobPath(tt.threadDir, tt.blob)
+			result := blobPath(filepath.Join(tmpDir, "blobs"), tt.threadDir, tt.blob)
 
 			if tt.wantEmpty {
 				if result != "" {
@@ -429,3 +452,53 @@ func TestLoadAttachments_MalformedLines(t *testing.T) {
 	}
 }
 
+func TestRunShow_Note(t *testing.T) {
+	ws := setupWorkspace(t)
+	ctx, _, errOut := newTestContext()
+	if code := RunAdd([]string{"write it up"}, ctx); code != 0 {
+		t.Fatalf("RunAdd() exit code = %d, stderr: %s", code, errOut.String())
+	}
+	note := "# Plan\n\n- **ship** it\n"
+	for _, args := range [][]string{
+		{"note", "--id", "1", "--message", note},
+		{"link", "--id", "1", "--url", "https://example.com"},
+	} {
+		ctx, _, errOut = newTestContext()
+		if code := RunAttach(args, ctx); code != 0 {
+			t.Fatalf("RunAttach(%v) exit code = %d, stderr: %s", args, code, errOut.String())
+		}
+	}
+
+	show := func(args ...string) (string, int) {
+		t.Helper()
+		ctx, out, _ := newTestContext()
+		code := RunShow(append(args, "1"), ctx)
+		return out.String(), code
+	}
+
+	// Not a terminal: the raw note
+	if got, code := show("--note", "1"); code != 0 || got != note {
+		t.Errorf("show --note 1 = %q (exit %d), want the raw note", got, code)
+	}
+
+	fakeTerminal(t)
+	t.Setenv("PAGER", "")
+	got, code := show("--note", "1")
+	if code != 0 || !strings.Contains(got, ansiBold+ansiUnderline+"Plan") || !strings.Contains(got, "  • "+ansiBold+"ship"+ansiNormal+" it") {
+		t.Errorf("show --note 1 on a terminal = %q (exit %d), want styled Markdown", got, code)
+	}
+	if got, _ := show("--note", "1", "--raw"); got != note {
+		t.Errorf("show --note 1 --raw = %q, want the raw note", got)
+	}
+	writeConfig(t, ws, "render_markdown = false\n")
+	if got, _ := show("--note", "1"); got != note {
+		t.Errorf("show --note 1 with render_markdown = false = %q, want the raw note", got)
+	}
+
+	if _, code := show("--note", "2"); code != 1 {
+		t.Errorf("show --note on a link exit code = %d, want 1", code)
+	}
+	if _, code := show("--raw"); code != 2 {
+		t.Errorf("show --raw without --note exit code = %d, want 2", code)
+	}
+}
@@ -0,0 +1,151 @@
+package commands
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/sjatkinson/threadkeeper/internal/config"
+	"github.com/sjatkinson/threadkeeper/internal/date"
+	"github.com/sjatkinson/threadkeeper/internal/task"
+)
+
+func RunSnooze(args []string, ctx CommandContext) int {
+	fs := flag.NewFlagSet(ctx.AppName+" snooze", flag.ContinueOnError)
+	fs.SetOutput(ctx.Err)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintln(ctx.Err, snoozeUsage(ctx.AppName))
+	}
+
+	if err := fs.Parse(args); err != nil {
+		_, _ = fmt.Fprintln(ctx.Err)
+		_, _ = fmt.Fprintln(ctx.Err, snoozeUsage(ctx.AppName))
+		return 2
+	}
+
+	rest := fs.Args()
+	if len(rest) < 2 {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: missing argument: task ID and date required\n")
+		_, _ = fmt.Fprintln(ctx.Err, snoozeUsage(ctx.AppName))
+		return 2
+	}
+	ids, spec := rest[:len(rest)-1], rest[len(rest)-1]
+
+	// Get paths and verify threads directory exists
+	paths, err := config.GetPaths(ctx.workspace())
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+
+	if _, err := os.Stat(paths.ThreadsDir); err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: threads directory does not exist at %s. Run '%s init' first.\n", paths.ThreadsDir, ctx.AppName)
+		return 1
+	}
+
+	locale, err := config.LoadDateLocale()
+	if err != nil {
+		locale = config.DateLocaleISO // Default on error
+	}
+	tz, err := config.LoadTimezone()
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+
+	// Resolve every task and its new due date before saving any
+	st := newStore(paths)
+	var tasks []*task.Task
+	var dues []time.Time
+	for _, idStr := range ids {
+		t, err := st.ResolveID(idStr)
+		if err != nil {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+			return 1
+		}
+		due, err := snoozeDue(t, spec, locale, ctx.clock(), tz)
+		if err != nil {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+			return 2
+		}
+		tasks = append(tasks, t)
+		dues = append(dues, due)
+	}
+
+	dateLayout, err := config.LoadDisplayDateFormat()
+	if err != nil {
+		dateLayout = config.DisplayLayoutISO // Default on error
+	}
+
+	now := ctx.clock().Now().UTC()
+	rec := newOpRecorder("snooze")
+	defer rec.commit(st, paths, ctx)
+	for i, t := range tasks {
+		snap := snapshotTask(t)
+		due := dues[i]
+		t.DueAt = &due
+		t.UpdatedAt = now
+
+		if err := st.Save(t); err != nil {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: failed to save task %s: %v\n", t.ID, err)
+			return 1
+		}
+		rec.add(t.ID, snap)
+
+		sidStr := "?"
+		if t.ShortID != nil {
+			sidStr = fmt.Sprintf("%d", *t.ShortID)
+		}
+		ctx.Success("Snoozed task %s (%s) until %s\n", sidStr, t.ID, due.Format(dateLayout))
+	}
+
+	return 0
+}
+
+// snoozeDue resolves spec to t's new due date. Shortcuts such as +3 or
+// monday count from the task's due date, or from today when the task has no
+// due date or is already overdue, so snoozing always moves forward from
+// where the task stands.
+func snoozeDue(t *task.Task, spec string, locale config.DateLocale, clock date.Clock, tz *time.Location) (time.Time, error) {
+	now := clock.Now().In(tz)
+	base := time.Date(now.Year(), now.Month(), now.Day(), 12, 0, 0, 0, tz)
+	if t.DueAt != nil {
+		// due_at holds a calendar date at midnight UTC
+		d := t.DueAt.UTC()
+		if due := time.Date(d.Year(), d.Month(), d.Day(), 12, 0, 0, 0, tz); due.After(base) {
+			base = due
+		}
+	}
+
+	canonical, err := date.ParseDate(spec, locale, date.FixedClock{FixedTime: base}, tz)
+	if err != nil {
+		return time.Time{}, err
+	}
+	parsed, err := time.Parse(dueDateLayout, canonical)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse canonical date: %w", err)
+	}
+	return parsed, nil
+}
+
+func snoozeUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s snooze <id> [<id> ...] <date>
+
+Moves each task's due date to <date>. Shortcuts count from the task's
+current due date, or from today if it has none or is overdue: +3 pushes a
+task due Friday to Monday. Absolute dates set the due date as given.
+
+Date shortcuts:
+  +N                  N days later
+  tomorrow            the next day
+  monday, fri, ...    the next such weekday
+  eow                 the coming Sunday
+
+Examples:
+  %s snooze 3 +7
+  %s snooze 3 5 monday
+
+`, app, app, app)
+}
@@ -0,0 +1,62 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sjatkinson/threadkeeper/internal/config"
+)
+
+func TestRunSnooze(t *testing.T) {
+	setupWorkspace(t)
+
+	clock := testClock()
+	for _, args := range [][]string{
+		{"--due", "2026-03-13", "future"},
+		{"--due", "2026-03-01", "overdue"},
+		{"undated"},
+	} {
+		ctx, _, errOut := newTestContext()
+		ctx.Clock = clock
+		if code := RunAdd(args, ctx); code != 0 {
+			t.Fatalf("RunAdd(%v) exit code = %d, stderr: %s", args, code, errOut.String())
+		}
+	}
+
+	ctx, out, errOut := newTestContext()
+	ctx.Clock = clock
+	if code := RunSnooze([]string{"1", "2", "3", "+3"}, ctx); code != 0 {
+		t.Fatalf("RunSnooze() exit code = %d, stderr: %s", code, errOut.String())
+	}
+	if !strings.Contains(out.String(), "until 2026-03-16") {
+		t.Errorf("stdout = %q, want the new due date reported", out.String())
+	}
+
+	paths, err := config.GetPaths("")
+	if err != nil {
+		t.Fatalf("GetPaths() error = %v", err)
+	}
+	st := newStore(paths)
+	want := map[string]string{"1": "2026-03-16", "2": "2026-03-13", "3": "2026-03-13"}
+	for id, wantDue := range want {
+		tk, err := st.ResolveID(id)
+		if err != nil {
+			t.Fatalf("ResolveID(%s) error = %v", id, err)
+		}
+		if tk.DueAt == nil || tk.DueAt.Format(dueDateLayout) != wantDue {
+			t.Errorf("task %s (%s) due = %v, want %s", id, tk.Title, tk.DueAt, wantDue)
+		}
+		if !tk.UpdatedAt.Equal(clock.FixedTime) {
+			t.Errorf("task %s updated_at = %v, want %v", id, tk.UpdatedAt, clock.FixedTime)
+		}
+	}
+
+	ctx, _, _ = newTestContext()
+	if code := RunSnooze([]string{"1", "someday"}, ctx); code != 2 {
+		t.Errorf("RunSnooze(bad date) exit code = %d, want 2", code)
+	}
+	ctx, _, _ = newTestContext()
+	if code := RunSnooze([]string{"+1"}, ctx); code != 2 {
+		t.Errorf("RunSnooze(no id) exit code = %d, want 2", code)
+	}
+}
@@ -0,0 +1,237 @@
+package commands
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sjatkinson/threadkeeper/internal/config"
+	"github.com/sjatkinson/threadkeeper/internal/date"
+	"github.com/sjatkinson/threadkeeper/internal/task"
+)
+
+// Dimensions accepted by stats --count-by.
+const (
+	countByProject = "project"
+	countByTag     = "tag"
+	countByStatus  = "status"
+	countByWeek    = "week"
+)
+
+// noneLabel is the bucket for tasks with no project or no tags.
+const noneLabel = "(none)"
+
+// histogramWidth is the length of the longest bar in text output.
+const histogramWidth = 40
+
+// statBucket is one row of a count-by histogram.
+type statBucket struct {
+	Label string `json:"label"`
+	Count int    `json:"count"`
+}
+
+func RunStats(args []string, ctx CommandContext) int {
+	fs := flag.NewFlagSet(ctx.AppName+" stats", flag.ContinueOnError)
+	fs.SetOutput(ctx.Err)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintln(ctx.Err, statsUsage(ctx.AppName))
+	}
+
+	var (
+		countBy string
+		project string
+		status  string
+		tags    stringList
+		asJSON  bool
+		summary bool
+	)
+	fs.StringVar(&countBy, "count-by", countByStatus, "dimension to count by (project|tag|status|week)")
+	fs.StringVar(&project, "project", "", "filter by project")
+	fs.StringVar(&project, "p", "", "filter by project (shorthand)")
+	fs.StringVar(&status, "status", "", "filter by status (open|done|archived)")
+	fs.Var(&tags, "tag", "filter by tag (repeatable; all must match)")
+	fs.BoolVar(&asJSON, "json", false, "print JSON instead of a histogram")
+	fs.BoolVar(&summary, "summary", false, "add a count of open, overdue and due-this-week tasks")
+
+	if err := fs.Parse(args); err != nil {
+		_, _ = fmt.Fprintln(ctx.Err)
+		_, _ = fmt.Fprintln(ctx.Err, statsUsage(ctx.AppName))
+		return 2
+	}
+
+	if len(fs.Args()) != 0 {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: unexpected arguments\n")
+		_, _ = fmt.Fprintln(ctx.Err, statsUsage(ctx.AppName))
+		return 2
+	}
+
+	switch countBy {
+	case countByProject, countByTag, countByStatus, countByWeek:
+	default:
+		_, _ = fmt.Fprintf(ctx.Err, "Error: invalid --count-by %q (must be project, tag, status, or week)\n", countBy)
+		return 2
+	}
+
+	// Get paths and verify threads directory exists
+	paths, err := config.GetPaths(ctx.workspace())
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+
+	if _, err := os.Stat(paths.ThreadsDir); err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: threads directory does not exist at %s. Run '%s init' first.\n", paths.ThreadsDir, ctx.AppName)
+		return 1
+	}
+
+	st := newStore(paths)
+	tasks, err := st.LoadAll()
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+
+	// Stats cover every status unless --status narrows them
+	filtered := filterTasks(tasks, taskFilter{All: true, Status: status, Project: project, Tags: tags})
+	buckets := countTasksBy(filtered, countBy)
+
+	var due dueSummary
+	if summary {
+		tz, err := config.LoadTimezone()
+		if err != nil {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+			return 1
+		}
+		due = summarizeDue(filtered, date.Today(ctx.clock(), tz))
+	}
+
+	if asJSON {
+		var v any = buckets
+		if summary {
+			// The summary sits beside the buckets rather than among them
+			v = struct {
+				Buckets []statBucket `json:"buckets"`
+				Summary dueSummary   `json:"summary"`
+			}{buckets, due}
+		}
+		enc := json.NewEncoder(ctx.Out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(v); err != nil {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	if len(buckets) == 0 {
+		_, _ = fmt.Fprintln(ctx.Out, "No tasks found.")
+		return 0
+	}
+
+	displayHistogram(ctx.Out, buckets)
+
+	// Total tracked time across the same tasks, including running timers
+	now := ctx.clock().Now()
+	var spent time.Duration
+	for _, t := range filtered {
+		spent += t.TotalTimeSpent(now)
+	}
+	if spent > 0 {
+		_, _ = fmt.Fprintf(ctx.Out, "\nTime spent: %s\n", spent)
+	}
+	if summary {
+		_, _ = fmt.Fprintf(ctx.Out, "\n%s\n", due)
+	}
+	return 0
+}
+
+// countTasksBy aggregates tasks into label -> count buckets for the given
+// dimension. A task with several tags counts once per tag. Weeks are sorted
+// chronologically; other dimensions by count (descending) then label.
+func countTasksBy(tasks []*task.Task, dimension string) []statBucket {
+	counts := make(map[string]int)
+	for _, t := range tasks {
+		for _, label := range taskLabels(t, dimension) {
+			counts[label]++
+		}
+	}
+
+	buckets := make([]statBucket, 0, len(counts))
+	for label, count := range counts {
+		buckets = append(buckets, statBucket{Label: label, Count: count})
+	}
+
+	sort.Slice(buckets, func(i, j int) bool {
+		if dimension != countByWeek && buckets[i].Count != buckets[j].Count {
+			return buckets[i].Count > buckets[j].Count
+		}
+		return buckets[i].Label < buckets[j].Label
+	})
+	return buckets
+}
+
+// taskLabels returns the bucket labels a task contributes to.
+func taskLabels(t *task.Task, dimension string) []string {
+	switch dimension {
+	case countByProject:
+		if t.Project == "" {
+			return []string{noneLabel}
+		}
+		return []string{t.Project}
+	case countByTag:
+		if len(t.Tags) == 0 {
+			return []string{noneLabel}
+		}
+		return t.Tags
+	case countByWeek:
+		year, week := t.UpdatedAt.ISOWeek()
+		return []string{fmt.Sprintf("%d-W%02d", year, week)}
+	default:
+		return []string{string(t.Status)}
+	}
+}
+
+// displayHistogram prints one "label  count  bar" line per bucket, with bars
+// scaled so the largest count spans histogramWidth characters.
+func displayHistogram(out io.Writer, buckets []statBucket) {
+	labelWidth, maxCount := 0, 0
+	for _, b := range buckets {
+		labelWidth = max(labelWidth, len(b.Label))
+		maxCount = max(maxCount, b.Count)
+	}
+
+	for _, b := range buckets {
+		bar := max(1, b.Count*histogramWidth/maxCount)
+		_, _ = fmt.Fprintf(out, "%-*s  %4d  %s\n", labelWidth, b.Label, b.Count, strings.Repeat("#", bar))
+	}
+}
+
+func statsUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s stats [flags]
+
+Counts tasks along one dimension and prints a histogram. Covers tasks of
+every status unless --status is given.
+
+Flags:
+  --count-by <dim>            project, tag, status (default), or week
+                              (ISO week of last update)
+  -p, --project <name>        filter by project
+  --status <open|done|archived> filter by status
+  --tag <tag>                 filter by tag (repeat to AND tags)
+  --json                      print [{"label": ..., "count": ...}] as JSON
+  --summary                   add "N open, M overdue, K due this week" for
+                              the counted tasks; with --json, print
+                              {"buckets": [...], "summary": {"open": ...,
+                              "overdue": ..., "due_this_week": ...}}
+
+Text output ends with the total time spent on the counted tasks (see
+'%s start'), if any.
+
+`, app, app)
+}
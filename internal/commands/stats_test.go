@@ -0,0 +1,44 @@
+package commands
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sjatkinson/threadkeeper/internal/task"
+)
+
+func statsFixture() []*task.Task {
+	mon := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)   // 2026-W02
+	next := time.Date(2026, 1, 12, 9, 0, 0, 0, time.UTC) // 2026-W03
+	return []*task.Task{
+		{ID: "A", Status: task.StatusOpen, Project: "web", UpdatedAt: mon, Tags: []string{"ui"}},
+		{ID: "B", Status: task.StatusOpen, Project: "web", UpdatedAt: next, Tags: []string{"ui", "bug"}},
+		{ID: "C", Status: task.StatusDone, Project: "api", UpdatedAt: mon, Tags: []string{}},
+		{ID: "D", Status: task.StatusArchived, UpdatedAt: next, Tags: []string{}},
+		{ID: "E", Status: task.StatusOpen, Project: "api", UpdatedAt: next, Tags: []string{}},
+		{ID: "F", Status: task.StatusOpen, Project: "web", UpdatedAt: mon, Tags: []string{}},
+	}
+}
+
+func TestCountTasksBy(t *testing.T) {
+	tests := []struct {
+		name      string
+		dimension string
+		want      []statBucket
+	}{
+		{"project", countByProject, []statBucket{{"web", 3}, {"api", 2}, {noneLabel, 1}}},
+		{"status", countByStatus, []statBucket{{"open", 4}, {"archived", 1}, {"done", 1}}},
+		{"tag", countByTag, []statBucket{{noneLabel, 4}, {"ui", 2}, {"bug", 1}}},
+		{"week", countByWeek, []statBucket{{"2026-W02", 3}, {"2026-W03", 3}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := countTasksBy(statsFixture(), tt.dimension)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("countTasksBy(%q) = %v, want %v", tt.dimension, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package commands
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/sjatkinson/threadkeeper/internal/config"
+	"github.com/sjatkinson/threadkeeper/internal/store"
+	"github.com/sjatkinson/threadkeeper/internal/task"
+)
+
+func RunTag(args []string, ctx CommandContext) int {
+	if len(args) == 0 {
+		_, _ = fmt.Fprintln(ctx.Err, tagUsage(ctx.AppName))
+		return 2
+	}
+
+	switch args[0] {
+	case "rename":
+		return runTagRename(args[1:], ctx)
+	default:
+		_, _ = fmt.Fprintf(ctx.Err, "Error: invalid tag subcommand %q (must be 'rename')\n", args[0])
+		_, _ = fmt.Fprintln(ctx.Err)
+		_, _ = fmt.Fprintln(ctx.Err, tagUsage(ctx.AppName))
+		return 2
+	}
+}
+
+func runTagRename(args []string, ctx CommandContext) int {
+	fs := flag.NewFlagSet(ctx.AppName+" tag rename", flag.ContinueOnError)
+	fs.SetOutput(ctx.Err)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintln(ctx.Err, tagUsage(ctx.AppName))
+	}
+
+	var dryRun bool
+	fs.BoolVar(&dryRun, "dry-run", false, "report how many tasks would change without saving")
+
+	if err := fs.Parse(args); err != nil {
+		_, _ = fmt.Fprintln(ctx.Err)
+		_, _ = fmt.Fprintln(ctx.Err, tagUsage(ctx.AppName))
+		return 2
+	}
+
+	rest := fs.Args()
+	if len(rest) != 2 {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: expected <old> and <new> tag names\n")
+		_, _ = fmt.Fprintln(ctx.Err, tagUsage(ctx.AppName))
+		return 2
+	}
+
+	oldTags := task.NormalizeTags(rest[:1])
+	newTags := task.NormalizeTags(rest[1:])
+	if len(oldTags) == 0 || len(newTags) == 0 {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: tag names must not be empty\n")
+		return 2
+	}
+	if oldTags[0] == newTags[0] {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: old and new tags are the same (%q)\n", oldTags[0])
+		return 2
+	}
+
+	// Get paths and verify threads directory exists
+	paths, err := config.GetPaths(ctx.workspace())
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+
+	if _, err := os.Stat(paths.ThreadsDir); err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: threads directory does not exist at %s. Run '%s init' first.\n", paths.ThreadsDir, ctx.AppName)
+		return 1
+	}
+
+	st := newStore(paths)
+	var changed int
+	if err := st.WithLock(func() error {
+		var err error
+		changed, err = retagTasks(st, oldTags[0], newTags[0], dryRun, ctx)
+		return err
+	}); err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+
+	if dryRun {
+		_, _ = fmt.Fprintf(ctx.Out, "Would rename tag %q to %q on %d tasks\n", oldTags[0], newTags[0], changed)
+		return 0
+	}
+	_, _ = fmt.Fprintf(ctx.Out, "Renamed tag %q to %q on %d tasks\n", oldTags[0], newTags[0], changed)
+	return 0
+}
+
+// retagTasks replaces oldTag with newTag on every task carrying it and saves
+// the changed tasks, unless dryRun is set. Returns the number of tasks
+// changed (or that would change). Callers must hold the workspace lock.
+func retagTasks(st *store.FileStore, oldTag, newTag string, dryRun bool, ctx CommandContext) (int, error) {
+	tasks, err := st.LoadAll()
+	if err != nil {
+		return 0, err
+	}
+
+	now := ctx.clock().Now().UTC()
+	changed := 0
+	for _, t := range tasks {
+		if !hasTag(t.Tags, oldTag) {
+			continue
+		}
+		newTags, tagsChanged := applyTagChanges(t.Tags, []string{newTag}, []string{oldTag})
+		if !tagsChanged {
+			continue
+		}
+		changed++
+		if dryRun {
+			continue
+		}
+
+		t.Tags = newTags
+		t.UpdatedAt = now
+		if err := st.Save(t); err != nil {
+			return changed - 1, fmt.Errorf("failed to save task %s: %w", t.ID, err)
+		}
+	}
+	return changed, nil
+}
+
+func tagUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s tag rename [--dry-run] <old> <new>
+
+Replaces tag <old> with <new> on every task that has it, merging with
+<new> where a task already has both. Tags are normalized first.
+
+Flags:
+  --dry-run      report how many tasks would change without saving
+
+`, app)
+}
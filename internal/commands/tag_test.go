@@ -0,0 +1,64 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sjatkinson/threadkeeper/internal/config"
+)
+
+func TestRunTagRename(t *testing.T) {
+	setupWorkspace(t)
+
+	for _, args := range [][]string{
+		{"--tag", "wip", "one"},
+		{"--tag", "wip", "--tag", "in-progress", "two"},
+		{"--tag", "other", "three"},
+	} {
+		ctx, _, errOut := newTestContext()
+		if code := RunAdd(args, ctx); code != 0 {
+			t.Fatalf("RunAdd() exit code = %d, stderr: %s", code, errOut.String())
+		}
+	}
+
+	tagsByTitle := func() map[string]string {
+		paths, err := config.GetPaths("")
+		if err != nil {
+			t.Fatalf("GetPaths() error = %v", err)
+		}
+		tasks, err := newStore(paths).LoadAll()
+		if err != nil {
+			t.Fatalf("LoadAll() error = %v", err)
+		}
+		m := make(map[string]string)
+		for _, tk := range tasks {
+			m[tk.Title] = strings.Join(tk.Tags, ",")
+		}
+		return m
+	}
+	before := tagsByTitle()
+
+	// Dry run reports but writes nothing
+	ctx, out, errOut := newTestContext()
+	if code := RunTag([]string{"rename", "--dry-run", "WIP", "in-progress"}, ctx); code != 0 {
+		t.Fatalf("RunTag() exit code = %d, stderr: %s", code, errOut.String())
+	}
+	if !strings.Contains(out.String(), "on 2 tasks") {
+		t.Errorf("dry run output = %q, want it to report 2 tasks", out.String())
+	}
+	if got := tagsByTitle(); got["one"] != before["one"] || got["two"] != before["two"] {
+		t.Errorf("dry run changed tags: %v", got)
+	}
+
+	ctx, _, errOut = newTestContext()
+	if code := RunTag([]string{"rename", "wip", "in-progress"}, ctx); code != 0 {
+		t.Fatalf("RunTag() exit code = %d, stderr: %s", code, errOut.String())
+	}
+	got := tagsByTitle()
+	want := map[string]string{"one": "in-progress", "two": "in-progress", "three": "other"}
+	for title, tags := range want {
+		if got[title] != tags {
+			t.Errorf("tags on %q = %q, want %q", title, got[title], tags)
+		}
+	}
+}
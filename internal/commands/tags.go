@@ -0,0 +1,181 @@
+package commands
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/sjatkinson/threadkeeper/internal/config"
+	"github.com/sjatkinson/threadkeeper/internal/task"
+)
+
+// tagCount is the number of tasks carrying a tag.
+type tagCount struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+func RunTags(args []string, ctx CommandContext) int {
+	fs := flag.NewFlagSet(ctx.AppName+" tags", flag.ContinueOnError)
+	fs.SetOutput(ctx.Err)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintln(ctx.Err, tagsUsage(ctx.AppName))
+	}
+
+	var (
+		openOnly bool
+		asJSON   bool
+	)
+	fs.BoolVar(&openOnly, "open", false, "only count open tasks")
+	fs.BoolVar(&asJSON, "json", false, "print JSON instead of a table")
+
+	if err := fs.Parse(args); err != nil {
+		_, _ = fmt.Fprintln(ctx.Err)
+		_, _ = fmt.Fprintln(ctx.Err, tagsUsage(ctx.AppName))
+		return 2
+	}
+
+	if len(fs.Args()) != 0 {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: unexpected arguments\n")
+		_, _ = fmt.Fprintln(ctx.Err, tagsUsage(ctx.AppName))
+		return 2
+	}
+
+	// Get paths and verify threads directory exists
+	paths, err := config.GetPaths(ctx.workspace())
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+
+	if _, err := os.Stat(paths.ThreadsDir); err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: threads directory does not exist at %s. Run '%s init' first.\n", paths.ThreadsDir, ctx.AppName)
+		return 1
+	}
+
+	st := newStore(paths)
+	tasks, err := st.LoadAll()
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+
+	counts := countTags(filterTasks(tasks, taskFilter{All: !openOnly}))
+
+	if asJSON {
+		enc := json.NewEncoder(ctx.Out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(counts); err != nil {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	if len(counts) == 0 {
+		_, _ = fmt.Fprintln(ctx.Out, "No tags found.")
+		return 0
+	}
+
+	displayTagCounts(ctx.Out, counts)
+	return 0
+}
+
+// countTags counts tasks per distinct normalized tag, sorted by count
+// (descending) then tag.
+func countTags(tasks []*task.Task) []tagCount {
+	byTag := make(map[string]int)
+	for _, t := range tasks {
+		for _, tag := range task.NormalizeTags(t.Tags) {
+			byTag[tag]++
+		}
+	}
+
+	counts := make([]tagCount, 0, len(byTag))
+	for tag, n := range byTag {
+		counts = append(counts, tagCount{Tag: tag, Count: n})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Tag < counts[j].Tag
+	})
+	return counts
+}
+
+// knownTags returns every distinct normalized tag used by tasks, sorted.
+func knownTags(tasks []*task.Task) []string {
+	counts := countTags(tasks)
+	tags := make([]string, 0, len(counts))
+	for _, c := range counts {
+		tags = append(tags, c.Tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// suggestTag returns the known tag closest to tag by edit distance, or ""
+// if tag is itself known or nothing is close enough to be a likely typo:
+// at most two edits, and fewer edits than tag has characters.
+func suggestTag(tag string, known []string) string {
+	best, bestDist := "", 3
+	for _, k := range known {
+		if k == tag {
+			return ""
+		}
+		if d := levenshtein(tag, k); d < bestDist && d < len([]rune(tag)) {
+			best, bestDist = k, d
+		}
+	}
+	return best
+}
+
+// levenshtein returns the number of single-rune insertions, deletions and
+// substitutions needed to turn a into b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+func displayTagCounts(out io.Writer, counts []tagCount) {
+	width := 0
+	for _, c := range counts {
+		width = max(width, len(c.Tag))
+	}
+	for _, c := range counts {
+		_, _ = fmt.Fprintf(out, "%-*s  %4d\n", width, c.Tag, c.Count)
+	}
+}
+
+func tagsUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s tags [--open] [--json]
+
+Lists every tag with the number of tasks using it, most used first.
+
+Flags:
+  --open         only count open tasks (default: all tasks)
+  --json         print [{"tag": ..., "count": ...}] as JSON
+
+`, app)
+}
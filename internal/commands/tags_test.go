@@ -0,0 +1,64 @@
+package commands
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sjatkinson/threadkeeper/internal/task"
+)
+
+func TestCountTags(t *testing.T) {
+	tasks := []*task.Task{
+		{ID: "a", Tags: []string{"bug", "ui"}},
+		{ID: "b", Tags: []string{"Bug", "bugs"}},
+		{ID: "c", Tags: []string{"ui"}},
+		{ID: "d", Tags: []string{}},
+	}
+
+	got := countTags(tasks)
+	want := []tagCount{{"bug", 2}, {"ui", 2}, {"bugs", 1}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("countTags() = %v, want %v", got, want)
+	}
+}
+
+func TestSuggestTag(t *testing.T) {
+	known := []string{"bug", "docs", "food", "sprint:q3"}
+	tests := []struct {
+		tag  string
+		want string
+	}{
+		{"foo", "food"},
+		{"dcos", "docs"},
+		{"sprint:q4", "sprint:q3"},
+		{"bug", ""},      // exists
+		{"xy", ""},       // two edits from "bug" is the whole tag
+		{"frontend", ""}, // nothing close
+	}
+	for _, tt := range tests {
+		if got := suggestTag(tt.tag, known); got != tt.want {
+			t.Errorf("suggestTag(%q) = %q, want %q", tt.tag, got, tt.want)
+		}
+	}
+}
+
+func TestRunList_SuggestsTag(t *testing.T) {
+	setupWorkspace(t)
+	addAndLoad(t, []string{"--tag", "food", "lunch"})
+
+	ctx, out, _ := newTestContext()
+	if code := RunList([]string{"--tag", "foo"}, ctx); code != 0 {
+		t.Fatalf("RunList() exit code = %d", code)
+	}
+	if got, want := out.String(), "No tasks found. Did you mean --tag food?\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+
+	ctx, out, _ = newTestContext()
+	if code := RunList([]string{"--tag", "unrelated"}, ctx); code != 0 {
+		t.Fatalf("RunList() exit code = %d", code)
+	}
+	if got, want := out.String(), "No tasks found.\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,124 @@
+package commands
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/sjatkinson/threadkeeper/internal/config"
+	"github.com/sjatkinson/threadkeeper/internal/task"
+)
+
+func RunStart(args []string, ctx CommandContext) int {
+	return runTimer("start", args, ctx)
+}
+
+func RunStop(args []string, ctx CommandContext) int {
+	return runTimer("stop", args, ctx)
+}
+
+// runTimer starts or stops the timer on a single task. Both share
+// everything but the change itself and the message.
+func runTimer(name string, args []string, ctx CommandContext) int {
+	usage := startUsage
+	if name == "stop" {
+		usage = stopUsage
+	}
+	fs := flag.NewFlagSet(ctx.AppName+" "+name, flag.ContinueOnError)
+	fs.SetOutput(ctx.Err)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintln(ctx.Err, usage(ctx.AppName))
+	}
+
+	if err := fs.Parse(args); err != nil {
+		_, _ = fmt.Fprintln(ctx.Err)
+		_, _ = fmt.Fprintln(ctx.Err, usage(ctx.AppName))
+		return 2
+	}
+
+	rest := fs.Args()
+	if len(rest) != 1 {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: missing argument: task ID required\n")
+		_, _ = fmt.Fprintln(ctx.Err, usage(ctx.AppName))
+		return 2
+	}
+
+	// Get paths and verify threads directory exists
+	paths, err := config.GetPaths(ctx.workspace())
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+
+	if _, err := os.Stat(paths.ThreadsDir); err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: threads directory does not exist at %s. Run '%s init' first.\n", paths.ThreadsDir, ctx.AppName)
+		return 1
+	}
+
+	st := newStore(paths)
+	t, err := st.ResolveID(rest[0])
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+
+	sidStr := "?"
+	if t.ShortID != nil {
+		sidStr = fmt.Sprintf("%d", *t.ShortID)
+	}
+
+	now := ctx.clock().Now().UTC()
+	snap := snapshotTask(t)
+	var msg string
+	if name == "start" {
+		if t.Status != task.StatusOpen {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: task %s (%s) is %s; only open tasks can be timed\n", sidStr, t.ID, t.Status)
+			return 1
+		}
+		if !t.StartTimer(now) {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: timer for task %s (%s) is already running since %s\n", sidStr, t.ID, t.StartedAt.Format("15:04Z"))
+			return 1
+		}
+		msg = fmt.Sprintf("Started timer for task %s (%s)\n", sidStr, t.ID)
+	} else {
+		elapsed, ok := t.StopTimer(now)
+		if !ok {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: no timer running for task %s (%s)\n", sidStr, t.ID)
+			return 1
+		}
+		msg = fmt.Sprintf("Stopped timer for task %s (%s): %s, %s in total\n", sidStr, t.ID, elapsed, t.TimeSpent)
+	}
+	t.UpdatedAt = now
+
+	rec := newOpRecorder(name)
+	defer rec.commit(st, paths, ctx)
+	if err := st.Save(t); err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: failed to save task %s: %v\n", t.ID, err)
+		return 1
+	}
+	rec.add(t.ID, snap)
+
+	ctx.Success("%s", msg)
+	return 0
+}
+
+func startUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s start <id>
+
+Starts a timer on an open task. '%s stop' adds the elapsed time to the
+task's time spent; done and archive stop a running timer too. 'show
+--full' and 'stats' report the totals.
+
+`, app, app)
+}
+
+func stopUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s stop <id>
+
+Stops the task's running timer and adds the elapsed time, to the second,
+to its time spent.
+
+`, app)
+}
@@ -0,0 +1,130 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sjatkinson/threadkeeper/internal/date"
+)
+
+func TestRunStartStop(t *testing.T) {
+	setupWorkspace(t)
+	tk := addAndLoad(t, []string{"timed"})
+	start := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+
+	run := func(fn func([]string, CommandContext) int, at time.Time, wantCode int) string {
+		t.Helper()
+		ctx, out, errOut := newTestContext()
+		ctx.Clock = date.FixedClock{FixedTime: at}
+		if code := fn([]string{tk.ID}, ctx); code != wantCode {
+			t.Fatalf("exit code = %d, want %d, stderr: %s", code, wantCode, errOut.String())
+		}
+		return out.String()
+	}
+
+	run(RunStop, start, 1) // nothing running
+	run(RunStart, start, 0)
+	run(RunStart, start, 1) // already running
+	if got := loadOnlyTask(t).StartedAt; got == nil || !got.Equal(start) {
+		t.Fatalf("StartedAt = %v, want %v", got, start)
+	}
+	if out := run(RunStop, start.Add(45*time.Minute), 0); !strings.Contains(out, "45m0s") {
+		t.Errorf("stop output = %q, want elapsed time", out)
+	}
+
+	// done stops a running timer
+	run(RunStart, start.Add(time.Hour), 0)
+	run(RunDone, start.Add(90*time.Minute), 0)
+	got := loadOnlyTask(t)
+	if got.StartedAt != nil || got.TimeSpent != 75*time.Minute {
+		t.Errorf("after done StartedAt = %v, TimeSpent = %v; want nil, 1h15m", got.StartedAt, got.TimeSpent)
+	}
+	run(RunStart, start.Add(2*time.Hour), 1) // done tasks can't be timed
+
+	ctx, out, _ := newTestContext()
+	if code := RunShow([]string{"--full", tk.ID}, ctx); code != 0 {
+		t.Fatalf("show --full exit code = %d", code)
+	}
+	if !strings.Contains(out.String(), "Spent  : 1h15m0s") {
+		t.Errorf("show --full = %q, want time spent", out.String())
+	}
+
+	ctx, out, _ = newTestContext()
+	if code := RunStats(nil, ctx); code != 0 {
+		t.Fatalf("stats exit code = %d", code)
+	}
+	if !strings.Contains(out.String(), "Time spent: 1h15m0s") {
+		t.Errorf("stats = %q, want time spent", out.String())
+	}
+}
+
+func TestEstimate(t *testing.T) {
+	setupWorkspace(t)
+
+	ctx, _, _ := newTestContext()
+	if code := RunAdd([]string{"--estimate", "soon", "bad"}, ctx); code != 2 {
+		t.Errorf("add --estimate soon exit code = %d, want 2", code)
+	}
+	ctx, _, _ = newTestContext()
+	if code := RunAdd([]string{"--estimate", "-1h", "bad"}, ctx); code != 2 {
+		t.Errorf("add --estimate -1h exit code = %d, want 2", code)
+	}
+
+	tk := addAndLoad(t, []string{"--estimate", "2h", "estimated"})
+	if tk.Estimate != 2*time.Hour {
+		t.Fatalf("Estimate = %v, want 2h", tk.Estimate)
+	}
+
+	ctx, out, _ := newTestContext()
+	RunList(nil, ctx)
+	if !strings.Contains(out.String(), " ~2h") {
+		t.Errorf("list = %q, want ~2h", out.String())
+	}
+
+	ctx, _, errOut := newTestContext()
+	if code := RunUpdate([]string{"--estimate", "1h30m", tk.ID}, ctx); code != 0 {
+		t.Fatalf("update --estimate exit code = %d, stderr: %s", code, errOut.String())
+	}
+	if got := loadOnlyTask(t).Estimate; got != 90*time.Minute {
+		t.Errorf("Estimate after update = %v, want 1h30m", got)
+	}
+
+	// Estimate against time actually spent
+	start := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	for i, fn := range []func([]string, CommandContext) int{RunStart, RunStop} {
+		ctx, _, _ := newTestContext()
+		ctx.Clock = date.FixedClock{FixedTime: start.Add(time.Duration(i) * 45 * time.Minute)}
+		if code := fn([]string{tk.ID}, ctx); code != 0 {
+			t.Fatalf("timer exit code = %d", code)
+		}
+	}
+	ctx, out, _ = newTestContext()
+	RunShow([]string{"--full", tk.ID}, ctx)
+	if !strings.Contains(out.String(), "Estimate: 1h30m0s") || !strings.Contains(out.String(), "Spent  : 45m0s (50% of estimate)") {
+		t.Errorf("show --full = %q, want estimate vs. spent", out.String())
+	}
+
+	ctx, _, _ = newTestContext()
+	if code := RunUpdate([]string{"--clear-estimate", tk.ID}, ctx); code != 0 {
+		t.Fatalf("update --clear-estimate exit code = %d", code)
+	}
+	if got := loadOnlyTask(t).Estimate; got != 0 {
+		t.Errorf("Estimate after clear = %v, want 0", got)
+	}
+}
+
+func TestShortDuration(t *testing.T) {
+	for d, want := range map[time.Duration]string{
+		2 * time.Hour:                "2h",
+		90 * time.Minute:             "1h30m",
+		45 * time.Minute:             "45m",
+		30 * time.Second:             "30s",
+		time.Hour + 30*time.Second:   "1h0m30s",
+		26*time.Hour + 5*time.Minute: "26h5m",
+	} {
+		if got := shortDuration(d); got != want {
+			t.Errorf("shortDuration(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
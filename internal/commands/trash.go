@@ -0,0 +1,172 @@
+package commands
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/sjatkinson/threadkeeper/internal/config"
+	"github.com/sjatkinson/threadkeeper/internal/store"
+	"github.com/sjatkinson/threadkeeper/internal/task"
+)
+
+func RunTrash(args []string, ctx CommandContext) int {
+	if len(args) == 0 {
+		_, _ = fmt.Fprintln(ctx.Err, trashUsage(ctx.AppName))
+		return 2
+	}
+
+	sub := args[0]
+	switch sub {
+	case "list", "restore", "empty":
+	default:
+		_, _ = fmt.Fprintf(ctx.Err, "Error: invalid trash subcommand %q (must be 'list', 'restore' or 'empty')\n", sub)
+		_, _ = fmt.Fprintln(ctx.Err)
+		_, _ = fmt.Fprintln(ctx.Err, trashUsage(ctx.AppName))
+		return 2
+	}
+
+	fs := flag.NewFlagSet(ctx.AppName+" trash "+sub, flag.ContinueOnError)
+	fs.SetOutput(ctx.Err)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintln(ctx.Err, trashUsage(ctx.AppName))
+	}
+
+	var yes bool
+	if sub == "empty" {
+		fs.BoolVar(&yes, "yes", false, "empty without asking")
+		fs.BoolVar(&yes, "y", false, "empty without asking (shorthand)")
+	}
+
+	if err := fs.Parse(args[1:]); err != nil {
+		_, _ = fmt.Fprintln(ctx.Err)
+		_, _ = fmt.Fprintln(ctx.Err, trashUsage(ctx.AppName))
+		return 2
+	}
+
+	rest := fs.Args()
+	if sub == "restore" && len(rest) == 0 {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: missing argument: task ID required\n")
+		return 2
+	}
+	if sub != "restore" && len(rest) != 0 {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: unexpected arguments\n")
+		_, _ = fmt.Fprintln(ctx.Err, trashUsage(ctx.AppName))
+		return 2
+	}
+
+	// Get paths and verify threads directory exists
+	paths, err := config.GetPaths(ctx.workspace())
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+
+	if _, err := os.Stat(paths.ThreadsDir); err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: threads directory does not exist at %s. Run '%s init' first.\n", paths.ThreadsDir, ctx.AppName)
+		return 1
+	}
+
+	st := newStore(paths)
+	switch sub {
+	case "list":
+		return runTrashList(st, ctx)
+	case "restore":
+		return runTrashRestore(st, rest, ctx)
+	default:
+		return runTrashEmpty(st, yes, ctx)
+	}
+}
+
+func runTrashList(st *store.FileStore, ctx CommandContext) int {
+	tasks, err := st.LoadTrash()
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+	if len(tasks) == 0 {
+		_, _ = fmt.Fprintln(ctx.Out, "Trash is empty.")
+		return 0
+	}
+	displayTrash(ctx.Out, tasks)
+	return 0
+}
+
+func runTrashRestore(st *store.FileStore, ids []string, ctx CommandContext) int {
+	hasErrors := false
+	for _, idStr := range ids {
+		trashed, err := st.ResolveTrashID(idStr)
+		if err != nil {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+			hasErrors = true
+			continue
+		}
+
+		t, err := st.Restore(trashed.ID)
+		if err != nil {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+			hasErrors = true
+			continue
+		}
+
+		sidStr := "?"
+		if t.ShortID != nil {
+			sidStr = fmt.Sprintf("%d", *t.ShortID)
+		}
+		ctx.Success("Restored task %s (%s)\n", sidStr, t.ID)
+	}
+
+	if hasErrors {
+		return 1
+	}
+	return 0
+}
+
+func runTrashEmpty(st *store.FileStore, yes bool, ctx CommandContext) int {
+	if !yes && isTerminal(ctx.stdin()) {
+		tasks, err := st.LoadTrash()
+		if err != nil {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+			return 1
+		}
+		if len(tasks) == 0 {
+			_, _ = fmt.Fprintln(ctx.Out, "Trash is empty.")
+			return 0
+		}
+		if !confirm(ctx, fmt.Sprintf("Permanently delete %d trashed tasks?", len(tasks))) {
+			_, _ = fmt.Fprintln(ctx.Err, "Aborted; the trash was not emptied.")
+			return 1
+		}
+	}
+
+	deleted, err := st.EmptyTrash()
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v (deleted %d threads before failing)\n", err, deleted)
+		return 1
+	}
+	ctx.Success("Permanently deleted %d trashed tasks\n", deleted)
+	return 0
+}
+
+func displayTrash(out io.Writer, tasks []*task.Task) {
+	for _, t := range tasks {
+		_, _ = fmt.Fprintf(out, "%s  %-8s  %s\n", t.ID, t.Status, t.Title)
+	}
+}
+
+func trashUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s trash list
+  %s trash restore <id> [<id> ...]
+  %s trash empty [--yes]
+
+Removed tasks are kept in .trash/ under the workspace until the trash is
+emptied. restore takes a durable ID or a unique prefix of one, as shown
+by 'trash list'.
+
+Flags:
+  -y, --yes      empty without asking (prompts only on a terminal)
+
+`, app, app, app)
+}
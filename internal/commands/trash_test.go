@@ -0,0 +1,76 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sjatkinson/threadkeeper/internal/config"
+)
+
+func TestRunTrash(t *testing.T) {
+	tmpDir := setupWorkspace(t)
+	orig := addAndLoad(t, []string{"oops"})
+
+	ctx, _, errOut := newTestContext()
+	if code := RunAttach([]string{"note", "--id", orig.ID, "--message", "keep me"}, ctx); code != 0 {
+		t.Fatalf("RunAttach() exit code = %d, stderr: %s", code, errOut.String())
+	}
+
+	ctx, out, errOut := newTestContext()
+	if code := RunRemove([]string{"--force", orig.ID}, ctx); code != 0 {
+		t.Fatalf("RunRemove() exit code = %d, stderr: %s", code, errOut.String())
+	}
+	if !strings.Contains(out.String(), "to the trash") {
+		t.Errorf("RunRemove() stdout = %q, want trash message", out.String())
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, ".trash", orig.ID, "attachments.jsonl")); err != nil {
+		t.Errorf("trashed attachments missing: %v", err)
+	}
+
+	// Reuse the short_id while the task is in the trash
+	other := addAndLoad(t, []string{"replacement"})
+	if other.ShortID == nil || *other.ShortID != *orig.ShortID {
+		t.Fatalf("replacement short_id = %v, want %d reused", other.ShortID, *orig.ShortID)
+	}
+
+	ctx, out, _ = newTestContext()
+	if code := RunTrash([]string{"list"}, ctx); code != 0 || !strings.Contains(out.String(), orig.ID) {
+		t.Errorf("trash list = %d %q, want %s listed", code, out.String(), orig.ID)
+	}
+
+	ctx, _, errOut = newTestContext()
+	if code := RunTrash([]string{"restore", strings.ToLower(orig.ID[:10])}, ctx); code != 0 {
+		t.Fatalf("trash restore exit code = %d, stderr: %s", code, errOut.String())
+	}
+	if got := openShortIDs(t); len(got) != 2 || got[0] == got[1] {
+		t.Errorf("open short_ids after restore = %v, want two distinct", got)
+	}
+	paths, err := config.GetPaths("")
+	if err != nil {
+		t.Fatalf("GetPaths() error = %v", err)
+	}
+	events, err := loadAttachments(newStore(paths).ThreadDir(orig.ID))
+	if err != nil || len(events) != 1 {
+		t.Errorf("restored attachments = %v, err = %v, want 1", events, err)
+	}
+
+	// Trash something again, then empty without a prompt
+	ctx, _, _ = newTestContext()
+	if code := RunRemove([]string{"--force", orig.ID}, ctx); code != 0 {
+		t.Fatalf("RunRemove() exit code = %d", code)
+	}
+	ctx, out, errOut = newTestContext()
+	ctx.Stdin = strings.NewReader("")
+	if code := RunTrash([]string{"empty"}, ctx); code != 0 {
+		t.Fatalf("trash empty exit code = %d, stderr: %s", code, errOut.String())
+	}
+	if !strings.Contains(out.String(), "deleted 1") {
+		t.Errorf("trash empty stdout = %q, want 1 deleted", out.String())
+	}
+	ctx, out, _ = newTestContext()
+	if RunTrash([]string{"list"}, ctx); !strings.Contains(out.String(), "empty") {
+		t.Errorf("trash list after empty = %q, want empty", out.String())
+	}
+}
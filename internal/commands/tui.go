@@ -0,0 +1,307 @@
+package commands
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sjatkinson/threadkeeper/internal/config"
+)
+
+func RunTUI(args []string, ctx CommandContext) int {
+	fs := flag.NewFlagSet(ctx.AppName+" tui", flag.ContinueOnError)
+	fs.SetOutput(ctx.Err)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintln(ctx.Err, tuiUsage(ctx.AppName))
+	}
+
+	if err := fs.Parse(args); err != nil {
+		_, _ = fmt.Fprintln(ctx.Err)
+		_, _ = fmt.Fprintln(ctx.Err, tuiUsage(ctx.AppName))
+		return 2
+	}
+
+	if len(fs.Args()) != 0 {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: unexpected arguments\n")
+		_, _ = fmt.Fprintln(ctx.Err, tuiUsage(ctx.AppName))
+		return 2
+	}
+
+	// Get paths and verify threads directory exists
+	paths, err := config.GetPaths(ctx.workspace())
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+
+	if _, err := os.Stat(paths.ThreadsDir); err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: threads directory does not exist at %s. Run '%s init' first.\n", paths.ThreadsDir, ctx.AppName)
+		return 1
+	}
+
+	dateLayout, err := config.LoadDisplayDateFormat()
+	if err != nil {
+		dateLayout = config.DisplayLayoutISO // Default on error
+	}
+
+	s := &tuiSession{
+		ctx:   ctx,
+		paths: paths,
+		opts:  displayOptions{DateLayout: dateLayout},
+	}
+	return s.run()
+}
+
+// tuiSession is the state of one interactive session. Every action runs
+// the matching command, so reads go through LoadAll and writes through Save
+// exactly as on the command line.
+type tuiSession struct {
+	ctx     CommandContext
+	paths   config.Paths
+	opts    displayOptions
+	showAll bool     // list every task instead of only open ones
+	stamp   tuiStamp // workspace state when the list was last drawn
+}
+
+// tuiStamp summarizes every thread.json so the session can tell when
+// another process changed the workspace.
+type tuiStamp struct {
+	Count   int
+	Size    int64
+	ModTime time.Time
+}
+
+// tuiPollInterval is how often a session waiting for input checks whether
+// another process changed the workspace.
+var tuiPollInterval = time.Second
+
+// tuiLine is one line read from the session's input.
+type tuiLine struct {
+	text string
+	err  error
+}
+
+func (s *tuiSession) run() int {
+	// Lines are read only when asked for on next, so actions that prompt
+	// on stdin themselves still get their input
+	next := make(chan struct{})
+	lines := make(chan tuiLine, 1)
+	go func() {
+		in := bufio.NewReader(s.ctx.stdin())
+		for range next {
+			text, err := in.ReadString('\n')
+			lines <- tuiLine{text, err}
+		}
+	}()
+	defer close(next)
+
+	ticker := time.NewTicker(tuiPollInterval)
+	defer ticker.Stop()
+
+	if code := s.draw(); code != 0 {
+		return code
+	}
+
+	for {
+		_, _ = fmt.Fprintf(s.ctx.Err, "%s> ", s.ctx.AppName)
+		next <- struct{}{}
+		line, code := s.await(lines, ticker.C)
+		if code != 0 {
+			return code
+		}
+		if line.err != nil && line.text == "" {
+			_, _ = fmt.Fprintln(s.ctx.Err)
+			return 0
+		}
+
+		fields := strings.Fields(line.text)
+		if len(fields) == 0 {
+			if code := s.draw(); code != 0 {
+				return code
+			}
+			continue
+		}
+		if fields[0] == "q" || fields[0] == "quit" {
+			return 0
+		}
+
+		// Redraw after actions, and before the next one if another process
+		// changed something in the meantime
+		if s.handle(fields[0], fields[1:]) || s.changed() {
+			if code := s.draw(); code != 0 {
+				return code
+			}
+		}
+	}
+}
+
+// await waits for the next input line, redrawing the list and the prompt
+// whenever another process changes the workspace in the meantime.
+func (s *tuiSession) await(lines <-chan tuiLine, tick <-chan time.Time) (tuiLine, int) {
+	for {
+		select {
+		case line := <-lines:
+			return line, 0
+		case <-tick:
+			if !s.changed() {
+				continue
+			}
+			if code := s.draw(); code != 0 {
+				return tuiLine{}, code
+			}
+			_, _ = fmt.Fprintf(s.ctx.Err, "%s> ", s.ctx.AppName)
+		}
+	}
+}
+
+// handle runs one action and reports whether the list should be redrawn.
+func (s *tuiSession) handle(action string, args []string) bool {
+	ctx := s.ctx
+	needID := func(n int) bool {
+		if len(args) < n {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: %s needs a task ID (type ? for help)\n", action)
+			return false
+		}
+		return true
+	}
+
+	switch action {
+	case "d", "done":
+		if needID(1) {
+			if RunDone(args, ctx) == 0 {
+				AutoCommit(ctx, "done", args)
+			}
+		}
+		return true
+	case "a", "archive":
+		if needID(1) {
+			if RunArchive(args, ctx) == 0 {
+				AutoCommit(ctx, "archive", args)
+			}
+		}
+		return true
+	case "r", "reopen":
+		if needID(1) {
+			if RunReopen(args, ctx) == 0 {
+				AutoCommit(ctx, "reopen", args)
+			}
+		}
+		return true
+	case "e", "edit":
+		if needID(1) {
+			if RunDescribe(args[:1], ctx) == 0 {
+				AutoCommit(ctx, "describe", args[:1])
+			}
+		}
+		return true
+	case "s", "show":
+		if needID(1) {
+			RunShow(args[:1], ctx)
+		}
+		return false
+	case "o", "open":
+		if needID(1) {
+			att := "1"
+			if len(args) > 1 {
+				att = args[1]
+			}
+			RunOpen([]string{"--att", att, args[0]}, ctx)
+		}
+		return false
+	case "v", "view":
+		s.showAll = !s.showAll
+		return true
+	case "?", "h", "help":
+		_, _ = fmt.Fprint(ctx.Err, tuiKeys)
+		return false
+	default:
+		_, _ = fmt.Fprintf(ctx.Err, "Error: unknown action %q (type ? for help)\n", action)
+		return false
+	}
+}
+
+// draw prints the task list and remembers the workspace state it shows.
+func (s *tuiSession) draw() int {
+	stamp, err := workspaceStamp(s.paths.ThreadsDir)
+	if err != nil {
+		_, _ = fmt.Fprintf(s.ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+
+	tasks, err := loadAllTasks(newStore(s.paths), s.ctx)
+	if err != nil {
+		_, _ = fmt.Fprintf(s.ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+	shown := filterTasks(tasks, taskFilter{All: s.showAll})
+
+	_, _ = fmt.Fprintln(s.ctx.Out)
+	if len(shown) == 0 {
+		_, _ = fmt.Fprintln(s.ctx.Out, "No tasks found.")
+	} else {
+		displayTasks(s.ctx.Out, shown, s.opts)
+	}
+	s.stamp = stamp
+	return 0
+}
+
+// changed reports whether the workspace differs from the drawn list.
+func (s *tuiSession) changed() bool {
+	stamp, err := workspaceStamp(s.paths.ThreadsDir)
+	if err != nil || stamp == s.stamp {
+		return false
+	}
+	_, _ = fmt.Fprintln(s.ctx.Err, "(tasks changed on disk; refreshed)")
+	return true
+}
+
+// workspaceStamp stats every thread.json under threadsDir.
+func workspaceStamp(threadsDir string) (tuiStamp, error) {
+	files, err := filepath.Glob(filepath.Join(threadsDir, "*", "*", "thread.json"))
+	if err != nil {
+		return tuiStamp{}, err
+	}
+	var stamp tuiStamp
+	for _, f := range files {
+		info, err := os.Stat(f)
+		if err != nil {
+			continue
+		}
+		stamp.Count++
+		stamp.Size += info.Size()
+		if info.ModTime().After(stamp.ModTime) {
+			stamp.ModTime = info.ModTime()
+		}
+	}
+	return stamp, nil
+}
+
+// tuiKeys is the in-session help.
+const tuiKeys = `Actions (IDs are short IDs or durable IDs):
+  d <id>...       mark done
+  a <id>...       archive
+  r <id>...       reopen
+  e <id>          edit the description in $EDITOR
+  s <id>          show the task
+  o <id> [<n>]    open attachment n (default 1)
+  v               toggle between open tasks and all tasks
+  <enter>         refresh the list
+  q               quit
+`
+
+func tuiUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s tui
+
+Starts an interactive session: the task list is shown and redrawn after
+each action, or when another process changes tasks on disk. Type an action
+and press enter; ? lists them. The session reads and saves tasks exactly
+as the other commands do.
+
+%s
+`, app, tuiKeys)
+}
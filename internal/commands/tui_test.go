@@ -0,0 +1,118 @@
+package commands
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sjatkinson/threadkeeper/internal/config"
+	"github.com/sjatkinson/threadkeeper/internal/task"
+)
+
+func TestRunTUI_Actions(t *testing.T) {
+	setupWorkspace(t)
+	for _, title := range []string{"first task", "second task"} {
+		ctx, _, errOut := newTestContext()
+		if code := RunAdd([]string{title}, ctx); code != 0 {
+			t.Fatalf("RunAdd(%q) exit code = %d, stderr: %s", title, code, errOut.String())
+		}
+	}
+
+	ctx, out, errOut := newTestContext()
+	ctx.Stdin = strings.NewReader("d 1\nbogus\nq\n")
+	if code := RunTUI(nil, ctx); code != 0 {
+		t.Fatalf("RunTUI() exit code = %d, stderr: %s", code, errOut.String())
+	}
+
+	if !strings.Contains(errOut.String(), `unknown action "bogus"`) {
+		t.Errorf("stderr = %q, want unknown action error", errOut.String())
+	}
+	// The list is drawn before and after the action; only the first
+	// drawing still shows the finished task
+	if got := strings.Count(out.String(), "first task"); got != 1 {
+		t.Errorf("output mentions first task %d times, want 1:\n%s", got, out.String())
+	}
+
+	paths, err := config.GetPaths("")
+	if err != nil {
+		t.Fatalf("GetPaths() error = %v", err)
+	}
+	tasks, err := newStore(paths).LoadAll()
+	if err != nil {
+		t.Fatalf("LoadAll() error = %v", err)
+	}
+	for _, tk := range tasks {
+		if want := tk.Title == "first task"; (tk.Status == task.StatusDone) != want {
+			t.Errorf("task %q status = %s, want done only for first task", tk.Title, tk.Status)
+		}
+	}
+}
+
+// signalWriter closes seen once want has been written to it.
+type signalWriter struct {
+	bytes.Buffer
+	want string
+	seen chan struct{}
+}
+
+func (w *signalWriter) Write(p []byte) (int, error) {
+	n, err := w.Buffer.Write(p)
+	if w.seen != nil && strings.Contains(w.String(), w.want) {
+		close(w.seen)
+		w.seen = nil
+	}
+	return n, err
+}
+
+// idleReader stands in for a user who types nothing until the session
+// shows seen: it runs edit, waits, then quits.
+type idleReader struct {
+	edit func()
+	seen <-chan struct{}
+	done bool
+}
+
+func (r *idleReader) Read(p []byte) (int, error) {
+	if r.done {
+		return 0, io.EOF
+	}
+	r.done = true
+	r.edit()
+	select {
+	case <-r.seen:
+	case <-time.After(5 * time.Second):
+	}
+	return copy(p, "q\n"), nil
+}
+
+func TestRunTUI_RefreshesWhileIdle(t *testing.T) {
+	setupWorkspace(t)
+	addAndLoad(t, []string{"first task"})
+
+	orig := tuiPollInterval
+	tuiPollInterval = 10 * time.Millisecond
+	t.Cleanup(func() { tuiPollInterval = orig })
+
+	seen := make(chan struct{})
+	out := &signalWriter{want: "added meanwhile", seen: seen}
+	var errOut bytes.Buffer
+	ctx := CommandContext{AppName: "tk", Out: out, Err: &errOut}
+	ctx.Stdin = &idleReader{seen: seen, edit: func() {
+		actx, _, aerr := newTestContext()
+		if code := RunAdd([]string{"added meanwhile"}, actx); code != 0 {
+			t.Errorf("RunAdd() exit code = %d, stderr: %s", code, aerr.String())
+		}
+	}}
+	if code := RunTUI(nil, ctx); code != 0 {
+		t.Fatalf("RunTUI() exit code = %d, stderr: %s", code, errOut.String())
+	}
+
+	if !strings.Contains(out.String(), "added meanwhile") {
+		t.Errorf("output = %q, want the list redrawn with the new task before any input", out.String())
+	}
+	if !strings.Contains(errOut.String(), "refreshed") {
+		t.Errorf("stderr = %q, want the refresh reported", errOut.String())
+	}
+}
@@ -0,0 +1,177 @@
+package commands
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/sjatkinson/threadkeeper/internal/config"
+	"github.com/sjatkinson/threadkeeper/internal/store"
+	"github.com/sjatkinson/threadkeeper/internal/task"
+)
+
+func RunUndo(args []string, ctx CommandContext) int {
+	fs := flag.NewFlagSet(ctx.AppName+" undo", flag.ContinueOnError)
+	fs.SetOutput(ctx.Err)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintln(ctx.Err, undoUsage(ctx.AppName))
+	}
+
+	var force bool
+	fs.BoolVar(&force, "force", false, "undo even if the tasks changed since")
+
+	if err := fs.Parse(args); err != nil {
+		_, _ = fmt.Fprintln(ctx.Err)
+		_, _ = fmt.Fprintln(ctx.Err, undoUsage(ctx.AppName))
+		return 2
+	}
+
+	if len(fs.Args()) != 0 {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: unexpected arguments\n")
+		_, _ = fmt.Fprintln(ctx.Err, undoUsage(ctx.AppName))
+		return 2
+	}
+
+	// Get paths and verify threads directory exists
+	paths, err := config.GetPaths(ctx.workspace())
+	if err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+
+	if _, err := os.Stat(paths.ThreadsDir); err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: threads directory does not exist at %s. Run '%s init' first.\n", paths.ThreadsDir, ctx.AppName)
+		return 1
+	}
+
+	st := newStore(paths)
+	var undone *opEntry
+	if err := st.WithLock(func() error {
+		var err error
+		undone, err = undoLastOp(st, paths, force, ctx)
+		return err
+	}); err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 1
+	}
+
+	if undone == nil {
+		_, _ = fmt.Fprintln(ctx.Out, "Nothing to undo.")
+		return 0
+	}
+
+	ctx.Success("Undid %s of %d tasks\n", undone.Command, len(undone.IDs))
+	return 0
+}
+
+// undoLastOp restores the before-state of the last undoable operation and
+// records the undo. Returns nil if there is nothing to undo. Callers must
+// hold the workspace lock.
+func undoLastOp(st *store.FileStore, paths config.Paths, force bool, ctx CommandContext) (*opEntry, error) {
+	entries, err := loadOps(paths)
+	if err != nil {
+		return nil, err
+	}
+	op := lastUndoableOp(entries)
+	if op == nil {
+		return nil, nil
+	}
+
+	opTime, err := time.Parse(time.RFC3339, op.TS)
+	if err != nil {
+		return nil, fmt.Errorf("operation %d has an invalid timestamp: %w", op.Seq, err)
+	}
+
+	before := make([]*task.Task, 0, len(op.Before))
+	for _, raw := range op.Before {
+		var t task.Task
+		if err := json.Unmarshal(raw, &t); err != nil {
+			return nil, fmt.Errorf("operation %d has an unreadable task: %w", op.Seq, err)
+		}
+		t.Normalize()
+		before = append(before, &t)
+	}
+
+	current, err := st.LoadAll()
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*task.Task, len(current))
+	for _, t := range current {
+		byID[t.ID] = t
+	}
+
+	// Refuse to clobber edits made after the operation
+	if !force {
+		for _, t := range before {
+			if cur, ok := byID[t.ID]; ok && cur.UpdatedAt.After(opTime) {
+				return nil, fmt.Errorf("task %s changed after the %s being undone; use --force to undo anyway", t.ID, op.Command)
+			}
+		}
+	}
+
+	// Check short_ids against the tasks as they will be once restored:
+	// an operation that renumbered tasks (auto_reindex) gives them back
+	// their old numbers together
+	restoring := make(map[string]bool, len(before))
+	for _, t := range before {
+		restoring[t.ID] = true
+	}
+	after := append([]*task.Task{}, before...)
+	for _, t := range current {
+		if !restoring[t.ID] {
+			after = append(after, t)
+		}
+	}
+
+	for _, t := range before {
+		// Its old short_id may have been reused while the task was closed
+		if t.Status == task.StatusOpen && t.ShortID != nil && shortIDTaken(after, t.ID, *t.ShortID) {
+			t.ShortID = nil
+		}
+		if err := st.Save(t); err != nil {
+			return nil, fmt.Errorf("failed to restore task %s: %w", t.ID, err)
+		}
+		if err := st.EnsureShortID(t); err != nil {
+			return nil, fmt.Errorf("failed to assign short_id to task %s: %w", t.ID, err)
+		}
+	}
+
+	entry := opEntry{
+		TS:      ctx.clock().Now().UTC().Format(time.RFC3339),
+		Command: opUndo,
+		IDs:     op.IDs,
+		Undoes:  op.Seq,
+	}
+	if err := appendOp(st, paths, entry); err != nil {
+		return nil, err
+	}
+	return op, nil
+}
+
+// shortIDTaken reports whether an open task other than id holds shortID.
+func shortIDTaken(tasks []*task.Task, id string, shortID int) bool {
+	for _, t := range tasks {
+		if t.ID != id && t.Status == task.StatusOpen && t.ShortID != nil && *t.ShortID == shortID {
+			return true
+		}
+	}
+	return false
+}
+
+func undoUsage(app string) string {
+	return fmt.Sprintf(`Usage:
+  %s undo [--force]
+
+Reverses the most recent done, archive, update, snooze or remove --permanent by
+restoring the affected tasks from %s. Only the last operation can be
+undone. Undoing a remove recreates thread.json but not the thread's
+attachments; trashed tasks come back with 'trash restore' instead.
+
+Flags:
+  --force        undo even if a task was changed after the operation
+
+`, app, opsLogName)
+}
@@ -0,0 +1,98 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sjatkinson/threadkeeper/internal/config"
+	"github.com/sjatkinson/threadkeeper/internal/date"
+	"github.com/sjatkinson/threadkeeper/internal/task"
+)
+
+func TestRunUndo(t *testing.T) {
+	setupWorkspace(t)
+	orig := addAndLoad(t, []string{"--tag", "keep", "undo me"})
+
+	run := func(name string, fn func([]string, CommandContext) int, args ...string) string {
+		t.Helper()
+		ctx, out, errOut := newTestContext()
+		if code := fn(args, ctx); code != 0 {
+			t.Fatalf("%s(%v) exit code = %d, stderr: %s", name, args, code, errOut.String())
+		}
+		return out.String()
+	}
+
+	// Nothing recorded yet
+	if out := run("RunUndo", RunUndo); !strings.Contains(out, "Nothing to undo") {
+		t.Errorf("RunUndo() on empty log = %q, want nothing to undo", out)
+	}
+
+	run("RunDone", RunDone, orig.ID)
+	if got := loadOnlyTask(t); got.Status != task.StatusDone {
+		t.Fatalf("Status after done = %q, want done", got.Status)
+	}
+	run("RunUndo", RunUndo)
+	got := loadOnlyTask(t)
+	if got.Status != task.StatusOpen || got.ShortID == nil || got.CompletedAt != nil {
+		t.Errorf("after undoing done: status %q, short_id %v, completed_at %v; want open with a short_id", got.Status, got.ShortID, got.CompletedAt)
+	}
+
+	// Single level: a second undo does nothing
+	if out := run("RunUndo", RunUndo); !strings.Contains(out, "Nothing to undo") {
+		t.Errorf("second RunUndo() = %q, want nothing to undo", out)
+	}
+
+	run("RunUpdate", RunUpdate, "--clear-tags", orig.ID)
+	run("RunUndo", RunUndo)
+	if got := loadOnlyTask(t); strings.Join(got.Tags, ",") != "keep" {
+		t.Errorf("Tags after undoing update = %v, want [keep]", got.Tags)
+	}
+
+	run("RunRemove", RunRemove, "--force", "--permanent", orig.ID)
+	run("RunUndo", RunUndo)
+	if got := loadOnlyTask(t); got.ID != orig.ID || got.Title != orig.Title {
+		t.Errorf("after undoing remove = %s %q, want %s %q", got.ID, got.Title, orig.ID, orig.Title)
+	}
+}
+
+func TestRunUndo_RefusesLaterChanges(t *testing.T) {
+	setupWorkspace(t)
+	orig := addAndLoad(t, []string{"changed later"})
+
+	paths, err := config.GetPaths("")
+	if err != nil {
+		t.Fatalf("GetPaths() error = %v", err)
+	}
+	opTime := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	ctx, _, errOut := newTestContext()
+	ctx.Clock = date.FixedClock{FixedTime: opTime}
+	if code := RunArchive([]string{orig.ID}, ctx); code != 0 {
+		t.Fatalf("RunArchive() exit code = %d, stderr: %s", code, errOut.String())
+	}
+
+	// Edit the task after the archive
+	later := loadOnlyTask(t)
+	later.Title = "edited"
+	later.UpdatedAt = opTime.Add(time.Hour)
+	if err := newStore(paths).Save(later); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	ctx, _, errOut = newTestContext()
+	if code := RunUndo(nil, ctx); code != 1 {
+		t.Errorf("RunUndo() exit code = %d, want 1", code)
+	}
+	if !strings.Contains(errOut.String(), "--force") {
+		t.Errorf("stderr = %q, want a hint about --force", errOut.String())
+	}
+
+	ctx, _, errOut = newTestContext()
+	if code := RunUndo([]string{"--force"}, ctx); code != 0 {
+		t.Fatalf("RunUndo(--force) exit code = %d, stderr: %s", code, errOut.String())
+	}
+	if got := loadOnlyTask(t); got.Status != task.StatusOpen || got.Title != "changed later" {
+		t.Errorf("after forced undo = %q %q, want open %q", got.Status, got.Title, "changed later")
+	}
+}
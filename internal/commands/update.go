@@ -10,7 +10,6 @@ import (
 
 	"github.com/sjatkinson/threadkeeper/internal/config"
 	"github.com/sjatkinson/threadkeeper/internal/date"
-	"github.com/sjatkinson/threadkeeper/internal/store"
 	"github.com/sjatkinson/threadkeeper/internal/task"
 )
 
@@ -36,6 +35,15 @@ func RunUpdate(args []string, ctx CommandContext) int {
 		project    string
 		addTags    updateStringList
 		removeTags updateStringList
+		estimate   durationFlag
+
+		clearDue      bool
+		clearProject  bool
+		clearTags     bool
+		clearEstimate bool
+
+		description       string
+		appendDescription string
 	)
 
 	fs.StringVar(&title, "title", "", "set new title")
@@ -43,14 +51,24 @@ func RunUpdate(args []string, ctx CommandContext) int {
 	fs.StringVar(&project, "project", "", "set project name")
 	fs.Var(&addTags, "add-tag", "repeatable tag to add")
 	fs.Var(&removeTags, "remove-tag", "repeatable tag to remove")
+	fs.BoolVar(&clearDue, "clear-due", false, "remove the due date")
+	fs.BoolVar(&clearProject, "clear-project", false, "remove the project")
+	fs.BoolVar(&clearTags, "clear-tags", false, "remove all tags")
+	fs.Var(&estimate, "estimate", "set expected effort, e.g. 2h or 1h30m")
+	fs.BoolVar(&clearEstimate, "clear-estimate", false, "remove the estimate")
+	fs.StringVar(&description, "description", "", "set description")
+	fs.StringVar(&description, "d", "", "set description (shorthand)")
+	fs.StringVar(&appendDescription, "append-description", "", "append a paragraph to the description")
 
 	// Pre-process args: convert -tag to --remove-tag tag
-	// Since we have no short flags, any -X (where X is not --) can be treated as tag removal
+	// -d is the only short flag; any other -X (where X is not --) is treated as tag removal
 	processedArgs := make([]string, 0, len(args))
 	for _, arg := range args {
 		// Check if this looks like a short flag that should be converted to tag removal
 		// Pattern: -X where X is not -- (long flag)
-		if strings.HasPrefix(arg, "-") && !strings.HasPrefix(arg, "--") && len(arg) > 1 {
+		if arg == "-d" || strings.HasPrefix(arg, "-d=") {
+			processedArgs = append(processedArgs, arg)
+		} else if strings.HasPrefix(arg, "-") && !strings.HasPrefix(arg, "--") && len(arg) > 1 {
 			// Convert -tag to --remove-tag tag
 			tagName := arg[1:] // Remove the leading -
 			processedArgs = append(processedArgs, "--remove-tag", tagName)
@@ -69,6 +87,14 @@ func RunUpdate(args []string, ctx CommandContext) int {
 		return 2
 	}
 
+	// An explicitly empty --description clears it, so track whether it was given
+	descriptionSet := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "description" || f.Name == "d" {
+			descriptionSet = true
+		}
+	})
+
 	// Parse positional arguments: separate IDs from +tag shortcuts
 	// Note: -tag is already handled by pre-processing above
 	remaining := fs.Args()
@@ -90,16 +116,39 @@ func RunUpdate(args []string, ctx CommandContext) int {
 		return 2
 	}
 
+	if err := task.ValidateTags(append(append([]string{}, addTags...), removeTags...)); err != nil {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+		return 2
+	}
+
 	// Check if at least one update field was provided
 	hasAddTags := len(addTags) > 0
 	hasRemoveTags := len(removeTags) > 0
-	if title == "" && due == "" && project == "" && !hasAddTags && !hasRemoveTags {
-		_, _ = fmt.Fprintf(ctx.Err, "Error: nothing to update. Provide --title/--due/--project/--add-tag/--remove-tag or use +tag/-tag shortcuts.\n")
+	if title == "" && due == "" && project == "" && !hasAddTags && !hasRemoveTags && !clearDue && !clearProject && !clearTags && !descriptionSet && appendDescription == "" && !estimate.set && !clearEstimate {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: nothing to update. Provide --title/--description/--due/--project/--estimate/--add-tag/--remove-tag/--clear-* or use +tag/-tag shortcuts.\n")
+		return 2
+	}
+
+	// Setting and clearing the same field is contradictory
+	if clearDue && due != "" {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: --due and --clear-due cannot be used together\n")
+		return 2
+	}
+	if clearProject && project != "" {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: --project and --clear-project cannot be used together\n")
+		return 2
+	}
+	if clearEstimate && estimate.set {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: --estimate and --clear-estimate cannot be used together\n")
+		return 2
+	}
+	if clearTags && hasAddTags {
+		_, _ = fmt.Fprintf(ctx.Err, "Error: --add-tag (or +tag) and --clear-tags cannot be used together\n")
 		return 2
 	}
 
 	// Get paths and verify tasks directory exists
-	paths, err := config.GetPaths(ctx.Path)
+	paths, err := config.GetPaths(ctx.workspace())
 	if err != nil {
 		_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
 		return 1
@@ -111,7 +160,7 @@ func RunUpdate(args []string, ctx CommandContext) int {
 	}
 
 	// Load and resolve tasks
-	st := store.NewFileStore(paths.ThreadsDir)
+	st := newStore(paths)
 	var tasks []*task.Task
 	for _, idStr := range ids {
 		t, err := st.ResolveID(idStr)
@@ -135,12 +184,20 @@ func RunUpdate(args []string, ctx CommandContext) int {
 			locale = config.DateLocaleISO // Default on error
 		}
 
+		// Load timezone used to resolve "today" and shortcuts
+		tz, err := config.LoadTimezone()
+		if err != nil {
+			_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
+			return 1
+		}
+
 		// Parse date using locale-aware parser
-		canonical, err := date.ParseDate(due, locale, date.RealClock{}, nil)
+		canonical, err := date.ParseDate(due, locale, ctx.clock(), tz)
 		if err != nil {
 			_, _ = fmt.Fprintf(ctx.Err, "Error: %v\n", err)
 			return 1
 		}
+		warnPastDue(ctx, canonical, tz)
 
 		// Convert canonical string to time.Time
 		parsed, err := time.Parse("2006-01-02", canonical)
@@ -153,9 +210,12 @@ func RunUpdate(args []string, ctx CommandContext) int {
 	}
 
 	// Update each task
-	now := time.Now().UTC()
+	now := ctx.clock().Now().UTC()
+	rec := newOpRecorder("update")
+	defer rec.commit(st, paths, ctx)
 	for _, t := range tasks {
 		changed := false
+		snap := snapshotTask(t)
 
 		// Update title
 		if title != "" && title != t.Title {
@@ -163,6 +223,16 @@ func RunUpdate(args []string, ctx CommandContext) int {
 			changed = true
 		}
 
+		// Update description
+		if descriptionSet && description != t.Description {
+			t.Description = description
+			changed = true
+		}
+		if appendDescription != "" {
+			t.Description = appendParagraph(t.Description, appendDescription)
+			changed = true
+		}
+
 		// Update due date
 		if dueAt != nil {
 			// Compare dates (ignore time component)
@@ -178,66 +248,42 @@ func RunUpdate(args []string, ctx CommandContext) int {
 				changed = true
 			}
 		}
+		if clearDue && t.DueAt != nil {
+			t.DueAt = nil
+			changed = true
+		}
 
 		// Update project
 		if project != "" && project != t.Project {
 			t.Project = project
 			changed = true
 		}
+		if clearProject && t.Project != "" {
+			t.Project = ""
+			changed = true
+		}
+
+		// Update estimate
+		if estimate.set && estimate.value != t.Estimate {
+			t.Estimate = estimate.value
+			changed = true
+		}
+		if clearEstimate && t.Estimate != 0 {
+			t.Estimate = 0
+			changed = true
+		}
 
 		// Update tags
 		if hasAddTags || hasRemoveTags {
-			existingTags := make(map[string]bool)
-			for _, tag := range t.Tags {
-				existingTags[tag] = true
-			}
-
-			// Make a copy to compare later
-			beforeTags := make(map[string]bool)
-			for tag := range existingTags {
-				beforeTags[tag] = true
-			}
-
-			// Add tags
-			for _, tag := range normalizedAddTags {
-				existingTags[tag] = true
-			}
-
-			// Remove tags
-			for _, tag := range normalizedRemoveTags {
-				delete(existingTags, tag)
-			}
-
-			// Check if tags actually changed (compare sets)
-			if len(existingTags) != len(beforeTags) {
+			if newTags, tagsChanged := applyTagChanges(t.Tags, normalizedAddTags, normalizedRemoveTags); tagsChanged {
+				t.Tags = newTags
 				changed = true
-			} else {
-				// Same size, but could be different tags
-				for tag := range existingTags {
-					if !beforeTags[tag] {
-						changed = true
-						break
-					}
-				}
-				if !changed {
-					for tag := range beforeTags {
-						if !existingTags[tag] {
-							changed = true
-							break
-						}
-					}
-				}
-			}
-
-			if changed {
-				// Convert map back to sorted slice
-				t.Tags = make([]string, 0, len(existingTags))
-				for tag := range existingTags {
-					t.Tags = append(t.Tags, tag)
-				}
-				sort.Strings(t.Tags)
 			}
 		}
+		if clearTags && len(t.Tags) > 0 {
+			t.Tags = []string{}
+			changed = true
+		}
 
 		// Save if changed
 		if changed {
@@ -246,36 +292,108 @@ func RunUpdate(args []string, ctx CommandContext) int {
 				_, _ = fmt.Fprintf(ctx.Err, "Error: failed to save task %s: %v\n", t.ID, err)
 				return 1
 			}
+			rec.add(t.ID, snap)
 
 			// Print confirmation
 			sidStr := "?"
 			if t.ShortID != nil {
 				sidStr = fmt.Sprintf("%d", *t.ShortID)
 			}
-			_, _ = fmt.Fprintf(ctx.Out, "Updated task %s (%s)\n", sidStr, t.ID)
+			ctx.Success("Updated task %s (%s)\n", sidStr, t.ID)
 		}
 	}
 
 	return 0
 }
 
+// appendParagraph appends text to desc as a new paragraph, separated by a
+// blank line. Trailing whitespace on desc is dropped first.
+func appendParagraph(desc, text string) string {
+	desc = strings.TrimRight(desc, " \t\r\n")
+	if desc == "" {
+		return text
+	}
+	return desc + "\n\n" + text
+}
+
+// applyTagChanges adds and then removes normalized tags from tags, returning
+// the resulting sorted tag set and whether it differs from the original.
+// A tag in both add and remove ends up removed.
+func applyTagChanges(tags, add, remove []string) ([]string, bool) {
+	existingTags := make(map[string]bool)
+	for _, tag := range tags {
+		existingTags[tag] = true
+	}
+
+	// Make a copy to compare later
+	beforeTags := make(map[string]bool)
+	for tag := range existingTags {
+		beforeTags[tag] = true
+	}
+
+	// Add tags
+	for _, tag := range add {
+		existingTags[tag] = true
+	}
+
+	// Remove tags
+	for _, tag := range remove {
+		delete(existingTags, tag)
+	}
+
+	// Check if tags actually changed (compare sets)
+	changed := len(existingTags) != len(beforeTags)
+	if !changed {
+		// Same size, but could be different tags
+		for tag := range existingTags {
+			if !beforeTags[tag] {
+				changed = true
+				break
+			}
+		}
+	}
+	if !changed {
+		return tags, false
+	}
+
+	// Convert map back to sorted slice
+	result := make([]string, 0, len(existingTags))
+	for tag := range existingTags {
+		result = append(result, tag)
+	}
+	sort.Strings(result)
+	return result, true
+}
+
 func updateUsage(app string) string {
 	return fmt.Sprintf(`Usage:
   %s update [flags] <id> [<id> ...] [+tag] [-tag] ...
 
 Flags:
   --title <string>    set new title
+  -d, --description <t>
+                      set description ("" clears it)
+  --append-description <t>
+                      append <t> to the description as a new paragraph
   --due <date>        set due date (format depends on date_locale config)
+                      (a date before today is kept, with a warning)
   --project <name>    set project name
+  --estimate <dur>    set expected effort, e.g. 45m, 2h, 1h30m
   --add-tag <tag>     add a tag (repeatable)
   --remove-tag <tag>  remove a tag (repeatable)
+  --clear-due         remove the due date (not with --due)
+  --clear-project     remove the project (not with --project)
+  --clear-tags        remove all tags (not with --add-tag or +tag)
+  --clear-estimate    remove the estimate (not with --estimate)
 
 Tag shortcuts:
   +tag                add a tag (e.g., +foo)
-  -tag                remove a tag (e.g., -bar)
+  -tag                remove a tag (e.g., -bar; use --remove-tag d for "d")
 
 Due date shortcuts:
-  today               set due date to today
+  today, tomorrow     set due date to today or tomorrow
+  monday, fri, ...    set due date to the next such weekday
+  eow                 set due date to the coming Sunday
   +N                  set due date to today + N days (e.g., +1, +2, +7)
 
 Examples:
@@ -283,6 +401,8 @@ Examples:
   %s update 3 --title "New title" +important
   %s update 3 --due today
   %s update 3 --due +7
+  %s update 3 --clear-due --clear-project
+  %s update 3 4 --append-description "Blocked on review"
 
-`, app, app, app, app, app)
+`, app, app, app, app, app, app, app)
 }
@@ -0,0 +1,113 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sjatkinson/threadkeeper/internal/config"
+	"github.com/sjatkinson/threadkeeper/internal/task"
+)
+
+// addAndLoad adds a single task with args and returns it as stored.
+func addAndLoad(t *testing.T, args []string) *task.Task {
+	t.Helper()
+	ctx, _, errOut := newTestContext()
+	if code := RunAdd(args, ctx); code != 0 {
+		t.Fatalf("RunAdd() exit code = %d, stderr: %s", code, errOut.String())
+	}
+	return loadOnlyTask(t)
+}
+
+// loadOnlyTask loads the workspace's single task.
+func loadOnlyTask(t *testing.T) *task.Task {
+	t.Helper()
+	paths, err := config.GetPaths("")
+	if err != nil {
+		t.Fatalf("GetPaths() error = %v", err)
+	}
+	tasks, err := newStore(paths).LoadAll()
+	if err != nil || len(tasks) != 1 {
+		t.Fatalf("LoadAll() = %d tasks, err = %v", len(tasks), err)
+	}
+	return tasks[0]
+}
+
+func TestRunUpdateClearFlags(t *testing.T) {
+	setupWorkspace(t)
+	orig := addAndLoad(t, []string{"--due", "2030-01-02", "--project", "work", "--tag", "a", "--tag", "b", "clear me"})
+
+	ctx, _, errOut := newTestContext()
+	if code := RunUpdate([]string{"--clear-due", "--clear-project", "--clear-tags", orig.ID}, ctx); code != 0 {
+		t.Fatalf("RunUpdate() exit code = %d, stderr: %s", code, errOut.String())
+	}
+
+	got := loadOnlyTask(t)
+	if got.DueAt != nil {
+		t.Errorf("DueAt = %v, want nil", got.DueAt)
+	}
+	if got.Project != "" {
+		t.Errorf("Project = %q, want empty", got.Project)
+	}
+	if len(got.Tags) != 0 {
+		t.Errorf("Tags = %v, want none", got.Tags)
+	}
+	if got.UpdatedAt.Before(orig.UpdatedAt) {
+		t.Errorf("UpdatedAt = %v, want not before %v", got.UpdatedAt, orig.UpdatedAt)
+	}
+}
+
+func TestRunUpdateClearConflicts(t *testing.T) {
+	setupWorkspace(t)
+	orig := addAndLoad(t, []string{"conflict"})
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"due", []string{"--due", "today", "--clear-due"}, "--clear-due"},
+		{"project", []string{"--project", "x", "--clear-project"}, "--clear-project"},
+		{"tags", []string{"--clear-tags", "+x"}, "--clear-tags"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _, errOut := newTestContext()
+			if code := RunUpdate(append(tt.args, orig.ID), ctx); code != 2 {
+				t.Errorf("RunUpdate(%v) exit code = %d, want 2", tt.args, code)
+			}
+			if !strings.Contains(errOut.String(), tt.want) {
+				t.Errorf("stderr = %q, want it to mention %s", errOut.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestRunUpdateDescription(t *testing.T) {
+	setupWorkspace(t)
+	orig := addAndLoad(t, []string{"-d", "first", "describe me"})
+
+	ctx, _, errOut := newTestContext()
+	if code := RunUpdate([]string{"--append-description", "second", orig.ID}, ctx); code != 0 {
+		t.Fatalf("RunUpdate() exit code = %d, stderr: %s", code, errOut.String())
+	}
+	if got := loadOnlyTask(t).Description; got != "first\n\nsecond" {
+		t.Errorf("Description after append = %q, want %q", got, "first\n\nsecond")
+	}
+
+	ctx, _, errOut = newTestContext()
+	if code := RunUpdate([]string{"-d", "replaced", "--title", "renamed", orig.ID}, ctx); code != 0 {
+		t.Fatalf("RunUpdate() exit code = %d, stderr: %s", code, errOut.String())
+	}
+	got := loadOnlyTask(t)
+	if got.Description != "replaced" || got.Title != "renamed" {
+		t.Errorf("task = {%q, %q}, want {%q, %q}", got.Title, got.Description, "renamed", "replaced")
+	}
+
+	ctx, _, errOut = newTestContext()
+	if code := RunUpdate([]string{"--description", "", orig.ID}, ctx); code != 0 {
+		t.Fatalf("RunUpdate() exit code = %d, stderr: %s", code, errOut.String())
+	}
+	if got := loadOnlyTask(t).Description; got != "" {
+		t.Errorf("Description after clearing = %q, want empty", got)
+	}
+}
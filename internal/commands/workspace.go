@@ -0,0 +1,29 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/sjatkinson/threadkeeper/internal/config"
+	"github.com/sjatkinson/threadkeeper/internal/store"
+	"github.com/sjatkinson/threadkeeper/internal/task"
+)
+
+// newStore returns a FileStore for the workspace, honoring the configured bucket width.
+func newStore(paths config.Paths) *store.FileStore {
+	return store.NewFileStoreWithBucketWidth(paths.ThreadsDir, paths.BucketWidth)
+}
+
+// loadAllTasks loads every task, warning on ctx.Err about unreadable thread
+// files when ctx.Verbose is set.
+func loadAllTasks(st *store.FileStore, ctx CommandContext) ([]*task.Task, error) {
+	tasks, skipped, err := st.LoadAllWithSkipped()
+	if err != nil {
+		return nil, err
+	}
+	if ctx.Verbose {
+		for _, s := range skipped {
+			_, _ = fmt.Fprintf(ctx.Err, "Warning: skipped unreadable thread %s: %v\n", s.Path, s.Err)
+		}
+	}
+	return tasks, nil
+}
@@ -3,9 +3,11 @@ package config
 import (
 	"bufio"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/pelletier/go-toml/v2"
 )
@@ -16,9 +18,36 @@ const (
 	// Env var for overriding workspace dir (CLI still wins).
 	WorkspaceEnvVar = "THREADKEEPER_WORKSPACE"
 
+	// Env var naming the config file to use instead of the XDG default.
+	ConfigEnvVar = "THREADKEEPER_CONFIG"
+
 	// Key we read from config.toml
 	DefaultWorkspaceKey = "default_workspace"
 	DateLocaleKey       = "date_locale"
+	DefaultTagsKey      = "default_tags"
+	DateFormatKey       = "date_format"
+	TimezoneKey         = "timezone"
+	BucketWidthKey      = "bucket_width"
+	GitAutocommitKey    = "git_autocommit"
+	PagerKey            = "pager"
+	EditorKey           = "editor"
+	AutoReindexKey      = "auto_reindex"
+	MaxAttachmentKey    = "max_attachment_bytes"
+	LinkCheckTimeoutKey = "link_check_timeout"
+	AttachFetchTitleKey = "attach_fetch_title"
+	RenderMarkdownKey   = "render_markdown"
+
+	// DefaultBucketWidth matches store.DefaultBucketWidth; kept here to avoid an import cycle.
+	DefaultBucketWidth = 2
+	maxBucketWidth     = 4
+
+	// DefaultMaxAttachmentBytes is the note size limit when
+	// max_attachment_bytes is not set: 10 MiB.
+	DefaultMaxAttachmentBytes = 10 << 20
+
+	// DefaultLinkCheckTimeout is how long check-links waits for each link
+	// when link_check_timeout is not set.
+	DefaultLinkCheckTimeout = 10 * time.Second
 )
 
 // DateLocale represents the locale for date parsing.
@@ -30,13 +59,26 @@ const (
 	DateLocaleEU  DateLocale = "eu"
 )
 
+// Display date layouts for the date_format config key.
+// These only affect presentation; thread.json always stores RFC3339.
+const (
+	DisplayLayoutISO = "2006-01-02"
+	DisplayLayoutUS  = "01/02/2006"
+	DisplayLayoutEU  = "02/01/2006"
+)
+
 type Paths struct {
 	Workspace  string
 	ThreadsDir string
-	// Later: AttachmentsDir, NotesDir, IndexDir, etc.
+	// BlobsDir is the content-addressed store for note blobs, shared by all threads.
+	BlobsDir string
+	// BucketWidth is the number of leading ID characters used for thread buckets.
+	BucketWidth int
+	// Later: NotesDir, IndexDir, etc.
 }
 
-// ConfigPath returns the config file path:
+// ConfigPath returns the config file path: $THREADKEEPER_CONFIG if set
+// (with a leading "~/" expanded), otherwise
 //
 //	$XDG_CONFIG_HOME/threadkeeper/config.toml
 //
@@ -44,6 +86,10 @@ type Paths struct {
 //
 //	~/.config/threadkeeper/config.toml
 func ConfigPath() (string, error) {
+	if p := strings.TrimSpace(os.Getenv(ConfigEnvVar)); p != "" {
+		return ExpandUser(p)
+	}
+
 	home, err := os.UserHomeDir()
 	if err != nil {
 		return "", err
@@ -57,6 +103,30 @@ func ConfigPath() (string, error) {
 	return filepath.Join(base, AppDirName, "config.toml"), nil
 }
 
+// CheckConfig reports whether config.toml, if present, is well-formed TOML.
+// A missing file is not an error. Loaders fall back to defaults when the file
+// is malformed, so callers use this to warn once rather than per setting.
+func CheckConfig() error {
+	cfgPath, err := ConfigPath()
+	if err != nil {
+		return nil // No config location means nothing to check
+	}
+
+	data, err := os.ReadFile(cfgPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+
+	var cfg map[string]any
+	if err := toml.Unmarshal(data, &cfg); err != nil {
+		return fmt.Errorf("malformed config %s: %w", cfgPath, err)
+	}
+	return nil
+}
+
 // DefaultDataDir returns the XDG-ish default data directory:
 //
 //	$XDG_DATA_HOME/threadkeeper
@@ -174,10 +244,17 @@ func GetPaths(custom string) (Paths, error) {
 		return Paths{}, err
 	}
 
+	width, err := LoadBucketWidth()
+	if err != nil {
+		width = DefaultBucketWidth // Default on error
+	}
+
 	ws = filepath.Clean(ws)
 	return Paths{
-		Workspace:  ws,
-		ThreadsDir: filepath.Join(ws, "threads"),
+		Workspace:   ws,
+		ThreadsDir:  filepath.Join(ws, "threads"),
+		BlobsDir:    filepath.Join(ws, "blobs"),
+		BucketWidth: width,
 	}, nil
 }
 
@@ -281,3 +358,416 @@ func LoadDateLocale() (DateLocale, error) {
 		return DateLocaleISO, nil
 	}
 }
+
+// LoadDefaultTags reads config.toml and returns the default_tags array.
+// These tags are applied to every new task in addition to any --tag flags.
+// Returns an empty slice (not an error) if the config file or key is missing,
+// or if the file is malformed TOML (see CheckConfig).
+func LoadDefaultTags() ([]string, error) {
+	cfgPath, err := ConfigPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(cfgPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []string{}, nil
+		}
+		return nil, err
+	}
+
+	var cfg struct {
+		DefaultTags []string `toml:"default_tags"`
+	}
+
+	if err := toml.Unmarshal(data, &cfg); err != nil {
+		// Malformed TOML - return default
+		return []string{}, nil
+	}
+
+	if cfg.DefaultTags == nil {
+		return []string{}, nil
+	}
+
+	return cfg.DefaultTags, nil
+}
+
+// LoadDisplayDateFormat reads config.toml and returns the Go time layout used
+// to display dates. The date_format key accepts "iso", "us", "eu", or a Go
+// layout string such as "Jan 2, 2006".
+// Returns the ISO layout (default) if not set or invalid.
+func LoadDisplayDateFormat() (string, error) {
+	cfgPath, err := ConfigPath()
+	if err != nil {
+		return DisplayLayoutISO, nil // Default on error
+	}
+
+	data, err := os.ReadFile(cfgPath)
+	if err != nil {
+		return DisplayLayoutISO, nil // Default if config doesn't exist or can't be read
+	}
+
+	var cfg struct {
+		DateFormat string `toml:"date_format"`
+	}
+
+	if err := toml.Unmarshal(data, &cfg); err != nil {
+		// Malformed TOML - return default
+		return DisplayLayoutISO, nil
+	}
+
+	format := strings.TrimSpace(cfg.DateFormat)
+	switch strings.ToLower(format) {
+	case "", string(DateLocaleISO):
+		return DisplayLayoutISO, nil
+	case string(DateLocaleUS):
+		return DisplayLayoutUS, nil
+	case string(DateLocaleEU):
+		return DisplayLayoutEU, nil
+	}
+
+	// Treat anything else as a Go layout. A layout with no reference
+	// components formats to itself, which means it isn't a layout at all.
+	if time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC).Format(format) == format {
+		return DisplayLayoutISO, nil
+	}
+	return format, nil
+}
+
+// LoadTimezone reads config.toml and returns the location named by the
+// timezone key (an IANA name such as "Europe/Berlin").
+// Returns time.Local if the config file or key is missing, or if the file is
+// malformed TOML (see CheckConfig).
+//
+// Returns an error if the zone name is invalid, since dates would silently
+// resolve against the wrong day.
+func LoadTimezone() (*time.Location, error) {
+	cfgPath, err := ConfigPath()
+	if err != nil {
+		return time.Local, nil // Default on error
+	}
+
+	data, err := os.ReadFile(cfgPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return time.Local, nil // Default if config doesn't exist
+		}
+		return nil, err
+	}
+
+	var cfg struct {
+		Timezone string `toml:"timezone"`
+	}
+
+	if err := toml.Unmarshal(data, &cfg); err != nil {
+		// Malformed TOML - return default
+		return time.Local, nil
+	}
+
+	name := strings.TrimSpace(cfg.Timezone)
+	if name == "" {
+		return time.Local, nil
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s %q in config: %w", TimezoneKey, name, err)
+	}
+	return loc, nil
+}
+
+// LoadBucketWidth reads config.toml and returns the bucket_width setting:
+// how many leading characters of a thread ID name its bucket directory.
+// Returns 2 (default) if not set or outside 1..4.
+//
+// Changing this on an existing workspace requires running 'tk rebucket'
+// to move threads into their new buckets.
+func LoadBucketWidth() (int, error) {
+	cfgPath, err := ConfigPath()
+	if err != nil {
+		return DefaultBucketWidth, nil // Default on error
+	}
+
+	data, err := os.ReadFile(cfgPath)
+	if err != nil {
+		return DefaultBucketWidth, nil // Default if config doesn't exist or can't be read
+	}
+
+	var cfg struct {
+		BucketWidth int `toml:"bucket_width"`
+	}
+
+	if err := toml.Unmarshal(data, &cfg); err != nil {
+		// Malformed TOML - return default
+		return DefaultBucketWidth, nil
+	}
+
+	if cfg.BucketWidth < 1 || cfg.BucketWidth > maxBucketWidth {
+		return DefaultBucketWidth, nil
+	}
+	return cfg.BucketWidth, nil
+}
+
+// LoadMaxAttachmentBytes reads config.toml and returns the
+// max_attachment_bytes setting: the largest note attach will store, or 0
+// for no limit. Returns DefaultMaxAttachmentBytes if the config file or key
+// is missing, the value is negative, or the file is malformed TOML (see
+// CheckConfig).
+func LoadMaxAttachmentBytes() (int64, error) {
+	cfgPath, err := ConfigPath()
+	if err != nil {
+		return DefaultMaxAttachmentBytes, nil // Default on error
+	}
+
+	data, err := os.ReadFile(cfgPath)
+	if err != nil {
+		return DefaultMaxAttachmentBytes, nil // Default if config doesn't exist or can't be read
+	}
+
+	var cfg struct {
+		MaxAttachmentBytes *int64 `toml:"max_attachment_bytes"`
+	}
+
+	if err := toml.Unmarshal(data, &cfg); err != nil {
+		// Malformed TOML - return default
+		return DefaultMaxAttachmentBytes, nil
+	}
+
+	if cfg.MaxAttachmentBytes == nil || *cfg.MaxAttachmentBytes < 0 {
+		return DefaultMaxAttachmentBytes, nil
+	}
+	return *cfg.MaxAttachmentBytes, nil
+}
+
+// LoadLinkCheckTimeout reads config.toml and returns the link_check_timeout
+// setting, a Go duration such as "5s": how long check-links waits for each
+// link. Returns DefaultLinkCheckTimeout if the config file or key is
+// missing, the value is not a positive duration, or the file is malformed
+// TOML (see CheckConfig).
+func LoadLinkCheckTimeout() (time.Duration, error) {
+	cfgPath, err := ConfigPath()
+	if err != nil {
+		return DefaultLinkCheckTimeout, nil // Default on error
+	}
+
+	data, err := os.ReadFile(cfgPath)
+	if err != nil {
+		return DefaultLinkCheckTimeout, nil // Default if config doesn't exist or can't be read
+	}
+
+	var cfg struct {
+		LinkCheckTimeout string `toml:"link_check_timeout"`
+	}
+
+	if err := toml.Unmarshal(data, &cfg); err != nil {
+		// Malformed TOML - return default
+		return DefaultLinkCheckTimeout, nil
+	}
+
+	d, err := time.ParseDuration(strings.TrimSpace(cfg.LinkCheckTimeout))
+	if err != nil || d <= 0 {
+		return DefaultLinkCheckTimeout, nil
+	}
+	return d, nil
+}
+
+// LoadAttachFetchTitle reads config.toml and returns the attach_fetch_title
+// setting: whether attach link fetches the page title to name a link given
+// without --label. Returns false (default) if the config file or key is
+// missing, or if the file is malformed TOML (see CheckConfig).
+func LoadAttachFetchTitle() (bool, error) {
+	cfgPath, err := ConfigPath()
+	if err != nil {
+		return false, nil // Default on error
+	}
+
+	data, err := os.ReadFile(cfgPath)
+	if err != nil {
+		return false, nil // Default if config doesn't exist or can't be read
+	}
+
+	var cfg struct {
+		AttachFetchTitle bool `toml:"attach_fetch_title"`
+	}
+
+	if err := toml.Unmarshal(data, &cfg); err != nil {
+		// Malformed TOML - return default
+		return false, nil
+	}
+
+	return cfg.AttachFetchTitle, nil
+}
+
+// LoadRenderMarkdown reads config.toml and returns the render_markdown
+// setting: whether show --note styles Markdown notes printed to a terminal.
+// Returns true (default) if the config file or key is missing, or if the
+// file is malformed TOML (see CheckConfig).
+func LoadRenderMarkdown() (bool, error) {
+	cfgPath, err := ConfigPath()
+	if err != nil {
+		return true, nil // Default on error
+	}
+
+	data, err := os.ReadFile(cfgPath)
+	if err != nil {
+		return true, nil // Default if config doesn't exist or can't be read
+	}
+
+	var cfg struct {
+		RenderMarkdown *bool `toml:"render_markdown"`
+	}
+
+	if err := toml.Unmarshal(data, &cfg); err != nil {
+		// Malformed TOML - return default
+		return true, nil
+	}
+
+	if cfg.RenderMarkdown == nil {
+		return true, nil
+	}
+	return *cfg.RenderMarkdown, nil
+}
+
+// LoadGitAutocommit reads config.toml and returns the git_autocommit setting:
+// whether commands that change the workspace commit it to git afterwards.
+// Returns false (default) if the config file or key is missing, or if the
+// file is malformed TOML (see CheckConfig).
+func LoadGitAutocommit() (bool, error) {
+	cfgPath, err := ConfigPath()
+	if err != nil {
+		return false, nil // Default on error
+	}
+
+	data, err := os.ReadFile(cfgPath)
+	if err != nil {
+		return false, nil // Default if config doesn't exist or can't be read
+	}
+
+	var cfg struct {
+		GitAutocommit bool `toml:"git_autocommit"`
+	}
+
+	if err := toml.Unmarshal(data, &cfg); err != nil {
+		// Malformed TOML - return default
+		return false, nil
+	}
+
+	return cfg.GitAutocommit, nil
+}
+
+// LoadAutoReindex reads config.toml and returns the auto_reindex setting:
+// whether done, archive and reopen renumber open tasks 1..N afterwards.
+// Returns false (default) if the config file or key is missing, or if the
+// file is malformed TOML (see CheckConfig).
+func LoadAutoReindex() (bool, error) {
+	cfgPath, err := ConfigPath()
+	if err != nil {
+		return false, nil // Default on error
+	}
+
+	data, err := os.ReadFile(cfgPath)
+	if err != nil {
+		return false, nil // Default if config doesn't exist or can't be read
+	}
+
+	var cfg struct {
+		AutoReindex bool `toml:"auto_reindex"`
+	}
+
+	if err := toml.Unmarshal(data, &cfg); err != nil {
+		// Malformed TOML - return default
+		return false, nil
+	}
+
+	return cfg.AutoReindex, nil
+}
+
+// ListDefaults are list's defaults from the [list] section of config.toml;
+// list flags given on the command line override them.
+type ListDefaults struct {
+	ShowAll       bool   `toml:"show_all"`       // like --all
+	RelativeDates bool   `toml:"relative_dates"` // like --relative
+	GroupBy       string `toml:"group_by"`       // like --group-by
+	Limit         int    `toml:"limit"`          // like --limit
+}
+
+// LoadListDefaults reads config.toml and returns its [list] section.
+// Returns zero defaults if the config file or section is missing, or if the
+// file is malformed TOML (see CheckConfig).
+func LoadListDefaults() (ListDefaults, error) {
+	cfgPath, err := ConfigPath()
+	if err != nil {
+		return ListDefaults{}, nil // Default on error
+	}
+
+	data, err := os.ReadFile(cfgPath)
+	if err != nil {
+		return ListDefaults{}, nil // Default if config doesn't exist or can't be read
+	}
+
+	var cfg struct {
+		List ListDefaults `toml:"list"`
+	}
+
+	if err := toml.Unmarshal(data, &cfg); err != nil {
+		// Malformed TOML - return default
+		return ListDefaults{}, nil
+	}
+
+	return cfg.List, nil
+}
+
+// LoadPager reads config.toml and returns the pager command line set by the
+// pager key, such as "less -R". Returns "" if the config file or key is
+// missing, or if the file is malformed TOML (see CheckConfig); callers then
+// fall back to $PAGER.
+func LoadPager() (string, error) {
+	cfgPath, err := ConfigPath()
+	if err != nil {
+		return "", nil // Default on error
+	}
+
+	data, err := os.ReadFile(cfgPath)
+	if err != nil {
+		return "", nil // Default if config doesn't exist or can't be read
+	}
+
+	var cfg struct {
+		Pager string `toml:"pager"`
+	}
+
+	if err := toml.Unmarshal(data, &cfg); err != nil {
+		// Malformed TOML - return default
+		return "", nil
+	}
+
+	return strings.TrimSpace(cfg.Pager), nil
+}
+
+// LoadEditor reads config.toml and returns the editor command line set by
+// the editor key, such as "code --wait". Returns "" if the config file or
+// key is missing, or if the file is malformed TOML (see CheckConfig);
+// callers then fall back to vi.
+func LoadEditor() (string, error) {
+	cfgPath, err := ConfigPath()
+	if err != nil {
+		return "", nil // Default on error
+	}
+
+	data, err := os.ReadFile(cfgPath)
+	if err != nil {
+		return "", nil // Default if config doesn't exist or can't be read
+	}
+
+	var cfg struct {
+		Editor string `toml:"editor"`
+	}
+
+	if err := toml.Unmarshal(data, &cfg); err != nil {
+		// Malformed TOML - return default
+		return "", nil
+	}
+
+	return strings.TrimSpace(cfg.Editor), nil
+}
@@ -0,0 +1,314 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// setting describes one config.toml key that 'config get' and 'config set'
+// know about. Keys in a table, such as the [list] section, are written
+// dotted: "list.limit".
+type setting struct {
+	// parse validates a value given on the command line and converts it
+	// to what is stored in config.toml.
+	parse func(raw string) (any, error)
+	// effective returns the value in use, with defaults filled in.
+	effective func() (string, error)
+}
+
+// settings are the known keys, in the order 'config get' prints them.
+var settings = []struct {
+	key string
+	setting
+}{
+	{DefaultWorkspaceKey, setting{parseString, effectiveWorkspace}},
+	{DateLocaleKey, setting{parseDateLocale, func() (string, error) {
+		l, err := LoadDateLocale()
+		return string(l), err
+	}}},
+	{DateFormatKey, setting{parseDateFormat, LoadDisplayDateFormat}},
+	{TimezoneKey, setting{parseTimezone, func() (string, error) {
+		loc, err := LoadTimezone()
+		if err != nil {
+			return "", err
+		}
+		return loc.String(), nil
+	}}},
+	{DefaultTagsKey, setting{parseTags, func() (string, error) {
+		tags, err := LoadDefaultTags()
+		return strings.Join(tags, ","), err
+	}}},
+	{BucketWidthKey, setting{parseBucketWidth, func() (string, error) {
+		w, err := LoadBucketWidth()
+		return strconv.Itoa(w), err
+	}}},
+	{GitAutocommitKey, setting{parseBool, effectiveBool(LoadGitAutocommit)}},
+	{AutoReindexKey, setting{parseBool, effectiveBool(LoadAutoReindex)}},
+	{PagerKey, setting{parseString, LoadPager}},
+	{EditorKey, setting{parseString, LoadEditor}},
+	{MaxAttachmentKey, setting{parseNonNegativeInt, func() (string, error) {
+		n, err := LoadMaxAttachmentBytes()
+		return strconv.FormatInt(n, 10), err
+	}}},
+	{LinkCheckTimeoutKey, setting{parseDuration, func() (string, error) {
+		d, err := LoadLinkCheckTimeout()
+		return d.String(), err
+	}}},
+	{AttachFetchTitleKey, setting{parseBool, effectiveBool(LoadAttachFetchTitle)}},
+	{RenderMarkdownKey, setting{parseBool, effectiveBool(LoadRenderMarkdown)}},
+	{"list.show_all", setting{parseBool, effectiveList(func(d ListDefaults) string { return strconv.FormatBool(d.ShowAll) })}},
+	{"list.relative_dates", setting{parseBool, effectiveList(func(d ListDefaults) string { return strconv.FormatBool(d.RelativeDates) })}},
+	{"list.group_by", setting{parseGroupBy, effectiveList(func(d ListDefaults) string { return d.GroupBy })}},
+	{"list.limit", setting{parseNonNegativeInt, effectiveList(func(d ListDefaults) string { return strconv.Itoa(d.Limit) })}},
+}
+
+// SettingKeys returns the keys 'config get' and 'config set' accept.
+func SettingKeys() []string {
+	keys := make([]string, len(settings))
+	for i, s := range settings {
+		keys[i] = s.key
+	}
+	return keys
+}
+
+// lookupSetting returns the known setting for key.
+func lookupSetting(key string) (setting, error) {
+	for _, s := range settings {
+		if s.key == key {
+			return s.setting, nil
+		}
+	}
+	return setting{}, fmt.Errorf("unknown config key %q", key)
+}
+
+// EffectiveSetting returns the value in use for key: the configured value,
+// or the default when it is unset or invalid.
+func EffectiveSetting(key string) (string, error) {
+	s, err := lookupSetting(key)
+	if err != nil {
+		return "", err
+	}
+	return s.effective()
+}
+
+// SetSetting validates raw for key and writes it to config.toml, creating
+// the file if needed. Other keys and tables, such as [alias], are kept;
+// comments and formatting are not. A malformed config.toml is left alone
+// and reported as an error.
+func SetSetting(key, raw string) error {
+	s, err := lookupSetting(key)
+	if err != nil {
+		return err
+	}
+	value, err := s.parse(strings.TrimSpace(raw))
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", key, err)
+	}
+
+	return updateConfig(func(cfg map[string]any) error {
+		// Walk to the key's table, creating it if needed
+		table := cfg
+		parts := strings.Split(key, ".")
+		for _, name := range parts[:len(parts)-1] {
+			sub, ok := table[name].(map[string]any)
+			if !ok {
+				sub = make(map[string]any)
+				table[name] = sub
+			}
+			table = sub
+		}
+		table[parts[len(parts)-1]] = value
+		return nil
+	})
+}
+
+// SetAlias writes name = target to the [alias] table of config.toml,
+// replacing any existing alias of that name. Callers check that the alias
+// is valid (see SetSetting for what is kept).
+func SetAlias(name, target string) error {
+	return updateConfig(func(cfg map[string]any) error {
+		table, ok := cfg["alias"].(map[string]any)
+		if !ok {
+			table = make(map[string]any)
+			cfg["alias"] = table
+		}
+		table[name] = target
+		return nil
+	})
+}
+
+// errNoAlias reports that RemoveAlias found nothing to remove.
+var errNoAlias = errors.New("no such alias")
+
+// RemoveAlias deletes name from the [alias] table of config.toml. It
+// reports false, without touching the file, if there is no such alias.
+func RemoveAlias(name string) (bool, error) {
+	err := updateConfig(func(cfg map[string]any) error {
+		table, ok := cfg["alias"].(map[string]any)
+		if _, exists := table[name]; !ok || !exists {
+			return errNoAlias
+		}
+		delete(table, name)
+		return nil
+	})
+	if errors.Is(err, errNoAlias) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// updateConfig reads config.toml, applies edit, and writes the result
+// back, creating the file if needed. If edit fails nothing is written. A
+// malformed config.toml is left alone and reported as an error.
+func updateConfig(edit func(cfg map[string]any) error) error {
+	cfgPath, err := ConfigPath()
+	if err != nil {
+		return err
+	}
+	cfg := make(map[string]any)
+	data, err := os.ReadFile(cfgPath)
+	switch {
+	case err == nil:
+		if err := toml.Unmarshal(data, &cfg); err != nil {
+			return fmt.Errorf("malformed config %s: %w", cfgPath, err)
+		}
+	case !errors.Is(err, os.ErrNotExist):
+		return err
+	}
+
+	if err := edit(cfg); err != nil {
+		return err
+	}
+	return writeConfig(cfgPath, cfg)
+}
+
+// writeConfig replaces the config file at path with cfg, via a temporary
+// file so a failed write leaves the old config in place.
+func writeConfig(path string, cfg map[string]any) error {
+	data, err := toml.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+func parseString(raw string) (any, error) {
+	return raw, nil
+}
+
+func parseBool(raw string) (any, error) {
+	b, err := strconv.ParseBool(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%q is not true or false", raw)
+	}
+	return b, nil
+}
+
+func parseNonNegativeInt(raw string) (any, error) {
+	n, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || n < 0 {
+		return nil, fmt.Errorf("%q is not a non-negative integer", raw)
+	}
+	return n, nil
+}
+
+func parseDateLocale(raw string) (any, error) {
+	switch l := DateLocale(strings.ToLower(raw)); l {
+	case DateLocaleISO, DateLocaleUS, DateLocaleEU:
+		return string(l), nil
+	}
+	return nil, fmt.Errorf("%q must be iso, us or eu", raw)
+}
+
+func parseDateFormat(raw string) (any, error) {
+	switch strings.ToLower(raw) {
+	case string(DateLocaleISO), string(DateLocaleUS), string(DateLocaleEU):
+		return strings.ToLower(raw), nil
+	}
+	// Same test as LoadDisplayDateFormat: a layout must format a date
+	if raw == "" || time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC).Format(raw) == raw {
+		return nil, fmt.Errorf("%q must be iso, us, eu or a Go time layout", raw)
+	}
+	return raw, nil
+}
+
+func parseTimezone(raw string) (any, error) {
+	if _, err := time.LoadLocation(raw); err != nil || raw == "" {
+		return nil, fmt.Errorf("%q is not an IANA time zone name", raw)
+	}
+	return raw, nil
+}
+
+func parseTags(raw string) (any, error) {
+	tags := []string{}
+	for _, tag := range strings.Split(raw, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags, nil
+}
+
+func parseBucketWidth(raw string) (any, error) {
+	n, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || n < 1 || n > maxBucketWidth {
+		return nil, fmt.Errorf("%q must be between 1 and %d", raw, maxBucketWidth)
+	}
+	return n, nil
+}
+
+func parseDuration(raw string) (any, error) {
+	d, err := time.ParseDuration(raw)
+	if err != nil || d <= 0 {
+		return nil, fmt.Errorf("%q is not a positive duration such as 5s", raw)
+	}
+	return raw, nil
+}
+
+func parseGroupBy(raw string) (any, error) {
+	switch raw {
+	case "", "project", "tag", "status":
+		return raw, nil
+	}
+	return nil, fmt.Errorf("%q must be project, tag or status", raw)
+}
+
+func effectiveWorkspace() (string, error) {
+	ws, ok, err := LoadDefaultWorkspace()
+	if err != nil || ok {
+		return ws, err
+	}
+	return DefaultDataDir()
+}
+
+func effectiveBool(load func() (bool, error)) func() (string, error) {
+	return func() (string, error) {
+		b, err := load()
+		return strconv.FormatBool(b), err
+	}
+}
+
+func effectiveList(field func(ListDefaults) string) func() (string, error) {
+	return func() (string, error) {
+		d, err := LoadListDefaults()
+		return field(d), err
+	}
+}
@@ -39,7 +39,7 @@ func (c FixedClock) Now() time.Time {
 //   - input: the date string to parse
 //   - locale: the date locale (iso, us, eu)
 //   - clock: provides current time for year-omitted dates
-//   - tz: timezone for determining "today" (defaults to America/Los_Angeles)
+//   - tz: timezone for determining "today" (defaults to the machine's local zone)
 //
 // Returns:
 //   - canonical date string (YYYY-MM-DD)
@@ -52,11 +52,7 @@ func ParseDate(input string, locale config.DateLocale, clock Clock, tz *time.Loc
 
 	// Default timezone
 	if tz == nil {
-		var err error
-		tz, err = time.LoadLocation("America/Los_Angeles")
-		if err != nil {
-			tz = time.UTC
-		}
+		tz = time.Local
 	}
 
 	now := clock.Now().In(tz)
@@ -107,14 +103,44 @@ func ParseDate(input string, locale config.DateLocale, clock Clock, tz *time.Loc
 	return "", fmt.Errorf("invalid due date: unable to parse %q", input)
 }
 
-// parseShortcuts handles date shortcuts like "today", "+1", "+2", etc.
+// weekdayNames maps full and three-letter weekday names to time.Weekday.
+var weekdayNames = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+// parseShortcuts handles date shortcuts like "today", "tomorrow", "eow",
+// weekday names, "+1", "+2", etc.
 func parseShortcuts(input string, today time.Time) (string, error) {
 	input = strings.ToLower(strings.TrimSpace(input))
 
-	// Check for "today"
+	// Check for "today" and "tomorrow"
 	if input == "today" {
 		return today.Format("2006-01-02"), nil
 	}
+	if input == "tomorrow" {
+		return today.AddDate(0, 0, 1).Format("2006-01-02"), nil
+	}
+
+	// Check for a weekday name: its next occurrence, a week out if it is today
+	if wd, ok := weekdayNames[input]; ok {
+		days := (int(wd) - int(today.Weekday()) + 7) % 7
+		if days == 0 {
+			days = 7
+		}
+		return today.AddDate(0, 0, days).Format("2006-01-02"), nil
+	}
+
+	// Check for "eow" (end of week): the coming Sunday, or today if it is Sunday
+	if input == "eow" {
+		daysToSunday := (7 - int(today.Weekday())) % 7
+		return today.AddDate(0, 0, daysToSunday).Format("2006-01-02"), nil
+	}
 
 	// Check for "+N" pattern where N is a positive integer
 	if strings.HasPrefix(input, "+") {
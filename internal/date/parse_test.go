@@ -266,6 +266,30 @@ func TestParseDate_ErrorMessages(t *testing.T) {
 	}
 }
 
+func TestParseDate_EndOfWeek(t *testing.T) {
+	tests := []struct {
+		name     string
+		today    time.Time
+		expected string
+	}{
+		{"Saturday", time.Date(2025, 12, 20, 10, 0, 0, 0, time.UTC), "2025-12-21"},
+		{"Sunday is its own end of week", time.Date(2025, 12, 21, 10, 0, 0, 0, time.UTC), "2025-12-21"},
+		{"crosses year", time.Date(2025, 12, 29, 10, 0, 0, 0, time.UTC), "2026-01-04"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseDate("eow", config.DateLocaleISO, FixedClock{FixedTime: tt.today}, time.UTC)
+			if err != nil {
+				t.Fatalf("ParseDate() error = %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("ParseDate() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
 func TestParseDate_Shortcuts(t *testing.T) {
 	// Use a fixed date: 2025-12-15
 	clock := FixedClock{FixedTime: time.Date(2025, 12, 15, 10, 0, 0, 0, time.UTC)}
@@ -287,6 +311,12 @@ func TestParseDate_Shortcuts(t *testing.T) {
 		{"+7", "+7", "2025-12-22", config.DateLocaleISO, false},
 		{"+30", "+30", "2026-01-14", config.DateLocaleISO, false},
 		{"+365", "+365", "2026-12-15", config.DateLocaleISO, false},
+		{"eow from Monday", "eow", "2025-12-21", config.DateLocaleISO, false},
+		{"EOW uppercase", "EOW", "2025-12-21", config.DateLocaleISO, false},
+		{"tomorrow", "tomorrow", "2025-12-16", config.DateLocaleISO, false},
+		{"weekday later this week", "friday", "2025-12-19", config.DateLocaleISO, false},
+		{"short weekday", "Wed", "2025-12-17", config.DateLocaleISO, false},
+		{"same weekday is next week", "monday", "2025-12-22", config.DateLocaleISO, false},
 		{"today with US locale", "today", "2025-12-15", config.DateLocaleUS, false},
 		{"+1 with EU locale", "+1", "2025-12-16", config.DateLocaleEU, false},
 		{"invalid: +abc", "+abc", "", config.DateLocaleISO, true},
@@ -0,0 +1,37 @@
+package date
+
+import (
+	"fmt"
+	"time"
+)
+
+// Today returns the current calendar day in tz as midnight UTC, the way
+// due dates are stored, so the two can be compared directly.
+func Today(clock Clock, tz *time.Location) time.Time {
+	if tz == nil {
+		tz = time.Local
+	}
+	now := clock.Now().In(tz)
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// RelativeDue describes a due date relative to today (see Today):
+// "due today", "due tomorrow", "due in 3 days", "overdue 2 days".
+func RelativeDue(due, today time.Time) string {
+	due = due.UTC()
+	day := time.Date(due.Year(), due.Month(), due.Day(), 0, 0, 0, 0, time.UTC)
+	days := int(day.Sub(today).Hours() / 24)
+
+	switch {
+	case days == 0:
+		return "due today"
+	case days == 1:
+		return "due tomorrow"
+	case days > 1:
+		return fmt.Sprintf("due in %d days", days)
+	case days == -1:
+		return "overdue 1 day"
+	default:
+		return fmt.Sprintf("overdue %d days", -days)
+	}
+}
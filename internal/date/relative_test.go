@@ -0,0 +1,44 @@
+package date
+
+import (
+	"testing"
+	"time"
+)
+
+func TestToday(t *testing.T) {
+	// 23:30 UTC on the 10th is already the 11th in Auckland
+	clock := FixedClock{FixedTime: time.Date(2026, 3, 10, 23, 30, 0, 0, time.UTC)}
+	auckland, err := time.LoadLocation("Pacific/Auckland")
+	if err != nil {
+		t.Skipf("tzdata not available: %v", err)
+	}
+
+	if got, want := Today(clock, time.UTC), time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("Today(UTC) = %v, want %v", got, want)
+	}
+	if got, want := Today(clock, auckland), time.Date(2026, 3, 11, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("Today(Auckland) = %v, want %v", got, want)
+	}
+}
+
+func TestRelativeDue(t *testing.T) {
+	today := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		due  time.Time
+		want string
+	}{
+		{today, "due today"},
+		{today.AddDate(0, 0, 1), "due tomorrow"},
+		{today.AddDate(0, 0, 3), "due in 3 days"},
+		{today.AddDate(0, 0, -1), "overdue 1 day"},
+		{today.AddDate(0, 0, -2), "overdue 2 days"},
+		{today.AddDate(0, 1, 0), "due in 31 days"},
+		{time.Date(2026, 3, 12, 15, 0, 0, 0, time.UTC), "due in 2 days"},
+	}
+	for _, tt := range tests {
+		if got := RelativeDue(tt.due, today); got != tt.want {
+			t.Errorf("RelativeDue(%s) = %q, want %q", tt.due.Format(time.RFC3339), got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package store
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/sjatkinson/threadkeeper/internal/task"
+)
+
+// IndexFileName is the cache at the workspace root that lets LoadAll skip
+// reading thread files that haven't changed since they were last parsed.
+const IndexFileName = ".index.json"
+
+// indexVersion is bumped whenever the cached task layout changes, so older
+// index files are discarded instead of misread.
+const indexVersion = 4
+
+// racyWindow is how recently a thread file may have been modified and still
+// be cached. A file written again within the filesystem's mtime granularity
+// could keep the same mtime and size, so such files are re-read until they
+// are older than this.
+const racyWindow = 2 * time.Second
+
+// taskIndex is the on-disk index: every cached task keyed by the path of its
+// thread.json relative to the threads directory.
+type taskIndex struct {
+	Version    int                   `json:"version"`
+	ThreadsDir string                `json:"threads_dir"`
+	Entries    map[string]indexEntry `json:"entries"`
+}
+
+// indexEntry is a parsed task along with the mtime and size of the thread
+// file it came from. The entry is valid only while both still match.
+type indexEntry struct {
+	ModTime int64      `json:"mtime"`
+	Size    int64      `json:"size"`
+	Task    *task.Task `json:"task"`
+}
+
+// IndexFile returns the path to the index for this store.
+// The workspace is the parent of the threads directory.
+func (s *FileStore) IndexFile() string {
+	return filepath.Join(filepath.Dir(s.threadsDir), IndexFileName)
+}
+
+// readIndex loads the index, returning an empty one when it is missing,
+// unreadable, from another version, or built for a different threads directory.
+func (s *FileStore) readIndex() *taskIndex {
+	empty := &taskIndex{Version: indexVersion, ThreadsDir: s.threadsDir, Entries: map[string]indexEntry{}}
+
+	data, err := os.ReadFile(s.IndexFile())
+	if err != nil {
+		return empty
+	}
+	var idx taskIndex
+	if err := json.Unmarshal(data, &idx); err != nil {
+		return empty
+	}
+	if idx.Version != indexVersion || idx.ThreadsDir != s.threadsDir || idx.Entries == nil {
+		return empty
+	}
+	return &idx
+}
+
+// writeIndex replaces the index atomically. The index is only a cache, so
+// callers ignore errors: the next LoadAll simply re-reads the thread files.
+func (s *FileStore) writeIndex(idx *taskIndex) error {
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return err
+	}
+
+	// A unique temp file keeps concurrent readers from clobbering each other
+	tmp, err := os.CreateTemp(filepath.Dir(s.IndexFile()), IndexFileName+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), s.IndexFile()); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
+
+// loadResult is the outcome of loading one thread file in LoadAll.
+type loadResult struct {
+	task   *task.Task
+	cached bool        // task came from an unchanged index entry
+	entry  *indexEntry // entry to store in the index, if the file may be cached
+	err    error
+}
+
+// loadCached loads the thread file at path, known to the index as rel, from
+// idx when the file is unchanged and by parsing it otherwise. It only reads
+// idx, so LoadAll can call it from several goroutines at once.
+func (s *FileStore) loadCached(idx *taskIndex, rel, path string, now time.Time) loadResult {
+	info, err := os.Stat(path)
+	if err != nil {
+		// Let loadTask produce the usual error
+		_, err = s.loadTask(path)
+		return loadResult{err: err}
+	}
+
+	if e, ok := idx.Entries[rel]; ok && e.Task != nil && e.ModTime == info.ModTime().UnixNano() && e.Size == info.Size() {
+		return loadResult{task: e.Task, cached: true}
+	}
+
+	t, err := s.loadTask(path)
+	if err != nil {
+		return loadResult{err: err}
+	}
+	if now.Sub(info.ModTime()) < racyWindow {
+		return loadResult{task: t}
+	}
+	return loadResult{task: t, entry: &indexEntry{ModTime: info.ModTime().UnixNano(), Size: info.Size(), Task: t}}
+}
+
+// RebuildIndex discards the index and builds it again from every thread
+// file. Returns the number of tasks loaded.
+func (s *FileStore) RebuildIndex() (int, error) {
+	if err := os.Remove(s.IndexFile()); err != nil && !os.IsNotExist(err) {
+		return 0, err
+	}
+	tasks, err := s.LoadAll()
+	if err != nil {
+		return 0, err
+	}
+	return len(tasks), nil
+}
@@ -0,0 +1,83 @@
+package store
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/sjatkinson/threadkeeper/internal/task"
+)
+
+func TestLoadAllUsesIndex(t *testing.T) {
+	st := NewFileStore(t.TempDir())
+
+	tk := &task.Task{ID: "01ARZ3NDEKTSV4RRFFQ69G5FAV", Title: "original", Status: task.StatusOpen, CreatedAt: time.Now().UTC(), Tags: []string{}}
+	save := func(title string, mtime time.Time) {
+		t.Helper()
+		tk.Title = title
+		if err := st.Save(tk); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+		// Age the file past the racy window so it can be cached
+		if err := os.Chtimes(st.ThreadFile(tk.ID), mtime, mtime); err != nil {
+			t.Fatalf("Chtimes() error = %v", err)
+		}
+	}
+	loadTitle := func() string {
+		t.Helper()
+		tasks, err := st.LoadAll()
+		if err != nil || len(tasks) != 1 {
+			t.Fatalf("LoadAll() = %d tasks, err = %v; want 1", len(tasks), err)
+		}
+		return tasks[0].Title
+	}
+
+	save("original", time.Now().Add(-time.Hour))
+	if got := loadTitle(); got != "original" {
+		t.Fatalf("LoadAll() title = %q, want original", got)
+	}
+
+	// Tamper with the cached copy: an unchanged thread file is served from it
+	idx := st.readIndex()
+	if len(idx.Entries) != 1 {
+		t.Fatalf("index entries = %d, want 1", len(idx.Entries))
+	}
+	for rel, e := range idx.Entries {
+		e.Task.Title = "cached"
+		idx.Entries[rel] = e
+	}
+	if err := st.writeIndex(idx); err != nil {
+		t.Fatalf("writeIndex() error = %v", err)
+	}
+	if got := loadTitle(); got != "cached" {
+		t.Errorf("LoadAll() title = %q, want the cached copy", got)
+	}
+
+	// A newer thread file is parsed again
+	save("updated", time.Now().Add(-time.Minute))
+	if got := loadTitle(); got != "updated" {
+		t.Errorf("LoadAll() title after save = %q, want updated", got)
+	}
+
+	// Rebuilding discards the index entirely
+	if n, err := st.RebuildIndex(); err != nil || n != 1 {
+		t.Errorf("RebuildIndex() = %d, %v; want 1 task", n, err)
+	}
+
+	// Removed threads drop out of the index
+	if err := os.RemoveAll(st.ThreadDir(tk.ID)); err != nil {
+		t.Fatalf("RemoveAll() error = %v", err)
+	}
+	if tasks, err := st.LoadAll(); err != nil || len(tasks) != 0 {
+		t.Errorf("LoadAll() after remove = %d tasks, err = %v; want none", len(tasks), err)
+	}
+	data, err := os.ReadFile(st.IndexFile())
+	if err != nil {
+		t.Fatalf("ReadFile(index) error = %v", err)
+	}
+	var onDisk taskIndex
+	if err := json.Unmarshal(data, &onDisk); err != nil || len(onDisk.Entries) != 0 {
+		t.Errorf("index after remove = %s, err = %v; want no entries", data, err)
+	}
+}
@@ -0,0 +1,128 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// LockFileName is the advisory lock file created at the workspace root.
+const LockFileName = ".tk.lock"
+
+// DefaultLockTimeout is how long mutating operations wait for the workspace lock.
+const DefaultLockTimeout = 5 * time.Second
+
+// lockRetryInterval is how often a busy lock is retried.
+const lockRetryInterval = 50 * time.Millisecond
+
+// ErrWorkspaceBusy is returned when the workspace lock cannot be acquired in time.
+var ErrWorkspaceBusy = errors.New("workspace is busy: another tk process is modifying it")
+
+// errLockHeld is returned by the platform tryLockFile when another holder has the lock.
+var errLockHeld = errors.New("lock held")
+
+// heldLock tracks a lock file held by this process.
+// depth makes the lock re-entrant, so Save inside EnsureShortID (or inside a
+// WithLock block) doesn't deadlock against itself. Ownership is per process,
+// not per goroutine: see AcquireLock.
+type heldLock struct {
+	f     *os.File
+	depth int
+}
+
+var (
+	locksMu   sync.Mutex
+	heldLocks = make(map[string]*heldLock)
+)
+
+// AcquireLock takes the advisory lock at path, waiting up to timeout.
+// Locks are re-entrant within a process: nested calls for the same path
+// succeed immediately and must each be paired with a ReleaseLock.
+// Returns an error wrapping ErrWorkspaceBusy on timeout.
+//
+// The lock excludes other processes only. A second goroutine in this
+// process also succeeds immediately, so goroutines that change the
+// workspace concurrently must serialize themselves (as serve does with
+// serveMu).
+func AcquireLock(path string, timeout time.Duration) error {
+	locksMu.Lock()
+	defer locksMu.Unlock()
+
+	if h, ok := heldLocks[path]; ok {
+		h.depth++
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create lock directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open lock file: %w", err)
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		err := tryLockFile(f)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, errLockHeld) {
+			f.Close()
+			return fmt.Errorf("failed to lock %s: %w", path, err)
+		}
+		if time.Now().After(deadline) {
+			f.Close()
+			return fmt.Errorf("%w (lock file %s)", ErrWorkspaceBusy, path)
+		}
+		time.Sleep(lockRetryInterval)
+	}
+
+	heldLocks[path] = &heldLock{f: f, depth: 1}
+	return nil
+}
+
+// ReleaseLock releases one level of the lock at path taken by AcquireLock.
+// The underlying file lock is dropped when the outermost holder releases.
+func ReleaseLock(path string) error {
+	locksMu.Lock()
+	defer locksMu.Unlock()
+
+	h, ok := heldLocks[path]
+	if !ok {
+		return nil
+	}
+	h.depth--
+	if h.depth > 0 {
+		return nil
+	}
+	delete(heldLocks, path)
+
+	err := unlockFile(h.f)
+	if cerr := h.f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// lockPath returns the workspace lock file for this store.
+// The workspace is the parent of the threads directory.
+func (s *FileStore) lockPath() string {
+	return filepath.Join(filepath.Dir(s.threadsDir), LockFileName)
+}
+
+// WithLock runs fn while holding the workspace lock.
+// Use it to make multi-step changes (read, compute, write) atomic with
+// respect to other tk processes; it does not exclude other goroutines of
+// this process (see AcquireLock).
+func (s *FileStore) WithLock(fn func() error) error {
+	path := s.lockPath()
+	if err := AcquireLock(path, DefaultLockTimeout); err != nil {
+		return err
+	}
+	defer ReleaseLock(path)
+	return fn()
+}
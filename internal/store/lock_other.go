@@ -0,0 +1,16 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd || windows)
+
+package store
+
+import "os"
+
+// tryLockFile is a no-op on platforms without flock or LockFileEx.
+// Mutating commands still run; they just aren't protected across processes.
+func tryLockFile(f *os.File) error {
+	return nil
+}
+
+// unlockFile is a no-op on platforms without file locking support.
+func unlockFile(f *os.File) error {
+	return nil
+}
@@ -0,0 +1,74 @@
+package store
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sjatkinson/threadkeeper/internal/task"
+)
+
+func TestAcquireLock_Reentrant(t *testing.T) {
+	path := filepath.Join(t.TempDir(), LockFileName)
+
+	if err := AcquireLock(path, time.Second); err != nil {
+		t.Fatalf("AcquireLock() error = %v", err)
+	}
+	// Nested acquire in the same process must not deadlock
+	if err := AcquireLock(path, 100*time.Millisecond); err != nil {
+		t.Fatalf("nested AcquireLock() error = %v", err)
+	}
+	if err := ReleaseLock(path); err != nil {
+		t.Fatalf("ReleaseLock() error = %v", err)
+	}
+	if err := ReleaseLock(path); err != nil {
+		t.Fatalf("outer ReleaseLock() error = %v", err)
+	}
+
+	// Fully released: acquiring again works
+	if err := AcquireLock(path, 100*time.Millisecond); err != nil {
+		t.Fatalf("AcquireLock() after release error = %v", err)
+	}
+	_ = ReleaseLock(path)
+}
+
+func TestSave_BusyWorkspace(t *testing.T) {
+	tmpDir := t.TempDir()
+	threadsDir := filepath.Join(tmpDir, "threads")
+	st := NewFileStore(threadsDir)
+
+	// Simulate another process holding the lock with a separate file handle
+	other, err := os.OpenFile(filepath.Join(tmpDir, LockFileName), os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		t.Fatalf("Failed to open lock file: %v", err)
+	}
+	defer other.Close()
+	if err := tryLockFile(other); err != nil {
+		t.Skipf("file locking not supported here: %v", err)
+	}
+
+	path := st.lockPath()
+	start := time.Now()
+	err = AcquireLock(path, 200*time.Millisecond)
+	if !errors.Is(err, ErrWorkspaceBusy) {
+		if err == nil {
+			_ = ReleaseLock(path)
+			t.Skip("platform does not enforce advisory locks between handles")
+		}
+		t.Fatalf("AcquireLock() error = %v, want ErrWorkspaceBusy", err)
+	}
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Errorf("AcquireLock() gave up after %v, want it to wait for the timeout", elapsed)
+	}
+
+	// Once the other holder releases, Save goes through
+	if err := unlockFile(other); err != nil {
+		t.Fatalf("Failed to release other lock: %v", err)
+	}
+	now := time.Now().UTC()
+	if err := st.Save(&task.Task{ID: "AGQTXELQFN43GFTMMU5OA2W2PQ", Status: task.StatusOpen, CreatedAt: now, UpdatedAt: now, Tags: []string{}}); err != nil {
+		t.Errorf("Save() after release error = %v", err)
+	}
+}
@@ -0,0 +1,62 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/sjatkinson/threadkeeper/internal/task"
+)
+
+// Migrate rewrites every thread file older than task.SchemaVersion, in the
+// threads directory and the trash, in the current format. With dryRun set
+// nothing is written. Returns the number of files that were (or would be)
+// migrated and the number examined. Stops at the first file that can't be
+// read or is from a newer schema.
+func (s *FileStore) Migrate(dryRun bool) (int, int, error) {
+	migrated, total := 0, 0
+	err := s.WithLock(func() error {
+		var paths []string
+		for _, pattern := range []string{
+			filepath.Join(s.threadsDir, "*", "*", "thread.json"),
+			filepath.Join(s.TrashDir(), "*", "thread.json"),
+		} {
+			matches, err := filepath.Glob(pattern)
+			if err != nil {
+				return err
+			}
+			paths = append(paths, matches...)
+		}
+
+		for _, path := range paths {
+			total++
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("failed to read task file %s: %w", path, err)
+			}
+			upgraded, from, err := task.Upgrade(data)
+			if err != nil {
+				return fmt.Errorf("task file %s: %w", path, err)
+			}
+			if from == task.SchemaVersion {
+				continue
+			}
+
+			migrated++
+			if dryRun {
+				continue
+			}
+			var t task.Task
+			if err := json.Unmarshal(upgraded, &t); err != nil {
+				return fmt.Errorf("failed to parse task file %s: %w", path, err)
+			}
+			t.Normalize()
+			if err := writeTaskFile(path, &t); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return migrated, total, err
+}
@@ -0,0 +1,66 @@
+package store
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sjatkinson/threadkeeper/internal/task"
+)
+
+func TestMigrate(t *testing.T) {
+	threadsDir := t.TempDir()
+	st := NewFileStore(threadsDir)
+
+	write := func(id, body string) string {
+		t.Helper()
+		if err := os.MkdirAll(st.ThreadDir(id), 0755); err != nil {
+			t.Fatalf("MkdirAll() error = %v", err)
+		}
+		path := st.ThreadFile(id)
+		if err := os.WriteFile(path, []byte(body), 0644); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+		return path
+	}
+	oldPath := write("01ARZ3NDEKTSV4RRFFQ69G5FAV", `{"id":"01ARZ3NDEKTSV4RRFFQ69G5FAV","title":"old","status":"done","created_at":"2026-01-01T00:00:00Z","updated_at":"2026-01-02T00:00:00Z","tags":[]}`)
+
+	// Old files load, upgraded in memory
+	tasks, err := st.LoadAll()
+	if err != nil || len(tasks) != 1 || tasks[0].CompletedAt == nil {
+		t.Fatalf("LoadAll() = %v, err %v; want the old task with completed_at", tasks, err)
+	}
+
+	if migrated, total, err := st.Migrate(true); err != nil || migrated != 1 || total != 1 {
+		t.Fatalf("Migrate(dry run) = %d, %d, %v; want 1, 1", migrated, total, err)
+	}
+	if data, _ := os.ReadFile(oldPath); strings.Contains(string(data), "schema_version") {
+		t.Fatalf("dry run rewrote %s", oldPath)
+	}
+
+	if migrated, _, err := st.Migrate(false); err != nil || migrated != 1 {
+		t.Fatalf("Migrate() = %d, %v; want 1", migrated, err)
+	}
+	data, err := os.ReadFile(oldPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := fmt.Sprintf(`"schema_version": %d`, task.SchemaVersion); !strings.Contains(string(data), want) || !strings.Contains(string(data), `"completed_at"`) {
+		t.Errorf("migrated file = %s, want %s and completed_at", data, want)
+	}
+	if migrated, _, err := st.Migrate(false); err != nil || migrated != 0 {
+		t.Errorf("second Migrate() = %d, %v; want nothing to do", migrated, err)
+	}
+
+	// Files from a newer tk are refused, not half-read
+	newerPath := write("01ARZ3NDEKTSV4RRFFQ69G5FBW", `{"id":"01ARZ3NDEKTSV4RRFFQ69G5FBW","title":"future","schema_version":99}`)
+	_, skipped, err := st.LoadAllWithSkipped()
+	if err != nil || len(skipped) != 1 || filepath.Clean(skipped[0].Path) != filepath.Clean(newerPath) || !strings.Contains(skipped[0].Err.Error(), "upgrade tk") {
+		t.Errorf("LoadAllWithSkipped() skipped = %v, err %v; want the newer file with an upgrade message", skipped, err)
+	}
+	if _, _, err := st.Migrate(false); err == nil {
+		t.Error("Migrate() with a newer file succeeded, want an error")
+	}
+}
@@ -2,7 +2,16 @@ package store
 
 import "path/filepath"
 
-// ThreadPath returns the canonical filesystem path for a thread directory.
+// DefaultBucketWidth is the number of leading ID characters used to name a
+// thread's bucket directory. It is 2 for every workspace created before the
+// width became configurable.
+const DefaultBucketWidth = 2
+
+// MaxBucketWidth is the largest supported bucket width.
+const MaxBucketWidth = 4
+
+// ThreadPath returns the canonical filesystem path for a thread directory
+// using the default bucket width.
 // Path function: bucket = tid[0:2], path = threads/{bucket}/{tid}/
 //
 // This function must be:
@@ -10,11 +19,31 @@ import "path/filepath"
 //   - Stable across versions (algorithm must never change)
 //   - Single source of truth (only implemented here)
 func ThreadPath(threadsDir, threadID string) string {
-	bucket := threadID[0:2]
-	return filepath.Join(threadsDir, bucket, threadID)
+	return ThreadPathWidth(threadsDir, threadID, DefaultBucketWidth)
+}
+
+// ThreadPathWidth returns the thread directory path for an explicit bucket width:
+// bucket = tid[0:width], path = threads/{bucket}/{tid}/
+//
+// Changing the width of an existing workspace moves every thread, so it
+// requires a re-bucket migration (see FileStore.Rebucket).
+func ThreadPathWidth(threadsDir, threadID string, width int) string {
+	return filepath.Join(threadsDir, bucketName(threadID, width), threadID)
 }
 
 // ThreadFilePath returns the path to thread.json within a thread directory.
 func ThreadFilePath(threadsDir, threadID string) string {
 	return filepath.Join(ThreadPath(threadsDir, threadID), "thread.json")
 }
+
+// bucketName returns the bucket directory name for a thread ID.
+// Widths outside 1..MaxBucketWidth fall back to the default.
+func bucketName(threadID string, width int) string {
+	if width < 1 || width > MaxBucketWidth {
+		width = DefaultBucketWidth
+	}
+	if len(threadID) < width {
+		return threadID
+	}
+	return threadID[0:width]
+}
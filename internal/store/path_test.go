@@ -0,0 +1,105 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sjatkinson/threadkeeper/internal/task"
+)
+
+func TestThreadPathWidth(t *testing.T) {
+	threadsDir := filepath.Join("ws", "threads")
+	id := "AGQTXELQFN43GFTMMU5OA2W2PQ"
+
+	tests := []struct {
+		name  string
+		width int
+		want  string
+	}{
+		{"width 1", 1, filepath.Join(threadsDir, "A", id)},
+		{"width 2", 2, filepath.Join(threadsDir, "AG", id)},
+		{"width 4", 4, filepath.Join(threadsDir, "AGQT", id)},
+		{"invalid width uses default", 0, filepath.Join(threadsDir, "AG", id)},
+		{"too wide uses default", 9, filepath.Join(threadsDir, "AG", id)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ThreadPathWidth(threadsDir, id, tt.width); got != tt.want {
+				t.Errorf("ThreadPathWidth(width=%d) = %q, want %q", tt.width, got, tt.want)
+			}
+		})
+	}
+
+	// ThreadPath must keep the historical 2-character layout
+	if got, want := ThreadPath(threadsDir, id), filepath.Join(threadsDir, "AG", id); got != want {
+		t.Errorf("ThreadPath() = %q, want %q", got, want)
+	}
+}
+
+func TestRebucket(t *testing.T) {
+	threadsDir := filepath.Join(t.TempDir(), "threads")
+	ids := []string{"AGQTXELQFN43GFTMMU5OA2W2PQ", "AGZZXELQFN43GFTMMU5OA2W2PQ", "BQQTXELQFN43GFTMMU5OA2W2PQ"}
+
+	// Write tasks using width 2
+	narrow := NewFileStoreWithBucketWidth(threadsDir, 2)
+	now := time.Now().UTC()
+	for _, id := range ids {
+		if err := narrow.Save(&task.Task{ID: id, Title: id, Status: task.StatusDone, CreatedAt: now, UpdatedAt: now, Tags: []string{}}); err != nil {
+			t.Fatalf("Save(%s) error = %v", id, err)
+		}
+	}
+
+	// Migrate to width 1
+	wide := NewFileStoreWithBucketWidth(threadsDir, 1)
+	moved, err := wide.Rebucket()
+	if err != nil {
+		t.Fatalf("Rebucket() error = %v", err)
+	}
+	if moved != len(ids) {
+		t.Errorf("Rebucket() moved %d threads, want %d", moved, len(ids))
+	}
+
+	for _, id := range ids {
+		if _, err := os.Stat(filepath.Join(threadsDir, id[:1], id, "thread.json")); err != nil {
+			t.Errorf("thread %s not found in width-1 bucket: %v", id, err)
+		}
+		if _, err := wide.GetByID(id); err != nil {
+			t.Errorf("GetByID(%s) after rebucket error = %v", id, err)
+		}
+	}
+
+	// Old buckets should be gone
+	for _, bucket := range []string{"AG", "BQ"} {
+		if _, err := os.Stat(filepath.Join(threadsDir, bucket)); !os.IsNotExist(err) {
+			t.Errorf("old bucket %s still exists (err = %v)", bucket, err)
+		}
+	}
+
+	// Running again is a no-op
+	moved, err = wide.Rebucket()
+	if err != nil {
+		t.Fatalf("second Rebucket() error = %v", err)
+	}
+	if moved != 0 {
+		t.Errorf("second Rebucket() moved %d threads, want 0", moved)
+	}
+
+	// And back to width 2
+	moved, err = narrow.Rebucket()
+	if err != nil {
+		t.Fatalf("Rebucket() back to width 2 error = %v", err)
+	}
+	if moved != len(ids) {
+		t.Errorf("Rebucket() back to width 2 moved %d threads, want %d", moved, len(ids))
+	}
+	tasks, err := narrow.LoadAll()
+	if err != nil {
+		t.Fatalf("LoadAll() error = %v", err)
+	}
+	if len(tasks) != len(ids) {
+		t.Errorf("LoadAll() returned %d tasks, want %d", len(tasks), len(ids))
+	}
+}
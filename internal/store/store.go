@@ -2,41 +2,89 @@ package store
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"sort"
 	"strconv"
+	"sync"
+	"time"
 
 	"github.com/sjatkinson/threadkeeper/internal/task"
 )
 
 // FileStore provides file-based storage for tasks.
 type FileStore struct {
-	threadsDir string
+	threadsDir  string
+	bucketWidth int
 }
 
-// NewFileStore creates a new FileStore for the given threads directory.
+// NewFileStore creates a new FileStore for the given threads directory
+// using the default bucket width.
 func NewFileStore(threadsDir string) *FileStore {
+	return NewFileStoreWithBucketWidth(threadsDir, DefaultBucketWidth)
+}
+
+// NewFileStoreWithBucketWidth creates a new FileStore whose thread directories
+// are bucketed by the first width characters of the thread ID.
+func NewFileStoreWithBucketWidth(threadsDir string, width int) *FileStore {
+	if width < 1 || width > MaxBucketWidth {
+		width = DefaultBucketWidth
+	}
 	return &FileStore{
-		threadsDir: threadsDir,
+		threadsDir:  threadsDir,
+		bucketWidth: width,
 	}
 }
 
+// ThreadDir returns the thread directory for a thread ID in this store.
+func (s *FileStore) ThreadDir(threadID string) string {
+	return ThreadPathWidth(s.threadsDir, threadID, s.bucketWidth)
+}
+
+// ThreadFile returns the path to thread.json for a thread ID in this store.
+func (s *FileStore) ThreadFile(threadID string) string {
+	return filepath.Join(s.ThreadDir(threadID), "thread.json")
+}
+
+// loadWorkers is the number of goroutines LoadAll uses to read thread files.
+// It is a variable so benchmarks can compare against a serial load.
+var loadWorkers = runtime.NumCPU()
+
+// SkippedThread is a bucket or thread file that LoadAll could not read.
+type SkippedThread struct {
+	Path string
+	Err  error
+}
+
 // LoadAll loads all tasks from the threads directory by scanning sharded buckets.
+// Thread files unchanged since the last scan come from the index instead of
+// being parsed again. Unreadable or corrupt thread files are skipped; use
+// LoadAllWithSkipped to find out which.
 func (s *FileStore) LoadAll() ([]*task.Task, error) {
+	tasks, _, err := s.LoadAllWithSkipped()
+	return tasks, err
+}
+
+// LoadAllWithSkipped is LoadAll, but also returns the buckets and thread files
+// that were skipped because they could not be read or parsed.
+func (s *FileStore) LoadAllWithSkipped() ([]*task.Task, []SkippedThread, error) {
 	// Check if threads directory exists
 	entries, err := os.ReadDir(s.threadsDir)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return []*task.Task{}, nil
+			return []*task.Task{}, nil, nil
 		}
-		return nil, fmt.Errorf("failed to read threads directory: %w", err)
+		return nil, nil, fmt.Errorf("failed to read threads directory: %w", err)
 	}
 
-	var tasks []*task.Task
+	var skipped []SkippedThread
 
-	// Scan each bucket directory
+	// Collect every thread file first; parsing them is what's slow
+	type threadFile struct{ rel, path string }
+	var files []threadFile
 	for _, bucketEntry := range entries {
 		if !bucketEntry.IsDir() {
 			continue
@@ -48,6 +96,7 @@ func (s *FileStore) LoadAll() ([]*task.Task, error) {
 		threadEntries, err := os.ReadDir(bucketPath)
 		if err != nil {
 			// Skip buckets that can't be read
+			skipped = append(skipped, SkippedThread{Path: bucketPath, Err: err})
 			continue
 		}
 
@@ -55,17 +104,70 @@ func (s *FileStore) LoadAll() ([]*task.Task, error) {
 			if !threadEntry.IsDir() {
 				continue
 			}
+			files = append(files, threadFile{
+				rel:  filepath.Join(bucketEntry.Name(), threadEntry.Name(), "thread.json"),
+				path: filepath.Join(bucketPath, threadEntry.Name(), "thread.json"),
+			})
+		}
+	}
+
+	// Load the files on a bounded pool of workers. Workers only read idx;
+	// results land in their file's slot and are applied below in order.
+	idx := s.readIndex()
+	now := time.Now()
+	results := make([]loadResult, len(files))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(loadWorkers, len(files)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = s.loadCached(idx, files[i].rel, files[i].path, now)
+			}
+		}()
+	}
+	for i := range files {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
 
-			// Load thread.json from this thread directory
-			threadJSONPath := filepath.Join(bucketPath, threadEntry.Name(), "thread.json")
-			t, err := s.loadTask(threadJSONPath)
-			if err != nil {
-				// Log but continue loading other tasks
-				// In a production system, you might want to log this to stderr
-				continue
+	var tasks []*task.Task
+	seen := make(map[string]bool, len(files))
+	dirty := false
+	for i, f := range files {
+		seen[f.rel] = true
+		r := results[i]
+		switch {
+		case r.cached:
+		case r.entry != nil:
+			idx.Entries[f.rel] = *r.entry
+			dirty = true
+		default:
+			// Unreadable or too recently modified to cache
+			if _, ok := idx.Entries[f.rel]; ok {
+				delete(idx.Entries, f.rel)
+				dirty = true
 			}
-			tasks = append(tasks, t)
 		}
+		if r.err != nil {
+			// Record and continue loading other tasks
+			skipped = append(skipped, SkippedThread{Path: f.path, Err: r.err})
+			continue
+		}
+		tasks = append(tasks, r.task)
+	}
+
+	// Drop entries for threads that were moved or removed
+	for rel := range idx.Entries {
+		if !seen[rel] {
+			delete(idx.Entries, rel)
+			dirty = true
+		}
+	}
+	if dirty {
+		_ = s.writeIndex(idx)
 	}
 
 	// Sort by created_at then ID for consistency
@@ -76,7 +178,7 @@ func (s *FileStore) LoadAll() ([]*task.Task, error) {
 		return tasks[i].ID < tasks[j].ID
 	})
 
-	return tasks, nil
+	return tasks, skipped, nil
 }
 
 // loadTask loads a single task from a JSON file.
@@ -86,6 +188,12 @@ func (s *FileStore) loadTask(path string) (*task.Task, error) {
 		return nil, fmt.Errorf("failed to read task file %s: %w", path, err)
 	}
 
+	// Older files are upgraded in memory; 'tk migrate' rewrites them
+	data, _, err = task.Upgrade(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse task file %s: %w", path, err)
+	}
+
 	var t task.Task
 	if err := json.Unmarshal(data, &t); err != nil {
 		return nil, fmt.Errorf("failed to parse task file %s: %w", path, err)
@@ -100,7 +208,7 @@ func (s *FileStore) loadTask(path string) (*task.Task, error) {
 // GetByID loads a task by its durable ID.
 // If the task is open and missing a short_id, one will be assigned automatically.
 func (s *FileStore) GetByID(id string) (*task.Task, error) {
-	threadPath := ThreadFilePath(s.threadsDir, id)
+	threadPath := s.ThreadFile(id)
 	t, err := s.loadTask(threadPath)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -120,6 +228,7 @@ func (s *FileStore) GetByID(id string) (*task.Task, error) {
 
 // GetByShortID finds a task by its short_id among open tasks only.
 // Returns an error if not found or if multiple open tasks have the same short_id.
+// The scan goes through LoadAll, so unchanged threads come from the index.
 func (s *FileStore) GetByShortID(shortID int) (*task.Task, error) {
 	tasks, err := s.LoadAll()
 	if err != nil {
@@ -144,6 +253,44 @@ func (s *FileStore) GetByShortID(shortID int) (*task.Task, error) {
 	return found, nil
 }
 
+// ShortIDConflict is a short_id shared by more than one open task.
+type ShortIDConflict struct {
+	ShortID int
+	TaskIDs []string // in the order the tasks were given
+}
+
+// CheckShortIDs loads all tasks and reports short_ids shared by more than one
+// open task. Run reindex to resolve them.
+func (s *FileStore) CheckShortIDs() ([]ShortIDConflict, error) {
+	tasks, err := s.LoadAll()
+	if err != nil {
+		return nil, err
+	}
+	return FindShortIDConflicts(tasks), nil
+}
+
+// FindShortIDConflicts reports short_ids shared by more than one open task
+// in tasks, sorted by short_id.
+func FindShortIDConflicts(tasks []*task.Task) []ShortIDConflict {
+	byShortID := make(map[int][]string)
+	for _, t := range tasks {
+		if t.Status == task.StatusOpen && t.ShortID != nil {
+			byShortID[*t.ShortID] = append(byShortID[*t.ShortID], t.ID)
+		}
+	}
+
+	var conflicts []ShortIDConflict
+	for sid, ids := range byShortID {
+		if len(ids) > 1 {
+			conflicts = append(conflicts, ShortIDConflict{ShortID: sid, TaskIDs: ids})
+		}
+	}
+	sort.Slice(conflicts, func(i, j int) bool {
+		return conflicts[i].ShortID < conflicts[j].ShortID
+	})
+	return conflicts
+}
+
 // GenerateNextShortID finds the maximum existing short_id across all tasks
 // and returns max + 1. If none exist, returns 1.
 func (s *FileStore) GenerateNextShortID() (int, error) {
@@ -165,18 +312,29 @@ func (s *FileStore) GenerateNextShortID() (int, error) {
 	return maxSID + 1, nil
 }
 
-// Save saves a task to its thread.json file.
+// Save saves a task to its thread.json file while holding the workspace lock.
 func (s *FileStore) Save(t *task.Task) error {
+	return s.WithLock(func() error {
+		return s.save(t)
+	})
+}
+
+// save writes thread.json; callers must hold the workspace lock.
+func (s *FileStore) save(t *task.Task) error {
 	// Get thread directory path
-	threadDir := ThreadPath(s.threadsDir, t.ID)
+	threadDir := s.ThreadDir(t.ID)
 
 	// Ensure thread directory exists
 	if err := os.MkdirAll(threadDir, 0755); err != nil {
 		return fmt.Errorf("failed to create thread directory: %w", err)
 	}
 
-	// Get path to thread.json
-	path := ThreadFilePath(s.threadsDir, t.ID)
+	return writeTaskFile(s.ThreadFile(t.ID), t)
+}
+
+// writeTaskFile atomically writes t to path in the current schema version.
+func writeTaskFile(path string, t *task.Task) error {
+	t.SchemaVersion = task.SchemaVersion
 
 	// Prepare data for JSON encoding
 	data, err := json.MarshalIndent(t, "", "  ")
@@ -211,14 +369,24 @@ func (s *FileStore) EnsureShortID(t *task.Task) error {
 		return nil
 	}
 
-	// Generate and assign next short_id
-	nextID, err := s.GenerateNextShortID()
-	if err != nil {
-		return fmt.Errorf("failed to generate short_id: %w", err)
-	}
+	// Generate and assign next short_id under the lock so a concurrent
+	// add can't pick the same number
+	return s.WithLock(func() error {
+		// Another process may have assigned one since t was loaded
+		if current, err := s.loadTask(s.ThreadFile(t.ID)); err == nil && current.ShortID != nil {
+			t.ShortID = current.ShortID
+			return nil
+		}
+
+		// max + 1 under the lock is always free
+		nextID, err := s.GenerateNextShortID()
+		if err != nil {
+			return fmt.Errorf("failed to generate short_id: %w", err)
+		}
 
-	t.ShortID = &nextID
-	return s.Save(t)
+		t.ShortID = &nextID
+		return s.save(t)
+	})
 }
 
 // ResolveID resolves a task ID which may be either a durable ID or a short_id.
@@ -268,3 +436,93 @@ func (s *FileStore) ResolveID(idStr string) (*task.Task, error) {
 
 	return t, nil
 }
+
+// LookupID resolves idStr like ResolveID, but only accepts durable IDs in
+// the form GenerateID returns and never assigns a short_id, so it never
+// writes to the workspace.
+func (s *FileStore) LookupID(idStr string) (*task.Task, error) {
+	if task.IsValidID(idStr) {
+		t, err := s.loadTask(s.ThreadFile(idStr))
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil, fmt.Errorf("task %s not found", idStr)
+			}
+			return nil, err
+		}
+		return t, nil
+	}
+
+	shortID, err := strconv.Atoi(idStr)
+	if err != nil {
+		return nil, fmt.Errorf("'%s' is not a valid task ID or short_id", idStr)
+	}
+	return s.GetByShortID(shortID)
+}
+
+// Rebucket moves every thread directory whose bucket doesn't match this
+// store's bucket width into the correct bucket. Empty buckets left behind
+// are removed. Returns the number of threads moved.
+//
+// Run this after changing the bucket width of an existing workspace.
+func (s *FileStore) Rebucket() (int, error) {
+	moved := 0
+	err := s.WithLock(func() error {
+		var err error
+		moved, err = s.rebucket()
+		return err
+	})
+	return moved, err
+}
+
+// rebucket does the work of Rebucket; callers must hold the workspace lock.
+func (s *FileStore) rebucket() (int, error) {
+	entries, err := os.ReadDir(s.threadsDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to read threads directory: %w", err)
+	}
+
+	moved := 0
+	for _, bucketEntry := range entries {
+		if !bucketEntry.IsDir() {
+			continue
+		}
+
+		bucketPath := filepath.Join(s.threadsDir, bucketEntry.Name())
+		threadEntries, err := os.ReadDir(bucketPath)
+		if err != nil {
+			return moved, fmt.Errorf("failed to read bucket %s: %w", bucketEntry.Name(), err)
+		}
+
+		for _, threadEntry := range threadEntries {
+			if !threadEntry.IsDir() {
+				continue
+			}
+
+			threadID := threadEntry.Name()
+			src := filepath.Join(bucketPath, threadID)
+			dst := s.ThreadDir(threadID)
+			if src == dst {
+				continue
+			}
+
+			if _, err := os.Stat(dst); err == nil {
+				return moved, fmt.Errorf("cannot move thread %s: %s already exists", threadID, dst)
+			}
+			if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+				return moved, fmt.Errorf("failed to create bucket directory: %w", err)
+			}
+			if err := os.Rename(src, dst); err != nil {
+				return moved, fmt.Errorf("failed to move thread %s: %w", threadID, err)
+			}
+			moved++
+		}
+
+		// Remove the bucket if it is now empty (ignore errors: it may still hold threads)
+		_ = os.Remove(bucketPath)
+	}
+
+	return moved, nil
+}
@@ -0,0 +1,86 @@
+package store
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sjatkinson/threadkeeper/internal/task"
+)
+
+func TestLoadAllWithSkipped(t *testing.T) {
+	threadsDir := t.TempDir()
+	st := NewFileStore(threadsDir)
+
+	good := &task.Task{ID: "01ARZ3NDEKTSV4RRFFQ69G5FAV", Title: "good", Status: task.StatusOpen, CreatedAt: time.Now().UTC(), Tags: []string{}}
+	if err := st.Save(good); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	corruptID := "01ARZ3NDEKTSV4RRFFQ69G5FBW"
+	if err := os.MkdirAll(st.ThreadDir(corruptID), 0755); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	corruptPath := st.ThreadFile(corruptID)
+	if err := os.WriteFile(corruptPath, []byte("{not json"), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	tasks, skipped, err := st.LoadAllWithSkipped()
+	if err != nil {
+		t.Fatalf("LoadAllWithSkipped() error = %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].ID != good.ID {
+		t.Errorf("tasks = %v, want only %s", tasks, good.ID)
+	}
+	if len(skipped) != 1 || filepath.Clean(skipped[0].Path) != filepath.Clean(corruptPath) || skipped[0].Err == nil {
+		t.Errorf("skipped = %v, want %s with an error", skipped, corruptPath)
+	}
+
+	// LoadAll keeps its lenient behavior
+	tasks, err = st.LoadAll()
+	if err != nil || len(tasks) != 1 {
+		t.Errorf("LoadAll() = %d tasks, err = %v; want 1 task", len(tasks), err)
+	}
+}
+
+// BenchmarkLoadAll parses a few thousand threads with and without the worker
+// pool. The index is removed before each run, so every file is parsed.
+func BenchmarkLoadAll(b *testing.B) {
+	st := NewFileStore(b.TempDir())
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 3000; i++ {
+		tk := &task.Task{
+			ID:          fmt.Sprintf("01BENCH%019d", i),
+			Title:       fmt.Sprintf("synthetic task %d", i),
+			Description: "Some description text long enough to be worth parsing.",
+			Status:      task.StatusOpen,
+			Project:     "bench",
+			Tags:        []string{"alpha", "beta"},
+			CreatedAt:   created.Add(time.Duration(i) * time.Minute),
+		}
+		if err := st.Save(tk); err != nil {
+			b.Fatalf("Save() error = %v", err)
+		}
+	}
+
+	for _, workers := range []int{1, 4, 16} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			orig := loadWorkers
+			loadWorkers = workers
+			b.Cleanup(func() { loadWorkers = orig })
+
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				_ = os.Remove(st.IndexFile())
+				b.StartTimer()
+				tasks, err := st.LoadAll()
+				if err != nil || len(tasks) != 3000 {
+					b.Fatalf("LoadAll() = %d tasks, err = %v", len(tasks), err)
+				}
+			}
+		})
+	}
+}
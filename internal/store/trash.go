@@ -0,0 +1,187 @@
+package store
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sjatkinson/threadkeeper/internal/task"
+)
+
+// TrashDirName is the directory at the workspace root that holds removed
+// threads until the trash is emptied.
+const TrashDirName = ".trash"
+
+// TrashDir returns the trash directory for this store.
+// The workspace is the parent of the threads directory.
+func (s *FileStore) TrashDir() string {
+	return filepath.Join(filepath.Dir(s.threadsDir), TrashDirName)
+}
+
+// Trash moves a thread directory, with all its attachments, into the trash.
+// An older trashed copy of the same thread is replaced.
+func (s *FileStore) Trash(threadID string) error {
+	return s.WithLock(func() error {
+		src := s.ThreadDir(threadID)
+		if _, err := os.Stat(src); err != nil {
+			return fmt.Errorf("thread %s not found: %w", threadID, err)
+		}
+
+		dst := filepath.Join(s.TrashDir(), threadID)
+		if err := os.RemoveAll(dst); err != nil {
+			return fmt.Errorf("failed to replace trashed thread %s: %w", threadID, err)
+		}
+		if err := os.MkdirAll(s.TrashDir(), 0755); err != nil {
+			return fmt.Errorf("failed to create trash directory: %w", err)
+		}
+		if err := os.Rename(src, dst); err != nil {
+			return fmt.Errorf("failed to move thread %s to trash: %w", threadID, err)
+		}
+
+		// Remove the bucket if it is now empty (ignore errors: it may still hold threads)
+		_ = os.Remove(filepath.Dir(src))
+		return nil
+	})
+}
+
+// LoadTrash loads the tasks in the trash, sorted by ID. Unreadable entries
+// are skipped.
+func (s *FileStore) LoadTrash() ([]*task.Task, error) {
+	entries, err := os.ReadDir(s.TrashDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []*task.Task{}, nil
+		}
+		return nil, fmt.Errorf("failed to read trash directory: %w", err)
+	}
+
+	var tasks []*task.Task
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		t, err := s.loadTask(filepath.Join(s.TrashDir(), entry.Name(), "thread.json"))
+		if err != nil {
+			continue
+		}
+		tasks = append(tasks, t)
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].ID < tasks[j].ID
+	})
+	return tasks, nil
+}
+
+// ResolveTrashID finds a trashed thread by its durable ID or a unique
+// prefix of it (case-insensitive).
+func (s *FileStore) ResolveTrashID(idStr string) (*task.Task, error) {
+	tasks, err := s.LoadTrash()
+	if err != nil {
+		return nil, err
+	}
+
+	idStr = strings.ToUpper(strings.TrimSpace(idStr))
+	var matches []*task.Task
+	for _, t := range tasks {
+		if t.ID == idStr {
+			return t, nil
+		}
+		if idStr != "" && strings.HasPrefix(t.ID, idStr) {
+			matches = append(matches, t)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no trashed task matches %q", idStr)
+	case 1:
+		return matches[0], nil
+	default:
+		return nil, fmt.Errorf("%q matches %d trashed tasks; use more of the ID", idStr, len(matches))
+	}
+}
+
+// Restore moves a trashed thread back into its bucket. An open task whose
+// short_id was reused while it was in the trash gets a new one.
+func (s *FileStore) Restore(threadID string) (*task.Task, error) {
+	var restored *task.Task
+	err := s.WithLock(func() error {
+		src := filepath.Join(s.TrashDir(), threadID)
+		dst := s.ThreadDir(threadID)
+		if _, err := os.Stat(dst); err == nil {
+			return fmt.Errorf("cannot restore thread %s: %s already exists", threadID, dst)
+		}
+
+		t, err := s.loadTask(filepath.Join(src, "thread.json"))
+		if err != nil {
+			return err
+		}
+
+		reused := false
+		if t.Status == task.StatusOpen && t.ShortID != nil {
+			if reused, err = s.shortIDInUse(*t.ShortID); err != nil {
+				return err
+			}
+		}
+
+		if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			return fmt.Errorf("failed to create bucket directory: %w", err)
+		}
+		if err := os.Rename(src, dst); err != nil {
+			return fmt.Errorf("failed to restore thread %s: %w", threadID, err)
+		}
+
+		if reused {
+			t.ShortID = nil
+			if err := s.save(t); err != nil {
+				return err
+			}
+		}
+		if err := s.EnsureShortID(t); err != nil {
+			return err
+		}
+		restored = t
+		return nil
+	})
+	return restored, err
+}
+
+// shortIDInUse reports whether any open task in the store holds shortID.
+func (s *FileStore) shortIDInUse(shortID int) (bool, error) {
+	tasks, err := s.LoadAll()
+	if err != nil {
+		return false, err
+	}
+	for _, t := range tasks {
+		if t.Status == task.StatusOpen && t.ShortID != nil && *t.ShortID == shortID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// EmptyTrash permanently deletes everything in the trash and returns the
+// number of threads deleted.
+func (s *FileStore) EmptyTrash() (int, error) {
+	deleted := 0
+	err := s.WithLock(func() error {
+		entries, err := os.ReadDir(s.TrashDir())
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return fmt.Errorf("failed to read trash directory: %w", err)
+		}
+		for _, entry := range entries {
+			if err := os.RemoveAll(filepath.Join(s.TrashDir(), entry.Name())); err != nil {
+				return fmt.Errorf("failed to delete %s: %w", entry.Name(), err)
+			}
+			if entry.IsDir() {
+				deleted++
+			}
+		}
+		return nil
+	})
+	return deleted, err
+}
@@ -29,3 +29,20 @@ func GenerateID() (string, error) {
 
 	return encoded, nil
 }
+
+// IDLength is the length of every ID GenerateID returns.
+const IDLength = 26
+
+// IsValidID reports whether s has the form of an ID from GenerateID:
+// IDLength characters of the unpadded base32 alphabet.
+func IsValidID(s string) bool {
+	if len(s) != IDLength {
+		return false
+	}
+	for _, c := range s {
+		if (c < 'A' || c > 'Z') && (c < '2' || c > '7') {
+			return false
+		}
+	}
+	return true
+}
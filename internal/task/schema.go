@@ -0,0 +1,89 @@
+package task
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// SchemaVersion is the thread.json format this build reads and writes.
+// Files without a schema_version field are version 1.
+const SchemaVersion = 4
+
+// schemaSteps upgrade a decoded thread.json object one version at a time:
+// schemaSteps[i] takes version i+1 to version i+2. Append a step whenever
+// SchemaVersion is bumped; never edit a step that has shipped.
+var schemaSteps = []func(obj map[string]any){
+	backfillCompletedAt, // 1 -> 2
+	noBackfill,          // 2 -> 3: started_at and time_spent
+	noBackfill,          // 3 -> 4: estimate
+}
+
+// NewerSchemaError reports a thread file written by a newer version of tk.
+type NewerSchemaError struct {
+	Version int
+}
+
+func (e *NewerSchemaError) Error() string {
+	return fmt.Sprintf("schema version %d is newer than this version of tk supports (%d); upgrade tk", e.Version, SchemaVersion)
+}
+
+// Upgrade brings raw thread.json data up to SchemaVersion and returns the
+// upgraded data along with the version it started at. Data that is already
+// current is returned unchanged. Returns a *NewerSchemaError for files from
+// a newer schema, since reading them could silently drop fields.
+func Upgrade(data []byte) ([]byte, int, error) {
+	var head struct {
+		SchemaVersion int `json:"schema_version"`
+	}
+	if err := json.Unmarshal(data, &head); err != nil {
+		return nil, 0, err
+	}
+	from := head.SchemaVersion
+	if from == 0 {
+		from = 1
+	}
+	if from > SchemaVersion {
+		return nil, from, &NewerSchemaError{Version: from}
+	}
+	if from == SchemaVersion {
+		return data, from, nil
+	}
+
+	// Decode numbers as json.Number so short IDs survive the round trip exactly
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var obj map[string]any
+	if err := dec.Decode(&obj); err != nil {
+		return nil, from, err
+	}
+	for v := from; v < SchemaVersion; v++ {
+		schemaSteps[v-1](obj)
+	}
+	obj["schema_version"] = SchemaVersion
+
+	upgraded, err := json.Marshal(obj)
+	if err != nil {
+		return nil, from, err
+	}
+	return upgraded, from, nil
+}
+
+// noBackfill is the step for versions that only add optional fields. The
+// bump still matters: it stops older builds from rewriting the file and
+// dropping fields they don't know.
+func noBackfill(map[string]any) {}
+
+// backfillCompletedAt gives done tasks from before completed_at existed
+// their last update time as the completion time, the closest record left.
+func backfillCompletedAt(obj map[string]any) {
+	if obj["status"] != string(StatusDone) {
+		return
+	}
+	if c, ok := obj["completed_at"].(string); ok && c != "" {
+		return
+	}
+	if u, ok := obj["updated_at"].(string); ok && u != "" {
+		obj["completed_at"] = u
+	}
+}
@@ -2,6 +2,7 @@ package task
 
 import (
 	"encoding/json"
+	"fmt"
 	"strings"
 	"time"
 )
@@ -27,20 +28,35 @@ type Task struct {
 	Project     string     `json:"project,omitempty"`
 	Tags        []string   `json:"tags"`
 	ShortID     *int       `json:"short_id,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	// StartedAt is set while a timer is running (tk start); TimeSpent is the
+	// total of all finished timer runs.
+	StartedAt *time.Time    `json:"started_at,omitempty"`
+	TimeSpent time.Duration `json:"time_spent,omitempty"`
+	// Estimate is how long the task is expected to take, if anyone said.
+	Estimate time.Duration `json:"estimate,omitempty"`
+	// SchemaVersion is the thread.json format the task was read from;
+	// FileStore.Save always writes the current SchemaVersion.
+	SchemaVersion int `json:"schema_version"`
 }
 
 // taskJSON is used for JSON unmarshaling to handle string timestamps.
 type taskJSON struct {
-	ID          string   `json:"id"`
-	Title       string   `json:"title"`
-	Description string   `json:"description"`
-	Status      Status   `json:"status"`
-	CreatedAt   string   `json:"created_at"`
-	UpdatedAt   string   `json:"updated_at"`
-	DueAt       *string  `json:"due_at,omitempty"`
-	Project     string   `json:"project,omitempty"`
-	Tags        []string `json:"tags"`
-	ShortID     *int     `json:"short_id,omitempty"`
+	ID            string   `json:"id"`
+	Title         string   `json:"title"`
+	Description   string   `json:"description"`
+	Status        Status   `json:"status"`
+	CreatedAt     string   `json:"created_at"`
+	UpdatedAt     string   `json:"updated_at"`
+	DueAt         *string  `json:"due_at,omitempty"`
+	Project       string   `json:"project,omitempty"`
+	Tags          []string `json:"tags"`
+	ShortID       *int     `json:"short_id,omitempty"`
+	CompletedAt   *string  `json:"completed_at,omitempty"`
+	StartedAt     *string  `json:"started_at,omitempty"`
+	TimeSpent     string   `json:"time_spent,omitempty"`
+	Estimate      string   `json:"estimate,omitempty"`
+	SchemaVersion int      `json:"schema_version"`
 }
 
 // UnmarshalJSON implements custom JSON unmarshaling to parse ISO8601 timestamps.
@@ -57,6 +73,10 @@ func (t *Task) UnmarshalJSON(data []byte) error {
 	t.Project = tj.Project
 	t.Tags = tj.Tags
 	t.ShortID = tj.ShortID
+	t.SchemaVersion = tj.SchemaVersion
+	if t.SchemaVersion == 0 {
+		t.SchemaVersion = 1 // Files from before schema_version existed
+	}
 
 	// Parse timestamps
 	if tj.CreatedAt != "" {
@@ -90,6 +110,34 @@ func (t *Task) UnmarshalJSON(data []byte) error {
 		}
 	}
 
+	if tj.CompletedAt != nil && *tj.CompletedAt != "" {
+		completedAt, err := time.Parse(time.RFC3339, *tj.CompletedAt)
+		if err == nil {
+			completedAt = completedAt.UTC()
+			t.CompletedAt = &completedAt
+		}
+	}
+
+	if tj.StartedAt != nil && *tj.StartedAt != "" {
+		startedAt, err := time.Parse(time.RFC3339, *tj.StartedAt)
+		if err == nil {
+			startedAt = startedAt.UTC()
+			t.StartedAt = &startedAt
+		}
+	}
+
+	if tj.TimeSpent != "" {
+		if spent, err := time.ParseDuration(tj.TimeSpent); err == nil {
+			t.TimeSpent = spent
+		}
+	}
+
+	if tj.Estimate != "" {
+		if estimate, err := time.ParseDuration(tj.Estimate); err == nil {
+			t.Estimate = estimate
+		}
+	}
+
 	return nil
 }
 
@@ -98,10 +146,14 @@ func (t *Task) UnmarshalJSON(data []byte) error {
 func (t *Task) MarshalJSON() ([]byte, error) {
 	type Alias Task
 	aux := &struct {
-		CreatedAt string  `json:"created_at"`
-		UpdatedAt string  `json:"updated_at"`
-		DueAt     *string `json:"due_at,omitempty"`
-		ShortID   *int    `json:"short_id,omitempty"`
+		CreatedAt   string  `json:"created_at"`
+		UpdatedAt   string  `json:"updated_at"`
+		DueAt       *string `json:"due_at,omitempty"`
+		ShortID     *int    `json:"short_id,omitempty"`
+		CompletedAt *string `json:"completed_at,omitempty"`
+		StartedAt   *string `json:"started_at,omitempty"`
+		TimeSpent   string  `json:"time_spent,omitempty"`
+		Estimate    string  `json:"estimate,omitempty"`
 		*Alias
 	}{
 		CreatedAt: t.CreatedAt.Format(time.RFC3339),
@@ -115,15 +167,71 @@ func (t *Task) MarshalJSON() ([]byte, error) {
 		aux.DueAt = &s
 	}
 
+	if t.CompletedAt != nil {
+		s := t.CompletedAt.Format(time.RFC3339)
+		aux.CompletedAt = &s
+	}
+
+	if t.StartedAt != nil {
+		s := t.StartedAt.Format(time.RFC3339)
+		aux.StartedAt = &s
+	}
+
+	// Durations are stored as Go duration strings ("1h30m0s") so they read
+	// well in the file
+	if t.TimeSpent > 0 {
+		aux.TimeSpent = t.TimeSpent.String()
+	}
+	if t.Estimate > 0 {
+		aux.Estimate = t.Estimate.String()
+	}
+
 	return json.Marshal(aux)
 }
 
+// StartTimer starts tracking time at now. Returns false, changing nothing,
+// if a timer is already running.
+func (t *Task) StartTimer(now time.Time) bool {
+	if t.StartedAt != nil {
+		return false
+	}
+	started := now.UTC()
+	t.StartedAt = &started
+	return true
+}
+
+// StopTimer adds the time since StartedAt to TimeSpent, to the second, and
+// clears StartedAt. Returns the time added and whether a timer was running.
+func (t *Task) StopTimer(now time.Time) (time.Duration, bool) {
+	if t.StartedAt == nil {
+		return 0, false
+	}
+	elapsed := max(now.Sub(*t.StartedAt).Truncate(time.Second), 0)
+	t.TimeSpent += elapsed
+	t.StartedAt = nil
+	return elapsed, true
+}
+
+// TotalTimeSpent is TimeSpent plus the running timer, if any, up to now.
+func (t *Task) TotalTimeSpent(now time.Time) time.Duration {
+	total := t.TimeSpent
+	if t.StartedAt != nil {
+		total += max(now.Sub(*t.StartedAt).Truncate(time.Second), 0)
+	}
+	return total
+}
+
 // NormalizeTags normalizes a list of tags by trimming whitespace and lowercasing.
+// Tags of the form key:value keep the case of their value; only the key is
+// lowercased (e.g. "Sprint:Q3" becomes "sprint:Q3"). A leading "#" is
+// dropped and runs of internal whitespace become a single hyphen, so
+// "#Bug" is "bug" and "needs review" is "needs-review". Tags that are empty
+// after normalizing are dropped; use ValidateTags to reject them instead.
 func NormalizeTags(tags []string) []string {
 	normalized := make([]string, 0, len(tags))
 	seen := make(map[string]bool)
 	for _, t := range tags {
-		cleaned := strings.TrimSpace(strings.ToLower(t))
+		cleaned := normalizeTag(t)
 		if cleaned != "" && !seen[cleaned] {
 			normalized = append(normalized, cleaned)
 			seen[cleaned] = true
@@ -132,6 +240,44 @@ func NormalizeTags(tags []string) []string {
 	return normalized
 }
 
+// ValidateTags returns an error for the first tag that is empty after
+// normalizing, such as "", "  " or "#".
+func ValidateTags(tags []string) error {
+	for _, t := range tags {
+		if normalizeTag(t) == "" {
+			return fmt.Errorf("invalid tag %q: empty after removing \"#\" and whitespace", t)
+		}
+	}
+	return nil
+}
+
+// normalizeTag normalizes a single tag, preserving value case for key:value tags.
+func normalizeTag(tag string) string {
+	tag = strings.TrimLeft(strings.TrimSpace(tag), "#")
+	key, value, ok := strings.Cut(tag, ":")
+	if !ok {
+		return hyphenate(strings.ToLower(tag))
+	}
+	key = hyphenate(strings.ToLower(key))
+	value = hyphenate(value)
+	if key == "" {
+		// ":foo" has no key; treat it as a plain tag
+		return hyphenate(strings.ToLower(tag))
+	}
+	return key + ":" + value
+}
+
+// hyphenate trims s and joins its whitespace-separated words with hyphens.
+func hyphenate(s string) string {
+	return strings.Join(strings.Fields(s), "-")
+}
+
+// SplitTag splits a key:value tag into its key and value.
+// Returns ok=false for plain tags without a colon.
+func SplitTag(tag string) (key, value string, ok bool) {
+	return strings.Cut(tag, ":")
+}
+
 // Normalize ensures a task has all expected fields with reasonable defaults.
 func (t *Task) Normalize() {
 	if t.Title == "" {
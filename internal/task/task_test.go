@@ -0,0 +1,202 @@
+package task
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []string
+		want  []string
+	}{
+		{"lowercase and trim", []string{"  Bug ", "WIP"}, []string{"bug", "wip"}},
+		{"dedupe after normalizing", []string{"bug", "BUG", " bug"}, []string{"bug"}},
+		{"drop empty", []string{"", "   ", "ok"}, []string{"ok"}},
+		{"key:value keeps colon", []string{"sprint:42"}, []string{"sprint:42"}},
+		{"key:value lowercases key only", []string{"Sprint:Q3"}, []string{"sprint:Q3"}},
+		{"key:value trims both sides", []string{" owner : Alice "}, []string{"owner:Alice"}},
+		{"key:value dedupes on key case", []string{"Sprint:Q3", "sprint:Q3"}, []string{"sprint:Q3"}},
+		{"key:value values are case-sensitive", []string{"env:Prod", "env:prod"}, []string{"env:Prod", "env:prod"}},
+		{"missing key is a plain tag", []string{":Foo"}, []string{":foo"}},
+		{"leading hash dropped", []string{"#bug", "bug", "##Bug"}, []string{"bug"}},
+		{"hash inside kept", []string{"c#"}, []string{"c#"}},
+		{"whitespace becomes hyphen", []string{"needs  review", "needs-review"}, []string{"needs-review"}},
+		{"key:value hyphenates both sides", []string{"#Team Name: Core Infra"}, []string{"team-name:Core-Infra"}},
+		{"drop hash only", []string{"#", " # "}, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NormalizeTags(tt.input)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NormalizeTags(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateTags(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   []string
+		wantErr bool
+	}{
+		{"plain", []string{"bug", "#wip", "needs review"}, false},
+		{"key:value", []string{"sprint:42"}, false},
+		{"none", nil, false},
+		{"empty", []string{"ok", ""}, true},
+		{"blank", []string{"   "}, true},
+		{"hash only", []string{"#"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTags(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateTags(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestIsValidID(t *testing.T) {
+	id, err := GenerateID()
+	if err != nil {
+		t.Fatalf("GenerateID() error = %v", err)
+	}
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{id, true},
+		{"", false},
+		{"12", false},
+		{strings.ToLower(id), false},
+		{id[:IDLength-1], false},
+		{id + "A", false},
+		{"../" + id[3:], false},
+		{id[:IDLength-1] + "1", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsValidID(tt.input); got != tt.want {
+			t.Errorf("IsValidID(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestTaskJSONCompletedAt(t *testing.T) {
+	completed := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	orig := &Task{ID: "a", Status: StatusDone, CompletedAt: &completed, Tags: []string{}}
+
+	data, err := json.Marshal(orig)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if !strings.Contains(string(data), `"completed_at":"2026-03-04T05:06:07Z"`) {
+		t.Errorf("Marshal() = %s, want completed_at", data)
+	}
+
+	var got Task
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if got.CompletedAt == nil || !got.CompletedAt.Equal(completed) {
+		t.Errorf("CompletedAt = %v, want %v", got.CompletedAt, completed)
+	}
+
+	orig.CompletedAt = nil
+	data, err = json.Marshal(orig)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if strings.Contains(string(data), "completed_at") {
+		t.Errorf("Marshal() = %s, want completed_at omitted", data)
+	}
+}
+
+func TestTaskJSONTimer(t *testing.T) {
+	started := time.Date(2026, 3, 4, 9, 0, 0, 0, time.UTC)
+	orig := &Task{ID: "a", Status: StatusOpen, StartedAt: &started, TimeSpent: 90 * time.Minute, Estimate: 2 * time.Hour, Tags: []string{}}
+
+	data, err := json.Marshal(orig)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if !strings.Contains(string(data), `"started_at":"2026-03-04T09:00:00Z"`) || !strings.Contains(string(data), `"time_spent":"1h30m0s"`) || !strings.Contains(string(data), `"estimate":"2h0m0s"`) {
+		t.Errorf("Marshal() = %s, want started_at, time_spent and estimate", data)
+	}
+
+	var got Task
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if got.StartedAt == nil || !got.StartedAt.Equal(started) || got.TimeSpent != 90*time.Minute || got.Estimate != 2*time.Hour {
+		t.Errorf("StartedAt, TimeSpent, Estimate = %v, %v, %v; want %v, 1h30m, 2h", got.StartedAt, got.TimeSpent, got.Estimate, started)
+	}
+
+	data, err = json.Marshal(&Task{ID: "b", Tags: []string{}})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if strings.Contains(string(data), "started_at") || strings.Contains(string(data), "time_spent") || strings.Contains(string(data), "estimate") {
+		t.Errorf("Marshal() = %s, want timer and estimate fields omitted", data)
+	}
+}
+
+func TestTaskTimer(t *testing.T) {
+	start := time.Date(2026, 3, 4, 9, 0, 0, 0, time.UTC)
+	tk := &Task{TimeSpent: time.Hour}
+
+	if _, ok := tk.StopTimer(start); ok {
+		t.Errorf("StopTimer() with no timer running reported ok")
+	}
+	if !tk.StartTimer(start) || tk.StartTimer(start.Add(time.Minute)) {
+		t.Errorf("StartTimer() should succeed once, then refuse while running")
+	}
+	if got := tk.TotalTimeSpent(start.Add(10 * time.Minute)); got != 70*time.Minute {
+		t.Errorf("TotalTimeSpent() while running = %v, want 1h10m", got)
+	}
+	elapsed, ok := tk.StopTimer(start.Add(25*time.Minute + 1500*time.Millisecond))
+	if !ok || elapsed != 25*time.Minute+time.Second {
+		t.Errorf("StopTimer() = %v, %v; want 25m1s, true", elapsed, ok)
+	}
+	if tk.StartedAt != nil || tk.TimeSpent != 85*time.Minute+time.Second {
+		t.Errorf("after stop StartedAt = %v, TimeSpent = %v; want nil, 1h25m1s", tk.StartedAt, tk.TimeSpent)
+	}
+}
+
+func TestUpgrade(t *testing.T) {
+	v1Done := `{"id":"a","status":"done","created_at":"2026-01-01T00:00:00Z","updated_at":"2026-01-05T10:00:00Z","short_id":7,"tags":[]}`
+	got, from, err := Upgrade([]byte(v1Done))
+	if err != nil || from != 1 {
+		t.Fatalf("Upgrade(v1) = from %d, err %v; want from 1", from, err)
+	}
+	var tk Task
+	if err := json.Unmarshal(got, &tk); err != nil {
+		t.Fatalf("Unmarshal(upgraded) error = %v", err)
+	}
+	if tk.SchemaVersion != SchemaVersion || tk.ShortID == nil || *tk.ShortID != 7 {
+		t.Errorf("upgraded task = version %d, short_id %v; want version %d, short_id 7", tk.SchemaVersion, tk.ShortID, SchemaVersion)
+	}
+	if tk.CompletedAt == nil || !tk.CompletedAt.Equal(tk.UpdatedAt) {
+		t.Errorf("CompletedAt = %v, want back-filled from updated_at %v", tk.CompletedAt, tk.UpdatedAt)
+	}
+
+	current := []byte(fmt.Sprintf(`{"id":"a","status":"open","schema_version":%d}`, SchemaVersion))
+	if got, from, err := Upgrade(current); err != nil || from != SchemaVersion || string(got) != string(current) {
+		t.Errorf("Upgrade(current) = %q, from %d, err %v; want it unchanged", got, from, err)
+	}
+
+	var newer *NewerSchemaError
+	if _, _, err := Upgrade([]byte(`{"id":"a","schema_version":99}`)); !errors.As(err, &newer) || newer.Version != 99 {
+		t.Errorf("Upgrade(newer) error = %v, want NewerSchemaError for 99", err)
+	}
+}